@@ -0,0 +1,98 @@
+package array
+
+import "reflect"
+
+// From returns the tail of the slice starting at position. A negative
+// position counts from the end of the slice. The result is empty when the
+// position is out of range.
+//
+//	From([]string{"a", "b", "c", "d"}, 2)  // => [c d]
+//	From([]string{"a", "b", "c", "d"}, 10) // => []
+//	From([]string{"a", "b", "c", "d"}, -2) // => [c d]
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Array.html#method-i-from
+func From[T any](s []T, position int) []T {
+	if position < 0 {
+		position += len(s)
+	}
+	if position < 0 || position > len(s) {
+		return []T{}
+	}
+	return s[position:]
+}
+
+// To returns the beginning of the slice up to position, included. A
+// negative position counts from the end of the slice.
+//
+//	To([]string{"a", "b", "c", "d"}, 0)   // => [a]
+//	To([]string{"a", "b", "c", "d"}, 10)  // => [a b c d]
+//	To([]string{"a", "b", "c", "d"}, -2)  // => [a b c]
+//	To([]string{"a", "b", "c", "d"}, -10) // => []
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Array.html#method-i-to
+func To[T any](s []T, position int) []T {
+	if position < 0 {
+		position += len(s)
+	}
+	if position < 0 {
+		return []T{}
+	}
+	if position >= len(s) {
+		return s
+	}
+	return s[:position+1]
+}
+
+// SplitAt divides the slice into one or more sub slices, using the elements
+// equal to value as separators. The separators are not included and
+// consecutive separators result in empty sub slices.
+//
+//	SplitAt([]int{1, 2, 3, 4, 5}, 3) // => [[1 2] [4 5]]
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Array.html#method-i-split
+func SplitAt[T comparable](s []T, value T) [][]T {
+	return SplitAtFunc(s, func(e T) bool { return e == value })
+}
+
+// SplitAtFunc is like SplitAt but uses the elements matching the predicate
+// as separators.
+//
+//	SplitAtFunc([]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, func(i int) bool { return i%3 == 0 })
+//	// => [[1 2] [4 5] [7 8] [10]]
+func SplitAtFunc[T any](s []T, separator func(T) bool) [][]T {
+	groups := [][]T{{}}
+	for _, e := range s {
+		if separator(e) {
+			groups = append(groups, []T{})
+			continue
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], e)
+	}
+	return groups
+}
+
+// Wrap wraps its argument in a slice unless it is already a slice (or an
+// array), in which case its elements are returned. nil results in an empty
+// slice.
+//
+//	Wrap(nil)              // => []
+//	Wrap([]int{1, 2, 3})   // => [1 2 3]
+//	Wrap(0)                // => [0]
+//	Wrap(map[string]int{}) // => [map[]]
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Array.html#method-c-wrap
+func Wrap(v interface{}) []interface{} {
+	if v == nil {
+		return []interface{}{}
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		wrapped := make([]interface{}, rv.Len())
+		for i := range wrapped {
+			wrapped[i] = rv.Index(i).Interface()
+		}
+		return wrapped
+	}
+	return []interface{}{v}
+}
@@ -0,0 +1,82 @@
+package array
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleSplitAtFunc() {
+	s := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	fmt.Println(SplitAt(s, 3))
+	fmt.Println(SplitAtFunc(s, func(i int) bool { return i%3 == 0 }))
+	// Output: [[1 2] [4 5 6 7 8 9 10]]
+	// [[1 2] [4 5] [7 8] [10]]
+}
+
+func ExampleWrap() {
+	fmt.Println(Wrap(nil))
+	fmt.Println(Wrap([]int{1, 2, 3}))
+	fmt.Println(Wrap("foo"))
+	// Output: []
+	// [1 2 3]
+	// [foo]
+}
+
+func TestAccess(t *testing.T) {
+	g := Goblin(t)
+	s := []string{"a", "b", "c", "d"}
+
+	g.Describe("From", func() {
+		g.It("Should return the tail of the slice", func() {
+			g.Assert(From(s, 0)).Eql(s)
+			g.Assert(From(s, 2)).Eql([]string{"c", "d"})
+			g.Assert(From(s, 4)).Eql([]string{})
+			g.Assert(From(s, 10)).Eql([]string{})
+			g.Assert(From(s, -2)).Eql([]string{"c", "d"})
+			g.Assert(From(s, -10)).Eql([]string{})
+		})
+	})
+
+	g.Describe("To", func() {
+		g.It("Should return the beginning of the slice", func() {
+			g.Assert(To(s, 0)).Eql([]string{"a"})
+			g.Assert(To(s, 2)).Eql([]string{"a", "b", "c"})
+			g.Assert(To(s, 10)).Eql(s)
+			g.Assert(To(s, -2)).Eql([]string{"a", "b", "c"})
+			g.Assert(To(s, -10)).Eql([]string{})
+			g.Assert(To([]string{}, 0)).Eql([]string{})
+		})
+	})
+
+	g.Describe("SplitAt", func() {
+		g.It("Should split on a value", func() {
+			g.Assert(SplitAt([]int{1, 2, 3, 4, 5}, 3)).Eql([][]int{{1, 2}, {4, 5}})
+			g.Assert(SplitAt([]int{1, 2, 3}, 3)).Eql([][]int{{1, 2}, {}})
+			g.Assert(SplitAt([]int{3, 1, 3, 3, 2}, 3)).Eql([][]int{{}, {1}, {}, {2}})
+			g.Assert(SplitAt([]int{1, 2}, 3)).Eql([][]int{{1, 2}})
+			g.Assert(SplitAt([]int{}, 3)).Eql([][]int{{}})
+		})
+
+		g.It("Should split with a predicate", func() {
+			odd := func(i int) bool { return i%2 == 1 }
+			g.Assert(SplitAtFunc([]int{2, 4, 5, 6, 7, 8}, odd)).Eql([][]int{{2, 4}, {6}, {8}})
+		})
+	})
+
+	g.Describe("Wrap", func() {
+		g.It("Should wrap anything in a slice", func() {
+			g.Assert(Wrap(nil)).Eql([]interface{}{})
+			g.Assert(Wrap(1)).Eql([]interface{}{1})
+			g.Assert(Wrap("foo")).Eql([]interface{}{"foo"})
+			g.Assert(Wrap(map[string]int{"a": 1})).Eql([]interface{}{map[string]int{"a": 1}})
+		})
+
+		g.It("Should return the elements of slices and arrays", func() {
+			g.Assert(Wrap([]int{1, 2})).Eql([]interface{}{1, 2})
+			g.Assert(Wrap([2]string{"a", "b"})).Eql([]interface{}{"a", "b"})
+			g.Assert(Wrap([]interface{}{})).Eql([]interface{}{})
+		})
+	})
+}
@@ -0,0 +1,70 @@
+// The array package ports ActiveSupport's Array extensions using generic
+// slices, so code ported from Rails can group and split collections the
+// same way.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Array.html
+package array
+
+// InGroupsOf splits the slice in groups of size n. When a fill value is
+// passed, the last group is padded with it, otherwise the last group can be
+// shorter, like passing false as fill_with in Rails.
+//
+//	InGroupsOf([]int{1, 2, 3, 4, 5, 6, 7}, 3, 0) // => [[1 2 3] [4 5 6] [7 0 0]]
+//	InGroupsOf([]int{1, 2, 3, 4, 5, 6, 7}, 3)    // => [[1 2 3] [4 5 6] [7]]
+//
+// InGroupsOf panics if n is not positive.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Array.html#method-i-in_groups_of
+func InGroupsOf[T any](s []T, n int, fill ...T) [][]T {
+	if n <= 0 {
+		panic("array: group size must be a positive integer")
+	}
+	groups := make([][]T, 0, (len(s)+n-1)/n)
+	for start := 0; start < len(s); start += n {
+		end := start + n
+		if end > len(s) {
+			end = len(s)
+		}
+		group := make([]T, end-start, n)
+		copy(group, s[start:end])
+		if len(fill) > 0 {
+			for len(group) < n {
+				group = append(group, fill[0])
+			}
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// InGroups splits the slice in n groups, sizing them as evenly as possible.
+// When a fill value is passed, the shorter groups are padded with it.
+//
+//	InGroups([]int{1, 2, 3, 4, 5, 6, 7}, 3, 0) // => [[1 2 3] [4 5 0] [6 7 0]]
+//	InGroups([]int{1, 2, 3, 4, 5, 6, 7}, 3)    // => [[1 2 3] [4 5] [6 7]]
+//
+// InGroups panics if n is not positive.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Array.html#method-i-in_groups
+func InGroups[T any](s []T, n int, fill ...T) [][]T {
+	if n <= 0 {
+		panic("array: number of groups must be a positive integer")
+	}
+	division, modulo := len(s)/n, len(s)%n
+	groups := make([][]T, 0, n)
+	start := 0
+	for i := 0; i < n; i++ {
+		length := division
+		if modulo > i {
+			length++
+		}
+		group := make([]T, length, division+1)
+		copy(group, s[start:start+length])
+		if len(fill) > 0 && modulo > 0 && length == division {
+			group = append(group, fill[0])
+		}
+		groups = append(groups, group)
+		start += length
+	}
+	return groups
+}
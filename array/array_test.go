@@ -0,0 +1,71 @@
+package array
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleInGroupsOf() {
+	fmt.Println(InGroupsOf([]string{"1", "2", "3", "4", "5", "6", "7"}, 3, "&nbsp;"))
+	fmt.Println(InGroupsOf([]int{1, 2, 3, 4, 5}, 2))
+	// Output: [[1 2 3] [4 5 6] [7 &nbsp; &nbsp;]]
+	// [[1 2] [3 4] [5]]
+}
+
+func ExampleInGroups() {
+	fmt.Println(InGroups([]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, 3, 0))
+	fmt.Println(InGroups([]int{1, 2, 3, 4, 5, 6, 7}, 3))
+	// Output: [[1 2 3 4] [5 6 7 0] [8 9 10 0]]
+	// [[1 2 3] [4 5] [6 7]]
+}
+
+func TestGroups(t *testing.T) {
+	g := Goblin(t)
+	seven := []int{1, 2, 3, 4, 5, 6, 7}
+
+	g.Describe("InGroupsOf", func() {
+		g.It("Should pad the last group with the fill value", func() {
+			g.Assert(InGroupsOf(seven, 3, 0)).Eql([][]int{{1, 2, 3}, {4, 5, 6}, {7, 0, 0}})
+			g.Assert(InGroupsOf(seven, 7, 0)).Eql([][]int{{1, 2, 3, 4, 5, 6, 7}})
+		})
+
+		g.It("Should not pad without a fill value", func() {
+			g.Assert(InGroupsOf(seven, 3)).Eql([][]int{{1, 2, 3}, {4, 5, 6}, {7}})
+			g.Assert(InGroupsOf(seven, 10)).Eql([][]int{{1, 2, 3, 4, 5, 6, 7}})
+			g.Assert(len(InGroupsOf([]int{}, 3))).Equal(0)
+		})
+
+		g.It("Should not share memory with the slice", func() {
+			s := []int{1, 2, 3, 4}
+			groups := InGroupsOf(s, 2)
+			groups[0][0] = 42
+			groups[0] = append(groups[0], 5)
+			g.Assert(s).Eql([]int{1, 2, 3, 4})
+		})
+
+		g.It("Should panic on invalid sizes", func() {
+			defer func() { g.Assert(recover() != nil).IsTrue() }()
+			InGroupsOf(seven, 0)
+		})
+	})
+
+	g.Describe("InGroups", func() {
+		g.It("Should split evenly", func() {
+			g.Assert(InGroups([]int{1, 2, 3, 4, 5, 6}, 3, 0)).Eql([][]int{{1, 2}, {3, 4}, {5, 6}})
+			g.Assert(InGroups(seven, 3, 0)).Eql([][]int{{1, 2, 3}, {4, 5, 0}, {6, 7, 0}})
+			g.Assert(InGroups(seven, 3)).Eql([][]int{{1, 2, 3}, {4, 5}, {6, 7}})
+		})
+
+		g.It("Should return empty groups when there are not enough elements", func() {
+			g.Assert(InGroups([]int{1, 2}, 4)).Eql([][]int{{1}, {2}, {}, {}})
+			g.Assert(InGroups([]int{1, 2}, 4, 0)).Eql([][]int{{1}, {2}, {0}, {0}})
+		})
+
+		g.It("Should panic on invalid sizes", func() {
+			defer func() { g.Assert(recover() != nil).IsTrue() }()
+			InGroups(seven, -1)
+		})
+	})
+}
@@ -0,0 +1,75 @@
+// The blank package ports ActiveSupport's Object#blank? and
+// Object#present?, so validation code ported from Rails can use a single
+// predicate instead of ad-hoc nil and length checks.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Object.html#method-i-blank-3F
+package blank
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Blanker is implemented by the types defining their own blankness, like
+// Ruby objects overriding blank?.
+type Blanker interface {
+	IsBlank() bool
+}
+
+// IsBlank reports whether v is blank:
+//
+//   - nil, including nil pointers, maps, slices, functions and channels
+//   - false
+//   - strings made of white spaces only
+//   - empty slices, arrays and maps
+//   - the zero time.Time
+//   - values implementing Blanker and reporting being blank
+//
+// Non nil pointers are blank if the value they point to is blank. Numbers
+// are never blank.
+//
+//	IsBlank("  ")       // => true
+//	IsBlank([]string{}) // => true
+//	IsBlank(0)          // => false
+func IsBlank(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		if rv.IsNil() {
+			return true
+		}
+	}
+	switch val := v.(type) {
+	case Blanker:
+		return val.IsBlank()
+	case time.Time:
+		return val.IsZero()
+	case string:
+		return strings.TrimSpace(val) == ""
+	case bool:
+		return !val
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		return IsBlank(rv.Elem().Interface())
+	case reflect.Bool:
+		return !rv.Bool()
+	case reflect.String:
+		return strings.TrimSpace(rv.String()) == ""
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return rv.Len() == 0
+	}
+	return false
+}
+
+// IsPresent is the opposite of IsBlank.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Object.html#method-i-present-3F
+func IsPresent(v interface{}) bool {
+	return !IsBlank(v)
+}
@@ -0,0 +1,59 @@
+package blank
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/franela/goblin"
+)
+
+type status string
+
+type account struct{ id int }
+
+func (a account) IsBlank() bool { return a.id == 0 }
+
+func ExampleIsBlank() {
+	fmt.Println(IsBlank(""))
+	fmt.Println(IsBlank(" \n\t"))
+	fmt.Println(IsBlank(map[string]int{}))
+	fmt.Println(IsBlank(0))
+	fmt.Println(IsPresent("Matt"))
+	// Output: true
+	// true
+	// true
+	// false
+	// true
+}
+
+func TestBlank(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("IsBlank", func() {
+		g.It("Should consider empty values blank", func() {
+			var nilPtr *string
+			var nilSlice []int
+			var nilMap map[string]int
+			empty := ""
+			for _, v := range []interface{}{
+				nil, false, "", " ", "　\n\t", status(""), nilPtr, &empty,
+				nilSlice, []int{}, [0]int{}, nilMap, map[string]int{}, time.Time{}, account{},
+			} {
+				g.Assert(IsBlank(v)).IsTrue()
+				g.Assert(IsPresent(v)).IsFalse()
+			}
+		})
+
+		g.It("Should consider other values present", func() {
+			name := "Matt"
+			for _, v := range []interface{}{
+				true, "a", " a ", status("active"), &name, []int{0}, map[string]int{"a": 0},
+				0, 1.5, time.Now(), account{id: 1}, struct{}{},
+			} {
+				g.Assert(IsBlank(v)).IsFalse()
+				g.Assert(IsPresent(v)).IsTrue()
+			}
+		})
+	})
+}
@@ -0,0 +1,120 @@
+// The cache package builds cache keys the way ActiveSupport::Cache and
+// ActiveRecord do, so a Go service can read and invalidate the entries a
+// Rails app stores in Rails.cache.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Cache.html
+package cache
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Keyer is implemented by the values providing their own cache key, like
+// Ruby objects responding to cache_key.
+type Keyer interface {
+	CacheKey() string
+}
+
+// VersionedKeyer is implemented by the values providing a versioned cache
+// key, like Ruby objects responding to cache_key_with_version.
+type VersionedKeyer interface {
+	CacheKeyWithVersion() string
+}
+
+// ExpandCacheKey expands the key into a string usable as a cache key,
+// prefixed by the namespace (if not empty) and by the RAILS_CACHE_ID or
+// RAILS_APP_VERSION environment variable (if set).
+//
+//	ExpandCacheKey([]interface{}{"views", user, 42}, "") // => "views/users/1-20240214150405000000/42"
+//	ExpandCacheKey("foo", "namespace")                   // => "namespace/foo"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Cache.html#method-c-expand_cache_key
+func ExpandCacheKey(key interface{}, namespace string) string {
+	var expanded string
+	if namespace != "" {
+		expanded = namespace + "/"
+	}
+	prefix := os.Getenv("RAILS_CACHE_ID")
+	if prefix == "" {
+		prefix = os.Getenv("RAILS_APP_VERSION")
+	}
+	if prefix != "" {
+		expanded += prefix + "/"
+	}
+	return expanded + RetrieveCacheKey(key)
+}
+
+// RetrieveCacheKey converts the key into a string: values implementing
+// VersionedKeyer or Keyer provide their own key, slices have their elements
+// converted and joined with "/", maps are converted as a list of key/value
+// pairs (sorted by key since Go maps are unordered) and other values use
+// their parameter representation.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Cache.html
+func RetrieveCacheKey(key interface{}) string {
+	switch k := key.(type) {
+	case VersionedKeyer:
+		return k.CacheKeyWithVersion()
+	case Keyer:
+		return k.CacheKey()
+	}
+
+	rv := reflect.ValueOf(key)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		parts := make([]string, rv.Len())
+		for i := range parts {
+			parts[i] = RetrieveCacheKey(rv.Index(i).Interface())
+		}
+		return strings.Join(parts, "/")
+	case reflect.Map:
+		parts := make([]string, 0, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			parts = append(parts, RetrieveCacheKey(iter.Key().Interface())+"/"+RetrieveCacheKey(iter.Value().Interface()))
+		}
+		sort.Strings(parts)
+		return strings.Join(parts, "/")
+	}
+	return toParam(key)
+}
+
+// NamespaceKey prefixes the key with the namespace of a cache store, like
+// the :namespace option of the Rails cache stores.
+//
+//	NamespaceKey("views/foo", "myapp") // => "myapp:views/foo"
+func NamespaceKey(key, namespace string) string {
+	if namespace == "" {
+		return key
+	}
+	return namespace + ":" + key
+}
+
+// toParam returns the representation of the value used in URLs and cache
+// keys.
+func toParam(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case []byte:
+		return string(val)
+	case time.Time:
+		if val.Location() == time.UTC {
+			return val.Format("2006-01-02 15:04:05 UTC")
+		}
+		return val.Format("2006-01-02 15:04:05 -0700")
+	case fmt.Stringer:
+		return val.String()
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/franela/goblin"
+)
+
+type customKey struct{}
+
+func (customKey) CacheKey() string { return "custom" }
+
+func ExampleExpandCacheKey() {
+	user := Record{ClassName: "User", ID: 1, UpdatedAt: time.Date(2024, 2, 14, 15, 4, 5, 123456000, time.UTC)}
+	fmt.Println(ExpandCacheKey([]interface{}{"views", user, 42}, ""))
+	fmt.Println(ExpandCacheKey("foo", "namespace"))
+	// Output: views/users/1-20240214150405123456/42
+	// namespace/foo
+}
+
+func TestExpandCacheKey(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("ExpandCacheKey", func() {
+		g.It("Should convert simple values", func() {
+			g.Assert(ExpandCacheKey("foo", "")).Equal("foo")
+			g.Assert(ExpandCacheKey(42, "")).Equal("42")
+			g.Assert(ExpandCacheKey(true, "")).Equal("true")
+			g.Assert(ExpandCacheKey(nil, "")).Equal("")
+			g.Assert(ExpandCacheKey(time.Date(2024, 2, 14, 15, 4, 5, 0, time.UTC), "")).Equal("2024-02-14 15:04:05 UTC")
+		})
+
+		g.It("Should convert nested collections", func() {
+			g.Assert(ExpandCacheKey([]interface{}{"foo", []interface{}{"bar", 1}, nil}, "")).Equal("foo/bar/1/")
+			g.Assert(ExpandCacheKey([]string{"a", "b"}, "")).Equal("a/b")
+			g.Assert(ExpandCacheKey(map[string]interface{}{"b": 2, "a": 1}, "")).Equal("a/1/b/2")
+		})
+
+		g.It("Should use the keys provided by the values", func() {
+			g.Assert(ExpandCacheKey(customKey{}, "")).Equal("custom")
+			g.Assert(ExpandCacheKey([]interface{}{customKey{}, Record{ClassName: "Post"}}, "")).Equal("custom/posts/new")
+		})
+
+		g.It("Should add the namespace and the environment prefix", func() {
+			g.Assert(ExpandCacheKey("foo", "ns")).Equal("ns/foo")
+			os.Setenv("RAILS_APP_VERSION", "v2")
+			defer os.Unsetenv("RAILS_APP_VERSION")
+			g.Assert(ExpandCacheKey("foo", "ns")).Equal("ns/v2/foo")
+			os.Setenv("RAILS_CACHE_ID", "build1")
+			defer os.Unsetenv("RAILS_CACHE_ID")
+			g.Assert(ExpandCacheKey("foo", "")).Equal("build1/foo")
+		})
+	})
+
+	g.Describe("NamespaceKey", func() {
+		g.It("Should prefix store keys", func() {
+			g.Assert(NamespaceKey("views/foo", "myapp")).Equal("myapp:views/foo")
+			g.Assert(NamespaceKey("views/foo", "")).Equal("views/foo")
+		})
+	})
+}
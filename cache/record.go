@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mattetti/goRailsYourself/datetime"
+	"github.com/mattetti/goRailsYourself/inflector"
+)
+
+// Record describes an ActiveRecord model instance and builds its cache
+// keys.
+//
+//	user := Record{ClassName: "User", ID: 1, UpdatedAt: updatedAt}
+//	user.CacheKey()            // => "users/1"
+//	user.CacheVersion()        // => "20240214150405123456"
+//	user.CacheKeyWithVersion() // => "users/1-20240214150405123456"
+type Record struct {
+	// ClassName is the name of the model class, namespaces included
+	// ("Admin::User").
+	ClassName string
+	// ID is the primary key of the record, nil for a new record.
+	ID interface{}
+	// UpdatedAt is the last update time of the record, the zero time if
+	// the record has no timestamp.
+	UpdatedAt time.Time
+	// DisableVersioning mirrors setting cache_versioning to false, the
+	// behavior before Rails 5.2: the version is then part of CacheKey.
+	DisableVersioning bool
+}
+
+// CacheKey returns the cache key of the record: its collection name
+// followed by its id, or "new" for new records. Without versioning, the
+// update time is appended.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveRecord/Integration.html#method-i-cache_key
+func (r Record) CacheKey() string {
+	collection := inflector.Pluralize(inflector.Underscore(r.ClassName))
+	if r.ID == nil {
+		return collection + "/new"
+	}
+	key := collection + "/" + fmt.Sprint(r.ID)
+	if r.DisableVersioning && !r.UpdatedAt.IsZero() {
+		key += "-" + r.timestamp()
+	}
+	return key
+}
+
+// CacheVersion returns the version of the record, its update time in the
+// :usec format, or an empty string when versioning is disabled or without
+// timestamp.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveRecord/Integration.html#method-i-cache_version
+func (r Record) CacheVersion() string {
+	if r.DisableVersioning || r.UpdatedAt.IsZero() {
+		return ""
+	}
+	return r.timestamp()
+}
+
+// CacheKeyWithVersion returns the cache key followed by the version.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveRecord/Integration.html#method-i-cache_key_with_version
+func (r Record) CacheKeyWithVersion() string {
+	if version := r.CacheVersion(); version != "" {
+		return r.CacheKey() + "-" + version
+	}
+	return r.CacheKey()
+}
+
+// timestamp formats the update time like to_fs(:usec) in UTC.
+func (r Record) timestamp() string {
+	return datetime.FormatNamed(r.UpdatedAt.UTC(), "usec")
+}
@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/franela/goblin"
+)
+
+func TestRecord(t *testing.T) {
+	g := Goblin(t)
+	paris := time.FixedZone("CET", 3600)
+	updatedAt := time.Date(2024, 2, 14, 16, 4, 5, 123456789, paris)
+
+	g.Describe("Record", func() {
+		g.It("Should build versioned keys", func() {
+			user := Record{ClassName: "User", ID: 1, UpdatedAt: updatedAt}
+			g.Assert(user.CacheKey()).Equal("users/1")
+			g.Assert(user.CacheVersion()).Equal("20240214150405123456")
+			g.Assert(user.CacheKeyWithVersion()).Equal("users/1-20240214150405123456")
+		})
+
+		g.It("Should build legacy keys", func() {
+			user := Record{ClassName: "User", ID: 1, UpdatedAt: updatedAt, DisableVersioning: true}
+			g.Assert(user.CacheKey()).Equal("users/1-20240214150405123456")
+			g.Assert(user.CacheVersion()).Equal("")
+			g.Assert(user.CacheKeyWithVersion()).Equal("users/1-20240214150405123456")
+		})
+
+		g.It("Should handle namespaces, new records and missing timestamps", func() {
+			g.Assert(Record{ClassName: "Admin::UserProfile", ID: "abc"}.CacheKey()).Equal("admin/user_profiles/abc")
+			g.Assert(Record{ClassName: "Person"}.CacheKeyWithVersion()).Equal("people/new")
+			g.Assert(Record{ClassName: "Person", ID: 3}.CacheKeyWithVersion()).Equal("people/3")
+		})
+	})
+}
@@ -0,0 +1,85 @@
+// The compress package ports ActiveSupport::Gzip and adds the Brotli
+// equivalent, with string in and out signatures, to read the payloads a
+// Rails app compressed before storing them (cache entries, cookie overflow
+// data...).
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Gzip.html
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Compress gzips the source using the default compression level.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Gzip.html#method-c-compress
+func Compress(source string) string {
+	compressed, _ := CompressLevel(source, gzip.DefaultCompression)
+	return compressed
+}
+
+// CompressLevel gzips the source using the passed compression level, from
+// gzip.HuffmanOnly to gzip.BestCompression.
+func CompressLevel(source string, level int) (string, error) {
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, level)
+	if err != nil {
+		return "", err
+	}
+	return finish(&buf, w, source)
+}
+
+// Decompress ungzips the source.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Gzip.html#method-c-decompress
+func Decompress(source string) (string, error) {
+	r, err := gzip.NewReader(bytes.NewBufferString(source))
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	return readAll(r)
+}
+
+// CompressBrotli compresses the source with Brotli using the default
+// compression level.
+func CompressBrotli(source string) string {
+	compressed, _ := CompressBrotliLevel(source, brotli.DefaultCompression)
+	return compressed
+}
+
+// CompressBrotliLevel compresses the source with Brotli using the passed
+// compression level, from brotli.BestSpeed (0) to brotli.BestCompression
+// (11).
+func CompressBrotliLevel(source string, level int) (string, error) {
+	var buf bytes.Buffer
+	return finish(&buf, brotli.NewWriterLevel(&buf, level), source)
+}
+
+// DecompressBrotli decompresses a Brotli compressed source.
+func DecompressBrotli(source string) (string, error) {
+	return readAll(brotli.NewReader(bytes.NewBufferString(source)))
+}
+
+func finish(buf *bytes.Buffer, w io.WriteCloser, source string) (string, error) {
+	if _, err := io.WriteString(w, source); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func readAll(r io.Reader) (string, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,74 @@
+package compress
+
+import (
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleCompress() {
+	compressed := Compress("Hello from Go")
+	decompressed, _ := Decompress(compressed)
+	fmt.Println(decompressed)
+	// Output: Hello from Go
+}
+
+func TestCompress(t *testing.T) {
+	g := Goblin(t)
+	source := strings.Repeat("Rails and Go sharing a cache. ", 100)
+
+	g.Describe("Gzip", func() {
+		g.It("Should round trip", func() {
+			compressed := Compress(source)
+			g.Assert(len(compressed) < len(source)).IsTrue()
+			decompressed, err := Decompress(compressed)
+			g.Assert(err).Equal(nil)
+			g.Assert(decompressed).Equal(source)
+		})
+
+		g.It("Should support compression levels", func() {
+			compressed, err := CompressLevel(source, gzip.BestCompression)
+			g.Assert(err).Equal(nil)
+			decompressed, _ := Decompress(compressed)
+			g.Assert(decompressed).Equal(source)
+			_, err = CompressLevel(source, 42)
+			g.Assert(err != nil).IsTrue()
+		})
+
+		g.It("Should decompress data gzipped elsewhere", func() {
+			data, _ := base64.StdEncoding.DecodeString("H4sIAADxU2UC//NIzcnJV0grys9VCErMzCkGAKgl9U4QAAAA")
+			decompressed, err := Decompress(string(data))
+			g.Assert(err).Equal(nil)
+			g.Assert(decompressed).Equal("Hello from Rails")
+		})
+
+		g.It("Should fail on invalid data", func() {
+			_, err := Decompress("not gzipped")
+			g.Assert(err != nil).IsTrue()
+		})
+	})
+
+	g.Describe("Brotli", func() {
+		g.It("Should round trip", func() {
+			compressed := CompressBrotli(source)
+			g.Assert(len(compressed) < len(source)).IsTrue()
+			decompressed, err := DecompressBrotli(compressed)
+			g.Assert(err).Equal(nil)
+			g.Assert(decompressed).Equal(source)
+
+			compressed, err = CompressBrotliLevel("", 11)
+			g.Assert(err).Equal(nil)
+			decompressed, _ = DecompressBrotli(compressed)
+			g.Assert(decompressed).Equal("")
+		})
+
+		g.It("Should fail on invalid data", func() {
+			_, err := DecompressBrotli("not brotli compressed data")
+			g.Assert(err != nil).IsTrue()
+		})
+	})
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/mattetti/goRailsYourself/crypto"
+	"gopkg.in/yaml.v3"
+)
+
+// MasterKeyEnv is the environment variable holding the key of the
+// credentials, which takes precedence over the key files.
+const MasterKeyEnv = "RAILS_MASTER_KEY"
+
+// Credentials are the encrypted credentials of a Rails app, a YAML
+// document encrypted in an EncryptedFile.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/EncryptedConfiguration.html
+type Credentials struct {
+	crypto.EncryptedFile
+}
+
+// LoadCredentials returns the credentials a Rails app rooted at root uses
+// in the environment, or the current one if env is empty:
+// config/credentials/<env>.yml.enc and config/credentials/<env>.key if the
+// former exists, the global config/credentials.yml.enc and
+// config/master.key otherwise, like Rails.
+//
+//	creds, err := config.LoadCredentials(".", "production").Config()
+//	creds.Dig("aws", "access_key_id")
+func LoadCredentials(root, env string) *Credentials {
+	if env == "" {
+		env = Env().String()
+	}
+	// only the content file tells which credentials are used, a lone
+	// environment key doesn't switch them
+	if envCreds := EnvironmentCredentials(root, env); fileExists(envCreds.ContentPath) {
+		return envCreds
+	}
+	return &Credentials{crypto.EncryptedFile{
+		ContentPath: filepath.Join(root, "config", "credentials.yml.enc"),
+		KeyPath:     filepath.Join(root, "config", "master.key"),
+		EnvKey:      MasterKeyEnv,
+	}}
+}
+
+// EnvironmentCredentials returns the credentials of an environment,
+// config/credentials/<env>.yml.enc and config/credentials/<env>.key, like
+// bin/rails credentials:edit --environment does, whether they exist or not.
+func EnvironmentCredentials(root, env string) *Credentials {
+	dir := filepath.Join(root, "config", "credentials")
+	return &Credentials{crypto.EncryptedFile{
+		ContentPath: filepath.Join(dir, env+".yml.enc"),
+		KeyPath:     filepath.Join(dir, env+".key"),
+		EnvKey:      MasterKeyEnv,
+	}}
+}
+
+// Config decrypts and parses the credentials. Like in Rails, their ERB
+// tags aren't evaluated.
+func (c *Credentials) Config() (*OrderedOptions, error) {
+	content, err := c.Read()
+	if err != nil {
+		return nil, err
+	}
+	opts := NewOrderedOptions()
+	if err := yaml.Unmarshal([]byte(content), opts); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// Write encrypts content into the credentials file after checking it is
+// valid YAML, so the credentials can't be corrupted.
+func (c *Credentials) Write(content string) error {
+	if err := yaml.Unmarshal([]byte(content), NewOrderedOptions()); err != nil {
+		return err
+	}
+	return c.EncryptedFile.Write(content)
+}
+
+// Change decrypts the credentials, passes their content to edit and
+// encrypts the result back, like bin/rails credentials:edit. The file is
+// created if it doesn't exist yet.
+//
+//	err := creds.Change(func(content string) (string, error) {
+//		return strings.Replace(content, oldToken, newToken, 1), nil
+//	})
+func (c *Credentials) Change(edit func(content string) (string, error)) error {
+	content, err := c.Read()
+	if err != nil && err != crypto.ErrMissingContent {
+		return err
+	}
+	if content, err = edit(content); err != nil {
+		return err
+	}
+	return c.Write(content)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
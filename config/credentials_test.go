@@ -0,0 +1,119 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mattetti/goRailsYourself/crypto"
+
+	. "github.com/franela/goblin"
+)
+
+const credentialsKey = "4d5a1f6c0b2e8e9a7c3d1f2b4a6c8e0d"
+
+// writeCredentials writes the credentials of an app rooted at root.
+func writeCredentials(root, content string, env string) {
+	creds := LoadCredentials(root, "test")
+	if env != "" {
+		creds = EnvironmentCredentials(root, env)
+	}
+	os.MkdirAll(filepath.Dir(creds.KeyPath), 0700)
+	os.WriteFile(creds.KeyPath, []byte(credentialsKey), 0600)
+	if err := creds.Write(content); err != nil {
+		panic(err)
+	}
+}
+
+func TestCredentials(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Credentials", func() {
+		var root string
+		g.BeforeEach(func() {
+			root, _ = os.MkdirTemp("", "credentials")
+			os.MkdirAll(filepath.Join(root, "config"), 0700)
+			writeCredentials(root, "secret_key_base: global\n", "")
+		})
+		g.AfterEach(func() {
+			os.RemoveAll(root)
+		})
+
+		g.It("loads the global credentials", func() {
+			creds, err := LoadCredentials(root, "production").Config()
+			g.Assert(err).Eql(nil)
+			g.Assert(creds.Get("secret_key_base")).Eql("global")
+		})
+
+		g.It("loads the credentials of the environment if they exist", func() {
+			writeCredentials(root, "secret_key_base: production\n", "production")
+			creds := LoadCredentials(root, "production")
+			g.Assert(creds.ContentPath).Eql(filepath.Join(root, "config", "credentials", "production.yml.enc"))
+			g.Assert(creds.KeyPath).Eql(filepath.Join(root, "config", "credentials", "production.key"))
+			config, err := creds.Config()
+			g.Assert(err).Eql(nil)
+			g.Assert(config.Get("secret_key_base")).Eql("production")
+
+			config, _ = LoadCredentials(root, "staging").Config()
+			g.Assert(config.Get("secret_key_base")).Eql("global")
+		})
+
+		g.It("ignores the key of the environment without its credentials", func() {
+			key := EnvironmentCredentials(root, "production").KeyPath
+			os.MkdirAll(filepath.Dir(key), 0700)
+			os.WriteFile(key, []byte(crypto.GenerateEncryptedFileKey()), 0600)
+			creds := LoadCredentials(root, "production")
+			g.Assert(creds.ContentPath).Eql(filepath.Join(root, "config", "credentials.yml.enc"))
+			g.Assert(creds.KeyPath).Eql(filepath.Join(root, "config", "master.key"))
+			config, err := creds.Config()
+			g.Assert(err).Eql(nil)
+			g.Assert(config.Get("secret_key_base")).Eql("global")
+		})
+
+		g.It("changes the credentials", func() {
+			creds := LoadCredentials(root, "production")
+			err := creds.Change(func(content string) (string, error) {
+				return content + "aws:\n  access_key_id: AKIA\n", nil
+			})
+			g.Assert(err).Eql(nil)
+			config, _ := creds.Config()
+			g.Assert(config.Dig("aws", "access_key_id")).Eql("AKIA")
+			g.Assert(config.Get("secret_key_base")).Eql("global")
+		})
+
+		g.It("creates the credentials of new environments", func() {
+			creds := EnvironmentCredentials(root, "staging")
+			os.MkdirAll(filepath.Dir(creds.KeyPath), 0700)
+			os.WriteFile(creds.KeyPath, []byte(crypto.GenerateEncryptedFileKey()), 0600)
+			err := creds.Change(func(content string) (string, error) {
+				g.Assert(content).Eql("")
+				return "secret_key_base: staging\n", nil
+			})
+			g.Assert(err).Eql(nil)
+			config, _ := LoadCredentials(root, "staging").Config()
+			g.Assert(config.Get("secret_key_base")).Eql("staging")
+		})
+
+		g.It("refuses invalid content", func() {
+			creds := LoadCredentials(root, "production")
+			g.Assert(creds.Write("foo: [bar") != nil).IsTrue()
+			editErr := errors.New("aborted")
+			g.Assert(creds.Change(func(string) (string, error) { return "", editErr })).Eql(editErr)
+			config, _ := creds.Config()
+			g.Assert(config.Get("secret_key_base")).Eql("global")
+		})
+	})
+}
+
+func ExampleLoadCredentials() {
+	root, _ := os.MkdirTemp("", "credentials")
+	defer os.RemoveAll(root)
+	os.MkdirAll(filepath.Join(root, "config"), 0700)
+	writeCredentials(root, "aws:\n  access_key_id: AKIA\n", "")
+
+	creds, err := LoadCredentials(root, "production").Config()
+	fmt.Println(creds.Dig("aws", "access_key_id"), err)
+	// Output: AKIA <nil>
+}
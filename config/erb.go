@@ -0,0 +1,268 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// EvalERB evaluates the ERB tags commonly found in Rails YAML files,
+// reading the environment variables with os.LookupEnv. The supported
+// expressions are:
+//
+//	<%= ENV["DATABASE_HOST"] %>
+//	<%= ENV["DATABASE_HOST"] || "localhost" %>
+//	<%= ENV.fetch("RAILS_MAX_THREADS") %>
+//	<%= ENV.fetch("RAILS_MAX_THREADS", 5) %>
+//	<%= ENV.fetch("RAILS_MAX_THREADS") { 5 } %>
+//	<%= ENV.fetch("PORT") { 3000 }.to_i %>
+//	<%# comments %>
+//
+// as well as string, integer, boolean and nil literals. Fetching a missing
+// variable without a default and any other Ruby code return an error.
+//
+// Ruby documentation: https://ruby-doc.org/stdlib/libdoc/erb/rdoc/ERB.html
+func EvalERB(src string) (string, error) {
+	var out strings.Builder
+	for {
+		start := strings.Index(src, "<%")
+		if start < 0 {
+			out.WriteString(src)
+			return out.String(), nil
+		}
+		out.WriteString(src[:start])
+		src = src[start+2:]
+		if strings.HasPrefix(src, "%") {
+			// <%% is a literal <%
+			out.WriteString("<%")
+			src = src[1:]
+			continue
+		}
+
+		end := strings.Index(src, "%>")
+		if end < 0 {
+			return "", fmt.Errorf("config: unterminated ERB tag")
+		}
+		tag := src[:end]
+		src = src[end+2:]
+		if strings.HasSuffix(tag, "-") {
+			// -%> trims the following new line
+			tag = tag[:len(tag)-1]
+			src = strings.TrimPrefix(src, "\n")
+		}
+		tag = strings.TrimPrefix(tag, "-")
+
+		switch {
+		case strings.HasPrefix(tag, "#"):
+		case strings.HasPrefix(tag, "="):
+			v, err := evalExpr(tag[1:])
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(rubyString(v))
+		default:
+			if strings.TrimSpace(tag) != "" {
+				return "", fmt.Errorf("config: unsupported ERB tag: <%%%s%%>", tag)
+			}
+		}
+	}
+}
+
+// evalExpr evaluates an ERB expression.
+func evalExpr(expr string) (interface{}, error) {
+	p := &exprParser{src: expr}
+	v, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpaces(); p.pos < len(p.src) {
+		return nil, p.unsupported()
+	}
+	return v, nil
+}
+
+// exprParser parses the small subset of Ruby supported by EvalERB.
+type exprParser struct {
+	src string
+	pos int
+}
+
+// or parses expr || expr.
+func (p *exprParser) or() (interface{}, error) {
+	v, err := p.call()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("||") {
+		right, err := p.call()
+		if err != nil {
+			return nil, err
+		}
+		// like in Ruby, only nil and false are falsy
+		if v == nil || v == false {
+			v = right
+		}
+	}
+	return v, nil
+}
+
+// call parses a value followed by conversion calls.
+func (p *exprParser) call() (interface{}, error) {
+	v, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.consume(".to_i"):
+			v = rubyToI(v)
+		case p.consume(".to_s"):
+			v = rubyString(v)
+		default:
+			return v, nil
+		}
+	}
+}
+
+// primary parses ENV lookups and literals.
+func (p *exprParser) primary() (interface{}, error) {
+	switch {
+	case p.consume("ENV.fetch"):
+		if !p.consume("(") {
+			return nil, p.unsupported()
+		}
+		name, err := p.stringLiteral()
+		if err != nil {
+			return nil, err
+		}
+		var def interface{}
+		hasDefault := p.consume(",")
+		if hasDefault {
+			if def, err = p.or(); err != nil {
+				return nil, err
+			}
+		}
+		if !p.consume(")") {
+			return nil, p.unsupported()
+		}
+		if p.consume("{") {
+			if def, err = p.or(); err != nil {
+				return nil, err
+			}
+			if !p.consume("}") {
+				return nil, p.unsupported()
+			}
+			hasDefault = true
+		}
+		if v, ok := os.LookupEnv(name); ok {
+			return v, nil
+		}
+		if !hasDefault {
+			return nil, fmt.Errorf("config: key not found: %q", name)
+		}
+		return def, nil
+	case p.consume("ENV["):
+		name, err := p.stringLiteral()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume("]") {
+			return nil, p.unsupported()
+		}
+		if v, ok := os.LookupEnv(name); ok {
+			return v, nil
+		}
+		return nil, nil
+	case p.consume("nil"):
+		return nil, nil
+	case p.consume("true"):
+		return true, nil
+	case p.consume("false"):
+		return false, nil
+	}
+
+	p.skipSpaces()
+	if p.pos < len(p.src) && (p.src[p.pos] == '"' || p.src[p.pos] == '\'') {
+		return p.stringLiteral()
+	}
+	start := p.pos
+	if p.pos < len(p.src) && p.src[p.pos] == '-' {
+		p.pos++
+	}
+	for p.pos < len(p.src) && (unicode.IsDigit(rune(p.src[p.pos])) || p.src[p.pos] == '_') {
+		p.pos++
+	}
+	n, err := strconv.ParseInt(strings.Replace(p.src[start:p.pos], "_", "", -1), 10, 64)
+	if err != nil {
+		p.pos = start
+		return nil, p.unsupported()
+	}
+	return n, nil
+}
+
+// stringLiteral parses a single or double quoted string.
+func (p *exprParser) stringLiteral() (string, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.src) || (p.src[p.pos] != '"' && p.src[p.pos] != '\'') {
+		return "", p.unsupported()
+	}
+	quote := p.src[p.pos]
+	end := strings.IndexByte(p.src[p.pos+1:], quote)
+	if end < 0 {
+		return "", p.unsupported()
+	}
+	s := p.src[p.pos+1 : p.pos+1+end]
+	p.pos += end + 2
+	return s, nil
+}
+
+// consume skips the spaces and the token if it's next.
+func (p *exprParser) consume(token string) bool {
+	p.skipSpaces()
+	if strings.HasPrefix(p.src[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) skipSpaces() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *exprParser) unsupported() error {
+	return fmt.Errorf("config: unsupported ERB expression: %s", strings.TrimSpace(p.src))
+}
+
+// rubyString converts a value like Ruby's to_s.
+func rubyString(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// rubyToI converts a value like Ruby's to_i, parsing the leading integer of
+// strings.
+func rubyToI(v interface{}) interface{} {
+	switch val := v.(type) {
+	case int64:
+		return val
+	case string:
+		s := strings.TrimLeftFunc(val, unicode.IsSpace)
+		end := 0
+		if end < len(s) && (s[end] == '-' || s[end] == '+') {
+			end++
+		}
+		for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+			end++
+		}
+		n, _ := strconv.ParseInt(s[:end], 10, 64)
+		return n
+	}
+	return int64(0)
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleEvalERB() {
+	os.Setenv("EXAMPLE_DB_HOST", "db.example.com")
+	out, _ := EvalERB(`host: <%= ENV["EXAMPLE_DB_HOST"] %>
+pool: <%= ENV.fetch("EXAMPLE_MAX_THREADS") { 5 } %>`)
+	fmt.Println(out)
+	// Output: host: db.example.com
+	// pool: 5
+}
+
+func TestEvalERB(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("EvalERB", func() {
+		t.Setenv("ERB_SET", "value")
+		t.Setenv("ERB_EMPTY", "")
+		t.Setenv("ERB_PORT", "3001")
+		eval := func(src string) string {
+			out, err := EvalERB(src)
+			g.Assert(err == nil).IsTrue()
+			return out
+		}
+
+		g.It("Should keep the text outside of the tags", func() {
+			g.Assert(eval("a: 1\nb: 2\n")).Equal("a: 1\nb: 2\n")
+			g.Assert(eval("a: <%% not a tag %>")).Equal("a: <% not a tag %>")
+			g.Assert(eval("<%# a comment %>a: 1")).Equal("a: 1")
+			g.Assert(eval("<% %>a: 1")).Equal("a: 1")
+		})
+
+		g.It("Should read the environment variables", func() {
+			g.Assert(eval(`<%= ENV["ERB_SET"] %>`)).Equal("value")
+			g.Assert(eval(`<%= ENV['ERB_MISSING'] %>`)).Equal("")
+			g.Assert(eval(`<%= ENV["ERB_MISSING"] || "default" %>`)).Equal("default")
+			g.Assert(eval(`<%= ENV["ERB_EMPTY"] || "default" %>`)).Equal("")
+		})
+
+		g.It("Should fetch the environment variables", func() {
+			g.Assert(eval(`<%= ENV.fetch("ERB_SET") %>`)).Equal("value")
+			g.Assert(eval(`<%= ENV.fetch("ERB_MISSING", "default") %>`)).Equal("default")
+			g.Assert(eval(`<%= ENV.fetch("ERB_MISSING") { 5 } %>`)).Equal("5")
+			g.Assert(eval(`<%= ENV.fetch("ERB_PORT") { 3000 }.to_i %>`)).Equal("3001")
+			g.Assert(eval(`<%= ENV.fetch("ERB_MISSING", nil) %>`)).Equal("")
+			_, err := EvalERB(`<%= ENV.fetch("ERB_MISSING") %>`)
+			g.Assert(err.Error()).Equal(`config: key not found: "ERB_MISSING"`)
+		})
+
+		g.It("Should trim the new lines", func() {
+			g.Assert(eval("a: <%= 1 -%>\nb: 2")).Equal("a: 1b: 2")
+		})
+
+		g.It("Should refuse the unsupported code", func() {
+			_, err := EvalERB(`<%= Rails.root.join("tmp") %>`)
+			g.Assert(err.Error()).Equal(`config: unsupported ERB expression: Rails.root.join("tmp")`)
+			_, err = EvalERB(`<% if true %>a<% end %>`)
+			g.Assert(err != nil).IsTrue()
+			_, err = EvalERB(`<%= 1`)
+			g.Assert(err != nil).IsTrue()
+		})
+	})
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/mattetti/goRailsYourself/inquirer"
+	"gopkg.in/yaml.v3"
+)
+
+// Env returns the current Rails environment, read from RAILS_ENV or
+// RACK_ENV and defaulting to "development".
+//
+//	config.Env().Production() // => true if RAILS_ENV=production
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Rails.html#method-c-env
+func Env() inquirer.EnvInquirer {
+	for _, name := range []string{"RAILS_ENV", "RACK_ENV"} {
+		if env := os.Getenv(name); env != "" {
+			return inquirer.EnvInquiry(env)
+		}
+	}
+	return inquirer.EnvInquiry("development")
+}
+
+// Parse evaluates the ERB tags of a YAML document (see EvalERB) and
+// decodes it, keeping the order of the keys.
+func Parse(data []byte) (*OrderedOptions, error) {
+	src, err := EvalERB(string(data))
+	if err != nil {
+		return nil, err
+	}
+	opts := NewOrderedOptions()
+	if err := yaml.Unmarshal([]byte(src), opts); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// ParseFile reads and parses a YAML file, evaluating its ERB tags.
+func ParseFile(path string) (*OrderedOptions, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// ConfigFor loads the section of the YAML file matching the environment,
+// or the current one if env is empty. The options of the "shared" section
+// are deep merged under the ones of the environment.
+//
+//	# config/payment.yml
+//	shared:
+//	  currency: usd
+//	production:
+//	  api_key: <%= ENV["PAYMENT_API_KEY"] %>
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Rails/Application.html#method-i-config_for
+func ConfigFor(path, env string) (*OrderedOptions, error) {
+	all, err := ParseFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return EnvSection(all, env), nil
+}
+
+// EnvSection returns the options of the environment section, or the current
+// one if env is empty, deep merged over the "shared" section. Empty options
+// are returned if neither section exists.
+func EnvSection(all *OrderedOptions, env string) *OrderedOptions {
+	if env == "" {
+		env = Env().String()
+	}
+	section := all.Options(env)
+	shared := all.Options("shared")
+	switch {
+	case shared == nil && section == nil:
+		return NewOrderedOptions()
+	case shared == nil:
+		return section
+	case section == nil:
+		return shared
+	}
+	return deepMerge(shared, section)
+}
+
+// Decode decodes the options into v, using the yaml tags of its fields.
+//
+//	var settings struct {
+//		Currency string `yaml:"currency"`
+//	}
+//	err := opts.Decode(&settings)
+func (o *OrderedOptions) Decode(v interface{}) error {
+	data, err := yaml.Marshal(o.ToMap())
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, v)
+}
+
+// deepMerge returns new options holding the ones of base and the ones of
+// other, merging the nested options.
+func deepMerge(base, other *OrderedOptions) *OrderedOptions {
+	merged := NewOrderedOptions()
+	for _, k := range base.keys {
+		merged.Set(k, base.values[k])
+	}
+	for _, k := range other.keys {
+		v := other.values[k]
+		if nested, ok := v.(*OrderedOptions); ok {
+			if current, ok := merged.values[k].(*OrderedOptions); ok {
+				v = deepMerge(current, nested)
+			}
+		}
+		merged.Set(k, v)
+	}
+	return merged
+}
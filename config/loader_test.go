@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleEnvSection() {
+	all, _ := Parse([]byte(`
+shared:
+  currency: usd
+  api:
+    timeout: 5
+production:
+  api:
+    key: secret
+`))
+	config := EnvSection(all, "production")
+	fmt.Println(config.Get("currency"), config.Dig("api", "timeout"), config.Dig("api", "key"))
+	// Output: usd 5 secret
+}
+
+func TestLoader(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("Env", func() {
+		g.It("Should read RAILS_ENV and RACK_ENV", func() {
+			t.Setenv("RAILS_ENV", "")
+			t.Setenv("RACK_ENV", "")
+			g.Assert(Env().Development()).IsTrue()
+			t.Setenv("RACK_ENV", "staging")
+			g.Assert(Env().Is("staging")).IsTrue()
+			t.Setenv("RAILS_ENV", "production")
+			g.Assert(Env().Production()).IsTrue()
+		})
+	})
+
+	g.Describe("ConfigFor", func() {
+		g.It("Should load the section of the environment", func() {
+			t.Setenv("LOADER_KEY", "from_env")
+			path := filepath.Join(t.TempDir(), "payment.yml")
+			ioutil.WriteFile(path, []byte(`
+shared:
+  currency: usd
+development:
+  key: dev
+production:
+  key: <%= ENV["LOADER_KEY"] %>
+  currency: eur
+`), 0644)
+			config, err := ConfigFor(path, "production")
+			g.Assert(err == nil).IsTrue()
+			g.Assert(config.Keys()).Equal([]string{"currency", "key"})
+			g.Assert(config.Get("key")).Equal("from_env")
+			g.Assert(config.Get("currency")).Equal("eur")
+
+			t.Setenv("RAILS_ENV", "development")
+			config, _ = ConfigFor(path, "")
+			g.Assert(config.Get("key")).Equal("dev")
+			g.Assert(config.Get("currency")).Equal("usd")
+
+			config, _ = ConfigFor(path, "test")
+			g.Assert(config.ToMap()).Equal(map[string]interface{}{"currency": "usd"})
+		})
+
+		g.It("Should report the errors", func() {
+			_, err := ConfigFor(filepath.Join(t.TempDir(), "missing.yml"), "test")
+			g.Assert(err != nil).IsTrue()
+			_, err = Parse([]byte("a: <%= Rails.env %>"))
+			g.Assert(err != nil).IsTrue()
+		})
+	})
+
+	g.Describe("EnvSection", func() {
+		g.It("Should return empty options without section", func() {
+			all, _ := Parse([]byte("production:\n  a: 1\n"))
+			g.Assert(EnvSection(all, "test").Len()).Equal(0)
+		})
+	})
+
+	g.Describe("Decode", func() {
+		g.It("Should decode the options into a struct", func() {
+			var settings struct {
+				Currency string `yaml:"currency"`
+				Limits   []int  `yaml:"limits"`
+				Nested   struct{ A bool }
+			}
+			opts, _ := Parse([]byte("currency: usd\nlimits: [1, 2]\nnested:\n  a: true\n"))
+			g.Assert(opts.Decode(&settings) == nil).IsTrue()
+			g.Assert(settings.Currency).Equal("usd")
+			g.Assert(settings.Limits).Equal([]int{1, 2})
+			g.Assert(settings.Nested.A).IsTrue()
+		})
+	})
+}
@@ -0,0 +1,321 @@
+// The config package ports the helpers Rails uses to expose its
+// configuration, so settings loaded from YAML files or credentials can be
+// navigated like in a Rails application.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/OrderedOptions.html
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mattetti/goRailsYourself/blank"
+	"github.com/mattetti/goRailsYourself/hashes"
+	"gopkg.in/yaml.v3"
+)
+
+// KeyError is returned when a required option is missing or blank.
+type KeyError struct {
+	Key string
+}
+
+func (e *KeyError) Error() string {
+	return ":" + e.Key + " is blank"
+}
+
+// OrderedOptions is a set of options remembering the order in which its
+// keys were added. Keys are case insensitive and symbol-style keys
+// (":name") are the same as string keys. Nested maps are converted to
+// OrderedOptions when they are added so nested settings can be reached
+// with Dig.
+//
+//	opts := NewOrderedOptions()
+//	opts.Set("Boy", "John")
+//	opts.Get("boy")      // => "John"
+//	opts.Require("girl") // => nil, ":girl is blank"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/OrderedOptions.html
+type OrderedOptions struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// NewOrderedOptions returns empty options.
+func NewOrderedOptions() *OrderedOptions {
+	return &OrderedOptions{values: map[string]interface{}{}}
+}
+
+// ToOrderedOptions converts a map[string]interface{}, a
+// map[interface{}]interface{} or a hashes.IndifferentMap into
+// OrderedOptions, converting the nested maps, including the ones found in
+// slices. Since Go maps aren't ordered, the keys are added in alphabetical
+// order. Any other value returns empty options.
+func ToOrderedOptions(v interface{}) *OrderedOptions {
+	if opts, ok := convertValue(v).(*OrderedOptions); ok {
+		return opts
+	}
+	return NewOrderedOptions()
+}
+
+// Get returns the value of the option or nil.
+func (o *OrderedOptions) Get(key string) interface{} {
+	return o.values[normalizeKey(key)]
+}
+
+// Fetch returns the value of the option and whether it was set.
+func (o *OrderedOptions) Fetch(key string) (interface{}, bool) {
+	v, ok := o.values[normalizeKey(key)]
+	return v, ok
+}
+
+// Require returns the value of the option or a *KeyError if the option is
+// blank. It's the equivalent of calling the bang version of an option in
+// Rails:
+//
+//	config.secret_key! # => KeyError: :secret_key is blank
+func (o *OrderedOptions) Require(key string) (interface{}, error) {
+	v := o.Get(key)
+	if blank.IsBlank(v) {
+		return nil, &KeyError{Key: normalizeKey(key)}
+	}
+	return v, nil
+}
+
+// Set sets the value of the option, converting nested maps. New keys are
+// added after the existing ones.
+func (o *OrderedOptions) Set(key string, value interface{}) {
+	if o.values == nil {
+		o.values = map[string]interface{}{}
+	}
+	key = normalizeKey(key)
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = convertValue(value)
+}
+
+// Delete removes the option and returns its value.
+func (o *OrderedOptions) Delete(key string) interface{} {
+	key = normalizeKey(key)
+	v, ok := o.values[key]
+	if !ok {
+		return nil
+	}
+	delete(o.values, key)
+	for i, k := range o.keys {
+		if k == key {
+			o.keys = append(o.keys[:i], o.keys[i+1:]...)
+			break
+		}
+	}
+	return v
+}
+
+// HasKey reports whether the option is set.
+func (o *OrderedOptions) HasKey(key string) bool {
+	_, ok := o.values[normalizeKey(key)]
+	return ok
+}
+
+// Keys returns the normalized keys in the order they were added.
+func (o *OrderedOptions) Keys() []string {
+	return append([]string(nil), o.keys...)
+}
+
+// Len returns the number of options.
+func (o *OrderedOptions) Len() int {
+	return len(o.keys)
+}
+
+// IsBlank reports whether no options are set.
+func (o *OrderedOptions) IsBlank() bool {
+	return o.Len() == 0
+}
+
+// Dig returns the nested option following the passed keys, or nil as soon
+// as a step can't be followed.
+//
+//	opts.Dig("database", "host") // => "localhost"
+func (o *OrderedOptions) Dig(keys ...string) interface{} {
+	var current interface{} = o
+	for _, key := range keys {
+		opts, ok := current.(*OrderedOptions)
+		if !ok {
+			return nil
+		}
+		current = opts.Get(key)
+	}
+	return current
+}
+
+// DigRequire is like Dig but returns a *KeyError naming the full path of
+// the option if it is blank.
+func (o *OrderedOptions) DigRequire(keys ...string) (interface{}, error) {
+	v := o.Dig(keys...)
+	if blank.IsBlank(v) {
+		path := make([]string, len(keys))
+		for i, key := range keys {
+			path[i] = normalizeKey(key)
+		}
+		return nil, &KeyError{Key: strings.Join(path, ".")}
+	}
+	return v, nil
+}
+
+// Options returns the nested options set for the key or nil.
+func (o *OrderedOptions) Options(key string) *OrderedOptions {
+	opts, _ := o.Get(key).(*OrderedOptions)
+	return opts
+}
+
+// String returns the string value of the option, or an empty string if the
+// option isn't a string.
+func (o *OrderedOptions) String(key string) string {
+	s, _ := o.Get(key).(string)
+	return s
+}
+
+// ToMap converts the options and the nested ones to plain
+// map[string]interface{} values.
+func (o *OrderedOptions) ToMap() map[string]interface{} {
+	return plainValue(o).(map[string]interface{})
+}
+
+// UnmarshalYAML decodes a YAML mapping, keeping the order of its keys.
+func (o *OrderedOptions) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("config: can't decode a %s into OrderedOptions", node.Tag)
+	}
+	*o = OrderedOptions{values: map[string]interface{}{}}
+	explicit := map[string]bool{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		value, err := decodeNode(node.Content[i+1])
+		if err != nil {
+			return err
+		}
+		if key.Tag == "!!merge" {
+			// <<: *default merges the keys which aren't set explicitly
+			if err := o.mergeYAML(value, explicit); err != nil {
+				return err
+			}
+			continue
+		}
+		o.Set(key.Value, value)
+		explicit[normalizeKey(key.Value)] = true
+	}
+	return nil
+}
+
+// mergeYAML merges the mappings referenced by a YAML merge key.
+func (o *OrderedOptions) mergeYAML(value interface{}, explicit map[string]bool) error {
+	switch v := value.(type) {
+	case *OrderedOptions:
+		for _, k := range v.keys {
+			if !explicit[k] && !o.HasKey(k) {
+				o.Set(k, v.values[k])
+			}
+		}
+		return nil
+	case []interface{}:
+		for _, m := range v {
+			if err := o.mergeYAML(m, explicit); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("config: can't merge a %T", value)
+}
+
+// decodeNode decodes a YAML node, decoding mappings as OrderedOptions.
+func decodeNode(node *yaml.Node) (interface{}, error) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		opts := NewOrderedOptions()
+		if err := opts.UnmarshalYAML(node); err != nil {
+			return nil, err
+		}
+		return opts, nil
+	case yaml.SequenceNode:
+		s := make([]interface{}, len(node.Content))
+		for i, n := range node.Content {
+			v, err := decodeNode(n)
+			if err != nil {
+				return nil, err
+			}
+			s[i] = v
+		}
+		return s, nil
+	}
+	var v interface{}
+	err := node.Decode(&v)
+	return v, err
+}
+
+// normalizeKey turns symbol-style and mixed case keys into lower case
+// strings.
+func normalizeKey(key string) string {
+	if len(key) > 1 && key[0] == ':' {
+		key = key[1:]
+	}
+	return strings.ToLower(key)
+}
+
+// convertValue converts the maps found in v to OrderedOptions.
+func convertValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		opts := NewOrderedOptions()
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			opts.Set(k, val[k])
+		}
+		return opts
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, v := range val {
+			m[fmt.Sprint(k)] = v
+		}
+		return convertValue(m)
+	case hashes.IndifferentMap:
+		return convertValue(map[string]interface{}(val))
+	case []interface{}:
+		s := make([]interface{}, len(val))
+		for i, v := range val {
+			s[i] = convertValue(v)
+		}
+		return s
+	}
+	return v
+}
+
+// plainValue converts the OrderedOptions found in v to plain maps.
+func plainValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case *OrderedOptions:
+		m := make(map[string]interface{}, val.Len())
+		for _, k := range val.keys {
+			m[k] = plainValue(val.values[k])
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(val))
+		for i, v := range val {
+			s[i] = plainValue(v)
+		}
+		return s
+	}
+	return v
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+	"gopkg.in/yaml.v3"
+)
+
+func ExampleOrderedOptions() {
+	opts := NewOrderedOptions()
+	opts.Set("Boy", "John")
+	opts.Set(":girl", "Mary")
+	fmt.Println(opts.Get("boy"), opts.Keys())
+	_, err := opts.Require("dog")
+	fmt.Println(err)
+	// Output: John [boy girl]
+	// :dog is blank
+}
+
+func TestOrderedOptions(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("OrderedOptions", func() {
+		g.It("Should normalize the keys", func() {
+			opts := NewOrderedOptions()
+			opts.Set("Host", "localhost")
+			g.Assert(opts.Get("host")).Equal("localhost")
+			g.Assert(opts.Get(":HOST")).Equal("localhost")
+			g.Assert(opts.HasKey("host")).IsTrue()
+			g.Assert(opts.Get("port") == nil).IsTrue()
+		})
+
+		g.It("Should keep the insertion order", func() {
+			opts := NewOrderedOptions()
+			opts.Set("b", 1)
+			opts.Set("a", 2)
+			opts.Set("c", 3)
+			opts.Set("B", 4)
+			g.Assert(opts.Keys()).Equal([]string{"b", "a", "c"})
+			g.Assert(opts.Delete("a")).Equal(2)
+			g.Assert(opts.Keys()).Equal([]string{"b", "c"})
+			g.Assert(opts.Len()).Equal(2)
+		})
+
+		g.It("Should require present values", func() {
+			opts := NewOrderedOptions()
+			opts.Set("name", "app")
+			opts.Set("empty", " ")
+			v, err := opts.Require("NAME")
+			g.Assert(err == nil).IsTrue()
+			g.Assert(v).Equal("app")
+			_, err = opts.Require("empty")
+			g.Assert(err.Error()).Equal(":empty is blank")
+			_, err = opts.Require("missing")
+			_, ok := err.(*KeyError)
+			g.Assert(ok).IsTrue()
+		})
+
+		g.It("Should dig the nested options", func() {
+			opts := ToOrderedOptions(map[string]interface{}{
+				"database": map[interface{}]interface{}{"host": "localhost", "port": 5432},
+				"hosts":    []interface{}{map[string]interface{}{"name": "a"}},
+			})
+			g.Assert(opts.Dig("database", "host")).Equal("localhost")
+			g.Assert(opts.Options("database").Get("port")).Equal(5432)
+			g.Assert(opts.Dig("database", "host", "name") == nil).IsTrue()
+			g.Assert(opts.Get("hosts").([]interface{})[0].(*OrderedOptions).String("name")).Equal("a")
+			v, err := opts.DigRequire("Database", "port")
+			g.Assert(err == nil).IsTrue()
+			g.Assert(v).Equal(5432)
+			_, err = opts.DigRequire("database", "user")
+			g.Assert(err.Error()).Equal(":database.user is blank")
+		})
+
+		g.It("Should decode YAML keeping the order", func() {
+			var opts OrderedOptions
+			err := yaml.Unmarshal([]byte("zeta: 1\nalpha:\n  Beta: true\n  list: [1, {a: b}]\n"), &opts)
+			g.Assert(err == nil).IsTrue()
+			g.Assert(opts.Keys()).Equal([]string{"zeta", "alpha"})
+			g.Assert(opts.Dig("alpha", "beta")).Equal(true)
+			g.Assert(opts.ToMap()).Equal(map[string]interface{}{
+				"zeta":  1,
+				"alpha": map[string]interface{}{"beta": true, "list": []interface{}{1, map[string]interface{}{"a": "b"}}},
+			})
+			g.Assert(yaml.Unmarshal([]byte("- 1"), &opts) != nil).IsTrue()
+		})
+
+		g.It("Should be blank when empty", func() {
+			g.Assert(NewOrderedOptions().IsBlank()).IsTrue()
+			g.Assert(ToOrderedOptions("nope").Len()).Equal(0)
+		})
+	})
+}
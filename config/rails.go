@@ -0,0 +1,131 @@
+package config
+
+// DatabaseConfig is a database configuration of config/database.yml.
+//
+// Rails documentation: http://guides.rubyonrails.org/configuring.html#configuring-a-database
+type DatabaseConfig struct {
+	Adapter  string `yaml:"adapter"`
+	Database string `yaml:"database"`
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	Socket   string `yaml:"socket"`
+	URL      string `yaml:"url"`
+	Encoding string `yaml:"encoding"`
+	SSLMode  string `yaml:"sslmode"`
+	Pool     int    `yaml:"pool"`
+	Timeout  int    `yaml:"timeout"`
+	Replica  bool   `yaml:"replica"`
+}
+
+// LoadDatabaseConfig loads the database configurations of the environment,
+// or the current one if env is empty, from a database.yml file. The
+// configurations are indexed by name, a single configuration being named
+// "primary" like in Rails.
+//
+//	configs, err := LoadDatabaseConfig("config/database.yml", "production")
+//	configs["primary"].Adapter // => "postgresql"
+func LoadDatabaseConfig(path, env string) (map[string]DatabaseConfig, error) {
+	section, err := ConfigFor(path, env)
+	if err != nil {
+		return nil, err
+	}
+	configs := map[string]DatabaseConfig{}
+	if !isMultiDatabase(section) {
+		var config DatabaseConfig
+		if err := section.Decode(&config); err != nil {
+			return nil, err
+		}
+		configs["primary"] = config
+		return configs, nil
+	}
+	if err := section.Decode(&configs); err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// isMultiDatabase reports whether the section lists named configurations,
+// which is the case when all its values are nested options.
+func isMultiDatabase(section *OrderedOptions) bool {
+	if section.Len() == 0 {
+		return false
+	}
+	for _, k := range section.keys {
+		if _, ok := section.values[k].(*OrderedOptions); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// StorageService is a service of config/storage.yml. The fields used
+// depend on the service (Disk, S3, GCS, AzureStorage or Mirror).
+//
+// Rails documentation: http://guides.rubyonrails.org/active_storage_overview.html#setup
+type StorageService struct {
+	Service string `yaml:"service"`
+	Public  bool   `yaml:"public"`
+
+	// Disk
+	Root string `yaml:"root"`
+
+	// S3
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+	Region          string `yaml:"region"`
+	Endpoint        string `yaml:"endpoint"`
+	ForcePathStyle  bool   `yaml:"force_path_style"`
+
+	// S3 and GCS
+	Bucket string `yaml:"bucket"`
+
+	// GCS, Credentials is either the path of a key file or the key itself.
+	Project     string      `yaml:"project"`
+	Credentials interface{} `yaml:"credentials"`
+
+	// AzureStorage
+	StorageAccountName string `yaml:"storage_account_name"`
+	StorageAccessKey   string `yaml:"storage_access_key"`
+	Container          string `yaml:"container"`
+
+	// Mirror
+	Primary string   `yaml:"primary"`
+	Mirrors []string `yaml:"mirrors"`
+}
+
+// LoadStorageConfig loads the services of a storage.yml file, indexed by
+// name. Unlike the other files, storage.yml isn't split by environment.
+func LoadStorageConfig(path string) (map[string]StorageService, error) {
+	all, err := ParseFile(path)
+	if err != nil {
+		return nil, err
+	}
+	services := map[string]StorageService{}
+	if err := all.Decode(&services); err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+// CableConfig is the Action Cable configuration of config/cable.yml.
+//
+// Rails documentation: http://guides.rubyonrails.org/action_cable_overview.html#subscription-adapter
+type CableConfig struct {
+	Adapter       string `yaml:"adapter"`
+	URL           string `yaml:"url"`
+	ChannelPrefix string `yaml:"channel_prefix"`
+}
+
+// LoadCableConfig loads the Action Cable configuration of the environment,
+// or the current one if env is empty, from a cable.yml file.
+func LoadCableConfig(path, env string) (CableConfig, error) {
+	var config CableConfig
+	section, err := ConfigFor(path, env)
+	if err != nil {
+		return config, err
+	}
+	err = section.Decode(&config)
+	return config, err
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestRailsConfigs(t *testing.T) {
+	g := Goblin(t)
+	write := func(name, content string) string {
+		path := filepath.Join(t.TempDir(), name)
+		ioutil.WriteFile(path, []byte(content), 0644)
+		return path
+	}
+
+	g.Describe("LoadDatabaseConfig", func() {
+		t.Setenv("DATABASE_PASSWORD", "s3cret")
+		path := write("database.yml", `
+default: &default
+  adapter: postgresql
+  encoding: unicode
+  pool: <%= ENV.fetch("RAILS_MAX_THREADS") { 5 } %>
+
+development:
+  <<: *default
+  database: app_development
+
+production:
+  primary:
+    <<: *default
+    database: app_production
+    password: <%= ENV["DATABASE_PASSWORD"] %>
+    pool: 10
+  cache:
+    <<: *default
+    database: app_production_cache
+    replica: true
+`)
+
+		g.It("Should load a single database", func() {
+			configs, err := LoadDatabaseConfig(path, "development")
+			g.Assert(err == nil).IsTrue()
+			g.Assert(configs).Equal(map[string]DatabaseConfig{
+				"primary": {Adapter: "postgresql", Encoding: "unicode", Pool: 5, Database: "app_development"},
+			})
+		})
+
+		g.It("Should load multiple databases", func() {
+			configs, err := LoadDatabaseConfig(path, "production")
+			g.Assert(err == nil).IsTrue()
+			g.Assert(configs["primary"]).Equal(DatabaseConfig{Adapter: "postgresql", Encoding: "unicode", Pool: 10, Database: "app_production", Password: "s3cret"})
+			g.Assert(configs["cache"].Database).Equal("app_production_cache")
+			g.Assert(configs["cache"].Replica).IsTrue()
+		})
+	})
+
+	g.Describe("LoadStorageConfig", func() {
+		g.It("Should load the services", func() {
+			path := write("storage.yml", `
+local:
+  service: Disk
+  root: <%= "storage" %>
+amazon:
+  service: S3
+  bucket: my-bucket
+  region: us-east-1
+mirror:
+  service: Mirror
+  primary: local
+  mirrors: [amazon]
+`)
+			services, err := LoadStorageConfig(path)
+			g.Assert(err == nil).IsTrue()
+			g.Assert(services["local"]).Equal(StorageService{Service: "Disk", Root: "storage"})
+			g.Assert(services["amazon"].Bucket).Equal("my-bucket")
+			g.Assert(services["mirror"].Mirrors).Equal([]string{"amazon"})
+		})
+	})
+
+	g.Describe("LoadCableConfig", func() {
+		g.It("Should load the environment adapter", func() {
+			path := write("cable.yml", `
+development:
+  adapter: async
+production:
+  adapter: redis
+  url: <%= ENV.fetch("REDIS_URL") { "redis://localhost:6379/1" } %>
+  channel_prefix: app_production
+`)
+			config, err := LoadCableConfig(path, "production")
+			g.Assert(err == nil).IsTrue()
+			g.Assert(config).Equal(CableConfig{Adapter: "redis", URL: "redis://localhost:6379/1", ChannelPrefix: "app_production"})
+			config, _ = LoadCableConfig(path, "development")
+			g.Assert(config.Adapter).Equal("async")
+		})
+	})
+}
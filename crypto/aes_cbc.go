@@ -4,105 +4,91 @@ import (
 	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
-	"crypto/rand"
-	"encoding/base64"
-	"errors"
-	"io"
-	"strings"
 )
 
-func (crypt *MessageEncryptor) aesCbcEncrypt(value interface{}) (string, error) {
-	// TODO: check the crypt is properly initiated
-	k := crypt.Key
-	// The longest accepted key is 32 byte long,
-	// instead of rejecting a long key, we truncate it.
-	// This is how openssl in Ruby works.
-	if len(k) > 32 {
-		k = crypt.Key[:32]
-	}
-	block, err := aes.NewCipher(k)
-	if err != nil {
-		return "", err
-	}
+// aesCBC is the aes-cbc cipher, which doesn't authenticate the messages:
+// the encryptor signs them with its verifier.
+type aesCBC struct {
+	keySize int
+	// key and blk are the key set up by the encryptor and its block.
+	key []byte
+	blk cipher.Block
+	// legacyPadding accepts the unpadded plaintexts, see
+	// MessageEncryptor.LegacyPadding.
+	legacyPadding bool
+}
 
-	// Set a default serializer if not already set
-	if crypt.Serializer == nil {
-		crypt.Serializer = JsonMsgSerializer{}
-	}
-	splaintext, err := crypt.Serializer.Serialize(value)
+func (c aesCBC) KeySize() int   { return c.keySize }
+func (c aesCBC) NonceSize() int { return aes.BlockSize }
+func (c aesCBC) TagSize() int   { return 0 }
+
+func (c aesCBC) Encrypt(key, iv, plaintext, authData []byte) ([]byte, []byte, error) {
+	block, err := c.block(key)
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
-	plaintext := []byte(splaintext)
 
 	// CBC mode works on blocks so plaintexts may need to be padded to the
 	// next whole block. See
 	// http://tools.ietf.org/html/rfc5652#section-6.3
-	plaintext = PKCS7Pad(plaintext)
-
-	// The IV needs to be unique, but not secure, it is included in the
-	// cypher text.
-	iv := make([]byte, aes.BlockSize)
-	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
-		return "", err
+	// cap the plaintext so the padding isn't appended in the spare capacity
+	// of the caller's slice
+	plaintext, err = PKCS7PadN(plaintext[:len(plaintext):len(plaintext)], aes.BlockSize)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// generate the cipher text
 	mode := cipher.NewCBCEncrypter(block, iv)
 	ciphertext := make([]byte, len(plaintext))
 	mode.CryptBlocks(ciphertext, plaintext)
-
-	// base64 the cipher text + the iv and join by "--"
-	output := base64.StdEncoding.EncodeToString(ciphertext) + "--" + base64.StdEncoding.EncodeToString(iv)
-	return output, nil
+	return ciphertext, nil, nil
 }
 
-func (crypt *MessageEncryptor) aesCbcDecrypt(encryptedMsg string, target interface{}) error {
-	k := crypt.Key
-	// The longest accepted key is 32 byte long,
-	// instead of rejecting a long key, we truncate it.
-	// This is how openssl in Ruby works.
-	if len(k) > 32 {
-		k = crypt.Key[:32]
-	}
-
-	block, err := aes.NewCipher(k)
-	if err != nil {
-		return err
-	}
-
-	// split the msg and decode each part
-	splitMsg := strings.Split(encryptedMsg, "--")
-	if len(splitMsg) != 2 {
-		return errors.New("bad data (--)")
-	}
-
-	ciphertext, err := base64.StdEncoding.DecodeString(splitMsg[0])
+func (c aesCBC) Decrypt(key, iv, ciphertext, tag, authData []byte) ([]byte, error) {
+	block, err := c.block(key)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	iv, err := base64.StdEncoding.DecodeString(splitMsg[1])
-	if err != nil {
-		return err
+	if len(iv) != aes.BlockSize {
+		return nil, malformed("bad data, wrong iv size")
 	}
-
 	if len(ciphertext) < aes.BlockSize {
-		return errors.New("bad data, ciphertext too short")
+		return nil, malformed("bad data, ciphertext too short")
 	}
 	if len(ciphertext)%aes.BlockSize != 0 {
-		return errors.New("bad data, ciphertext is not a multiple of the block size")
+		return nil, malformed("bad data, ciphertext is not a multiple of the block size")
 	}
 
+	// The encryptor only decrypts the messages once their signature is
+	// verified, and the padding is checked in constant time, so this isn't
+	// a padding oracle.
 	mode := cipher.NewCBCDecrypter(block, iv)
 	mode.CryptBlocks(ciphertext, ciphertext)
-	unPaddedCiphertext := PKCS7Unpad(ciphertext)
+	plaintext, err := PKCS7UnpadN(ciphertext, aes.BlockSize)
+	if err != nil && c.legacyPadding {
+		return ciphertext, nil
+	}
+	return plaintext, err
+}
 
-	// In some cases, Rails sends us messages padded with 0x10 (while this package only pads with 0x01-0x0f).
-	// For now, we handle this case here when the Serializer is JSON (so we know that 0x10 is actually a padding
-	// and not valid data - because this is an invalid json character).
-	if _, ok := crypt.Serializer.(JsonMsgSerializer); ok {
-		unPaddedCiphertext = bytes.TrimRight(unPaddedCiphertext, "\x10")
+// block returns the AES block cipher of key.
+func (c aesCBC) block(key []byte) (cipher.Block, error) {
+	if c.blk != nil && bytes.Equal(key, c.key) {
+		return c.blk, nil
 	}
+	return aes.NewCipher(key)
+}
 
-	return crypt.Serializer.Unserialize(string(unPaddedCiphertext), target)
+func (c aesCBC) setup(key []byte) (interface{}, error) {
+	return aes.NewCipher(key)
+}
+
+func (c aesCBC) withSetup(key []byte, v interface{}) (Cipher, bool) {
+	block, ok := v.(cipher.Block)
+	if !ok {
+		return c, false
+	}
+	c.key, c.blk = key, block
+	return c, true
 }
@@ -4,106 +4,76 @@ import (
 	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
-	"crypto/rand"
-	"encoding/base64"
-	"fmt"
-	"io"
 )
 
-func (crypt *MessageEncryptor) aesGCMEncrypt(value interface{}) (string, error) {
-	// TODO: check the crypt is properly initiated
-	k := crypt.Key
-	// The longest accepted key is 32 byte long,
-	// instead of rejecting a long key, we truncate it.
-	// This is how openssl in Ruby works.
-	if len(k) > 32 {
-		k = crypt.Key[:32]
-	}
-	block, err := aes.NewCipher(k)
-	if err != nil {
-		return "", err
-	}
+// aesGCM is the aes-gcm cipher, which authenticates the messages. Rails
+// stores the GCM auth tag separately from the encrypted data, unlike the
+// cipher package, so a little munging is required.
+type aesGCM struct {
+	keySize int
+	// key and gcm are the key set up by the encryptor and its AEAD.
+	key []byte
+	gcm cipher.AEAD
+}
 
-	aesgcm, err := cipher.NewGCM(block)
-	if err != nil {
-		return "", err
-	}
+func (c aesGCM) KeySize() int   { return c.keySize }
+func (c aesGCM) NonceSize() int { return 12 }
+func (c aesGCM) TagSize() int   { return 16 }
 
-	// Set a default serializer if not already set
-	if crypt.Serializer == nil {
-		crypt.Serializer = JsonMsgSerializer{}
-	}
-	splaintext, err := crypt.Serializer.Serialize(value)
+func (c aesGCM) Encrypt(key, nonce, plaintext, authData []byte) ([]byte, []byte, error) {
+	aesgcm, err := c.aead(key)
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
-	plaintext := []byte(splaintext)
-
-	iv := make([]byte, aesgcm.NonceSize())
-	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
-		return "", err
-	}
-
-	ciphertext := aesgcm.Seal(nil, iv, plaintext, nil)
-
-	// Rails stores the GCM auth tag separately from the encrypted data,
-	// unlike the cipher package, so a little munging is required.
-	// Luckily aesgcm.Overhead() is the tag size (which is 16).
+	ciphertext := aesgcm.Seal(nil, nonce, plaintext, authData)
+	// aesgcm.Overhead() is the tag size
 	tagStart := len(ciphertext) - aesgcm.Overhead()
-	tag := ciphertext[tagStart:]
-	enc := ciphertext[:tagStart]
-
-	vectors := [][]byte{enc, iv, tag}
-	for i, vec := range vectors {
-		dst := make([]byte, base64.StdEncoding.EncodedLen(len(vec)))
-		base64.StdEncoding.Encode(dst, vec)
-		vectors[i] = dst
-	}
-
-	output := string(bytes.Join(vectors, []byte("--")))
-	return output, nil
+	return ciphertext[:tagStart], ciphertext[tagStart:], nil
 }
 
-func (crypt *MessageEncryptor) aesGCMDecrypt(encryptedMsg string, target interface{}) error {
-	k := crypt.Key
-	// The longest accepted key is 32 byte long,
-	// instead of rejecting a long key, we truncate it.
-	// This is how openssl in Ruby works.
-	if len(k) > 32 {
-		k = crypt.Key[:32]
-	}
-
-	block, err := aes.NewCipher(k)
+func (c aesGCM) Decrypt(key, nonce, ciphertext, tag, authData []byte) ([]byte, error) {
+	aesgcm, err := c.aead(key)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	aesgcm, err := cipher.NewGCM(block)
+	if len(nonce) != aesgcm.NonceSize() {
+		return nil, malformed("bad data, wrong nonce size")
+	}
+	// the tag follows the ciphertext when decrypting messages, so nothing
+	// is copied nor allocated to open them in place
+	sealed := append(ciphertext, tag...)
+	plaintext, err := aesgcm.Open(sealed[:0], nonce, sealed, authData)
 	if err != nil {
-		return err
+		return nil, invalidSignature(err.Error())
 	}
+	return plaintext, nil
+}
 
-	vectors := bytes.SplitN([]byte(encryptedMsg), []byte("--"), 3)
-	if len(vectors) != 3 {
-		return fmt.Errorf("missing vectors, want 3, got %d", len(vectors))
-	}
-	for i, vec := range vectors {
-		dst := make([]byte, base64.StdEncoding.DecodedLen(len(vec)))
-		n, err := base64.StdEncoding.Decode(dst, vec)
-		if err != nil {
-			return fmt.Errorf("bad base64 encoding")
-		}
-		vectors[i] = dst[:n]
+// aead returns the GCM AEAD of key.
+func (c aesGCM) aead(key []byte) (cipher.AEAD, error) {
+	if c.gcm != nil && bytes.Equal(key, c.key) {
+		return c.gcm, nil
 	}
+	return newGCM(key)
+}
 
-	enc := vectors[0]
-	// Rails splits the auth tag into a separate vector, which is unnecessary really, but fine.
-	enc = append(enc, vectors[2]...)
-	nonce := vectors[1]
+func (c aesGCM) setup(key []byte) (interface{}, error) {
+	return newGCM(key)
+}
 
-	plain, err := aesgcm.Open(nil, nonce, enc, nil)
-	if err != nil {
-		return err
+func (c aesGCM) withSetup(key []byte, v interface{}) (Cipher, bool) {
+	aead, ok := v.(cipher.AEAD)
+	if !ok {
+		return c, false
 	}
+	c.key, c.gcm = key, aead
+	return c, true
+}
 
-	return crypt.Serializer.Unserialize(string(plain), target)
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
 }
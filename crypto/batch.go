@@ -0,0 +1,82 @@
+package crypto
+
+import (
+	"crypto/sha1"
+	"runtime"
+	"sync"
+)
+
+// BatchResult is the outcome of the decryption of one of the messages
+// passed to DecryptAndVerifyBatch.
+type BatchResult struct {
+	// Value is the pointer returned by the factory, populated with the
+	// message.
+	Value interface{}
+	Err   error
+}
+
+// DecryptAndVerifyBatch decrypts and verifies msgs concurrently, using as
+// many workers as GOMAXPROCS. factory returns the pointers the messages
+// are decrypted into, one per message:
+//
+//	results := e.DecryptAndVerifyBatch(cookies, func() interface{} { return &map[string]interface{}{} })
+//	for i, res := range results {
+//		if res.Err != nil {
+//			log.Printf("cookie %d: %v", i, res.Err)
+//		}
+//	}
+//
+// The results are in the order of msgs.
+func (crypt *MessageEncryptor) DecryptAndVerifyBatch(msgs []string, factory func() interface{}) []BatchResult {
+	return crypt.DecryptAndVerifyBatchWithPurpose(msgs, factory, "")
+}
+
+// DecryptAndVerifyBatchWithPurpose is like DecryptAndVerifyBatch for the
+// messages generated with a purpose, see DecryptAndVerifyWithPurpose.
+func (crypt *MessageEncryptor) DecryptAndVerifyBatchWithPurpose(msgs []string, factory func() interface{}, purpose string) []BatchResult {
+	results := make([]BatchResult, len(msgs))
+	if len(msgs) == 0 {
+		return results
+	}
+	// the defaults are set lazily, set them before sharing the encryptor
+	// between the workers
+	crypt.setDefaults()
+	for _, rotation := range crypt.rotations {
+		rotation.setDefaults()
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(msgs) {
+		workers = len(msgs)
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				target := factory()
+				results[i] = BatchResult{Value: target, Err: crypt.DecryptAndVerifyWithPurpose(msgs[i], target, purpose)}
+			}
+		}()
+	}
+	for i := range msgs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+// setDefaults sets the default serializer and verifier, and the default
+// hasher of the verifier.
+func (crypt *MessageEncryptor) setDefaults() {
+	if crypt.Serializer == nil {
+		crypt.Serializer = JsonMsgSerializer{}
+	}
+	crypt.setDefaultVerifier()
+	if crypt.Verifier != nil && crypt.Verifier.Hasher == nil {
+		crypt.Verifier.Hasher = sha1.New
+	}
+}
@@ -0,0 +1,76 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestDecryptAndVerifyBatch(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("DecryptAndVerifyBatch", func() {
+		g.It("decrypts the messages in order", func() {
+			for _, cipher := range []string{"aes-cbc", "aes-256-gcm"} {
+				key := GenerateRandomKey(32)
+				e := &MessageEncryptor{Key: key, SignKey: key, Cipher: cipher}
+				msgs := make([]string, 200)
+				for i := range msgs {
+					msgs[i], _ = e.EncryptAndSign(map[string]int{"i": i})
+				}
+				msgs[7] = "tampered"
+
+				e = &MessageEncryptor{Key: key, SignKey: key, Cipher: cipher}
+				results := e.DecryptAndVerifyBatch(msgs, func() interface{} { return &map[string]int{} })
+				g.Assert(len(results)).Eql(200)
+				for i, res := range results {
+					if i == 7 {
+						g.Assert(errors.Is(res.Err, ErrMalformedMessage)).IsTrue()
+						continue
+					}
+					g.Assert(res.Err).Eql(nil)
+					g.Assert((*res.Value.(*map[string]int))["i"]).Eql(i)
+				}
+			}
+		})
+
+		g.It("uses the rotations and the purpose", func() {
+			oldKey, key := GenerateRandomKey(32), GenerateRandomKey(32)
+			old := &MessageEncryptor{Key: oldKey, Cipher: "aes-256-gcm"}
+			e := &MessageEncryptor{Key: key, Cipher: "aes-256-gcm"}
+			e.Rotate(oldKey, "", nil)
+			msg1, _ := old.EncryptAndSignWithOptions("old", MessageOptions{Purpose: "p"})
+			msg2, _ := e.EncryptAndSignWithOptions("new", MessageOptions{Purpose: "p"})
+			msg3, _ := e.EncryptAndSign("no purpose")
+			results := e.DecryptAndVerifyBatchWithPurpose([]string{msg1, msg2, msg3}, func() interface{} { return new(string) }, "p")
+			g.Assert(*results[0].Value.(*string)).Eql("old")
+			g.Assert(*results[1].Value.(*string)).Eql("new")
+			g.Assert(errors.Is(results[2].Err, ErrWrongPurpose)).IsTrue()
+		})
+
+		g.It("handles empty batches", func() {
+			e := &MessageEncryptor{Key: GenerateRandomKey(32), Cipher: "aes-256-gcm"}
+			g.Assert(len(e.DecryptAndVerifyBatch(nil, func() interface{} { return new(string) }))).Eql(0)
+		})
+	})
+}
+
+func ExampleMessageEncryptor_DecryptAndVerifyBatch() {
+	e := &MessageEncryptor{Key: GenerateRandomKey(32), Cipher: "aes-256-gcm"}
+	cookie1, _ := e.EncryptAndSign(map[string]string{"user_id": "1"})
+	cookie2, _ := e.EncryptAndSign(map[string]string{"user_id": "2"})
+	results := e.DecryptAndVerifyBatch([]string{cookie1, cookie2, "tampered"}, func() interface{} { return &map[string]string{} })
+	for _, res := range results {
+		if res.Err != nil {
+			fmt.Println("invalid cookie")
+			continue
+		}
+		fmt.Println((*res.Value.(*map[string]string))["user_id"])
+	}
+	// Output:
+	// 1
+	// 2
+	// invalid cookie
+}
@@ -0,0 +1,48 @@
+package crypto
+
+import "sync"
+
+// maxPooledBuffer is the capacity above which buffers aren't pooled, so a
+// few large messages don't keep their memory around.
+const maxPooledBuffer = 64 << 10
+
+// bufferPool recycles the scratch buffers the messages are encoded and
+// decoded in, which are only needed for the duration of a call.
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 1024)
+		return &b
+	},
+}
+
+// getBuffer returns a pooled buffer of n bytes, to be released with
+// putBuffer once its content is no longer referenced.
+func getBuffer(n int) *[]byte {
+	b := bufferPool.Get().(*[]byte)
+	if cap(*b) < n {
+		*b = make([]byte, n)
+	}
+	*b = (*b)[:n]
+	return b
+}
+
+// putBuffer returns a buffer to the pool.
+func putBuffer(b *[]byte) {
+	if cap(*b) > maxPooledBuffer {
+		return
+	}
+	bufferPool.Put(b)
+}
+
+// pooledString calls appendTo with a pooled buffer and returns what it
+// appended as a string, so building it doesn't allocate.
+func pooledString(appendTo func(dst []byte) ([]byte, error)) (string, error) {
+	buf := getBuffer(0)
+	defer putBuffer(buf)
+	b, err := appendTo(*buf)
+	if err != nil {
+		return "", err
+	}
+	*buf = b
+	return string(b), nil
+}
@@ -0,0 +1,66 @@
+package crypto
+
+import (
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestBuffers(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("getBuffer", func() {
+		g.It("returns buffers of the requested size", func() {
+			for _, n := range []int{0, 10, 5000} {
+				b := getBuffer(n)
+				g.Assert(len(*b)).Eql(n)
+				putBuffer(b)
+			}
+		})
+	})
+
+	g.Describe("Pooled buffers", func() {
+		g.It("aren't referenced by the decrypted messages", func() {
+			key := GenerateRandomKey(32)
+			e := MessageEncryptor{Key: key, SignKey: key, Cipher: "aes-256-gcm", Serializer: NullMsgSerializer{}}
+			msg1, _ := e.EncryptAndSign("first message")
+			msg2, _ := e.EncryptAndSign("second message")
+			var out1, out2 string
+			g.Assert(e.DecryptAndVerify(msg1, &out1)).Eql(nil)
+			g.Assert(e.DecryptAndVerify(msg2, &out2)).Eql(nil)
+			g.Assert(out1).Eql("first message")
+			g.Assert(out2).Eql("second message")
+
+			raw, _ := e.DecryptAndVerifyRaw(msg1, "")
+			e.DecryptAndVerify(msg2, &out2)
+			g.Assert(string(raw)).Eql("first message")
+		})
+	})
+}
+
+func benchmarkEncryptor(b *testing.B, cipher string) {
+	key := GenerateRandomKey(32)
+	e := MessageEncryptor{Key: key, SignKey: key, Cipher: cipher}
+	session := map[string]string{"session_id": "b2d63c07ea7a9d58e415e3672e3f31a2"}
+	msg, _ := e.EncryptAndSign(session)
+	b.Run("EncryptAndSign", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := e.EncryptAndSign(session); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("DecryptAndVerify", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var s map[string]string
+			if err := e.DecryptAndVerify(msg, &s); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkMessageEncryptorGCM(b *testing.B) { benchmarkEncryptor(b, "aes-256-gcm") }
+func BenchmarkMessageEncryptorCBC(b *testing.B) { benchmarkEncryptor(b, "aes-cbc") }
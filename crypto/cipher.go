@@ -0,0 +1,223 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"sync"
+)
+
+// Cipher encrypts and decrypts the messages of a MessageEncryptor.
+// Ciphers are selected by the name they are registered with, see
+// RegisterCipher.
+type Cipher interface {
+	// KeySize is the size of the keys. Longer keys are truncated, this is
+	// how openssl in Ruby works.
+	KeySize() int
+	// NonceSize is the size of the initialization vectors.
+	NonceSize() int
+	// TagSize is the size of the authentication tags, or zero if the
+	// cipher doesn't authenticate the messages, which are then signed by
+	// the verifier of the encryptor.
+	TagSize() int
+	// Encrypt encrypts plaintext and returns the ciphertext and its
+	// authentication tag.
+	Encrypt(key, nonce, plaintext, authData []byte) (ciphertext, tag []byte, err error)
+	// Decrypt authenticates and decrypts ciphertext. The ciphertext can
+	// be decrypted in place.
+	Decrypt(key, nonce, ciphertext, tag, authData []byte) ([]byte, error)
+}
+
+var errUnsupportedCipher = notConfigured("cipher not set or not supported")
+
+var (
+	ciphersMu sync.RWMutex
+	ciphers   = map[string]Cipher{
+		"aes-cbc":     aesCBC{keySize: 32},
+		"aes-128-cbc": aesCBC{keySize: 16},
+		"aes-192-cbc": aesCBC{keySize: 24},
+		"aes-256-cbc": aesCBC{keySize: 32},
+		"aes-256-gcm": aesGCM{keySize: 32},
+		"aes-128-gcm": aesGCM{keySize: 16},
+	}
+)
+
+// RegisterCipher makes a cipher available to the encryptors under a name,
+// replacing the cipher previously registered with the same name. It is
+// meant to be called from an init function:
+//
+//	func init() {
+//		crypto.RegisterCipher("sm4-gcm", sm4GCM{})
+//	}
+func RegisterCipher(name string, c Cipher) {
+	ciphersMu.Lock()
+	defer ciphersMu.Unlock()
+	ciphers[name] = c
+}
+
+// lookupCipher returns the cipher registered with a name, aes-cbc if the
+// name is empty.
+func lookupCipher(name string) (Cipher, error) {
+	if name == "" {
+		name = "aes-cbc"
+	}
+	ciphersMu.RLock()
+	defer ciphersMu.RUnlock()
+	c, ok := ciphers[name]
+	if !ok {
+		return nil, errUnsupportedCipher
+	}
+	return c, nil
+}
+
+// cipherKey returns the key of the encryptor truncated to the size of the
+// cipher, or an error if the encryptor has StrictKeys and the key doesn't
+// have the cipher's size.
+func (crypt *MessageEncryptor) cipherKey(c Cipher) ([]byte, error) {
+	key := crypt.Key
+	if crypt.StrictKeys && len(key) != c.KeySize() {
+		return nil, notConfigured("crypto: the %s cipher needs a %d byte key, got %d bytes", crypt.cipherName(), c.KeySize(), len(key))
+	}
+	if len(key) > c.KeySize() {
+		return key[:c.KeySize()], nil
+	}
+	return key, nil
+}
+
+// boundCipher returns c set up for the key of the encryptor, and the key.
+func (crypt *MessageEncryptor) boundCipher(c Cipher) (Cipher, []byte, error) {
+	key, err := crypt.cipherKey(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c, err = crypt.keyCache().bind(c, key); err != nil {
+		return nil, nil, err
+	}
+	return c, key, nil
+}
+
+// cipherName returns the name of the encryptor's cipher.
+func (crypt *MessageEncryptor) cipherName() string {
+	if crypt.Cipher == "" {
+		return "aes-cbc"
+	}
+	return crypt.Cipher
+}
+
+// appendParts encrypts plaintext and appends the message Rails would to
+// dst: the base64 encoded ciphertext, initialization vector and auth tag,
+// if any, joined by "--".
+func (crypt *MessageEncryptor) appendParts(dst []byte, c Cipher, plaintext []byte) ([]byte, error) {
+	// The IV needs to be unique, but not secure, it is included in the
+	// message.
+	c, key, err := crypt.boundCipher(c)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, c.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	ciphertext, tag, err := c.Encrypt(key, nonce, plaintext, crypt.AuthData)
+	if err != nil {
+		return nil, err
+	}
+	if crypt.Encoding == Raw {
+		if c.TagSize() > 0 {
+			return rawAppend(dst, ciphertext, nonce, tag), nil
+		}
+		return rawAppend(dst, ciphertext, nonce), nil
+	}
+	enc, sep := crypt.Encoding, separatorOr(crypt.Separator)
+	parts := [][]byte{ciphertext, nonce, tag}
+	if c.TagSize() == 0 {
+		parts = parts[:2]
+	}
+	// grow dst once and encode the parts in place
+	size := len(sep) * (len(parts) - 1)
+	for _, part := range parts {
+		size += enc.encodedLen(len(part), crypt.URLSafe)
+	}
+	n := len(dst)
+	if cap(dst)-n < size {
+		grown := make([]byte, n, n+size)
+		copy(grown, dst)
+		dst = grown
+	}
+	dst = dst[:n+size]
+	for i, part := range parts {
+		if i > 0 {
+			n += copy(dst[n:], sep)
+		}
+		n += enc.encodeTo(dst[n:], part, crypt.URLSafe)
+	}
+	return dst, nil
+}
+
+// decryptParts splits a message in its parts and decrypts it, leaving msg
+// untouched. dst is the scratch space of the decoded parts, it must hold
+// len(msg) bytes and the returned plaintext may point to it.
+func (crypt *MessageEncryptor) decryptParts(c Cipher, msg, dst []byte) ([]byte, error) {
+	c, key, err := crypt.boundCipher(c)
+	if err != nil {
+		return nil, err
+	}
+	if crypt.Encoding == Raw {
+		return crypt.decryptRawParts(c, key, msg, dst)
+	}
+	// the auth tag and the nonce have a fixed length
+	sep := separatorOr(crypt.Separator)
+	rest, encodedTag := msg, []byte(nil)
+	if c.TagSize() > 0 {
+		rest, encodedTag, err = crypt.Encoding.cutLastPart(msg, sep, c.TagSize())
+	}
+	var encodedNonce []byte
+	if err == nil {
+		rest, encodedNonce, err = crypt.Encoding.cutLastPart(rest, sep, c.NonceSize())
+	}
+	if err != nil {
+		if c.TagSize() > 0 {
+			return nil, malformed("missing vectors, want 3, got %d", bytes.Count(msg, []byte(sep))+1)
+		}
+		return nil, err
+	}
+	// decode the ciphertext followed by the tag so aes-gcm opens them in
+	// place
+	nonceStart := len(rest) + len(sep)
+	var parts [3][]byte
+	n := 0
+	for i, r := range [3][2]int{
+		{0, len(rest)},
+		{len(msg) - len(encodedTag), len(msg)},
+		{nonceStart, nonceStart + len(encodedNonce)},
+	} {
+		m, err := crypt.Encoding.decodeTo(dst[n:], msg[r[0]:r[1]])
+		if err != nil {
+			return nil, malformed("bad encoding")
+		}
+		parts[i] = dst[n : n+m]
+		n += m
+	}
+	return c.Decrypt(key, parts[2], parts[0], parts[1], crypt.AuthData)
+}
+
+// decryptRawParts decrypts a message framed by rawAppend, copied to dst
+// since the ciphers decrypt in place.
+func (crypt *MessageEncryptor) decryptRawParts(c Cipher, key, msg, dst []byte) ([]byte, error) {
+	n := 2
+	if c.TagSize() > 0 {
+		n = 3
+	}
+	parts, err := rawSplit(dst[:copy(dst, msg)], n)
+	if err != nil {
+		return nil, err
+	}
+	var tag []byte
+	if n == 3 {
+		tag = parts[2]
+	}
+	if len(parts[1]) != c.NonceSize() || len(tag) != c.TagSize() {
+		return nil, errBadParts
+	}
+	return c.Decrypt(key, parts[1], parts[0], tag, crypt.AuthData)
+}
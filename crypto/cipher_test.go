@@ -0,0 +1,117 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+// longNonceGCM is aes-256-gcm using 16 byte nonces, registered by the
+// tests.
+type longNonceGCM struct{}
+
+func (longNonceGCM) KeySize() int   { return 32 }
+func (longNonceGCM) NonceSize() int { return 16 }
+func (longNonceGCM) TagSize() int   { return 16 }
+
+func (longNonceGCM) aead(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCMWithNonceSize(block, 16)
+}
+
+func (c longNonceGCM) Encrypt(key, nonce, plaintext, authData []byte) ([]byte, []byte, error) {
+	aead, err := c.aead(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	sealed := aead.Seal(nil, nonce, plaintext, authData)
+	tagStart := len(sealed) - aead.Overhead()
+	return sealed[:tagStart], sealed[tagStart:], nil
+}
+
+func (c longNonceGCM) Decrypt(key, nonce, ciphertext, tag, authData []byte) ([]byte, error) {
+	aead, err := c.aead(key)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Open(nil, nonce, append(ciphertext, tag...), authData)
+}
+
+func TestCipherRegistry(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("RegisterCipher", func() {
+		RegisterCipher("aes-256-gcm-long-nonce", longNonceGCM{})
+
+		g.It("makes the cipher available to the encryptors", func() {
+			e := MessageEncryptor{Key: GenerateRandomKey(64), Cipher: "aes-256-gcm-long-nonce"}
+			msg, err := e.EncryptAndSign("hello")
+			g.Assert(err).Eql(nil)
+			g.Assert(len(strings.Split(msg, "--"))).Eql(3)
+			var out string
+			g.Assert(e.DecryptAndVerify(msg, &out)).Eql(nil)
+			g.Assert(out).Eql("hello")
+
+			gcm := MessageEncryptor{Key: e.Key, Cipher: "aes-256-gcm"}
+			g.Assert(gcm.DecryptAndVerify(msg, &out) != nil).IsTrue()
+		})
+
+		g.It("refuses unknown ciphers", func() {
+			e := MessageEncryptor{Key: GenerateRandomKey(32), SignKey: GenerateRandomKey(32), Cipher: "rot13"}
+			_, err := e.EncryptAndSign("hello")
+			g.Assert(err).Eql(errUnsupportedCipher)
+		})
+
+		g.It("registers aes-256-cbc as aes-cbc", func() {
+			key, signKey := GenerateRandomKey(32), GenerateRandomKey(32)
+			e := MessageEncryptor{Key: key, SignKey: signKey, Cipher: "aes-256-cbc"}
+			msg, _ := e.EncryptAndSign("hello")
+			var out string
+			legacy := MessageEncryptor{Key: key, SignKey: signKey}
+			g.Assert(legacy.DecryptAndVerify(msg, &out)).Eql(nil)
+			g.Assert(out).Eql("hello")
+		})
+	})
+
+	g.Describe("aes-128-cbc and aes-192-cbc", func() {
+		g.It("encrypt using 16 and 24 byte keys", func() {
+			for cipherName, size := range map[string]int{"aes-128-cbc": 16, "aes-192-cbc": 24} {
+				key, signKey := GenerateRandomKey(32), GenerateRandomKey(32)
+				e := MessageEncryptor{Key: key, SignKey: signKey, Cipher: cipherName}
+				msg, err := e.EncryptAndSign("hello")
+				g.Assert(err).Eql(nil)
+				var out string
+				exact := MessageEncryptor{Key: key[:size], SignKey: signKey, Cipher: cipherName, StrictKeys: true}
+				g.Assert(exact.DecryptAndVerify(msg, &out)).Eql(nil)
+				g.Assert(out).Eql("hello")
+				aes256 := MessageEncryptor{Key: key, SignKey: signKey, Cipher: "aes-256-cbc"}
+				g.Assert(aes256.DecryptAndVerify(msg, &out) != nil).IsTrue()
+			}
+		})
+	})
+
+	g.Describe("StrictKeys", func() {
+		g.It("refuses the keys which don't have the size of the cipher", func() {
+			for _, size := range []int{16, 64} {
+				e := MessageEncryptor{Key: GenerateRandomKey(size), Cipher: "aes-256-gcm", StrictKeys: true}
+				_, err := e.EncryptAndSign("hello")
+				g.Assert(err.Error()).Eql(fmt.Sprintf("crypto: the aes-256-gcm cipher needs a 32 byte key, got %d bytes", size))
+			}
+		})
+
+		g.It("accepts the keys of the cipher's size", func() {
+			e := MessageEncryptor{Key: GenerateRandomKey(32), SignKey: GenerateRandomKey(64), StrictKeys: true}
+			msg, err := e.EncryptAndSign("hello")
+			g.Assert(err).Eql(nil)
+			var out string
+			g.Assert(e.DecryptAndVerify(msg, &out)).Eql(nil)
+		})
+	})
+}
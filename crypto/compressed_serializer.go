@@ -0,0 +1,116 @@
+package crypto
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression is a compression algorithm of CompressedSerializer.
+type Compression int
+
+const (
+	// Gzip compresses the messages using gzip, which Rails can read with
+	// ActiveSupport::Gzip.
+	Gzip Compression = iota
+	// Zstd compresses the messages using Zstandard, faster and usually
+	// smaller than gzip.
+	Zstd
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+	// the zstd encoder and decoder are safe for concurrent use by
+	// EncodeAll and DecodeAll
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(uint64(maxInflatedSize)))
+)
+
+// CompressedSerializer returns a serializer compressing the messages
+// serialized by inner using algo, and decompressing them before inner
+// unserializes them, to keep the sessions under the 4KB cookie limit:
+//
+//	e := MessageEncryptor{Key: key, Cipher: "aes-256-gcm", Serializer: CompressedSerializer(JsonMsgSerializer{}, Zstd)}
+//
+// Unlike the Compress option of the encryptors, all the messages are
+// compressed, whatever their size, and they have no metadata envelope.
+// The messages which aren't compressed, like the ones serialized before
+// using the serializer, are passed to inner as is.
+func CompressedSerializer(inner MsgSerializer, algo Compression) BytesMsgSerializer {
+	return compressedSerializer{inner, algo}
+}
+
+type compressedSerializer struct {
+	inner MsgSerializer
+	algo  Compression
+}
+
+func (s compressedSerializer) Serialize(v interface{}) (string, error) {
+	data, err := s.SerializeBytes(v)
+	return string(data), err
+}
+
+func (s compressedSerializer) Unserialize(data string, v interface{}) error {
+	return s.UnserializeBytes([]byte(data), v)
+}
+
+func (s compressedSerializer) SerializeBytes(v interface{}) ([]byte, error) {
+	data, err := serializeBytes(s.inner, v)
+	if err != nil {
+		return nil, err
+	}
+	switch s.algo {
+	case Gzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case Zstd:
+		return zstdEncoder.EncodeAll(data, nil), nil
+	}
+	return nil, fmt.Errorf("crypto: unsupported compression %d", s.algo)
+}
+
+func (s compressedSerializer) UnserializeBytes(data []byte, v interface{}) error {
+	var err error
+	switch {
+	case bytes.HasPrefix(data, gzipMagic):
+		data, err = gunzip(data)
+	case bytes.HasPrefix(data, zstdMagic):
+		data, err = zstdDecoder.DecodeAll(data, nil)
+	}
+	if err != nil {
+		return malformed("crypto: bad compressed message: %v", err)
+	}
+	if bs, ok := s.inner.(BytesMsgSerializer); ok {
+		return bs.UnserializeBytes(data, v)
+	}
+	return s.inner.Unserialize(string(data), v)
+}
+
+// gunzip decompresses gzip data, limited to maxInflatedSize bytes.
+func gunzip(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	inflated, err := io.ReadAll(io.LimitReader(r, int64(maxInflatedSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(inflated) > maxInflatedSize {
+		return nil, errInflatedTooLarge
+	}
+	return inflated, nil
+}
@@ -0,0 +1,83 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestCompressedSerializer(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("CompressedSerializer", func() {
+		session := map[string]string{"flash": strings.Repeat("Welcome back! ", 200)}
+
+		for _, algo := range []Compression{Gzip, Zstd} {
+			algo := algo
+			g.It(fmt.Sprintf("compresses the serialized messages (%d)", algo), func() {
+				s := CompressedSerializer(JsonMsgSerializer{}, algo)
+				data, err := s.Serialize(session)
+				g.Assert(err).Eql(nil)
+				json, _ := JsonMsgSerializer{}.Serialize(session)
+				g.Assert(len(data) < len(json)/10).IsTrue()
+				var out map[string]string
+				g.Assert(s.Unserialize(data, &out)).Eql(nil)
+				g.Assert(out).Eql(session)
+			})
+
+			g.It(fmt.Sprintf("is used by the encryptors (%d)", algo), func() {
+				key := GenerateRandomKey(32)
+				e := MessageEncryptor{Key: key, Cipher: "aes-256-gcm", Serializer: CompressedSerializer(JsonMsgSerializer{}, algo)}
+				msg, err := e.EncryptAndSign(session)
+				g.Assert(err).Eql(nil)
+				g.Assert(len(msg) < 4096).IsTrue()
+				var out map[string]string
+				g.Assert(e.DecryptAndVerify(msg, &out)).Eql(nil)
+				g.Assert(out).Eql(session)
+			})
+		}
+
+		g.It("reads the messages which aren't compressed", func() {
+			s := CompressedSerializer(JsonMsgSerializer{}, Zstd)
+			var out string
+			g.Assert(s.Unserialize(`"hello"`, &out)).Eql(nil)
+			g.Assert(out).Eql("hello")
+		})
+
+		g.It("wraps the serializers without bytes methods", func() {
+			s := CompressedSerializer(XMLMsgSerializer{}, Gzip)
+			data, err := s.Serialize("hello")
+			g.Assert(err).Eql(nil)
+			g.Assert(bytes.HasPrefix([]byte(data), gzipMagic)).IsTrue()
+			var out string
+			g.Assert(s.Unserialize(data, &out)).Eql(nil)
+			g.Assert(out).Eql("hello")
+		})
+
+		g.It("reports the corrupted messages", func() {
+			s := CompressedSerializer(JsonMsgSerializer{}, Gzip)
+			data, _ := s.Serialize(session)
+			var out map[string]string
+			err := s.Unserialize(data[:len(data)/2], &out)
+			g.Assert(errors.Is(err, ErrMalformedMessage)).IsTrue()
+		})
+
+		g.It("refuses unknown algorithms", func() {
+			_, err := CompressedSerializer(JsonMsgSerializer{}, Compression(42)).Serialize("hello")
+			g.Assert(err.Error()).Eql("crypto: unsupported compression 42")
+		})
+	})
+}
+
+func ExampleCompressedSerializer() {
+	e := MessageEncryptor{Key: make([]byte, 32), Cipher: "aes-256-gcm", Serializer: CompressedSerializer(JsonMsgSerializer{}, Zstd)}
+	msg, _ := e.EncryptAndSign(strings.Repeat("a", 10000))
+	var data string
+	e.DecryptAndVerify(msg, &data)
+	fmt.Println(len(msg) < 200, len(data))
+	// Output: true 10000
+}
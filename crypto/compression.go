@@ -0,0 +1,61 @@
+package crypto
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+)
+
+// DefaultCompressThreshold is the size in bytes above which serialized
+// messages are compressed when compression is enabled, 1 kilobyte like
+// Rails.
+const DefaultCompressThreshold = 1024
+
+// maxInflatedSize limits the size of the inflated messages so a small
+// compressed message can't exhaust the memory. It is a variable so the
+// tests can lower it.
+var maxInflatedSize = 64 << 20
+
+var errInflatedTooLarge = malformed("compressed message too large")
+
+// compressAbove returns the size above which messages are compressed, or
+// 0 if compression is disabled.
+func compressAbove(compress bool, threshold int) int {
+	switch {
+	case !compress:
+		return 0
+	case threshold <= 0:
+		return DefaultCompressThreshold
+	}
+	return threshold
+}
+
+// deflate compresses data using zlib, like Zlib::Deflate.deflate.
+func deflate(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// inflate decompresses zlib data, like Zlib::Inflate.inflate.
+func inflate(data []byte) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, malformed("bad compressed message: %v", err)
+	}
+	defer r.Close()
+	inflated, err := io.ReadAll(io.LimitReader(r, int64(maxInflatedSize)+1))
+	if err != nil {
+		return nil, malformed("bad compressed message: %v", err)
+	}
+	if len(inflated) > maxInflatedSize {
+		return nil, errInflatedTooLarge
+	}
+	return inflated, nil
+}
@@ -0,0 +1,80 @@
+package crypto
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestCompression(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Message compression", func() {
+		large := map[string]string{"data": strings.Repeat("compress me ", 200)}
+
+		for _, cipher := range []string{"aes-256-gcm", "aes-cbc"} {
+			cipher := cipher
+			g.It("compresses large messages using "+cipher, func() {
+				key := GenerateRandomKey(32)
+				plain := MessageEncryptor{Key: key, SignKey: key, Cipher: cipher}
+				compressed := MessageEncryptor{Key: key, SignKey: key, Cipher: cipher, Compress: true}
+
+				msg, err := compressed.EncryptAndSign(large)
+				g.Assert(err).Eql(nil)
+				uncompressedMsg, _ := plain.EncryptAndSign(large)
+				g.Assert(len(msg) < len(uncompressedMsg)/4).IsTrue()
+
+				// compressed messages are read whatever the setting
+				var out map[string]string
+				g.Assert(plain.DecryptAndVerify(msg, &out)).Eql(nil)
+				g.Assert(out).Eql(large)
+			})
+		}
+
+		g.It("doesn't compress messages under the threshold", func() {
+			v := MessageVerifier{Secret: []byte("secret"), Serializer: JsonMsgSerializer{}, Compress: true}
+			token, _ := v.Generate("small")
+			data, _ := base64.StdEncoding.DecodeString(strings.Split(token, "--")[0])
+			g.Assert(string(data)).Eql(`"small"`)
+
+			v.CompressThreshold = 4
+			token, _ = v.Generate(strings.Repeat("a", 100))
+			data, _ = base64.StdEncoding.DecodeString(strings.Split(token, "--")[0])
+			g.Assert(strings.Contains(string(data), `"cmp":true`)).IsTrue()
+			var out string
+			g.Assert(v.Verify(token, &out)).Eql(nil)
+			g.Assert(out).Eql(strings.Repeat("a", 100))
+		})
+
+		g.It("keeps the metadata of compressed messages", func() {
+			v := MessageVerifier{Secret: []byte("secret"), Serializer: JsonMsgSerializer{}, Compress: true, CompressThreshold: 1}
+			token, _ := v.GenerateWithOptions(large, MessageOptions{Purpose: "export"})
+			var out map[string]string
+			g.Assert(v.Verify(token, &out)).Eql(ErrWrongPurpose)
+			g.Assert(v.VerifyWithPurpose(token, &out, "export")).Eql(nil)
+			g.Assert(out).Eql(large)
+		})
+
+		g.It("refuses messages inflating too much", func() {
+			defer func(size int) { maxInflatedSize = size }(maxInflatedSize)
+			maxInflatedSize = 1024
+			bomb, _ := deflate(make([]byte, maxInflatedSize+1))
+			_, err := inflate(bomb)
+			g.Assert(err).Eql(errInflatedTooLarge)
+			g.Assert(errors.Is(err, ErrMalformedMessage)).IsTrue()
+			_, err = inflate(bomb[:len(bomb)/2])
+			g.Assert(errors.Is(err, ErrMalformedMessage)).IsTrue()
+		})
+	})
+}
+
+func ExampleMessageEncryptor_compression() {
+	e := MessageEncryptor{Key: GenerateRandomKey(32), Cipher: "aes-256-gcm", Compress: true}
+	msg, _ := e.EncryptAndSign(strings.Repeat("a large session ", 1000))
+	fmt.Println(len(msg) < 1000)
+	// Output: true
+}
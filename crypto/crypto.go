@@ -2,6 +2,9 @@ package crypto
 
 import (
 	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
 	"io"
 )
 
@@ -10,12 +13,56 @@ type MsgSerializer interface {
 	Unserialize(data string, v interface{}) error
 }
 
+// BytesMsgSerializer is implemented by the serializers which can read and
+// write bytes, sparing the conversions to and from strings. The encryptors
+// and verifiers use it when available. UnserializeBytes must not retain
+// data, which may be reused once it returns.
+type BytesMsgSerializer interface {
+	MsgSerializer
+	SerializeBytes(v interface{}) ([]byte, error)
+	UnserializeBytes(data []byte, v interface{}) error
+}
+
+// randReader is the source of the random keys, replaced by the tests.
+var randReader io.Reader = rand.Reader
+
 // Generates a random key of the passed length.
 // As a reminder, for AES keys of length 16, 24, or 32 bytes are expected for AES-128, AES-192, or AES-256.
+// It returns nil if the random source fails, see GenerateRandomKeyErr.
 func GenerateRandomKey(strength int) []byte {
-	k := make([]byte, strength)
-	if _, err := io.ReadFull(rand.Reader, k); err != nil {
+	k, err := GenerateRandomKeyErr(strength)
+	if err != nil {
 		return nil
 	}
 	return k
 }
+
+// GenerateRandomKeyErr is like GenerateRandomKey but returns an error if
+// the random source fails, rather than a nil key.
+func GenerateRandomKeyErr(strength int) ([]byte, error) {
+	k := make([]byte, strength)
+	if _, err := io.ReadFull(randReader, k); err != nil {
+		return nil, fmt.Errorf("crypto: can't generate a random key: %w", err)
+	}
+	return k, nil
+}
+
+// GenerateRandomHexKey returns a random key of strength bytes encoded in
+// lowercase hexadecimal, like SecureRandom.hex.
+func GenerateRandomHexKey(strength int) (string, error) {
+	k, err := GenerateRandomKeyErr(strength)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(k), nil
+}
+
+// GenerateRandomBase64Key returns a random key of strength bytes encoded in
+// padded standard base64, like SecureRandom.base64.
+func GenerateRandomBase64Key(strength int) (string, error) {
+	k, err := GenerateRandomKeyErr(strength)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(k), nil
+}
@@ -0,0 +1,65 @@
+package crypto
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	. "github.com/franela/goblin"
+)
+
+func TestGenerateRandomKey(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Generating random keys", func() {
+		g.It("returns keys of the requested strength", func() {
+			key, err := GenerateRandomKeyErr(32)
+			g.Assert(err).Eql(nil)
+			g.Assert(len(key)).Eql(32)
+			g.Assert(len(GenerateRandomKey(16))).Eql(16)
+		})
+
+		g.It("encodes the keys like SecureRandom", func() {
+			key, err := GenerateRandomHexKey(16)
+			g.Assert(err).Eql(nil)
+			g.Assert(len(key)).Eql(32)
+			_, err = hex.DecodeString(key)
+			g.Assert(err).Eql(nil)
+			g.Assert(strings.ToLower(key)).Eql(key)
+
+			key, err = GenerateRandomBase64Key(16)
+			g.Assert(err).Eql(nil)
+			g.Assert(len(key)).Eql(24)
+			decoded, err := base64.StdEncoding.DecodeString(key)
+			g.Assert(err).Eql(nil)
+			g.Assert(len(decoded)).Eql(16)
+		})
+
+		g.It("reports the failures of the random source", func() {
+			failure := errors.New("no entropy")
+			defer func(r io.Reader) { randReader = r }(randReader)
+			randReader = iotest.ErrReader(failure)
+			_, err := GenerateRandomKeyErr(32)
+			g.Assert(errors.Is(err, failure)).IsTrue()
+			g.Assert(GenerateRandomKey(32) == nil).IsTrue()
+			_, err = GenerateRandomHexKey(32)
+			g.Assert(errors.Is(err, failure)).IsTrue()
+			_, err = GenerateRandomBase64Key(32)
+			g.Assert(errors.Is(err, failure)).IsTrue()
+		})
+	})
+}
+
+func ExampleGenerateRandomKeyErr() {
+	key, err := GenerateRandomKeyErr(32)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(len(key))
+	// Output: 32
+}
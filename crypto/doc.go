@@ -8,6 +8,7 @@
 Package crypto ports some of Ruby on Rails' crypto:
   * version 4+: encrypted & signed messages (aes-cbc)
   * version 5.2+: encrypted & authenticated messages (aes-256-gcm)
+  * version 5.2+: encrypted files (aes-128-gcm), see EncryptedFile
 Messages can be shared between a Ruby app and a Go app. That said, this
 library is useful to anyone wanting to encrypt/sign/authenticate data.
 
@@ -51,8 +52,12 @@ I wrote a patch to change Rails' default serializer to JSON:
 https://gist.github.com/mattetti/7624413
 This package can use different serializers and you can also add your
 own. This is useful if for instance you only have Go apps and choose to
-use gob encoding or another encoding solution. Three serializers are
-available JSON, XML and Null, the last serializer is basically a no-op
+use gob encoding or another encoding solution. Five serializers are
+available JSON, XML, Marshal, MessagePack and Null. The Marshal serializer
+reads and writes the Ruby Marshal format so the sessions of apps which
+can't move away from it can still be shared. The MessagePack serializer
+matches the :message_pack cookies serializer of Rails 7.1, including its
+extension types. The Null serializer is basically a no-op
 serializer used when the data doesn't need serialization and can be
 transported as strings.
 
@@ -89,6 +94,23 @@ Here is a quick and high level of what Rails does (Ruby code):
 
 The equivalent in Go is available in the documentation examples: http://godoc.org/github.com/mattetti/goRailsYourself/crypto#pkg-examples
 
+Message metadata
+
+Since Rails 6, messages can carry a purpose and an expiration date, Rails
+cookies for instance always use the "cookie.<name>" purpose. Use
+EncryptAndSignWithOptions and DecryptAndVerifyWithPurpose to write and
+read such messages:
+
+  e.EncryptAndSignWithOptions(session, MessageOptions{Purpose: CookiePurpose("_app_session")})
+  e.DecryptAndVerifyWithPurpose(cookie, &session, CookiePurpose("_app_session"))
+
+MessageVerifier offers the same options with GenerateWithOptions and
+VerifyWithPurpose.
+
+Large messages can be deflated by setting Compress on the encryptor or
+verifier, to keep sessions under the cookie size limit. Compressed messages
+are flagged in the metadata envelope and inflated transparently.
+
 Derived keys
 
 A few important things need to be mentioned. Rails uses a unique secret
@@ -133,6 +155,11 @@ Here is an example for aes-256-gcm (Rails 5.2+):
   secret := kg.CacheGenerate(authenticated, 32)
   e := MessageEncryptor{Key: secret, Cipher: "aes-256-gcm"}
 
+Rails 7+ apps derive their keys using sha256 instead of sha1, set the
+digest of the key generator accordingly:
+
+  kg := KeyGenerator{Secret: railsSecret, HashDigest: sha256.New}
+
 Without Ruby
 
 The encryption used in Rails isn't specific to Ruby and this library can
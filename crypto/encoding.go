@@ -0,0 +1,134 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+)
+
+// defaultSeparator joins the parts of the signed and encrypted messages,
+// like Rails.
+const defaultSeparator = "--"
+
+// separatorOr returns sep, or the default separator if sep is empty.
+func separatorOr(sep string) string {
+	if sep == "" {
+		return defaultSeparator
+	}
+	return sep
+}
+
+// Encoding is the encoding of the signed and encrypted messages.
+type Encoding int
+
+const (
+	// Base64 encodes the parts of the messages using base64 and joins them
+	// with the separator, like Rails. It is the default.
+	Base64 Encoding = iota
+	// Raw frames the binary parts of the messages with their length. The
+	// messages are a third smaller, which suits the ones stored in a
+	// database or a cache rather than a cookie, but Rails can't read them.
+	Raw
+	// Hex encodes the parts of the messages using lowercase hexadecimal and
+	// joins them with the separator, for the systems mangling base64.
+	Hex
+)
+
+var errBadParts = malformed("bad data (--)")
+
+// encodedLen returns the length of n bytes encoded by encodeTo.
+func (e Encoding) encodedLen(n int, urlSafe bool) int {
+	switch {
+	case e == Hex:
+		return hex.EncodedLen(n)
+	case urlSafe:
+		return base64.RawURLEncoding.EncodedLen(n)
+	}
+	return base64.StdEncoding.EncodedLen(n)
+}
+
+// encodeTo encodes a part of a message into dst using hex, or base64 for
+// the other text encodings, URL safe and without padding when urlSafe is
+// set like Rails does with the url_safe option. dst must hold
+// encodedLen(len(src)) bytes. It returns the number of bytes written.
+func (e Encoding) encodeTo(dst, src []byte, urlSafe bool) int {
+	switch {
+	case e == Hex:
+		return hex.Encode(dst, src)
+	case urlSafe:
+		base64.RawURLEncoding.Encode(dst, src)
+	default:
+		base64.StdEncoding.Encode(dst, src)
+	}
+	return e.encodedLen(len(src), urlSafe)
+}
+
+// maxDecodedLen returns the maximum length of n encoded bytes once
+// decoded, whatever the encoding.
+func maxDecodedLen(n int) int {
+	return base64.RawStdEncoding.DecodedLen(n)
+}
+
+// decodeTo decodes a part of a message encoded by encodeTo into dst, which
+// must hold maxDecodedLen(len(src)) bytes. Base64 is decoded whatever its
+// variant. It returns the number of bytes written.
+func (e Encoding) decodeTo(dst, src []byte) (int, error) {
+	if e == Hex {
+		return hex.Decode(dst, src)
+	}
+	src = bytes.TrimRight(src, "=")
+	if bytes.ContainsAny(src, "-_") {
+		return base64.RawURLEncoding.Decode(dst, src)
+	}
+	return base64.RawStdEncoding.Decode(dst, src)
+}
+
+// cutLastPart splits the last encoded part of a message, n bytes once
+// decoded, from the rest of the message joined by sep. The parts are cut
+// using their length since URL safe base64 can contain the separator.
+func (e Encoding) cutLastPart(msg []byte, sep string, n int) (rest, part []byte, err error) {
+	length := base64.RawStdEncoding.EncodedLen(n)
+	if e == Hex {
+		length = hex.EncodedLen(n)
+	} else if bytes.HasSuffix(msg, []byte("=")) {
+		length = base64.StdEncoding.EncodedLen(n)
+	}
+	i := len(msg) - length - len(sep)
+	if i < 0 || string(msg[i:i+len(sep)]) != sep {
+		return nil, nil, errBadParts
+	}
+	return msg[:i], msg[i+len(sep):], nil
+}
+
+// rawAppend appends the parts of a message to dst, framing them by
+// prefixing each of them with its uvarint encoded length.
+func rawAppend(dst []byte, parts ...[]byte) []byte {
+	var size [binary.MaxVarintLen64]byte
+	for _, part := range parts {
+		n := binary.PutUvarint(size[:], uint64(len(part)))
+		dst = append(dst, size[:n]...)
+		dst = append(dst, part...)
+	}
+	return dst
+}
+
+// rawSplit splits a message framed by rawAppend in its n parts, which
+// point to data.
+func rawSplit(data []byte, n int) ([][]byte, error) {
+	parts := make([][]byte, 0, n)
+	for len(data) > 0 && len(parts) < n {
+		size, read := binary.Uvarint(data)
+		if read <= 0 || size > uint64(len(data)-read) {
+			return nil, errBadParts
+		}
+		data = data[read:]
+		// cap the parts so appending to one doesn't overwrite the next
+		parts = append(parts, data[:size:size])
+		data = data[size:]
+	}
+	if len(parts) != n || len(data) > 0 {
+		return nil, errBadParts
+	}
+	return parts, nil
+}
@@ -0,0 +1,233 @@
+package crypto
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestURLSafeEncoding(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("URL safe messages", func() {
+		g.It("are signed without padding nor URL unsafe characters", func() {
+			v := MessageVerifier{Secret: []byte("secret"), Serializer: JsonMsgSerializer{}, URLSafe: true}
+			token, err := v.Generate("hello")
+			g.Assert(err).Eql(nil)
+			g.Assert(strings.HasPrefix(token, "ImhlbGxvIg--")).IsTrue()
+
+			for i := 0; i < 50; i++ {
+				token, _ := v.Generate(fmt.Sprintf("%x", GenerateRandomKey(i)))
+				g.Assert(strings.ContainsAny(token, "+/=")).IsFalse()
+				var out string
+				g.Assert(v.Verify(token, &out)).Eql(nil)
+			}
+		})
+
+		for _, cipher := range []string{"aes-256-gcm", "aes-cbc"} {
+			cipher := cipher
+			g.It("are encrypted using "+cipher, func() {
+				key := GenerateRandomKey(32)
+				e := MessageEncryptor{Key: key, SignKey: key, Cipher: cipher, URLSafe: true}
+				for i := 0; i < 50; i++ {
+					data := strings.Repeat("x", i)
+					msg, err := e.EncryptAndSign(data)
+					g.Assert(err).Eql(nil)
+					g.Assert(strings.ContainsAny(msg, "+/=")).IsFalse()
+					var out string
+					g.Assert(e.DecryptAndVerify(msg, &out)).Eql(nil)
+					g.Assert(out).Eql(data)
+				}
+			})
+
+			g.It("are read along the standard base64 ones using "+cipher, func() {
+				key := GenerateRandomKey(32)
+				std := MessageEncryptor{Key: key, SignKey: key, Cipher: cipher}
+				urlSafe := MessageEncryptor{Key: key, SignKey: key, Cipher: cipher, URLSafe: true}
+				msg, _ := std.EncryptAndSign("data")
+				var out string
+				g.Assert(urlSafe.DecryptAndVerify(msg, &out)).Eql(nil)
+				g.Assert(out).Eql("data")
+				msg, _ = urlSafe.EncryptAndSign("data")
+				g.Assert(std.DecryptAndVerify(msg, &out)).Eql(nil)
+			})
+		}
+	})
+
+	g.Describe("decodeTo", func() {
+		g.It("decodes all the base64 variants", func() {
+			for _, encoded := range []string{"+/+/", "-_-_", "+/8=", "+/8", "-_8"} {
+				dst := make([]byte, maxDecodedLen(len(encoded)))
+				_, err := Base64.decodeTo(dst, []byte(encoded))
+				g.Assert(err).Eql(nil)
+			}
+		})
+	})
+}
+
+func TestRawEncoding(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Raw messages", func() {
+		g.It("are signed and verified", func() {
+			v := MessageVerifier{Secret: []byte("secret"), Serializer: JsonMsgSerializer{}, Encoding: Raw}
+			token, err := v.Generate("hello")
+			g.Assert(err).Eql(nil)
+			g.Assert(len(token)).Eql(1 + 7 + 1 + 20)
+			var out string
+			g.Assert(v.Verify(token, &out)).Eql(nil)
+			g.Assert(out).Eql("hello")
+		})
+
+		g.It("refuse tampered or truncated signed messages", func() {
+			v := MessageVerifier{Secret: []byte("secret"), Serializer: JsonMsgSerializer{}, Encoding: Raw}
+			token, _ := v.Generate("hello")
+			var out string
+			tampered := []byte(token)
+			tampered[3] ^= 1
+			g.Assert(v.Verify(string(tampered), &out) != nil).IsTrue()
+			g.Assert(v.Verify(token[:len(token)-1], &out) != nil).IsTrue()
+			g.Assert(v.Verify(token+"x", &out) != nil).IsTrue()
+		})
+
+		for _, cipher := range []string{"aes-256-gcm", "aes-cbc"} {
+			cipher := cipher
+			g.It("are encrypted using "+cipher, func() {
+				key := GenerateRandomKey(32)
+				raw := MessageEncryptor{Key: key, SignKey: key, Cipher: cipher, Encoding: Raw}
+				std := MessageEncryptor{Key: key, SignKey: key, Cipher: cipher}
+				data := strings.Repeat("x", 100)
+				msg, err := raw.EncryptAndSign(data)
+				g.Assert(err).Eql(nil)
+				encoded, _ := std.EncryptAndSign(data)
+				g.Assert(len(msg) < len(encoded)).IsTrue()
+
+				var out string
+				g.Assert(raw.DecryptAndVerify(msg, &out)).Eql(nil)
+				g.Assert(out).Eql(data)
+				g.Assert(std.DecryptAndVerify(msg, &out) != nil).IsTrue()
+
+				tampered := []byte(msg)
+				tampered[len(tampered)/2] ^= 1
+				g.Assert(raw.DecryptAndVerify(string(tampered), &out) != nil).IsTrue()
+			})
+		}
+	})
+
+	g.Describe("rawSplit", func() {
+		g.It("splits the parts framed by rawAppend", func() {
+			parts, err := rawSplit(rawAppend(nil, []byte("a"), nil, []byte(strings.Repeat("b", 300))), 3)
+			g.Assert(err).Eql(nil)
+			g.Assert(string(parts[0])).Eql("a")
+			g.Assert(len(parts[1])).Eql(0)
+			g.Assert(len(parts[2])).Eql(300)
+		})
+
+		g.It("refuses badly framed messages", func() {
+			for _, msg := range []string{"", "\x05abc", "\x01a", "\x01a\x01b\x01c"} {
+				_, err := rawSplit([]byte(msg), 2)
+				g.Assert(err).Eql(errBadParts)
+			}
+		})
+	})
+}
+
+func TestHexEncoding(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Hex messages", func() {
+		g.It("are signed and verified", func() {
+			v := MessageVerifier{Secret: []byte("secret"), Serializer: JsonMsgSerializer{}, Encoding: Hex}
+			token, err := v.Generate("hello")
+			g.Assert(err).Eql(nil)
+			g.Assert(strings.HasPrefix(token, "2268656c6c6f22--")).IsTrue()
+			g.Assert(strings.HasSuffix(token, "--"+v.DigestFor("2268656c6c6f22"))).IsTrue()
+			var out string
+			g.Assert(v.Verify(token, &out)).Eql(nil)
+			g.Assert(out).Eql("hello")
+		})
+
+		for _, cipher := range []string{"aes-256-gcm", "aes-cbc"} {
+			cipher := cipher
+			g.It("are encrypted using "+cipher, func() {
+				key := GenerateRandomKey(32)
+				e := MessageEncryptor{Key: key, SignKey: key, Cipher: cipher, Encoding: Hex}
+				for i := 0; i < 20; i++ {
+					data := strings.Repeat("x", i)
+					msg, err := e.EncryptAndSign(data)
+					g.Assert(err).Eql(nil)
+					g.Assert(strings.Trim(strings.ReplaceAll(msg, "--", ""), "0123456789abcdef")).Eql("")
+					var out string
+					g.Assert(e.DecryptAndVerify(msg, &out)).Eql(nil)
+					g.Assert(out).Eql(data)
+				}
+			})
+		}
+
+		g.It("splits the ciphertext, IV and auth tag", func() {
+			key := GenerateRandomKey(32)
+			e := MessageEncryptor{Key: key, Cipher: "aes-256-gcm", Encoding: Hex}
+			msg, _ := e.EncryptAndSign("data")
+			parts := strings.Split(msg, "--")
+			g.Assert(len(parts)).Eql(3)
+			g.Assert(len(parts[1])).Eql(24)
+			g.Assert(len(parts[2])).Eql(32)
+		})
+	})
+}
+
+func TestSeparator(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Custom separators", func() {
+		g.It("join the data and the digest", func() {
+			v := MessageVerifier{Secret: []byte("secret"), Serializer: JsonMsgSerializer{}, Separator: "."}
+			token, err := v.Generate("hello")
+			g.Assert(err).Eql(nil)
+			g.Assert(token).Eql("ImhlbGxvIg==." + v.DigestFor("ImhlbGxvIg=="))
+			var out string
+			g.Assert(v.Verify(token, &out)).Eql(nil)
+			g.Assert(out).Eql("hello")
+
+			std := MessageVerifier{Secret: []byte("secret"), Serializer: JsonMsgSerializer{}}
+			g.Assert(std.Verify(token, &out) != nil).IsTrue()
+		})
+
+		for _, cipher := range []string{"aes-256-gcm", "aes-cbc"} {
+			cipher := cipher
+			g.It("join the parts of the messages encrypted using "+cipher, func() {
+				key := GenerateRandomKey(32)
+				e := MessageEncryptor{Key: key, SignKey: key, Cipher: cipher, URLSafe: true, Separator: "~"}
+				for i := 0; i < 20; i++ {
+					data := strings.Repeat("x", i)
+					msg, err := e.EncryptAndSign(data)
+					g.Assert(err).Eql(nil)
+					g.Assert(strings.Contains(msg, "~")).IsTrue()
+					var out string
+					g.Assert(e.DecryptAndVerify(msg, &out)).Eql(nil)
+					g.Assert(out).Eql(data)
+				}
+			})
+		}
+
+		g.It("are kept by the rotations", func() {
+			oldKey, newKey := GenerateRandomKey(32), GenerateRandomKey(32)
+			old := MessageEncryptor{Key: oldKey, Cipher: "aes-256-gcm", Encoding: Hex, Separator: "."}
+			msg, _ := old.EncryptAndSign("data")
+			e := MessageEncryptor{Key: newKey, Cipher: "aes-256-gcm", Encoding: Hex, Separator: "."}
+			e.Rotate(oldKey, "", nil)
+			var out string
+			g.Assert(e.DecryptAndVerify(msg, &out)).Eql(nil)
+			g.Assert(out).Eql("data")
+		})
+	})
+}
+
+func ExampleMessageVerifier_urlSafe() {
+	v := MessageVerifier{Secret: []byte("Hey, I'm a secret!"), Serializer: JsonMsgSerializer{}, URLSafe: true}
+	token, _ := v.Generate("hello")
+	fmt.Println(strings.Split(token, "--")[0])
+	// Output: ImhlbGxvIg
+}
@@ -0,0 +1,126 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EncryptedFileCipher is the cipher of the encrypted files.
+const EncryptedFileCipher = "aes-128-gcm"
+
+var (
+	// ErrMissingKey is returned when the key of an encrypted file is
+	// neither in its environment variable nor in its key file.
+	ErrMissingKey = errors.New("crypto: missing encryption key")
+	// ErrMissingContent is returned when reading an encrypted file which
+	// doesn't exist.
+	ErrMissingContent = errors.New("crypto: missing encrypted content file")
+)
+
+// EncryptedFile reads and writes files encrypted like Rails does, such as
+// config/credentials.yml.enc or the files of
+// Rails.application.encrypted("config/secrets.yml.enc"). The content is
+// encrypted using aes-128-gcm and a hex encoded key read from EnvKey or
+// KeyPath.
+//
+//	f := crypto.EncryptedFile{
+//		ContentPath: "config/credentials.yml.enc",
+//		KeyPath:     "config/master.key",
+//		EnvKey:      "RAILS_MASTER_KEY",
+//	}
+//	yaml, err := f.Read()
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/EncryptedFile.html
+type EncryptedFile struct {
+	// ContentPath is the path of the encrypted file.
+	ContentPath string
+	// KeyPath is the path of the file containing the key.
+	KeyPath string
+	// EnvKey is the environment variable containing the key, it takes
+	// precedence over KeyPath.
+	EnvKey string
+}
+
+// GenerateEncryptedFileKey returns a new random key for an encrypted file,
+// like ActiveSupport::EncryptedFile.generate_key.
+func GenerateEncryptedFileKey() string {
+	return hex.EncodeToString(GenerateRandomKey(16))
+}
+
+// Key returns the hex encoded key of the file, read from EnvKey or
+// KeyPath. ErrMissingKey is returned if there is none.
+func (f *EncryptedFile) Key() (string, error) {
+	if f.EnvKey != "" {
+		if key := os.Getenv(f.EnvKey); key != "" {
+			return key, nil
+		}
+	}
+	if f.KeyPath == "" {
+		return "", ErrMissingKey
+	}
+	data, err := os.ReadFile(f.KeyPath)
+	if os.IsNotExist(err) {
+		return "", ErrMissingKey
+	}
+	if err != nil {
+		return "", err
+	}
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return "", ErrMissingKey
+	}
+	return key, nil
+}
+
+// Read decrypts the content of the file. ErrMissingContent is returned if
+// the file doesn't exist.
+func (f *EncryptedFile) Read() (string, error) {
+	data, err := os.ReadFile(f.ContentPath)
+	if os.IsNotExist(err) {
+		return "", ErrMissingContent
+	}
+	if err != nil {
+		return "", err
+	}
+	encryptor, err := f.encryptor()
+	if err != nil {
+		return "", err
+	}
+	var content string
+	err = encryptor.DecryptAndVerify(strings.TrimSpace(string(data)), &content)
+	return content, err
+}
+
+// Write encrypts the content into the file, replacing it atomically.
+func (f *EncryptedFile) Write(content string) error {
+	encryptor, err := f.encryptor()
+	if err != nil {
+		return err
+	}
+	encrypted, err := encryptor.EncryptAndSign(content)
+	if err != nil {
+		return err
+	}
+	tmp := f.ContentPath + ".tmp"
+	if err := os.WriteFile(tmp, []byte(encrypted), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.ContentPath)
+}
+
+// encryptor returns the encryptor of the file's key. The content is a
+// Marshal serialized string, like in Rails.
+func (f *EncryptedFile) encryptor() (*MessageEncryptor, error) {
+	hexKey, err := f.Key()
+	if err != nil {
+		return nil, err
+	}
+	key, err := hex.DecodeString(hexKey)
+	if err != nil || len(key) != 16 {
+		return nil, fmt.Errorf("crypto: the encrypted file key must be 32 hex characters, got %d", len(hexKey))
+	}
+	return &MessageEncryptor{Key: key, Cipher: EncryptedFileCipher, Serializer: MarshalMsgSerializer{}}, nil
+}
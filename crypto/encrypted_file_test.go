@@ -0,0 +1,104 @@
+package crypto
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+const (
+	encryptedFileKey = "4d5a1f6c0b2e8e9a7c3d1f2b4a6c8e0d"
+	// content encrypted by Rails with encryptedFileKey
+	railsEncryptedFile        = "ZKUdPtBmLejjm/BVnBABQPNuy4QVkSK6rU9ydcYkjIwtw2UUXVzHahy6SZlOdIpXQOkFnBZM1cSP/0sVww==--MDEyMzQ1Njc4OWFi--pS4iiRrzOX3I2Q9t4hqk7w==\n"
+	railsEncryptedFileContent = "secret_key_base: abc123\naws:\n  access_key_id: AKIA\n"
+)
+
+func TestEncryptedFile(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("EncryptedFile", func() {
+		var dir string
+		var f *EncryptedFile
+		g.BeforeEach(func() {
+			dir, _ = os.MkdirTemp("", "encrypted_file")
+			f = &EncryptedFile{
+				ContentPath: filepath.Join(dir, "secrets.yml.enc"),
+				KeyPath:     filepath.Join(dir, "secrets.key"),
+				EnvKey:      "GO_RAILS_YOURSELF_TEST_KEY",
+			}
+			os.WriteFile(f.ContentPath, []byte(railsEncryptedFile), 0600)
+			os.WriteFile(f.KeyPath, []byte(encryptedFileKey+"\n"), 0600)
+		})
+		g.AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		g.It("reads the files encrypted by Rails", func() {
+			content, err := f.Read()
+			g.Assert(err).Eql(nil)
+			g.Assert(content).Eql(railsEncryptedFileContent)
+		})
+
+		g.It("reads the key from the environment first", func() {
+			os.Setenv(f.EnvKey, "00000000000000000000000000000000")
+			defer os.Unsetenv(f.EnvKey)
+			key, err := f.Key()
+			g.Assert(err).Eql(nil)
+			g.Assert(key).Eql("00000000000000000000000000000000")
+			_, err = f.Read()
+			g.Assert(err != nil).IsTrue()
+		})
+
+		g.It("round trips content", func() {
+			g.Assert(f.Write("foo: bar\n")).Eql(nil)
+			content, err := f.Read()
+			g.Assert(err).Eql(nil)
+			g.Assert(content).Eql("foo: bar\n")
+			_, err = os.Stat(f.ContentPath + ".tmp")
+			g.Assert(os.IsNotExist(err)).IsTrue()
+		})
+
+		g.It("reports missing keys and content", func() {
+			os.Remove(f.KeyPath)
+			_, err := f.Read()
+			g.Assert(err).Eql(ErrMissingKey)
+			os.Remove(f.ContentPath)
+			_, err = f.Read()
+			g.Assert(err).Eql(ErrMissingContent)
+		})
+
+		g.It("refuses keys of the wrong length", func() {
+			os.WriteFile(f.KeyPath, []byte("abcd"), 0600)
+			g.Assert(f.Write("foo") != nil).IsTrue()
+		})
+	})
+
+	g.Describe("GenerateEncryptedFileKey", func() {
+		g.It("returns 32 hex characters", func() {
+			key := GenerateEncryptedFileKey()
+			g.Assert(len(key)).Eql(32)
+			g.Assert(strings.Trim(key, "0123456789abcdef")).Eql("")
+		})
+	})
+}
+
+func ExampleEncryptedFile() {
+	dir, _ := os.MkdirTemp("", "encrypted_file")
+	defer os.RemoveAll(dir)
+	os.WriteFile(filepath.Join(dir, "master.key"), []byte(encryptedFileKey), 0600)
+
+	f := EncryptedFile{
+		ContentPath: filepath.Join(dir, "credentials.yml.enc"),
+		KeyPath:     filepath.Join(dir, "master.key"),
+	}
+	f.Write("secret_key_base: abc123\n")
+	content, err := f.Read()
+	fmt.Print(content, err)
+	// Output:
+	// secret_key_base: abc123
+	// <nil>
+}
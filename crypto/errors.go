@@ -0,0 +1,57 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	// ErrInvalidSignature is returned when the signature or the auth tag of
+	// a message doesn't match, because it was tampered with or generated
+	// with another secret.
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrMalformedMessage is returned when a message can't be split in its
+	// parts or decoded.
+	ErrMalformedMessage = errors.New("malformed message")
+	// ErrNotConfigured is returned when an encryptor or a verifier misses
+	// a setting, or has an invalid one.
+	ErrNotConfigured = errors.New("not configured")
+	// ErrNotAPointer is returned when the target a message is read into
+	// isn't a non nil pointer, which couldn't be populated.
+	ErrNotAPointer = errors.New("crypto: the target must be a non nil pointer")
+)
+
+// messageError details one of the sentinel errors, which can be matched
+// using errors.Is:
+//
+//	if errors.Is(err, crypto.ErrInvalidSignature) {
+//		http.Error(w, "invalid session", http.StatusUnauthorized)
+//	}
+type messageError struct {
+	kind   error
+	detail string
+}
+
+func (e *messageError) Error() string { return e.detail }
+func (e *messageError) Unwrap() error { return e.kind }
+
+func invalidSignature(format string, args ...interface{}) error {
+	return &messageError{ErrInvalidSignature, fmt.Sprintf(format, args...)}
+}
+
+func malformed(format string, args ...interface{}) error {
+	return &messageError{ErrMalformedMessage, fmt.Sprintf(format, args...)}
+}
+
+func notConfigured(format string, args ...interface{}) error {
+	return &messageError{ErrNotConfigured, fmt.Sprintf(format, args...)}
+}
+
+// checkTarget returns ErrNotAPointer if target can't be populated.
+func checkTarget(target interface{}) error {
+	if v := reflect.ValueOf(target); v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("%w, got %T", ErrNotAPointer, target)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/franela/goblin"
+)
+
+func TestErrors(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Errors", func() {
+		key := GenerateRandomKey(32)
+		var out string
+
+		g.It("report tampered messages as invalid signatures", func() {
+			v := MessageVerifier{Secret: key, Serializer: JsonMsgSerializer{}}
+			token, _ := v.Generate("data")
+			err := v.Verify("x"+token, &out)
+			g.Assert(errors.Is(err, ErrInvalidSignature)).IsTrue()
+			g.Assert(err.Error()).Eql("Invalid signature - bad data (compare)")
+
+			for _, cipher := range []string{"aes-cbc", "aes-256-gcm"} {
+				e := MessageEncryptor{Key: key, SignKey: key, Cipher: cipher}
+				other := MessageEncryptor{Key: GenerateRandomKey(32), SignKey: GenerateRandomKey(32), Cipher: cipher}
+				msg, _ := other.EncryptAndSign("data")
+				g.Assert(errors.Is(e.DecryptAndVerify(msg, &out), ErrInvalidSignature)).IsTrue()
+			}
+		})
+
+		g.It("report malformed messages", func() {
+			v := MessageVerifier{Secret: key, Serializer: JsonMsgSerializer{}}
+			g.Assert(errors.Is(v.Verify("", &out), ErrMalformedMessage)).IsTrue()
+			g.Assert(errors.Is(v.Verify("bad", &out), ErrMalformedMessage)).IsTrue()
+
+			e := MessageEncryptor{Key: key, Cipher: "aes-256-gcm"}
+			g.Assert(errors.Is(e.DecryptAndVerify("bad", &out), ErrMalformedMessage)).IsTrue()
+		})
+
+		g.It("report broken configurations", func() {
+			v := MessageVerifier{Secret: key}
+			_, err := v.Generate("data")
+			g.Assert(errors.Is(err, ErrNotConfigured)).IsTrue()
+
+			e := MessageEncryptor{Key: key}
+			_, err = e.EncryptAndSign("data")
+			g.Assert(errors.Is(err, ErrNotConfigured)).IsTrue()
+
+			e = MessageEncryptor{Key: key, Cipher: "rot13"}
+			_, err = e.EncryptAndSign("data")
+			g.Assert(errors.Is(err, ErrNotConfigured)).IsTrue()
+
+			_, err = NewMessageEncryptor(key[:20], WithCipher("aes-256-gcm"))
+			g.Assert(errors.Is(err, ErrNotConfigured)).IsTrue()
+		})
+
+		g.It("report targets which aren't pointers", func() {
+			v := MessageVerifier{Secret: key, Serializer: JsonMsgSerializer{}}
+			token, _ := v.Generate("data")
+			err := v.Verify(token, out)
+			g.Assert(errors.Is(err, ErrNotAPointer)).IsTrue()
+			g.Assert(err.Error()).Eql("crypto: the target must be a non nil pointer, got string")
+			g.Assert(errors.Is(v.Verify(token, nil), ErrNotAPointer)).IsTrue()
+			g.Assert(errors.Is(v.Verify(token, (*string)(nil)), ErrNotAPointer)).IsTrue()
+
+			for _, cipher := range []string{"aes-cbc", "aes-256-gcm"} {
+				e := MessageEncryptor{Key: key, SignKey: key, Cipher: cipher, AllowUnsignedCBC: true}
+				msg, _ := e.EncryptAndSign("data")
+				g.Assert(errors.Is(e.DecryptAndVerify(msg, out), ErrNotAPointer)).IsTrue()
+				msg, _ = e.Encrypt("data")
+				g.Assert(errors.Is(e.Decrypt(msg, out), ErrNotAPointer)).IsTrue()
+			}
+		})
+
+		g.It("keep the expiration and purpose errors of signed messages", func() {
+			e := MessageEncryptor{Key: key, SignKey: key}
+			msg, _ := e.EncryptAndSignWithOptions("data", MessageOptions{Purpose: "login", ExpiresAt: time.Now().Add(-time.Hour)})
+			g.Assert(errors.Is(e.DecryptAndVerifyWithPurpose(msg, &out, "login"), ErrExpired)).IsTrue()
+			msg, _ = e.EncryptAndSignWithOptions("data", MessageOptions{Purpose: "login"})
+			g.Assert(errors.Is(e.DecryptAndVerifyWithPurpose(msg, &out, "reset"), ErrWrongPurpose)).IsTrue()
+		})
+	})
+}
+
+func ExampleErrInvalidSignature() {
+	v := MessageVerifier{Secret: []byte("secret"), Serializer: JsonMsgSerializer{}}
+	var data string
+	err := v.Verify("ImhlbGxvIg==--tampered", &data)
+	switch {
+	case errors.Is(err, ErrInvalidSignature), errors.Is(err, ErrMalformedMessage):
+		fmt.Println("401")
+	case err != nil:
+		fmt.Println("500")
+	}
+	// Output: 401
+}
@@ -0,0 +1,40 @@
+package crypto
+
+// DecryptAndVerify decrypts and verifies msg like
+// MessageEncryptor.DecryptAndVerify, returning the message as a T instead
+// of populating a pointer:
+//
+//	session, err := crypto.DecryptAndVerify[map[string]interface{}](e, cookie)
+func DecryptAndVerify[T any](e *MessageEncryptor, msg string) (T, error) {
+	return DecryptAndVerifyWithPurpose[T](e, msg, "")
+}
+
+// DecryptAndVerifyWithPurpose is like DecryptAndVerify for the messages
+// generated with a purpose, see MessageEncryptor.DecryptAndVerifyWithPurpose.
+func DecryptAndVerifyWithPurpose[T any](e *MessageEncryptor, msg, purpose string) (T, error) {
+	var value T
+	if err := e.DecryptAndVerifyWithPurpose(msg, &value, purpose); err != nil {
+		var zero T
+		return zero, err
+	}
+	return value, nil
+}
+
+// Verify verifies msg like MessageVerifier.Verify, returning the message
+// as a T instead of populating a pointer:
+//
+//	userID, err := crypto.Verify[int](v, token)
+func Verify[T any](v *MessageVerifier, msg string) (T, error) {
+	return VerifyWithPurpose[T](v, msg, "")
+}
+
+// VerifyWithPurpose is like Verify for the messages generated with a
+// purpose, see MessageVerifier.VerifyWithPurpose.
+func VerifyWithPurpose[T any](v *MessageVerifier, msg, purpose string) (T, error) {
+	var value T
+	if err := v.VerifyWithPurpose(msg, &value, purpose); err != nil {
+		var zero T
+		return zero, err
+	}
+	return value, nil
+}
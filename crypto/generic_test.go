@@ -0,0 +1,73 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestGenericHelpers(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("DecryptAndVerify[T]", func() {
+		key := GenerateRandomKey(32)
+		e := &MessageEncryptor{Key: key, Cipher: "aes-256-gcm"}
+
+		g.It("returns the decrypted message", func() {
+			type session struct {
+				UserID int    `json:"user_id"`
+				Locale string `json:"locale"`
+			}
+			msg, _ := e.EncryptAndSign(session{UserID: 42, Locale: "fr"})
+			s, err := DecryptAndVerify[session](e, msg)
+			g.Assert(err).Eql(nil)
+			g.Assert(s).Eql(session{UserID: 42, Locale: "fr"})
+		})
+
+		g.It("returns the zero value on errors", func() {
+			msg, _ := e.EncryptAndSign(map[string]string{"a": "b"})
+			m, err := DecryptAndVerify[map[string]string](e, "x"+msg)
+			g.Assert(err != nil).IsTrue()
+			g.Assert(m == nil).IsTrue()
+		})
+
+		g.It("checks the purpose", func() {
+			msg, _ := e.EncryptAndSignWithOptions("data", MessageOptions{Purpose: "login"})
+			data, err := DecryptAndVerifyWithPurpose[string](e, msg, "login")
+			g.Assert(err).Eql(nil)
+			g.Assert(data).Eql("data")
+			_, err = DecryptAndVerifyWithPurpose[string](e, msg, "reset")
+			g.Assert(errors.Is(err, ErrWrongPurpose)).IsTrue()
+		})
+	})
+
+	g.Describe("Verify[T]", func() {
+		v := &MessageVerifier{Secret: []byte("secret"), Serializer: JsonMsgSerializer{}}
+
+		g.It("returns the verified message", func() {
+			token, _ := v.Generate(42)
+			id, err := Verify[int](v, token)
+			g.Assert(err).Eql(nil)
+			g.Assert(id).Eql(42)
+		})
+
+		g.It("checks the purpose", func() {
+			token, _ := v.GenerateWithOptions(42, MessageOptions{Purpose: "reset"})
+			_, err := Verify[int](v, token)
+			g.Assert(errors.Is(err, ErrWrongPurpose)).IsTrue()
+			id, err := VerifyWithPurpose[int](v, token, "reset")
+			g.Assert(err).Eql(nil)
+			g.Assert(id).Eql(42)
+		})
+	})
+}
+
+func ExampleVerify() {
+	v := &MessageVerifier{Secret: []byte("Hey, I'm a secret!"), Serializer: JsonMsgSerializer{}}
+	token, _ := v.Generate([]string{"admin", "editor"})
+	roles, err := Verify[[]string](v, token)
+	fmt.Println(roles, err)
+	// Output: [admin editor] <nil>
+}
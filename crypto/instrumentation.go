@@ -0,0 +1,30 @@
+package crypto
+
+import "github.com/mattetti/goRailsYourself/notifications"
+
+// instrument runs fn as an event of the default notifier. The payload is
+// only built if the event is listened to.
+func instrument(name string, payload func() map[string]interface{}, fn func() error) error {
+	if !notifications.Listening(name) {
+		return fn()
+	}
+	return notifications.Instrument(name, payload(), func(map[string]interface{}) error {
+		return fn()
+	})
+}
+
+// payload returns the information about the encryptor sent with its events.
+func (crypt *MessageEncryptor) payload() map[string]interface{} {
+	if crypt == nil {
+		return nil
+	}
+	cipher := crypt.Cipher
+	if cipher == "" {
+		cipher = "aes-cbc"
+	}
+	return map[string]interface{}{"cipher": cipher}
+}
+
+func noPayload() map[string]interface{} {
+	return nil
+}
@@ -0,0 +1,40 @@
+package crypto
+
+import (
+	"testing"
+
+	. "github.com/franela/goblin"
+	"github.com/mattetti/goRailsYourself/notifications"
+)
+
+func TestInstrumentation(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Instrumentation", func() {
+		g.It("Should publish encryption and verification events", func() {
+			var events []notifications.Event
+			sub := notifications.Subscribe("", func(e notifications.Event) { events = append(events, e) })
+			defer notifications.Unsubscribe(sub)
+
+			e := MessageEncryptor{Key: GenerateRandomKey(32), SignKey: []byte("this is a secret!")}
+			msg, err := e.EncryptAndSign("my secret data")
+			g.Assert(err).Eql(nil)
+			var decrypted string
+			g.Assert(e.DecryptAndVerify(msg, &decrypted)).Eql(nil)
+			err = e.DecryptAndVerify("bad--data", &decrypted)
+			g.Assert(err != nil).IsTrue()
+
+			names := []string{}
+			for _, e := range events {
+				names = append(names, e.Name)
+			}
+			g.Assert(names).Eql([]string{
+				"generate.message_verifier", "encrypt_and_sign.message_encryptor",
+				"verify.message_verifier", "decrypt_and_verify.message_encryptor",
+				"verify.message_verifier", "decrypt_and_verify.message_encryptor",
+			})
+			g.Assert(events[1].Payload["cipher"]).Equal("aes-cbc")
+			g.Assert(events[5].Err).Equal(err)
+		})
+	})
+}
@@ -18,3 +18,11 @@ func (s JsonMsgSerializer) Serialize(v interface{}) (string, error) {
 func (s JsonMsgSerializer) Unserialize(data string, v interface{}) error {
 	return json.Unmarshal([]byte(data), v)
 }
+
+func (s JsonMsgSerializer) SerializeBytes(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (s JsonMsgSerializer) UnserializeBytes(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,92 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/scrypt"
+)
+
+// KDF is a key derivation function used by a KeyGenerator instead of
+// PBKDF2. The keys it derives can't be read by Rails, which only uses
+// PBKDF2.
+type KDF interface {
+	DeriveKey(secret, salt []byte, keySize int) ([]byte, error)
+}
+
+// ScryptParams derives keys using scrypt, which is memory hard. The zero
+// values are replaced by the parameters recommended for interactive logins
+// in 2017: N=32768, R=8 and P=1.
+type ScryptParams struct {
+	// N is the CPU and memory cost, a power of two.
+	N int
+	// R is the block size.
+	R int
+	// P is the parallelization.
+	P int
+}
+
+func (p ScryptParams) DeriveKey(secret, salt []byte, keySize int) ([]byte, error) {
+	n, r, par := p.N, p.R, p.P
+	if n == 0 {
+		n = 32768
+	}
+	if r == 0 {
+		r = 8
+	}
+	if par == 0 {
+		par = 1
+	}
+	return scrypt.Key(secret, salt, n, r, par, keySize)
+}
+
+// Argon2idParams derives keys using Argon2id, the winner of the Password
+// Hashing Competition. The zero values are replaced by the parameters
+// recommended by golang.org/x/crypto/argon2: a single pass over 64 MiB of
+// memory using 4 threads.
+type Argon2idParams struct {
+	// Time is the number of passes over the memory.
+	Time uint32
+	// Memory is the size of the memory in KiB.
+	Memory uint32
+	// Threads is the number of threads.
+	Threads uint8
+}
+
+func (p Argon2idParams) DeriveKey(secret, salt []byte, keySize int) ([]byte, error) {
+	time, memory, threads := p.Time, p.Memory, p.Threads
+	if time == 0 {
+		time = 1
+	}
+	if memory == 0 {
+		memory = 64 * 1024
+	}
+	if threads == 0 {
+		threads = 4
+	}
+	return argon2.IDKey(secret, salt, time, memory, threads, uint32(keySize)), nil
+}
+
+// HKDFParams derives keys using HKDF. It is fast, so the secret must be a
+// random key rather than a password, like a secret_key_base.
+type HKDFParams struct {
+	// Digest is the digest of HKDF, sha256 if not set.
+	Digest func() hash.Hash
+	// Info binds the keys to a context, in addition to the salt.
+	Info []byte
+}
+
+func (p HKDFParams) DeriveKey(secret, salt []byte, keySize int) ([]byte, error) {
+	digest := p.Digest
+	if digest == nil {
+		digest = sha256.New
+	}
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(hkdf.New(digest, secret, salt, p.Info), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,81 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestKDF(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("ScryptParams", func() {
+		g.It("derives the RFC 7914 test vector", func() {
+			key, err := ScryptParams{N: 1024, R: 8, P: 16}.DeriveKey([]byte("password"), []byte("NaCl"), 64)
+			g.Assert(err).Eql(nil)
+			g.Assert(hex.EncodeToString(key)).Eql("fdbabe1c9d3472007856e7190d01e9fe7c6ad7cbc8237830e77376634b3731622eaf30d92e22a3886ff109279d9830dac727afb94a83ee6d8360cbdfa2cc0640")
+		})
+
+		g.It("refuses invalid parameters", func() {
+			_, err := ScryptParams{N: 1000}.DeriveKey([]byte("password"), []byte("NaCl"), 32)
+			g.Assert(err != nil).IsTrue()
+			gen := KeyGenerator{Secret: "password", KDF: ScryptParams{N: 1000}}
+			g.Assert(gen.CacheGenerate([]byte("NaCl"), 32) == nil).IsTrue()
+			g.Assert(len(gen.cache)).Eql(0)
+			key, err := gen.GenerateErr([]byte("NaCl"), 32)
+			g.Assert(key == nil).IsTrue()
+			g.Assert(err.Error()).Eql("crypto: can't derive the key: scrypt: N must be > 1 and a power of 2")
+			key, err = gen.CacheGenerateErr([]byte("NaCl"), 32)
+			g.Assert(key == nil).IsTrue()
+			g.Assert(err != nil).IsTrue()
+			g.Assert(len(gen.cache)).Eql(0)
+		})
+	})
+
+	g.Describe("Argon2idParams", func() {
+		g.It("derives keys depending on the salt and the parameters", func() {
+			params := Argon2idParams{Memory: 64}
+			key, err := params.DeriveKey([]byte("password"), []byte("somesalt"), 32)
+			g.Assert(err).Eql(nil)
+			g.Assert(len(key)).Eql(32)
+			again, _ := params.DeriveKey([]byte("password"), []byte("somesalt"), 32)
+			g.Assert(again).Eql(key)
+			other, _ := params.DeriveKey([]byte("password"), []byte("othersalt"), 32)
+			g.Assert(bytes.Equal(other, key)).IsFalse()
+			other, _ = Argon2idParams{Memory: 64, Time: 2}.DeriveKey([]byte("password"), []byte("somesalt"), 32)
+			g.Assert(bytes.Equal(other, key)).IsFalse()
+		})
+	})
+
+	g.Describe("HKDFParams", func() {
+		g.It("derives the RFC 5869 test vector", func() {
+			secret := bytes.Repeat([]byte{0x0b}, 22)
+			salt, _ := hex.DecodeString("000102030405060708090a0b0c")
+			info, _ := hex.DecodeString("f0f1f2f3f4f5f6f7f8f9")
+			key, err := HKDFParams{Info: info}.DeriveKey(secret, salt, 42)
+			g.Assert(err).Eql(nil)
+			g.Assert(hex.EncodeToString(key)).Eql("3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865")
+		})
+	})
+
+	g.Describe("A KeyGenerator using a KDF", func() {
+		g.It("derives the keys with it", func() {
+			gen := KeyGenerator{Secret: "secret", KDF: HKDFParams{}}
+			key, _ := HKDFParams{}.DeriveKey([]byte("secret"), []byte("salt"), 32)
+			g.Assert(gen.Generate([]byte("salt"), 32)).Eql(key)
+			g.Assert(gen.CacheGenerate([]byte("salt"), 32)).Eql(key)
+			pbkdf2 := KeyGenerator{Secret: "secret"}
+			g.Assert(bytes.Equal(pbkdf2.Generate([]byte("salt"), 32), key)).IsFalse()
+		})
+	})
+}
+
+func ExampleHKDFParams() {
+	kg := KeyGenerator{Secret: "f7b5763636f4c1f3ff4bd444eacccca2", KDF: HKDFParams{Info: []byte("tenant 42")}}
+	key := kg.CacheGenerate([]byte("encrypted cookie"), 32)
+	fmt.Println(len(key))
+	// Output: 32
+}
@@ -0,0 +1,61 @@
+package crypto
+
+import (
+	"bytes"
+	"sync/atomic"
+)
+
+// cachingCipher is implemented by the ciphers whose key setup can be
+// kept by the encryptors, like the AES block and the GCM tables.
+type cachingCipher interface {
+	Cipher
+	// setup returns the cipher.Block or cipher.AEAD of key.
+	setup(key []byte) (interface{}, error)
+	// withSetup returns a copy of the cipher using v for key, or false if
+	// v wasn't set up by this kind of cipher.
+	withSetup(key []byte, v interface{}) (Cipher, bool)
+}
+
+// keyCache keeps the cipher.Block or cipher.AEAD an encryptor set up for
+// its last key, so the AES key schedule and the GCM tables aren't
+// computed for every message. A nil cache sets up the keys every time.
+type keyCache struct {
+	last atomic.Value // *keyEntry
+}
+
+type keyEntry struct {
+	key   []byte
+	value interface{}
+}
+
+// bind returns c set up for key, reusing the setup of the previous key
+// if it is the same.
+func (k *keyCache) bind(c Cipher, key []byte) (Cipher, error) {
+	cc, ok := c.(cachingCipher)
+	if !ok || k == nil {
+		return c, nil
+	}
+	if e, _ := k.last.Load().(*keyEntry); e != nil && bytes.Equal(e.key, key) {
+		if bound, ok := cc.withSetup(e.key, e.value); ok {
+			return bound, nil
+		}
+	}
+	v, err := cc.setup(key)
+	if err != nil {
+		return nil, err
+	}
+	e := &keyEntry{key: append([]byte(nil), key...), value: v}
+	k.last.Store(e)
+	bound, _ := cc.withSetup(e.key, e.value)
+	return bound, nil
+}
+
+// keyCache returns the key cache of the encryptor, shared with the copies
+// made by withProvidedKeys.
+func (crypt *MessageEncryptor) keyCache() *keyCache {
+	if k, ok := crypt.keys.Load().(*keyCache); ok {
+		return k
+	}
+	crypt.keys.CompareAndSwap(nil, &keyCache{})
+	return crypt.keys.Load().(*keyCache)
+}
@@ -0,0 +1,93 @@
+package crypto
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestKeyCache(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("keyCache", func() {
+		g.It("sets up the last key once", func() {
+			c := &keyCache{}
+			key := GenerateRandomKey(32)
+			b1, err := c.bind(aesGCM{keySize: 32}, key)
+			g.Assert(err).Eql(nil)
+			b2, _ := c.bind(aesGCM{keySize: 32}, append([]byte{}, key...))
+			g.Assert(b1.(aesGCM).gcm == b2.(aesGCM).gcm).IsTrue()
+
+			b3, _ := c.bind(aesGCM{keySize: 32}, GenerateRandomKey(32))
+			g.Assert(b1.(aesGCM).gcm != b3.(aesGCM).gcm).IsTrue()
+		})
+
+		g.It("sets up the key again for another kind of cipher", func() {
+			c := &keyCache{}
+			key := GenerateRandomKey(32)
+			c.bind(aesGCM{keySize: 32}, key)
+			cbc, err := c.bind(aesCBC{keySize: 32}, key)
+			g.Assert(err).Eql(nil)
+			g.Assert(cbc.(aesCBC).blk != nil).IsTrue()
+		})
+
+		g.It("keeps a copy of the key", func() {
+			c := &keyCache{}
+			key := GenerateRandomKey(32)
+			b1, _ := c.bind(aesCBC{keySize: 32}, key)
+			key[0]++
+			b2, _ := c.bind(aesCBC{keySize: 32}, key)
+			g.Assert(b1.(aesCBC).blk != b2.(aesCBC).blk).IsTrue()
+		})
+
+		g.It("doesn't cache the errors", func() {
+			c := &keyCache{}
+			_, err := c.bind(aesCBC{keySize: 32}, []byte("short"))
+			g.Assert(err != nil).IsTrue()
+			g.Assert(c.last.Load() == nil).IsTrue()
+		})
+
+		g.It("sets up the keys every time when nil", func() {
+			var c *keyCache
+			bound, err := c.bind(aesCBC{keySize: 32}, GenerateRandomKey(32))
+			g.Assert(err).Eql(nil)
+			g.Assert(bound.(aesCBC).blk == nil).IsTrue()
+		})
+	})
+
+	g.Describe("MessageEncryptor key cache", func() {
+		g.It("is per encryptor", func() {
+			key := GenerateRandomKey(32)
+			e1 := MessageEncryptor{Key: key, Cipher: "aes-256-gcm"}
+			e2 := MessageEncryptor{Key: key, Cipher: "aes-256-gcm"}
+			e1.EncryptAndSign("hello")
+			e2.EncryptAndSign("hello")
+			g.Assert(e1.keyCache() != e2.keyCache()).IsTrue()
+		})
+
+		g.It("follows the changes of the key", func() {
+			e := MessageEncryptor{Key: GenerateRandomKey(32), Cipher: "aes-256-gcm"}
+			e.EncryptAndSign("hello")
+			e.Key = GenerateRandomKey(32)
+			msg, err := e.EncryptAndSign("hello")
+			g.Assert(err).Eql(nil)
+			var out string
+			fresh := MessageEncryptor{Key: e.Key, Cipher: "aes-256-gcm"}
+			g.Assert(fresh.DecryptAndVerify(msg, &out)).Eql(nil)
+			g.Assert(out).Eql("hello")
+		})
+
+		g.It("is shared with the provided keys", func() {
+			key := GenerateRandomKey(32)
+			e := MessageEncryptor{Cipher: "aes-256-gcm", KeyProvider: KeyProviderFunc(func(context.Context, string) ([]byte, error) {
+				return key, nil
+			})}
+			_, err := e.EncryptAndSign("hello")
+			g.Assert(err).Eql(nil)
+			entry, _ := e.keyCache().last.Load().(*keyEntry)
+			g.Assert(entry != nil).IsTrue()
+			g.Assert(entry.key).Eql(key)
+		})
+	})
+}
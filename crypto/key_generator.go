@@ -2,37 +2,215 @@ package crypto
 
 import (
 	"golang.org/x/crypto/pbkdf2"
+	"container/list"
+	"context"
 	"crypto/sha1"
 	"fmt"
+	"hash"
+	"sync"
+	"time"
 )
 
-// KeyGenerator is a simple wrapper around a PBKDF2 implementation.
+// KeyGenerator is a simple wrapper around a PBKDF2 implementation, or
+// another KDF.
 // It can be used to derive a number of keys for various purposes from a given secret.
 // This lets applications have a single secure secret, but avoid reusing that
 // key in multiple incompatible contexts.
 type KeyGenerator struct {
-	Secret     string
-	Iterations int
-	cache      map[string][]byte
+	Secret string
+	// SecretProvider provides the secret, requested for SecretPurpose,
+	// instead of Secret when set, for instance to fetch secret_key_base
+	// from AWS KMS or Vault. Wrap it in a CachingKeyProvider so the secret
+	// isn't fetched for every key. The keys cached by CacheGenerate are
+	// dropped when the secret changes.
+	SecretProvider KeyProvider
+	SecretPurpose  string
+	Iterations     int
+	// HashDigest is the PBKDF2 digest, sha1 if not set. Rails 7+ apps use
+	// sha256 unless config.active_support.key_generator_hash_digest_class
+	// is set to OpenSSL::Digest::SHA1.
+	HashDigest func() hash.Hash
+	// KDF derives the keys instead of PBKDF2 when set, for instance
+	// ScryptParams{}, Argon2idParams{} or HKDFParams{}. Iterations and
+	// HashDigest are then ignored. PBKDF2 is the only KDF Rails supports.
+	KDF KDF
+	// MaxCacheEntries bounds the number of keys kept by CacheGenerate, which
+	// evicts the least recently used ones. The cache is unbounded if not set.
+	MaxCacheEntries int
+	// CacheTTL is how long CacheGenerate keeps the keys, forever if not set.
+	CacheTTL time.Duration
+
+	mu sync.Mutex
+	// secret is the sha1 digest of the secret the cached keys derive from.
+	secret [sha1.Size]byte
+	cache  map[cacheKey]*list.Element
+	// lru lists the cached keys, the most recently used first.
+	lru *list.List
+	now func() time.Time
+}
+
+// cacheKey identifies the keys cached by CacheGenerate.
+type cacheKey struct {
+	salt string
+	size int
+}
+
+type generatedKey struct {
+	name      cacheKey
+	key       []byte
+	expiresAt time.Time
 }
 
 // CacheGenerate() write through cache used to save generated keys.
+// It is safe for concurrent use.
+// It returns nil if the parameters of the KDF are invalid, see
+// CacheGenerateErr.
 func (g *KeyGenerator) CacheGenerate(salt []byte, keySize int) []byte {
-	key := fmt.Sprintf("%s%d", salt, keySize)
+	key, err := g.CacheGenerateErr(salt, keySize)
+	if err != nil {
+		return nil
+	}
+	return key
+}
+
+// CacheGenerateErr is like CacheGenerate but returns the error of the KDF
+// or of the SecretProvider rather than a nil key. Failed derivations aren't
+// cached. The keys are derived without holding the cache lock, so a slow
+// KDF doesn't block the lookups of the cached keys.
+func (g *KeyGenerator) CacheGenerateErr(salt []byte, keySize int) ([]byte, error) {
+	return g.CacheGenerateContext(context.Background(), salt, keySize)
+}
+
+// CacheGenerateContext is like CacheGenerateErr but passes ctx to the
+// SecretProvider.
+func (g *KeyGenerator) CacheGenerateContext(ctx context.Context, salt []byte, keySize int) ([]byte, error) {
+	secret, err := g.currentSecret(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha1.Sum(secret)
+	name := cacheKey{string(salt), keySize}
+	if key, ok := g.cached(sum, name); ok {
+		return key, nil
+	}
+	key, err := g.derive(secret, salt, keySize)
+	if err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.clock()
+	if sum != g.secret {
+		g.secret, g.cache, g.lru = sum, nil, nil
+	}
+	if elem := g.cache[name]; elem != nil {
+		// another goroutine derived the same key meanwhile
+		g.evict(elem)
+	}
 	if g.cache == nil {
-		g.cache = map[string][]byte{}
+		g.cache = map[cacheKey]*list.Element{}
+		g.lru = list.New()
+	}
+	entry := &generatedKey{name: name, key: key}
+	if g.CacheTTL > 0 {
+		entry.expiresAt = now.Add(g.CacheTTL)
+	}
+	g.cache[name] = g.lru.PushFront(entry)
+	for g.MaxCacheEntries > 0 && g.lru.Len() > g.MaxCacheEntries {
+		g.evict(g.lru.Back())
+	}
+	return entry.key, nil
+}
+
+// cached returns the cached key of name if it derives from the secret
+// and hasn't expired.
+func (g *KeyGenerator) cached(secret [sha1.Size]byte, name cacheKey) ([]byte, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	elem := g.cache[name]
+	if elem == nil || secret != g.secret {
+		return nil, false
 	}
-	if g.cache[key] == nil {
-		g.cache[key] = g.Generate(salt, keySize)
+	entry := elem.Value.(*generatedKey)
+	if !entry.expiresAt.IsZero() && !g.clock().Before(entry.expiresAt) {
+		g.evict(elem)
+		return nil, false
 	}
-	return g.cache[key]
+	g.lru.MoveToFront(elem)
+	return entry.key, true
+}
+
+func (g *KeyGenerator) clock() time.Time {
+	if g.now != nil {
+		return g.now()
+	}
+	return time.Now()
+}
+
+// Purge empties the cache of CacheGenerate, for instance after rotating the
+// secret.
+func (g *KeyGenerator) Purge() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.cache, g.lru = nil, nil
+}
+
+func (g *KeyGenerator) evict(elem *list.Element) {
+	g.lru.Remove(elem)
+	delete(g.cache, elem.Value.(*generatedKey).name)
 }
 
 // Generates a derived key based on a salt. rails default key size is 64.
+// It returns nil if the parameters of the KDF are invalid, see GenerateErr.
 func (g *KeyGenerator) Generate(salt []byte, keySize int) []byte {
-	// set a default
-	if g.Iterations == 0 {
-		g.Iterations = 1000 // rails 4 default when setting the session.
+	key, err := g.GenerateErr(salt, keySize)
+	if err != nil {
+		return nil
+	}
+	return key
+}
+
+// GenerateErr is like Generate but returns the error of the KDF or of the
+// SecretProvider rather than a nil key.
+func (g *KeyGenerator) GenerateErr(salt []byte, keySize int) ([]byte, error) {
+	return g.GenerateContext(context.Background(), salt, keySize)
+}
+
+// GenerateContext is like GenerateErr but passes ctx to the SecretProvider.
+func (g *KeyGenerator) GenerateContext(ctx context.Context, salt []byte, keySize int) ([]byte, error) {
+	secret, err := g.currentSecret(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return g.derive(secret, salt, keySize)
+}
+
+// currentSecret returns the secret of the SecretProvider, or Secret.
+func (g *KeyGenerator) currentSecret(ctx context.Context) ([]byte, error) {
+	if g.SecretProvider == nil {
+		return []byte(g.Secret), nil
+	}
+	return g.SecretProvider.GetKey(ctx, g.SecretPurpose)
+}
+
+// derive derives a key of secret using the KDF, or PBKDF2.
+func (g *KeyGenerator) derive(secret, salt []byte, keySize int) ([]byte, error) {
+	if g.KDF != nil {
+		key, err := g.KDF.DeriveKey(secret, salt, keySize)
+		if err != nil {
+			return nil, fmt.Errorf("crypto: can't derive the key: %w", err)
+		}
+		return key, nil
+	}
+	// set a default, without changing the generator which may be shared
+	iterations := g.Iterations
+	if iterations == 0 {
+		iterations = 1000 // rails 4 default when setting the session.
+	}
+	digest := g.HashDigest
+	if digest == nil {
+		digest = sha1.New
 	}
-	return pbkdf2.Key([]byte(g.Secret), salt, g.Iterations, keySize, sha1.New)
+	return pbkdf2.Key(secret, salt, iterations, keySize, digest), nil
 }
@@ -1,9 +1,12 @@
 package crypto
 
 import (
-	"fmt"
+	"crypto/sha256"
+	"encoding/hex"
 	. "github.com/franela/goblin"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestKegenerator_Generate(t *testing.T) {
@@ -26,8 +29,8 @@ func TestKegenerator_Generate(t *testing.T) {
 	g.Describe("Cache Generate a key", func() {
 		gen := KeyGenerator{Secret: "f7b5763636f4c1f3ff4bd444eacccca295d87b990cc104124017ad70550edcfd22b8e89465338254e0b608592a9aac29025440bfd9ce53579835ba06a86f85f9"}
 		g.It("caches the keys", func() {
-			key := func(s []byte) string {
-				return fmt.Sprintf("%s%d", s, 64)
+			key := func(s []byte) cacheKey {
+				return cacheKey{string(s), 64}
 			}
 			salt1 := []byte("encrypted cookie")
 			salt2 := []byte("signed cookie")
@@ -37,6 +40,109 @@ func TestKegenerator_Generate(t *testing.T) {
 			_ = gen.CacheGenerate(salt2, 64)
 			g.Assert(gen.cache[key(salt2)] != nil).IsTrue()
 		})
+
+		g.It("tells the salts and the sizes apart", func() {
+			g.Assert(gen.CacheGenerate([]byte("a1"), 6)).Eql(gen.Generate([]byte("a1"), 6))
+			g.Assert(gen.CacheGenerate([]byte("a"), 16)).Eql(gen.Generate([]byte("a"), 16))
+		})
+	})
+
+	g.Describe("A bounded key cache", func() {
+		g.It("evicts the least recently used keys", func() {
+			gen := KeyGenerator{Secret: "secret", Iterations: 1, MaxCacheEntries: 2}
+			a, b, c := []byte("a"), []byte("b"), []byte("c")
+			gen.CacheGenerate(a, 32)
+			gen.CacheGenerate(b, 32)
+			gen.CacheGenerate(a, 32)
+			gen.CacheGenerate(c, 32)
+			g.Assert(len(gen.cache)).Eql(2)
+			g.Assert(gen.cache[cacheKey{"a", 32}] != nil).IsTrue()
+			g.Assert(gen.cache[cacheKey{"b", 32}] == nil).IsTrue()
+			g.Assert(gen.CacheGenerate(b, 32)).Eql(gen.Generate(b, 32))
+		})
+
+		g.It("expires the keys after the TTL", func() {
+			now := time.Now()
+			gen := KeyGenerator{Secret: "secret", Iterations: 1, CacheTTL: time.Minute}
+			gen.now = func() time.Time { return now }
+			key := gen.CacheGenerate([]byte("a"), 32)
+			elem := gen.cache[cacheKey{"a", 32}]
+			now = now.Add(59 * time.Second)
+			g.Assert(gen.CacheGenerate([]byte("a"), 32)).Eql(key)
+			g.Assert(gen.cache[cacheKey{"a", 32}] == elem).IsTrue()
+			now = now.Add(time.Second)
+			g.Assert(gen.CacheGenerate([]byte("a"), 32)).Eql(key)
+			g.Assert(gen.cache[cacheKey{"a", 32}] != elem).IsTrue()
+		})
+
+		g.It("can be purged", func() {
+			gen := KeyGenerator{Secret: "secret", Iterations: 1}
+			gen.CacheGenerate([]byte("a"), 32)
+			gen.Purge()
+			g.Assert(len(gen.cache)).Eql(0)
+			g.Assert(len(gen.CacheGenerate([]byte("a"), 32))).Eql(32)
+		})
+
+		g.It("can be used concurrently", func() {
+			gen := KeyGenerator{Secret: "secret", Iterations: 1, MaxCacheEntries: 3}
+			var wg sync.WaitGroup
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					for j := 0; j < 50; j++ {
+						gen.CacheGenerate([]byte{byte((i + j) % 5)}, 32)
+					}
+				}(i)
+			}
+			wg.Wait()
+			g.Assert(len(gen.cache)).Eql(3)
+			g.Assert(gen.lru.Len()).Eql(3)
+		})
+
+		g.It("serves the cached keys while deriving others", func() {
+			kdf := &blockingKDF{started: make(chan struct{}), release: make(chan struct{})}
+			gen := KeyGenerator{Secret: "secret", KDF: kdf}
+			key := gen.CacheGenerate([]byte("fast"), 32)
+			done := make(chan []byte)
+			go func() { done <- gen.CacheGenerate([]byte("slow"), 32) }()
+			<-kdf.started
+			g.Assert(gen.CacheGenerate([]byte("fast"), 32)).Eql(key)
+			close(kdf.release)
+			g.Assert(len(<-done)).Eql(32)
+			g.Assert(len(gen.cache)).Eql(2)
+		})
+
+		g.It("doesn't change the generator", func() {
+			gen := KeyGenerator{Secret: "secret"}
+			gen.Generate([]byte("salt"), 32)
+			g.Assert(gen.Iterations).Eql(0)
+		})
+	})
+
+	g.Describe("Generating a key with another digest", func() {
+		g.It("uses the HashDigest", func() {
+			// PBKDF2-HMAC-SHA256 test vector from RFC 7914
+			gen := KeyGenerator{Secret: "passwd", Iterations: 1, HashDigest: sha256.New}
+			g.Assert(hex.EncodeToString(gen.Generate([]byte("salt"), 16))).Eql("55ac046e56e3089fec1691c22544b605")
+
+			sha1Gen := KeyGenerator{Secret: "passwd", Iterations: 1}
+			g.Assert(hex.EncodeToString(sha1Gen.Generate([]byte("salt"), 16)) != "55ac046e56e3089fec1691c22544b605").IsTrue()
+		})
 	})
 
 }
+
+// blockingKDF derives the keys of the "slow" salt once released.
+type blockingKDF struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (k *blockingKDF) DeriveKey(secret, salt []byte, keySize int) ([]byte, error) {
+	if string(salt) == "slow" {
+		close(k.started)
+		<-k.release
+	}
+	return HKDFParams{}.DeriveKey(secret, salt, keySize)
+}
@@ -0,0 +1,176 @@
+package crypto
+
+import (
+	"context"
+	"crypto/sha1"
+	"hash"
+	"sync"
+	"time"
+)
+
+// KeyProvider provides the keys of the encryptors, or the secret of a
+// KeyGenerator, for a purpose, for instance fetching them from AWS KMS,
+// Vault or another secret manager instead of wiring raw keys.
+type KeyProvider interface {
+	GetKey(ctx context.Context, purpose string) ([]byte, error)
+}
+
+// KeyProviderFunc adapts a function to the KeyProvider interface.
+type KeyProviderFunc func(ctx context.Context, purpose string) ([]byte, error)
+
+// GetKey calls f.
+func (f KeyProviderFunc) GetKey(ctx context.Context, purpose string) ([]byte, error) {
+	return f(ctx, purpose)
+}
+
+// CachingKeyProvider caches the keys of a provider for TTL, so a remote
+// secret manager isn't queried for every message while rotated keys are
+// still picked up once the cached ones expire. It can be used
+// concurrently.
+//
+//	keys := crypto.NewCachingKeyProvider(vaultProvider, 10*time.Minute)
+type CachingKeyProvider struct {
+	Provider KeyProvider
+	// TTL is how long the keys are cached, forever if zero.
+	TTL time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cachedKey
+	now     func() time.Time
+}
+
+type cachedKey struct {
+	key       []byte
+	fetchedAt time.Time
+}
+
+// NewCachingKeyProvider returns a provider caching the keys of provider.
+func NewCachingKeyProvider(provider KeyProvider, ttl time.Duration) *CachingKeyProvider {
+	return &CachingKeyProvider{Provider: provider, TTL: ttl}
+}
+
+// GetKey returns the cached key of the purpose, fetching it from the
+// underlying provider if it isn't cached or expired. The cache isn't
+// locked while fetching, so a slow fetch doesn't block the other purposes.
+func (p *CachingKeyProvider) GetKey(ctx context.Context, purpose string) ([]byte, error) {
+	p.mu.Lock()
+	now := time.Now()
+	if p.now != nil {
+		now = p.now()
+	}
+	entry, ok := p.entries[purpose]
+	p.mu.Unlock()
+	if ok && (p.TTL == 0 || now.Sub(entry.fetchedAt) < p.TTL) {
+		return entry.key, nil
+	}
+
+	key, err := p.Provider.GetKey(ctx, purpose)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.entries == nil {
+		p.entries = map[string]cachedKey{}
+	}
+	p.entries[purpose] = cachedKey{key: key, fetchedAt: now}
+	return key, nil
+}
+
+// Refresh drops the cached keys, for instance after a rotation, so they
+// are fetched again.
+func (p *CachingKeyProvider) Refresh() {
+	p.mu.Lock()
+	p.entries = nil
+	p.mu.Unlock()
+}
+
+// DerivedKeyProvider derives the keys from a secret provided by Secret,
+// like KeyGenerator derives them from secret_key_base: the purpose of the
+// keys is the salt.
+//
+//	e := crypto.MessageEncryptor{
+//		Cipher:      "aes-256-gcm",
+//		KeyProvider: &crypto.DerivedKeyProvider{Secret: secrets, SecretPurpose: "secret_key_base", HashDigest: sha256.New},
+//		KeyPurpose:  "authenticated encrypted cookie",
+//	}
+type DerivedKeyProvider struct {
+	// Secret provides the secret, such as secret_key_base.
+	Secret KeyProvider
+	// SecretPurpose is the purpose the secret is requested for.
+	SecretPurpose string
+	// Iterations and HashDigest configure the derivation like the fields
+	// of KeyGenerator, 1000 iterations of sha1 if not set.
+	Iterations int
+	HashDigest func() hash.Hash
+	// KeySize is the size of the derived keys, 32 if not set.
+	KeySize int
+
+	mu sync.Mutex
+	// secret is the sha1 digest of the secret the cached keys derive from.
+	secret [sha1.Size]byte
+	cache  map[string][]byte
+}
+
+// GetKey derives the key of the purpose from the current secret. The
+// derived keys are cached as long as the secret doesn't change, the keys
+// of the previous secrets are dropped.
+func (p *DerivedKeyProvider) GetKey(ctx context.Context, purpose string) ([]byte, error) {
+	secret, err := p.Secret.GetKey(ctx, p.SecretPurpose)
+	if err != nil {
+		return nil, err
+	}
+	size := p.KeySize
+	if size == 0 {
+		size = 32
+	}
+	sum := sha1.Sum(secret)
+
+	p.mu.Lock()
+	key, ok := p.cache[purpose]
+	ok = ok && sum == p.secret
+	p.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	// derive the key without blocking the lookups of the cached ones
+	kg := KeyGenerator{Secret: string(secret), Iterations: p.Iterations, HashDigest: p.HashDigest}
+	key, err = kg.GenerateErr([]byte(purpose), size)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if sum != p.secret {
+		p.secret, p.cache = sum, nil
+	}
+	if p.cache == nil {
+		p.cache = map[string][]byte{}
+	}
+	p.cache[purpose] = key
+	return key, nil
+}
+
+// withProvidedKeys returns a copy of the encryptor using the keys its
+// KeyProvider returns for ctx, or the encryptor itself if it has none.
+func (crypt *MessageEncryptor) withProvidedKeys(ctx context.Context) (*MessageEncryptor, error) {
+	if crypt.KeyProvider == nil {
+		return crypt, nil
+	}
+	key, err := crypt.KeyProvider.GetKey(ctx, crypt.KeyPurpose)
+	if err != nil {
+		return nil, err
+	}
+	// the copy shares the key cache of the encryptor
+	crypt.keyCache()
+	c := *crypt
+	c.Key, c.KeyProvider = key, nil
+	if crypt.SignKeyPurpose != "" {
+		if c.SignKey, err = crypt.KeyProvider.GetKey(ctx, crypt.SignKeyPurpose); err != nil {
+			return nil, err
+		}
+		c.Verifier = nil
+	}
+	return &c, nil
+}
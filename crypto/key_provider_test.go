@@ -0,0 +1,222 @@
+package crypto
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/franela/goblin"
+)
+
+// countingProvider returns the current secret and counts the calls.
+type countingProvider struct {
+	secret []byte
+	calls  int
+}
+
+func (p *countingProvider) GetKey(ctx context.Context, purpose string) ([]byte, error) {
+	p.calls++
+	return p.secret, nil
+}
+
+func TestKeyProvider(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("CachingKeyProvider", func() {
+		g.It("caches the keys until they expire", func() {
+			source := &countingProvider{secret: []byte("secret")}
+			p := NewCachingKeyProvider(source, time.Minute)
+			now := time.Now()
+			p.now = func() time.Time { return now }
+
+			p.GetKey(context.Background(), "a")
+			p.GetKey(context.Background(), "a")
+			g.Assert(source.calls).Eql(1)
+			p.GetKey(context.Background(), "b")
+			g.Assert(source.calls).Eql(2)
+
+			now = now.Add(time.Minute)
+			source.secret = []byte("rotated")
+			key, _ := p.GetKey(context.Background(), "a")
+			g.Assert(string(key)).Eql("rotated")
+			g.Assert(source.calls).Eql(3)
+		})
+
+		g.It("fetches the keys again once refreshed", func() {
+			source := &countingProvider{secret: []byte("secret")}
+			p := NewCachingKeyProvider(source, 0)
+			p.GetKey(context.Background(), "a")
+			p.Refresh()
+			p.GetKey(context.Background(), "a")
+			g.Assert(source.calls).Eql(2)
+		})
+
+		g.It("doesn't cache errors", func() {
+			fail := errors.New("unavailable")
+			p := NewCachingKeyProvider(KeyProviderFunc(func(context.Context, string) ([]byte, error) {
+				return nil, fail
+			}), time.Minute)
+			_, err := p.GetKey(context.Background(), "a")
+			g.Assert(err).Eql(fail)
+			g.Assert(len(p.entries)).Eql(0)
+		})
+
+		g.It("serves the cached keys while fetching others", func() {
+			started, release := make(chan struct{}), make(chan struct{})
+			p := NewCachingKeyProvider(KeyProviderFunc(func(ctx context.Context, purpose string) ([]byte, error) {
+				if purpose == "slow" {
+					close(started)
+					<-release
+				}
+				return []byte(purpose), nil
+			}), 0)
+			p.GetKey(context.Background(), "fast")
+			done := make(chan []byte)
+			go func() {
+				key, _ := p.GetKey(context.Background(), "slow")
+				done <- key
+			}()
+			<-started
+			key, err := p.GetKey(context.Background(), "fast")
+			g.Assert(err).Eql(nil)
+			g.Assert(string(key)).Eql("fast")
+			close(release)
+			g.Assert(string(<-done)).Eql("slow")
+		})
+	})
+
+	g.Describe("KeyGenerator with a SecretProvider", func() {
+		g.It("derives the keys from the provided secret", func() {
+			source := &countingProvider{secret: []byte("secret_key_base")}
+			kg := KeyGenerator{SecretProvider: source, SecretPurpose: "secret_key_base", Iterations: 1}
+			raw := KeyGenerator{Secret: "secret_key_base", Iterations: 1}
+			key, err := kg.GenerateErr([]byte("salt"), 32)
+			g.Assert(err).Eql(nil)
+			g.Assert(key).Eql(raw.Generate([]byte("salt"), 32))
+			g.Assert(kg.CacheGenerate([]byte("salt"), 32)).Eql(key)
+		})
+
+		g.It("drops the cached keys when the secret changes", func() {
+			source := &countingProvider{secret: []byte("secret_key_base")}
+			kg := KeyGenerator{SecretProvider: source, Iterations: 1}
+			kg.CacheGenerate([]byte("a"), 32)
+			kg.CacheGenerate([]byte("b"), 32)
+			source.secret = []byte("rotated secret_key_base")
+			key := kg.CacheGenerate([]byte("a"), 32)
+			g.Assert(len(kg.cache)).Eql(1)
+			raw := KeyGenerator{Secret: "rotated secret_key_base", Iterations: 1}
+			g.Assert(key).Eql(raw.Generate([]byte("a"), 32))
+		})
+
+		g.It("returns the errors of the provider", func() {
+			fail := errors.New("unavailable")
+			kg := KeyGenerator{SecretProvider: KeyProviderFunc(func(context.Context, string) ([]byte, error) {
+				return nil, fail
+			})}
+			_, err := kg.CacheGenerateContext(context.Background(), []byte("a"), 32)
+			g.Assert(err).Eql(fail)
+			g.Assert(kg.Generate([]byte("a"), 32) == nil).IsTrue()
+		})
+	})
+
+	g.Describe("DerivedKeyProvider", func() {
+		g.It("derives the keys like KeyGenerator", func() {
+			p := &DerivedKeyProvider{Secret: &countingProvider{secret: []byte("secret_key_base")}, HashDigest: sha256.New}
+			key, err := p.GetKey(context.Background(), "authenticated encrypted cookie")
+			g.Assert(err).Eql(nil)
+			kg := KeyGenerator{Secret: "secret_key_base", HashDigest: sha256.New}
+			g.Assert(key).Eql(kg.Generate([]byte("authenticated encrypted cookie"), 32))
+		})
+
+		g.It("drops the keys of the previous secrets", func() {
+			source := &countingProvider{secret: []byte("secret_key_base")}
+			p := &DerivedKeyProvider{Secret: source}
+			p.GetKey(context.Background(), "a")
+			p.GetKey(context.Background(), "b")
+			g.Assert(len(p.cache)).Eql(2)
+			source.secret = []byte("rotated secret_key_base")
+			key, _ := p.GetKey(context.Background(), "a")
+			g.Assert(len(p.cache)).Eql(1)
+			kg := KeyGenerator{Secret: "rotated secret_key_base"}
+			g.Assert(key).Eql(kg.Generate([]byte("a"), 32))
+		})
+	})
+
+	g.Describe("MessageEncryptor with a KeyProvider", func() {
+		source := &countingProvider{secret: []byte("secret_key_base")}
+		keys := &DerivedKeyProvider{Secret: source, HashDigest: sha256.New}
+
+		g.It("encrypts using the provided keys", func() {
+			for _, cipher := range []string{"aes-256-gcm", "aes-cbc"} {
+				e := MessageEncryptor{Cipher: cipher, KeyProvider: keys, KeyPurpose: "encrypted", SignKeyPurpose: "signed"}
+				msg, err := e.EncryptAndSign("hello")
+				g.Assert(err).Eql(nil)
+				kg := KeyGenerator{Secret: "secret_key_base", HashDigest: sha256.New}
+				raw := MessageEncryptor{Cipher: cipher, Key: kg.Generate([]byte("encrypted"), 32), SignKey: kg.Generate([]byte("signed"), 32)}
+				var s string
+				g.Assert(raw.DecryptAndVerify(msg, &s)).Eql(nil)
+				g.Assert(s).Eql("hello")
+			}
+		})
+
+		g.It("uses the rotated keys", func() {
+			e := MessageEncryptor{Cipher: "aes-256-gcm", KeyProvider: keys, KeyPurpose: "encrypted"}
+			msg, _ := e.EncryptAndSign("hello")
+			source.secret = []byte("rotated secret_key_base")
+			var s string
+			g.Assert(e.DecryptAndVerify(msg, &s) != nil).IsTrue()
+			kg := KeyGenerator{Secret: "secret_key_base", HashDigest: sha256.New}
+			e.Rotate(kg.Generate([]byte("encrypted"), 32), "aes-256-gcm", nil)
+			g.Assert(e.DecryptAndVerify(msg, &s)).Eql(nil)
+		})
+
+		g.It("returns the errors of the provider", func() {
+			fail := errors.New("unavailable")
+			e := MessageEncryptor{Cipher: "aes-256-gcm", KeyProvider: KeyProviderFunc(func(context.Context, string) ([]byte, error) {
+				return nil, fail
+			})}
+			_, err := e.EncryptAndSign("hello")
+			g.Assert(err).Eql(fail)
+		})
+
+		g.It("passes the context to the provider", func() {
+			e := MessageEncryptor{Cipher: "aes-256-gcm", KeyPurpose: "encrypted", KeyProvider: KeyProviderFunc(func(ctx context.Context, purpose string) ([]byte, error) {
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+				return keys.GetKey(ctx, purpose)
+			})}
+			msg, err := e.EncryptAndSignContext(context.Background(), "hello", MessageOptions{Purpose: "greeting"})
+			g.Assert(err).Eql(nil)
+			var s string
+			g.Assert(e.DecryptAndVerifyContext(context.Background(), msg, &s, "greeting")).Eql(nil)
+			g.Assert(s).Eql("hello")
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err = e.EncryptAndSignContext(ctx, "hello", MessageOptions{})
+			g.Assert(err).Eql(context.Canceled)
+			g.Assert(e.DecryptAndVerifyContext(ctx, msg, &s, "greeting")).Eql(context.Canceled)
+		})
+	})
+}
+
+func ExampleDerivedKeyProvider() {
+	// fetches secret_key_base from a secret manager
+	secrets := KeyProviderFunc(func(ctx context.Context, purpose string) ([]byte, error) {
+		return []byte("f7b5763636f4c1f3ff4bd444eacccca295d87b990cc104124017ad70550edcfd22b8e89465338254e0b608592a9aac29025440bfd9ce53579835ba06a86f85f9"), nil
+	})
+	e := MessageEncryptor{
+		Cipher:      "aes-256-gcm",
+		KeyProvider: &DerivedKeyProvider{Secret: NewCachingKeyProvider(secrets, 10*time.Minute), SecretPurpose: "secret_key_base"},
+		KeyPurpose:  "authenticated encrypted cookie",
+	}
+
+	var session map[string]interface{}
+	err := e.DecryptAndVerify("Co+XxC9PK1ptoHftqua6C3PNrlvk4EA09IpKho+wk5qbMi4jrl6SS2g6xexK68b8kjKWqXzCcT/ZjkbAO/0Sxm01JIK0zY/qGa56ogFaVViZKgaCGlSQYDWrVDm3mCSTlTzHDl3nrIjMffwNEn2x5IPHaQQoR0skkv3A17zejE4d18pRqRYaCuZLg2H04HWYv0Y/s88Kurmevw8w/8xUwLIV8P3SpszfMHEU--Cs17rTBCsResqqC5--ym0c0ZE+ts7wExyw/t35QA==", &session)
+	fmt.Println(session["session_id"], err)
+	// Output: b2d63c07ea7a9d58e415e3672e3f31a2 <nil>
+}
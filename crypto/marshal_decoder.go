@@ -0,0 +1,441 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errMarshalTruncated = errors.New("marshal: truncated data")
+
+// marshalDecoder reads Ruby Marshal data, keeping the symbols and objects
+// already read so the links to them can be resolved.
+type marshalDecoder struct {
+	data    []byte
+	pos     int
+	symbols []string
+	objects []interface{}
+}
+
+func (d *marshalDecoder) readByte() (byte, error) {
+	if d.pos >= len(d.data) {
+		return 0, errMarshalTruncated
+	}
+	b := d.data[d.pos]
+	d.pos++
+	return b, nil
+}
+
+// readInt reads a packed integer.
+func (d *marshalDecoder) readInt() (int, error) {
+	b, err := d.readByte()
+	if err != nil {
+		return 0, err
+	}
+	c := int(int8(b))
+	switch {
+	case c == 0:
+		return 0, nil
+	case c > 4:
+		return c - 5, nil
+	case c < -4:
+		return c + 5, nil
+	}
+
+	size := c
+	if size < 0 {
+		size = -size
+	}
+	n := 0
+	for i := 0; i < size; i++ {
+		b, err := d.readByte()
+		if err != nil {
+			return 0, err
+		}
+		n |= int(b) << (8 * i)
+	}
+	if c < 0 {
+		n -= 1 << (8 * size)
+	}
+	return n, nil
+}
+
+func (d *marshalDecoder) readBytes() ([]byte, error) {
+	n, err := d.readInt()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 || d.pos+n > len(d.data) {
+		return nil, errMarshalTruncated
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// readSymbol reads a symbol or a link to a symbol already read.
+func (d *marshalDecoder) readSymbol() (string, error) {
+	b, err := d.readByte()
+	if err != nil {
+		return "", err
+	}
+	switch b {
+	case ':':
+		name, err := d.readBytes()
+		if err != nil {
+			return "", err
+		}
+		d.symbols = append(d.symbols, string(name))
+		return string(name), nil
+	case ';':
+		i, err := d.readInt()
+		if err != nil {
+			return "", err
+		}
+		if i < 0 || i >= len(d.symbols) {
+			return "", fmt.Errorf("marshal: bad symbol link %d", i)
+		}
+		return d.symbols[i], nil
+	case 'I':
+		// symbols with a non ASCII name carry their encoding
+		name, err := d.readSymbol()
+		if err != nil {
+			return "", err
+		}
+		_, err = d.readIvars()
+		return name, err
+	}
+	return "", fmt.Errorf("marshal: expected a symbol, got %q", b)
+}
+
+// register reserves the index of an object in the object table, Ruby
+// registering the objects before their content.
+func (d *marshalDecoder) register() int {
+	d.objects = append(d.objects, nil)
+	return len(d.objects) - 1
+}
+
+// readIvars reads the instance variables following an object.
+func (d *marshalDecoder) readIvars() (map[string]interface{}, error) {
+	n, err := d.readInt()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 || n > len(d.data)-d.pos {
+		return nil, errMarshalTruncated
+	}
+	ivars := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		name, err := d.readSymbol()
+		if err != nil {
+			return nil, err
+		}
+		value, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		ivars[name] = value
+	}
+	return ivars, nil
+}
+
+func (d *marshalDecoder) decode() (interface{}, error) {
+	b, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+	switch b {
+	case '0':
+		return nil, nil
+	case 'T':
+		return true, nil
+	case 'F':
+		return false, nil
+	case 'i':
+		return d.readInt()
+	case ':', ';':
+		d.pos--
+		name, err := d.readSymbol()
+		return RubySymbol(name), err
+	case '@':
+		i, err := d.readInt()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || i >= len(d.objects) {
+			return nil, fmt.Errorf("marshal: bad object link %d", i)
+		}
+		return d.objects[i], nil
+	case 'I':
+		return d.decodeWithIvars()
+	case '"':
+		index := d.register()
+		s, err := d.readBytes()
+		d.objects[index] = string(s)
+		return string(s), err
+	case 'f':
+		index := d.register()
+		f, err := d.readFloat()
+		d.objects[index] = f
+		return f, err
+	case 'l':
+		index := d.register()
+		n, err := d.readBignum()
+		if err != nil {
+			return nil, err
+		}
+		if n.IsInt64() && int64(int(n.Int64())) == n.Int64() {
+			d.objects[index] = int(n.Int64())
+		} else {
+			d.objects[index] = n
+		}
+		return d.objects[index], nil
+	case '[':
+		return d.decodeArray()
+	case '{', '}':
+		return d.decodeHash(b == '}')
+	case 'C':
+		// subclasses of String, Array and Hash such as
+		// HashWithIndifferentAccess are read as their parent class
+		if _, err := d.readSymbol(); err != nil {
+			return nil, err
+		}
+		return d.decode()
+	case 'e':
+		// objects extended with a module
+		if _, err := d.readSymbol(); err != nil {
+			return nil, err
+		}
+		return d.decode()
+	case 'o', 'S':
+		return d.decodeObject()
+	case 'u':
+		return d.decodeUserDefined(nil)
+	case 'U':
+		index := d.register()
+		class, err := d.readSymbol()
+		if err != nil {
+			return nil, err
+		}
+		o := &RubyObject{Class: class}
+		d.objects[index] = o
+		o.Data, err = d.decode()
+		return o, err
+	case '/':
+		index := d.register()
+		source, err := d.readBytes()
+		if err != nil {
+			return nil, err
+		}
+		_, err = d.readByte()
+		d.objects[index] = string(source)
+		return string(source), err
+	case 'c', 'm', 'M':
+		index := d.register()
+		name, err := d.readBytes()
+		d.objects[index] = string(name)
+		return string(name), err
+	}
+	return nil, fmt.Errorf("marshal: unsupported type %q", b)
+}
+
+// decodeWithIvars reads an object followed by instance variables, usually
+// a string and its encoding.
+func (d *marshalDecoder) decodeWithIvars() (interface{}, error) {
+	if d.pos < len(d.data) && d.data[d.pos] == 'u' {
+		d.pos++
+		return d.decodeUserDefined(d.readIvars)
+	}
+	value, err := d.decode()
+	if err != nil {
+		return nil, err
+	}
+	_, err = d.readIvars()
+	return value, err
+}
+
+func (d *marshalDecoder) readFloat() (float64, error) {
+	b, err := d.readBytes()
+	if err != nil {
+		return 0, err
+	}
+	// Ruby 1.8 appended the mantissa bits after a NUL byte
+	s := strings.SplitN(string(b), "\x00", 2)[0]
+	return strconv.ParseFloat(s, 64)
+}
+
+func (d *marshalDecoder) readBignum() (*big.Int, error) {
+	sign, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+	n, err := d.readInt()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 || d.pos+2*n > len(d.data) {
+		return nil, errMarshalTruncated
+	}
+	le := d.data[d.pos : d.pos+2*n]
+	d.pos += 2 * n
+	be := make([]byte, len(le))
+	for i, b := range le {
+		be[len(le)-1-i] = b
+	}
+	v := new(big.Int).SetBytes(be)
+	if sign == '-' {
+		v.Neg(v)
+	}
+	return v, nil
+}
+
+func (d *marshalDecoder) decodeArray() (interface{}, error) {
+	index := d.register()
+	n, err := d.readInt()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 || n > len(d.data)-d.pos {
+		return nil, errMarshalTruncated
+	}
+	array := make([]interface{}, n)
+	for i := range array {
+		if array[i], err = d.decode(); err != nil {
+			return nil, err
+		}
+	}
+	d.objects[index] = array
+	return array, nil
+}
+
+// decodeHash reads a hash, ignoring its default value. The keys which
+// aren't strings or symbols are formatted with fmt.
+func (d *marshalDecoder) decodeHash(withDefault bool) (interface{}, error) {
+	index := d.register()
+	n, err := d.readInt()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 || n > len(d.data)-d.pos {
+		return nil, errMarshalTruncated
+	}
+	hash := make(map[string]interface{}, n)
+	d.objects[index] = hash
+	for i := 0; i < n; i++ {
+		key, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		value, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		switch k := key.(type) {
+		case string:
+			hash[k] = value
+		case RubySymbol:
+			hash[string(k)] = value
+		default:
+			hash[fmt.Sprint(k)] = value
+		}
+	}
+	if withDefault {
+		if _, err := d.decode(); err != nil {
+			return nil, err
+		}
+	}
+	return hash, nil
+}
+
+// decodeObject reads an object or a struct, both being a class name
+// followed by named values.
+func (d *marshalDecoder) decodeObject() (interface{}, error) {
+	index := d.register()
+	class, err := d.readSymbol()
+	if err != nil {
+		return nil, err
+	}
+	o := &RubyObject{Class: class}
+	d.objects[index] = o
+	ivars, err := d.readIvars()
+	if err != nil {
+		return nil, err
+	}
+	o.Ivars = make(map[string]interface{}, len(ivars))
+	for name, value := range ivars {
+		o.Ivars[strings.TrimPrefix(name, "@")] = value
+	}
+	return o, nil
+}
+
+// decodeUserDefined reads an object dumped with _dump. Like Ruby, the
+// object is registered after its instance variables.
+func (d *marshalDecoder) decodeUserDefined(readIvars func() (map[string]interface{}, error)) (interface{}, error) {
+	class, err := d.readSymbol()
+	if err != nil {
+		return nil, err
+	}
+	data, err := d.readBytes()
+	if err != nil {
+		return nil, err
+	}
+	var ivars map[string]interface{}
+	if readIvars != nil {
+		if ivars, err = readIvars(); err != nil {
+			return nil, err
+		}
+	}
+
+	var value interface{}
+	if class == "Time" {
+		if value, err = loadTime(data, ivars); err != nil {
+			return nil, err
+		}
+	} else {
+		value = &RubyObject{Class: class, Data: string(data)}
+	}
+	d.objects = append(d.objects, value)
+	return value, nil
+}
+
+// loadTime reads a Time dumped by Time#_dump.
+func loadTime(data []byte, ivars map[string]interface{}) (time.Time, error) {
+	if len(data) != 8 {
+		return time.Time{}, errors.New("marshal: bad Time data")
+	}
+	var p, s uint32
+	for i := 0; i < 4; i++ {
+		p |= uint32(data[i]) << (8 * i)
+		s |= uint32(data[4+i]) << (8 * i)
+	}
+
+	var t time.Time
+	if p&(1<<31) == 0 {
+		// old format: seconds and microseconds since the epoch
+		t = time.Unix(int64(p), int64(s)*1000).UTC()
+	} else {
+		year := int(p>>14&0xffff) + 1900
+		month := time.Month(p>>10&0xf) + 1
+		day := int(p >> 5 & 0x1f)
+		hour := int(p & 0x1f)
+		min := int(s >> 26 & 0x3f)
+		sec := int(s >> 20 & 0x3f)
+		usec := int(s & 0xfffff)
+		nsec := usec * 1000
+		if num, ok := ivars["nano_num"].(int); ok {
+			if den, ok := ivars["nano_den"].(int); ok && den != 0 {
+				nsec += num / den
+			}
+		}
+		t = time.Date(year, month, day, hour, min, sec, nsec, time.UTC)
+	}
+
+	if offset, ok := ivars["offset"].(int); ok {
+		zone, _ := ivars["zone"].(string)
+		t = t.In(time.FixedZone(zone, offset))
+	}
+	return t, nil
+}
@@ -0,0 +1,419 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MarshalMsgSerializer reads and writes Ruby Marshal 4.8 payloads, the
+// format of the sessions of Rails apps still using the default Marshal
+// cookie serializer.
+//
+// Ruby values are unserialized as:
+//
+//   - nil, true and false as nil and bools
+//   - integers as ints, or *big.Int if they don't fit
+//   - floats as float64
+//   - strings as strings, whatever their encoding
+//   - symbols as RubySymbol, except for hash keys
+//   - arrays as []interface{}
+//   - hashes, including HashWithIndifferentAccess, as map[string]interface{}
+//   - Time as time.Time
+//   - other objects and structs as *RubyObject
+//
+// When the target isn't a *interface{}, the unserialized value is copied
+// into it using encoding/json, so structs with json tags can be used.
+// Serialize writes the same types and converts the other values, such as
+// structs, using encoding/json first.
+//
+// Only unserialize data you trust: the payload must be verified or
+// authenticated before reaching the serializer.
+type MarshalMsgSerializer struct{}
+
+// RubySymbol is a Ruby symbol.
+type RubySymbol string
+
+// RubyObject is a Ruby object or struct which has no Go equivalent.
+type RubyObject struct {
+	// Class is the name of the class of the object.
+	Class string `json:"class"`
+	// Ivars are the instance variables of the object, without their @
+	// prefix, or the members of a struct.
+	Ivars map[string]interface{} `json:"ivars,omitempty"`
+	// Data is the data of an object dumped with _dump or marshal_dump.
+	Data interface{} `json:"data,omitempty"`
+}
+
+func (s MarshalMsgSerializer) Serialize(v interface{}) (string, error) {
+	e := &marshalEncoder{symbols: map[string]int{}}
+	e.buf.Write([]byte{4, 8})
+	if err := e.encode(v); err != nil {
+		return "", err
+	}
+	return e.buf.String(), nil
+}
+
+func (s MarshalMsgSerializer) Unserialize(data string, v interface{}) error {
+	d := &marshalDecoder{data: []byte(data)}
+	if len(d.data) < 2 || d.data[0] != 4 || d.data[1] > 8 {
+		return errors.New("marshal: incompatible format version")
+	}
+	d.pos = 2
+	value, err := d.decode()
+	if err != nil {
+		return err
+	}
+	return assignGeneric(value, v)
+}
+
+// assignGeneric copies a generic value into v using encoding/json, unless
+// v is a *interface{}.
+func assignGeneric(value interface{}, v interface{}) error {
+	if target, ok := v.(*interface{}); ok {
+		*target = value
+		return nil
+	}
+	if reflect.TypeOf(v) == nil || reflect.TypeOf(v).Kind() != reflect.Ptr {
+		return errors.New("crypto: unserialize target isn't a pointer")
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// toGeneric converts a value to the maps, slices, strings, bools and
+// json.Numbers of its JSON representation.
+func toGeneric(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var generic interface{}
+	err = dec.Decode(&generic)
+	return generic, err
+}
+
+// marshalEncoder writes Ruby Marshal data.
+type marshalEncoder struct {
+	buf     bytes.Buffer
+	symbols map[string]int
+}
+
+func (e *marshalEncoder) encode(v interface{}) error {
+	switch val := v.(type) {
+	case nil:
+		e.buf.WriteByte('0')
+	case bool:
+		if val {
+			e.buf.WriteByte('T')
+		} else {
+			e.buf.WriteByte('F')
+		}
+	case RubySymbol:
+		e.writeSymbol(string(val))
+	case string:
+		e.writeString(val)
+	case []byte:
+		// binary strings have no encoding
+		e.buf.WriteByte('"')
+		e.writeBytes(val)
+	case *big.Int:
+		e.writeBignum(val)
+	case json.Number:
+		if n, err := val.Int64(); err == nil {
+			e.writeInteger(n)
+			return nil
+		}
+		f, err := val.Float64()
+		if err != nil {
+			return err
+		}
+		e.writeFloat(f)
+	case time.Time:
+		e.writeTime(val)
+	case *RubyObject:
+		return e.writeObject(val)
+	default:
+		return e.encodeValue(reflect.ValueOf(v))
+	}
+	return nil
+}
+
+// encodeValue encodes the numbers, slices and maps of any type, and
+// converts the other values using encoding/json.
+func (e *marshalEncoder) encodeValue(rv reflect.Value) error {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.writeInteger(rv.Int())
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := rv.Uint(); u > math.MaxInt64 {
+			e.writeBignum(new(big.Int).SetUint64(u))
+		} else {
+			e.writeInteger(int64(u))
+		}
+		return nil
+	case reflect.Float32, reflect.Float64:
+		e.writeFloat(rv.Float())
+		return nil
+	case reflect.Bool:
+		return e.encode(rv.Bool())
+	case reflect.String:
+		return e.encode(rv.String())
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			e.buf.WriteByte('0')
+			return nil
+		}
+		return e.encode(rv.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			e.buf.WriteByte('0')
+			return nil
+		}
+		e.buf.WriteByte('[')
+		e.writeInt(rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			if err := e.encode(rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if rv.IsNil() {
+			e.buf.WriteByte('0')
+			return nil
+		}
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		e.buf.WriteByte('{')
+		e.writeInt(len(keys))
+		for _, k := range keys {
+			key := k.Interface()
+			if _, ok := key.(RubySymbol); !ok {
+				key = fmt.Sprint(key)
+			}
+			if err := e.encode(key); err != nil {
+				return err
+			}
+			if err := e.encode(rv.MapIndex(k).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// structs and other types are converted to their JSON representation
+	generic, err := toGeneric(rv.Interface())
+	if err != nil {
+		return err
+	}
+	return e.encode(generic)
+}
+
+// writeInt writes the packed integer used for lengths and fixnums.
+func (e *marshalEncoder) writeInt(n int) {
+	switch {
+	case n == 0:
+		e.buf.WriteByte(0)
+	case n > 0 && n < 123:
+		e.buf.WriteByte(byte(n + 5))
+	case n < 0 && n > -124:
+		e.buf.WriteByte(byte(n - 5))
+	default:
+		var b [4]byte
+		size := 0
+		for x := n; size < 4; size++ {
+			b[size] = byte(x)
+			x >>= 8
+			if (n > 0 && x == 0) || (n < 0 && x == -1) {
+				size++
+				break
+			}
+		}
+		if n < 0 {
+			e.buf.WriteByte(byte(-size))
+		} else {
+			e.buf.WriteByte(byte(size))
+		}
+		e.buf.Write(b[:size])
+	}
+}
+
+// writeInteger writes a fixnum, or a bignum if it doesn't fit in the 31
+// bits of a Marshal fixnum.
+func (e *marshalEncoder) writeInteger(n int64) {
+	if n < -(1<<30) || n >= 1<<30 {
+		e.writeBignum(big.NewInt(n))
+		return
+	}
+	e.buf.WriteByte('i')
+	e.writeInt(int(n))
+}
+
+func (e *marshalEncoder) writeBignum(n *big.Int) {
+	e.buf.WriteByte('l')
+	if n.Sign() < 0 {
+		e.buf.WriteByte('-')
+	} else {
+		e.buf.WriteByte('+')
+	}
+	be := new(big.Int).Abs(n).Bytes()
+	if len(be)%2 == 1 {
+		be = append([]byte{0}, be...)
+	}
+	e.writeInt(len(be) / 2)
+	for i := len(be) - 1; i >= 0; i-- {
+		e.buf.WriteByte(be[i])
+	}
+}
+
+// writeFloat writes a float the way Ruby formats it.
+func (e *marshalEncoder) writeFloat(f float64) {
+	e.buf.WriteByte('f')
+	var str string
+	switch {
+	case math.IsInf(f, 1):
+		str = "inf"
+	case math.IsInf(f, -1):
+		str = "-inf"
+	case math.IsNaN(f):
+		str = "nan"
+	case f == 0:
+		str = "0"
+		if math.Signbit(f) {
+			str = "-0"
+		}
+	default:
+		str = rubyFloat(f)
+	}
+	e.writeBytes([]byte(str))
+}
+
+// rubyFloat formats a float with the shortest representation, using an
+// exponent only for very large or small numbers, like Marshal does.
+func rubyFloat(f float64) string {
+	var sign string
+	if f < 0 {
+		sign, f = "-", -f
+	}
+	mantissa, exp, _ := strings.Cut(strconv.FormatFloat(f, 'e', -1, 64), "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	decpt, _ := strconv.Atoi(exp)
+	decpt++
+	switch {
+	case decpt < -3 || decpt > len(digits):
+		str := digits[:1]
+		if len(digits) > 1 {
+			str += "." + digits[1:]
+		}
+		return sign + str + "e" + strconv.Itoa(decpt-1)
+	case decpt > 0:
+		if decpt == len(digits) {
+			return sign + digits
+		}
+		return sign + digits[:decpt] + "." + digits[decpt:]
+	}
+	return sign + "0." + strings.Repeat("0", -decpt) + digits
+}
+
+func (e *marshalEncoder) writeBytes(b []byte) {
+	e.writeInt(len(b))
+	e.buf.Write(b)
+}
+
+// writeString writes an UTF-8 string.
+func (e *marshalEncoder) writeString(s string) {
+	e.buf.WriteByte('I')
+	e.buf.WriteByte('"')
+	e.writeBytes([]byte(s))
+	e.writeInt(1)
+	e.writeSymbol("E")
+	e.buf.WriteByte('T')
+}
+
+func (e *marshalEncoder) writeSymbol(name string) {
+	if i, ok := e.symbols[name]; ok {
+		e.buf.WriteByte(';')
+		e.writeInt(i)
+		return
+	}
+	e.symbols[name] = len(e.symbols)
+	e.buf.WriteByte(':')
+	e.writeBytes([]byte(name))
+}
+
+// writeTime writes a Time the way Time#_dump does.
+func (e *marshalEncoder) writeTime(t time.Time) {
+	utc := t.UTC()
+	isUTC := t.Location() == time.UTC
+	p := uint32(1)<<31 | uint32(utc.Year()-1900)<<14 | uint32(utc.Month()-1)<<10 | uint32(utc.Day())<<5 | uint32(utc.Hour())
+	if isUTC {
+		p |= 1 << 30
+	}
+	s := uint32(utc.Minute())<<26 | uint32(utc.Second())<<20 | uint32(utc.Nanosecond()/1000)
+	data := make([]byte, 8)
+	for i := 0; i < 4; i++ {
+		data[i] = byte(p >> (8 * i))
+		data[4+i] = byte(s >> (8 * i))
+	}
+
+	ivars := 1
+	nano := utc.Nanosecond() % 1000
+	if nano != 0 {
+		ivars += 2
+	}
+	if !isUTC {
+		ivars++
+	}
+	e.buf.WriteByte('I')
+	e.buf.WriteByte('u')
+	e.writeSymbol("Time")
+	e.writeBytes(data)
+	e.writeInt(ivars)
+	if nano != 0 {
+		e.writeSymbol("nano_num")
+		e.writeInteger(int64(nano))
+		e.writeSymbol("nano_den")
+		e.writeInteger(1)
+	}
+	name, offset := t.Zone()
+	if !isUTC {
+		e.writeSymbol("offset")
+		e.writeInteger(int64(offset))
+	}
+	e.writeSymbol("zone")
+	e.writeString(name)
+}
+
+// writeObject writes a Ruby object with its instance variables.
+func (e *marshalEncoder) writeObject(o *RubyObject) error {
+	e.buf.WriteByte('o')
+	e.writeSymbol(o.Class)
+	names := make([]string, 0, len(o.Ivars))
+	for name := range o.Ivars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	e.writeInt(len(names))
+	for _, name := range names {
+		e.writeSymbol("@" + name)
+		if err := e.encode(o.Ivars[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
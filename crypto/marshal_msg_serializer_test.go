@@ -0,0 +1,136 @@
+package crypto
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	. "github.com/franela/goblin"
+)
+
+func TestMarshalSerializer(t *testing.T) {
+	g := Goblin(t)
+	serializer := MarshalMsgSerializer{}
+
+	g.Describe("Ruby Marshal payloads", func() {
+		g.It("decodes the payloads dumped by Ruby", func() {
+			examples := map[string]interface{}{
+				"\x04\b0":                   nil,
+				"\x04\bT":                   true,
+				"\x04\bi\x00":               0,
+				"\x04\bi\x06":               1,
+				"\x04\bi\xfa":               -1,
+				"\x04\bi\x02,\x01":          300,
+				"\x04\bi\xfe\xd4\xfe":       -300,
+				"\x04\bf\b1.5":              1.5,
+				"\x04\b:\nthree":            RubySymbol("three"),
+				"\x04\bI\"\btwo\x06:\x06ET": "two",
+			}
+			for data, expected := range examples {
+				var out interface{}
+				g.Assert(serializer.Unserialize(data, &out)).Eql(nil)
+				g.Assert(out).Eql(expected)
+			}
+		})
+
+		g.It("decodes bignums", func() {
+			var out interface{}
+			g.Assert(serializer.Unserialize("\x04\bl+\b\x00\x00\x00\x00\x00\x01", &out)).Eql(nil)
+			g.Assert(out).Eql(1 << 40)
+			g.Assert(serializer.Unserialize("\x04\bl-\n\x00\x00\x00\x00\x00\x00\x00\x00\x01\x00", &out)).Eql(nil)
+			g.Assert(out.(*big.Int).String()).Eql("-18446744073709551616")
+		})
+
+		g.It("decodes arrays and hashes with symbol and object links", func() {
+			var out interface{}
+			data := "\x04\b[\ni\x06I\"\btwo\x06:\x06ET:\nthree0T"
+			g.Assert(serializer.Unserialize(data, &out)).Eql(nil)
+			g.Assert(out).Eql([]interface{}{1, "two", RubySymbol("three"), nil, true})
+
+			data = "\x04\b{\aI\"\x06a\x06:\x06ETI\"\x06x\x06;\x00TI\"\x06b\x06;\x00T@\a"
+			g.Assert(serializer.Unserialize(data, &out)).Eql(nil)
+			g.Assert(out).Eql(map[string]interface{}{"a": "x", "b": "x"})
+		})
+
+		g.It("decodes HashWithIndifferentAccess as a hash", func() {
+			class := "ActiveSupport::HashWithIndifferentAccess"
+			data := "\x04\bC:" + string(rune(len(class)+5)) + class + "{\x06I\"\x06a\x06:\x06ETi\x06"
+			var out map[string]int
+			g.Assert(serializer.Unserialize(data, &out)).Eql(nil)
+			g.Assert(out).Eql(map[string]int{"a": 1})
+		})
+
+		g.It("decodes Time", func() {
+			// Marshal.dump(Time.at(0).utc)
+			data := "\x04\bIu:\tTime\r\x20\x80\x11\xc0\x00\x00\x00\x00\x06:\tzoneI\"\bUTC\x06:\x06EF"
+			var out time.Time
+			g.Assert(serializer.Unserialize(data, &out)).Eql(nil)
+			g.Assert(out.Equal(time.Unix(0, 0))).IsTrue()
+		})
+
+		g.It("refuses other formats and truncated data", func() {
+			var out interface{}
+			g.Assert(serializer.Unserialize(`{"a":1}`, &out) != nil).IsTrue()
+			g.Assert(serializer.Unserialize("\x04\b[\ni\x06", &out)).Eql(errMarshalTruncated)
+			g.Assert(serializer.Unserialize("\x04\bI\"\x06a\x03\xff\xff\x7f", &out)).Eql(errMarshalTruncated)
+			g.Assert(serializer.Unserialize("\x04\bI\"\x06a\x04\xff\xff\xff\x7f", &out)).Eql(errMarshalTruncated)
+		})
+	})
+
+	g.Describe("a Marshal serialized value", func() {
+		g.It("is encoded like Ruby does", func() {
+			examples := map[string]interface{}{
+				"\x04\bi\x02,\x01":                            300,
+				"\x04\bi\xfe\xd4\xfe":                         -300,
+				"\x04\bl+\b\x00\x00\x00\x00\x00\x01":          int64(1 << 40),
+				"\x04\bf\b1.5":                                1.5,
+				"\x04\bf\x061":                                1.0,
+				"\x04\bf\t1e-5":                               0.00001,
+				"\x04\b[\ni\x06I\"\btwo\x06:\x06ET:\nthree0T": []interface{}{1, "two", RubySymbol("three"), nil, true},
+				"\x04\b{\x06I\"\x06a\x06:\x06ETi\x06":         map[string]int{"a": 1},
+			}
+			for expected, value := range examples {
+				output, err := serializer.Serialize(value)
+				g.Assert(err).Eql(nil)
+				g.Assert(output).Eql(expected)
+			}
+		})
+
+		g.It("round trips times", func() {
+			in := time.Date(2024, 3, 1, 10, 30, 15, 123456789, time.FixedZone("CET", 3600))
+			output, err := serializer.Serialize(in)
+			g.Assert(err).Eql(nil)
+			var out interface{}
+			g.Assert(serializer.Unserialize(output, &out)).Eql(nil)
+			g.Assert(out.(time.Time).Equal(in)).IsTrue()
+			name, offset := out.(time.Time).Zone()
+			g.Assert(name).Eql("CET")
+			g.Assert(offset).Eql(3600)
+		})
+
+		g.It("round trips structs", func() {
+			type Session struct {
+				SessionID string   `json:"session_id"`
+				UserID    int      `json:"user_id"`
+				Roles     []string `json:"roles"`
+			}
+			data := Session{SessionID: "abc", UserID: 42, Roles: []string{"admin"}}
+			output, err := serializer.Serialize(data)
+			g.Assert(err).Eql(nil)
+			var o Session
+			g.Assert(serializer.Unserialize(output, &o)).Eql(nil)
+			g.Assert(o).Eql(data)
+		})
+	})
+}
+
+func ExampleMarshalMsgSerializer() {
+	// a Rails session dumped by Marshal
+	data := "\x04\b{\aI\"\x0fsession_id\x06:\x06ETI\"\babc\x06;\x00TI\"\fuser_id\x06;\x00Ti\x02\x39\x05"
+
+	var session map[string]interface{}
+	err := MarshalMsgSerializer{}.Unserialize(data, &session)
+	fmt.Println(session["session_id"], session["user_id"], err)
+	// Output: abc 1337 <nil>
+}
@@ -1,8 +1,11 @@
 package crypto
 
 import (
+	"context"
 	"crypto/sha1"
-	"errors"
+	"fmt"
+	"hash"
+	"sync/atomic"
 )
 
 //
@@ -16,8 +19,11 @@ import (
 // where you don't want users to be able to determine the value of the payload.
 //
 // Different kind of ciphers are supported:
-//  - aes-cbc - Rails' default until 5.2, requires a verifier
+//  - aes-cbc (or aes-256-cbc) - Rails' default until 5.2, requires a verifier
+//  - aes-128-cbc and aes-192-cbc - the variants using 16 and 24 byte keys
 //  - aes-256-gcm - Rails 5.2+ default, ignores verifier.
+//  - aes-128-gcm - used by Rails' encrypted files and credentials, ignores verifier.
+// Other ciphers can be added with RegisterCipher.
 //
 // Note: The old Rails default serializer, Marshal is neither safe or
 // portable across langauges, use the JSON serializer.
@@ -25,18 +31,99 @@ type MessageEncryptor struct {
 	Key []byte
 	// optional property used to automatically set the
 	// verifier if not already set.
-	SignKey    []byte
+	SignKey []byte
+	// SignDigest is the digest of the verifier set from SignKey, like the
+	// digest option of ActiveSupport::MessageEncryptor. Defaults to sha1
+	// like Rails, use sha256.New or sha512.New to match apps signing with
+	// another digest.
+	SignDigest func() hash.Hash
 	Cipher     string
+	// StrictKeys refuses the keys which don't have the size of the cipher
+	// instead of truncating the longer ones like Ruby's OpenSSL does.
+	StrictKeys bool
 	Verifier   *MessageVerifier
 	Serializer MsgSerializer
+	// Compress deflates the serialized messages larger than
+	// CompressThreshold, DefaultCompressThreshold if not set. Compressed
+	// messages are flagged in their metadata envelope and inflated when
+	// decrypted, whatever the setting.
+	Compress          bool
+	CompressThreshold int
+	// StreamChunkSize is the size of the chunks encrypted by EncryptStream,
+	// DefaultStreamChunkSize if not set.
+	StreamChunkSize int
+	// URLSafe encodes the messages using URL safe base64 without padding,
+	// like the url_safe option Rails 7.1 enables by default. Messages are
+	// decoded whatever their base64 variant.
+	URLSafe bool
+	// Encoding is the encoding of the messages, Base64 by default. The
+	// verifier set from SignKey uses the same encoding.
+	Encoding Encoding
+	// Separator joins the encrypted data, the IV and the auth tag, "--" by
+	// default, for instance to read the messages of an ActiveSupport
+	// subclass overriding it. The verifier set from SignKey uses the same
+	// separator.
+	Separator string
+	// OnRotation is called when a message is decrypted using one of the
+	// older configurations registered with Rotate.
+	OnRotation func()
+	// AuthData is additional data authenticated by the aes-256-gcm and
+	// aes-128-gcm ciphers, for instance a cookie name or a tenant id. The
+	// messages can then only be decrypted with the same AuthData, without
+	// changing their format. Rails authenticates no data.
+	AuthData []byte
+	// KeyProvider provides Key, and SignKey for aes-cbc, for every message
+	// when set. The keys are requested for KeyPurpose and SignKeyPurpose,
+	// with the context passed to EncryptAndSignContext and
+	// DecryptAndVerifyContext, or a background one.
+	KeyProvider    KeyProvider
+	KeyPurpose     string
+	SignKeyPurpose string
+	// AllowUnsignedCBC lets Decrypt decrypt the messages of the ciphers
+	// which don't authenticate them, like aes-cbc, without verifying their
+	// signature. Decrypting unverified aes-cbc messages exposes a padding
+	// oracle, so only set it if the messages are authenticated by other
+	// means.
+	AllowUnsignedCBC bool
+	// LegacyPadding reads the aes-cbc messages encrypted by the earlier
+	// versions of this package, which didn't pad the plaintexts already
+	// a multiple of 16 bytes: the plaintexts with an invalid padding are
+	// returned as is instead of being refused. Plaintexts which end like a
+	// padding can't be told apart, so only set it while such messages are
+	// still around.
+	LegacyPadding bool
+
+	// older configurations tried by DecryptAndVerify, see Rotate.
+	rotations []*MessageEncryptor
+	// keys is the *keyCache of the cipher, see keyCache.
+	keys atomic.Value
 }
 
-func (crypt *MessageEncryptor) withVerifier() bool {
-	switch crypt.Cipher {
-	case "aes-256-gcm":
-		return false
+// setDefaultVerifier sets a verifier using SignDigest if a signature key
+// was given instead of setting the verifier directly.
+func (crypt *MessageEncryptor) setDefaultVerifier() {
+	if crypt.Verifier != nil || crypt.SignKey == nil {
+		return
+	}
+	hasher := crypt.SignDigest
+	if hasher == nil {
+		hasher = sha1.New
+	}
+	crypt.Verifier = &MessageVerifier{
+		Secret:     crypt.SignKey,
+		Hasher:     hasher,
+		Serializer: NullMsgSerializer{},
+		URLSafe:    crypt.URLSafe,
+		Encoding:   crypt.Encoding,
+		Separator:  crypt.Separator,
 	}
-	return true
+}
+
+// withVerifier reports whether the messages are signed, which is the case
+// when the cipher doesn't authenticate them.
+func (crypt *MessageEncryptor) withVerifier() bool {
+	c, err := lookupCipher(crypt.Cipher)
+	return err != nil || c.TagSize() == 0
 }
 
 // EncryptAndSign performs encryption with authentication, or encryption
@@ -49,35 +136,78 @@ func (crypt *MessageEncryptor) withVerifier() bool {
 // Reference: http://www.limited-entropy.com/padding-oracle-attacks.
 // The output string can be converted back using DecryptAndVerify() and is
 // encoded using base64.
-func (crypt *MessageEncryptor) EncryptAndSign(value interface{}) (string, error) {
+// The operation is instrumented as "encrypt_and_sign.message_encryptor".
+func (crypt *MessageEncryptor) EncryptAndSign(value interface{}) (msg string, err error) {
+	return crypt.EncryptAndSignWithOptions(value, MessageOptions{})
+}
+
+// EncryptAndSignWithOptions is like EncryptAndSign but embeds the purpose
+// and expiration date of the message in a Rails 6+ metadata envelope:
+//
+//	e.EncryptAndSignWithOptions(data, MessageOptions{Purpose: "login", ExpiresIn: time.Hour})
+//
+// Such messages can only be read with DecryptAndVerifyWithPurpose and the
+// same purpose, until they expire.
+func (crypt *MessageEncryptor) EncryptAndSignWithOptions(value interface{}, opts MessageOptions) (msg string, err error) {
+	return crypt.EncryptAndSignContext(context.Background(), value, opts)
+}
+
+// EncryptAndSignContext is like EncryptAndSignWithOptions but passes ctx to
+// the KeyProvider, so the key requests can be canceled or given a
+// deadline.
+func (crypt *MessageEncryptor) EncryptAndSignContext(ctx context.Context, value interface{}, opts MessageOptions) (msg string, err error) {
+	err = instrument("encrypt_and_sign.message_encryptor", crypt.payload, func() error {
+		msg, err = pooledString(func(dst []byte) ([]byte, error) {
+			return crypt.encryptAndSign(ctx, dst, value, opts)
+		})
+		return err
+	})
+	return msg, err
+}
+
+// EncryptAndSignBytes is like EncryptAndSign but returns the message as
+// bytes, for the callers writing it to a []byte based API.
+func (crypt *MessageEncryptor) EncryptAndSignBytes(value interface{}) (msg []byte, err error) {
+	err = instrument("encrypt_and_sign.message_encryptor", crypt.payload, func() error {
+		msg, err = crypt.encryptAndSign(context.Background(), nil, value, MessageOptions{})
+		return err
+	})
+	return msg, err
+}
+
+// encryptAndSign appends the encrypted and signed message to dst.
+func (crypt *MessageEncryptor) encryptAndSign(ctx context.Context, dst []byte, value interface{}, opts MessageOptions) ([]byte, error) {
 	if crypt == nil {
-		return "", errors.New("can't call EncryptAndSign on a nil *MessageEncryptor")
+		return nil, notConfigured("can't call EncryptAndSign on a nil *MessageEncryptor")
+	}
+	crypt, err := crypt.withProvidedKeys(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	if !crypt.withVerifier() {
-		return crypt.Encrypt(value)
+		return crypt.encrypt(dst, value, opts)
 	}
 
-	// Set a default verifier if a signature key was given instead of setting the verifier directly.
-	if crypt.Verifier == nil && crypt.SignKey != nil {
-		crypt.Verifier = &MessageVerifier{
-			Secret:     crypt.SignKey,
-			Hasher:     sha1.New,
-			Serializer: NullMsgSerializer{},
-		}
-	}
+	crypt.setDefaultVerifier()
 	if crypt.Verifier == nil {
-		return "", errors.New("Verifier and/or signature key not set: ")
+		return nil, notConfigured("Verifier and/or signature key not set: ")
 	}
 	vvalid, err := crypt.Verifier.IsValid()
 	if !vvalid {
-		return "", errors.New("Verifier not properly set: " + err.Error())
+		return nil, fmt.Errorf("Verifier not properly set: %w", err)
 	}
-	encryptedMsg, err := crypt.Encrypt(value)
+	encryptedMsg, err := pooledString(func(dst []byte) ([]byte, error) {
+		return crypt.encrypt(dst, value, opts)
+	})
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	signed, err := crypt.Verifier.Generate(encryptedMsg)
+	if err != nil {
+		return nil, err
 	}
-	return crypt.Verifier.Generate(encryptedMsg)
+	return append(dst, signed...), nil
 }
 
 // DecryptAndVerify decrypts and either authenticates or verifies the signature
@@ -85,59 +215,162 @@ func (crypt *MessageEncryptor) EncryptAndSign(value interface{}) (string, error)
 // either signed or authenticated (GCM) on top of being encrypted in order to
 // avoid padding attacks. Reference: http://www.limited-entropy.com/padding-oracle-attacks.
 // The serializer will populate the pointer you are passing as second argument.
+// Messages generated with a purpose are refused, use
+// DecryptAndVerifyWithPurpose to read them.
+// The operation is instrumented as "decrypt_and_verify.message_encryptor".
 func (crypt *MessageEncryptor) DecryptAndVerify(msg string, target interface{}) error {
+	return crypt.DecryptAndVerifyWithPurpose(msg, target, "")
+}
 
+// DecryptAndVerifyWithPurpose is like DecryptAndVerify but reads messages
+// with Rails 6+ metadata. ErrWrongPurpose is returned if the message was
+// generated for another purpose and ErrExpired if it expired.
+//
+//	err := e.DecryptAndVerifyWithPurpose(cookie, &session, CookiePurpose("_app_session"))
+func (crypt *MessageEncryptor) DecryptAndVerifyWithPurpose(msg string, target interface{}, purpose string) error {
+	return crypt.DecryptAndVerifyContext(context.Background(), msg, target, purpose)
+}
+
+// DecryptAndVerifyContext is like DecryptAndVerifyWithPurpose but passes
+// ctx to the KeyProvider, so the key requests can be canceled or given a
+// deadline.
+func (crypt *MessageEncryptor) DecryptAndVerifyContext(ctx context.Context, msg string, target interface{}, purpose string) error {
+	return instrument("decrypt_and_verify.message_encryptor", crypt.payload, func() error {
+		// decrypt a pooled copy of the message, which spares a conversion
+		buf := getBuffer(len(msg))
+		defer putBuffer(buf)
+		copy(*buf, msg)
+		return crypt.decryptAndVerifyWithRotations(ctx, *buf, target, purpose)
+	})
+}
+
+// DecryptAndVerifyBytes is like DecryptAndVerify for the messages held as
+// bytes, which are read without being copied nor modified.
+func (crypt *MessageEncryptor) DecryptAndVerifyBytes(msg []byte, target interface{}) error {
+	return instrument("decrypt_and_verify.message_encryptor", crypt.payload, func() error {
+		return crypt.decryptAndVerifyWithRotations(context.Background(), msg, target, "")
+	})
+}
+
+// DecryptAndVerifyRaw decrypts and verifies a message like
+// DecryptAndVerifyWithPurpose but returns the serialized message, out of
+// its metadata envelope, instead of unserializing it. The message can then
+// be routed on one of its fields before choosing its type, or forwarded as
+// is:
+//
+//	data, err := e.DecryptAndVerifyRaw(cookie, CookiePurpose("_app_session"))
+//	var version struct{ V int `json:"v"` }
+//	json.Unmarshal(data, &version)
+//
+// Pass an empty purpose to read the messages generated without one.
+func (crypt *MessageEncryptor) DecryptAndVerifyRaw(msg string, purpose string) ([]byte, error) {
+	var raw rawMessage
+	if err := crypt.DecryptAndVerifyWithPurpose(msg, &raw, purpose); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+func (crypt *MessageEncryptor) decryptAndVerify(ctx context.Context, msg []byte, target interface{}, purpose string) error {
+	if err := checkTarget(target); err != nil {
+		return err
+	}
+	crypt, err := crypt.withProvidedKeys(ctx)
+	if err != nil {
+		return err
+	}
 	if !crypt.withVerifier() {
-		return crypt.Decrypt(msg, target)
+		return crypt.decrypt(msg, target, purpose)
 	}
 
-	// Set a default verifier if a signature key was given instead of setting the verifier directly.
-	if crypt.Verifier == nil && crypt.SignKey != nil {
-		crypt.Verifier = &MessageVerifier{
-			Secret:     crypt.SignKey,
-			Hasher:     sha1.New,
-			Serializer: NullMsgSerializer{},
-		}
+	crypt.setDefaultVerifier()
+	if crypt.Verifier == nil {
+		return notConfigured("Verifier and/or signature key not set: ")
 	}
 	var base64Msg string
 	// verify the data and get the encoded data out.
-	err := crypt.Verifier.Verify(msg, &base64Msg)
+	err = crypt.Verifier.Verify(string(msg), &base64Msg)
 	if err != nil {
-		return errors.New("Verification failed: " + err.Error())
+		return fmt.Errorf("Verification failed: %w", err)
 	}
-	return crypt.Decrypt(base64Msg, target)
+	return crypt.decryptString(base64Msg, target, purpose)
 }
 
 // Encrypt encrypts a message using the set cipher and the secret.
-// The returned value is a base 64 encoded string of the encrypted data + IV joined by "--".
+// The returned value is a base 64 encoded string of the encrypted data + IV joined by the separator, "--" by default.
 // An encrypted message isn't safe unless it's signed!
 func (crypt *MessageEncryptor) Encrypt(value interface{}) (string, error) {
-	switch crypt.Cipher {
-	case "aes-cbc":
-		return crypt.aesCbcEncrypt(value)
-	case "aes-256-gcm":
-		return crypt.aesGCMEncrypt(value)
-	case "":
-		// using a default if not set
-		return crypt.aesCbcEncrypt(value)
+	crypt, err := crypt.withProvidedKeys(context.Background())
+	if err != nil {
+		return "", err
 	}
-	return "", errors.New("cipher not set or not supported")
+	return pooledString(func(dst []byte) ([]byte, error) {
+		return crypt.encrypt(dst, value, MessageOptions{})
+	})
+}
+
+// encrypt appends the encrypted message to dst.
+func (crypt *MessageEncryptor) encrypt(dst []byte, value interface{}, opts MessageOptions) ([]byte, error) {
+	// Set a default serializer if not already set
+	if crypt.Serializer == nil {
+		crypt.Serializer = JsonMsgSerializer{}
+	}
+	c, err := lookupCipher(crypt.Cipher)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := serialize(crypt.Serializer, value, opts, compressAbove(crypt.Compress, crypt.CompressThreshold))
+	if err != nil {
+		return nil, err
+	}
+	return crypt.appendParts(dst, c, plaintext)
 }
 
 // Decrypt decrypts a message using the set cipher and the secret.
-// The passed value is expected to be a base 64 encoded string of the encrypted data + IV joined by "--"
+// The passed value is expected to be a base 64 encoded string of the encrypted data + IV joined by the separator, "--" by default.
+// The messages of aes-cbc are refused unless AllowUnsignedCBC is set, use
+// DecryptAndVerify to verify their signature first.
 func (crypt *MessageEncryptor) Decrypt(value string, target interface{}) error {
+	if err := checkTarget(target); err != nil {
+		return err
+	}
+	crypt, err := crypt.withProvidedKeys(context.Background())
+	if err != nil {
+		return err
+	}
+	if c, err := lookupCipher(crypt.Cipher); err == nil && c.TagSize() == 0 && !crypt.AllowUnsignedCBC {
+		return notConfigured("crypto: the %s messages must be verified, use DecryptAndVerify or set AllowUnsignedCBC", crypt.cipherName())
+	}
+	return crypt.decryptString(value, target, "")
+}
+
+// decryptString decrypts a pooled copy of value.
+func (crypt *MessageEncryptor) decryptString(value string, target interface{}, purpose string) error {
+	buf := getBuffer(len(value))
+	defer putBuffer(buf)
+	copy(*buf, value)
+	return crypt.decrypt(*buf, target, purpose)
+}
+
+func (crypt *MessageEncryptor) decrypt(msg []byte, target interface{}, purpose string) error {
 	if crypt.Serializer == nil {
 		crypt.Serializer = JsonMsgSerializer{}
 	}
-	switch crypt.Cipher {
-	case "aes-cbc":
-		return crypt.aesCbcDecrypt(value, target)
-	case "aes-256-gcm":
-		return crypt.aesGCMDecrypt(value, target)
-	case "":
-		// using a default if not set
-		return crypt.aesCbcDecrypt(value, target)
+	c, err := lookupCipher(crypt.Cipher)
+	if err != nil {
+		return err
+	}
+	// the plaintext may point to the pooled buffer, which is released
+	// once the message is unserialized
+	buf := getBuffer(len(msg))
+	defer putBuffer(buf)
+	if cbc, ok := c.(aesCBC); ok && crypt.LegacyPadding {
+		cbc.legacyPadding = true
+		c = cbc
+	}
+	plaintext, err := crypt.decryptParts(c, msg, *buf)
+	if err != nil {
+		return err
 	}
-	return errors.New("cipher not set or not supported")
+	return unserialize(crypt.Serializer, plaintext, target, purpose)
 }
@@ -1,8 +1,14 @@
 package crypto
 
 import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
 	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -22,6 +28,10 @@ func TestMessageEncryptorDefaultSettings(t *testing.T) {
 			g.Assert(err).Eql(nil)
 			var newMsg string
 			err = e.Decrypt(msg, &newMsg)
+			g.Assert(errors.Is(err, ErrNotConfigured)).IsTrue()
+			unsigned := e
+			unsigned.AllowUnsignedCBC = true
+			err = unsigned.Decrypt(msg, &newMsg)
 			g.Assert(err).Eql(nil)
 			g.Assert(newMsg).Eql("my secret data")
 		})
@@ -109,6 +119,23 @@ func TestMessageEncryptor(t *testing.T) {
 			g.Assert(err).Eql(nil)
 			g.Assert(output).Eql(testData)
 		})
+
+		g.It("binds the messages to their AuthData", func() {
+			e := newCrypt()
+			e.AuthData = []byte("tenant 1")
+			msg, err := e.EncryptAndSign("tenant data")
+			g.Assert(err).Eql(nil)
+			g.Assert(len(strings.Split(msg, "--"))).Eql(3)
+			var output string
+			g.Assert(e.DecryptAndVerify(msg, &output)).Eql(nil)
+			g.Assert(output).Eql("tenant data")
+
+			other := e
+			other.AuthData = []byte("tenant 2")
+			g.Assert(other.DecryptAndVerify(msg, &output) != nil).IsTrue()
+			other.AuthData = nil
+			g.Assert(other.DecryptAndVerify(msg, &output) != nil).IsTrue()
+		})
 	})
 
 	g.Describe("MessageEncryptor properly setup using aes cbc", func() {
@@ -120,7 +147,8 @@ func TestMessageEncryptor(t *testing.T) {
 					Hasher:     sha1.New,
 					Serializer: NullMsgSerializer{},
 				},
-				Serializer: JsonMsgSerializer{},
+				Serializer:       JsonMsgSerializer{},
+				AllowUnsignedCBC: true,
 			}
 		}
 
@@ -185,6 +213,160 @@ func TestMessageEncryptor(t *testing.T) {
 			g.Assert(err).Eql(nil)
 			g.Assert(output).Eql(testData)
 		})
+
+		g.It("signs with the SignDigest", func() {
+			key := GenerateRandomKey(32)
+			e := MessageEncryptor{Key: key, SignKey: []byte("signature secret!"), SignDigest: sha256.New, Cipher: "aes-cbc"}
+			msg, err := e.EncryptAndSign("data")
+			g.Assert(err).Eql(nil)
+			// a sha256 hex digest is 64 characters long
+			g.Assert(len(msg[strings.LastIndex(msg, "--")+2:])).Eql(64)
+			var output string
+			g.Assert(e.DecryptAndVerify(msg, &output)).Eql(nil)
+			g.Assert(output).Eql("data")
+
+			sha1Crypt := MessageEncryptor{Key: key, SignKey: []byte("signature secret!"), Cipher: "aes-cbc"}
+			g.Assert(sha1Crypt.DecryptAndVerify(msg, &output) != nil).IsTrue()
+			sha1Crypt.Verifier = nil
+			sha1Crypt.SignDigest = sha256.New
+			g.Assert(sha1Crypt.DecryptAndVerify(msg, &output)).Eql(nil)
+		})
+	})
+}
+
+func TestMessageEncryptorUnsignedCBC(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("MessageEncryptor using aes-cbc without a signature", func() {
+		key := GenerateRandomKey(32)
+
+		g.It("refuses to decrypt the messages without verifying them", func() {
+			e := MessageEncryptor{Key: key, SignKey: key}
+			msg, _ := e.Encrypt("data")
+			var out string
+			err := e.Decrypt(msg, &out)
+			g.Assert(err.Error()).Eql("crypto: the aes-cbc messages must be verified, use DecryptAndVerify or set AllowUnsignedCBC")
+			g.Assert(out).Eql("")
+		})
+
+		g.It("refuses to verify the messages without a verifier", func() {
+			e := MessageEncryptor{Key: key}
+			var out string
+			err := e.DecryptAndVerify("ZGF0YQ==--ZGF0YQ==--ZGF0YQ==", &out)
+			g.Assert(errors.Is(err, ErrNotConfigured)).IsTrue()
+		})
+
+		g.It("doesn't tell invalid paddings from invalid signatures", func() {
+			e := MessageEncryptor{Key: key, SignKey: key}
+			msg, _ := e.EncryptAndSign("data")
+			var out string
+			tampered := []byte(msg)
+			tampered[5] ^= 1
+			err := e.DecryptAndVerify(string(tampered), &out)
+			g.Assert(errors.Is(err, ErrInvalidSignature)).IsTrue()
+			g.Assert(errors.Is(err, ErrInvalidPadding)).IsFalse()
+		})
+	})
+}
+
+func TestMessageEncryptorCBCPadding(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("MessageEncryptor using aes-cbc", func() {
+		key := GenerateRandomKey(32)
+
+		g.It("pads the plaintexts like OpenSSL", func() {
+			e := MessageEncryptor{Key: key, SignKey: key, Serializer: NullMsgSerializer{}}
+			for _, payload := range [][]byte{
+				bytes.Repeat([]byte{0x10}, 16),
+				append(bytes.Repeat([]byte{'x'}, 15), 0x10),
+				{0x01},
+				{},
+			} {
+				msg, err := e.EncryptAndSignBytes(payload)
+				g.Assert(err).Eql(nil)
+				var out []byte
+				g.Assert(e.DecryptAndVerifyBytes(msg, &out)).Eql(nil)
+				g.Assert(bytes.Equal(out, payload)).IsTrue()
+			}
+		})
+
+		g.It("reads the messages without padding in the legacy mode", func() {
+			// the earlier versions didn't pad the plaintexts which were a
+			// multiple of the block size
+			plaintext := []byte(`"0123456789abcd"`)
+			iv := GenerateRandomKey(aes.BlockSize)
+			block, _ := aes.NewCipher(key)
+			ciphertext := make([]byte, len(plaintext))
+			cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, plaintext)
+			verifier := MessageVerifier{Secret: key, Hasher: sha1.New, Serializer: NullMsgSerializer{}}
+			msg, _ := verifier.Generate(base64.StdEncoding.EncodeToString(ciphertext) + "--" + base64.StdEncoding.EncodeToString(iv))
+
+			e := MessageEncryptor{Key: key, SignKey: key}
+			var out string
+			g.Assert(errors.Is(e.DecryptAndVerify(msg, &out), ErrInvalidPadding)).IsTrue()
+			e.LegacyPadding = true
+			g.Assert(e.DecryptAndVerify(msg, &out)).Eql(nil)
+			g.Assert(out).Eql("0123456789abcd")
+
+			current, _ := e.EncryptAndSign("data")
+			g.Assert(e.DecryptAndVerify(current, &out)).Eql(nil)
+			g.Assert(out).Eql("data")
+		})
+	})
+}
+
+func TestMessageEncryptorBytes(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("EncryptAndSignBytes and DecryptAndVerifyBytes", func() {
+		key := GenerateRandomKey(32)
+
+		for _, cipher := range []string{"aes-256-gcm", "aes-cbc"} {
+			cipher := cipher
+			g.It("read the string messages and vice versa using "+cipher, func() {
+				e := MessageEncryptor{Key: key, SignKey: key, Cipher: cipher}
+				msg, err := e.EncryptAndSignBytes(map[string]int{"id": 1})
+				g.Assert(err).Eql(nil)
+				var out map[string]int
+				g.Assert(e.DecryptAndVerify(string(msg), &out)).Eql(nil)
+				g.Assert(out).Eql(map[string]int{"id": 1})
+
+				str, _ := e.EncryptAndSign(map[string]int{"id": 2})
+				g.Assert(e.DecryptAndVerifyBytes([]byte(str), &out)).Eql(nil)
+				g.Assert(out).Eql(map[string]int{"id": 2})
+			})
+		}
+
+		g.It("don't modify the messages", func() {
+			for _, encoding := range []Encoding{Base64, Raw, Hex} {
+				e := MessageEncryptor{Key: key, Cipher: "aes-256-gcm", Encoding: encoding}
+				msg, _ := e.EncryptAndSignBytes("data")
+				original := append([]byte(nil), msg...)
+				var out string
+				g.Assert(e.DecryptAndVerifyBytes(msg, &out)).Eql(nil)
+				g.Assert(out).Eql("data")
+				g.Assert(msg).Eql(original)
+			}
+		})
+
+		g.It("keep binary payloads intact", func() {
+			e := MessageEncryptor{Key: key, Cipher: "aes-256-gcm", Serializer: NullMsgSerializer{}, Encoding: Raw}
+			payload := []byte{0, 0xff, 0x10, 0x10, '-', '-', 0x80}
+			msg, err := e.EncryptAndSignBytes(payload)
+			g.Assert(err).Eql(nil)
+			var out []byte
+			g.Assert(e.DecryptAndVerifyBytes(msg, &out)).Eql(nil)
+			g.Assert(out).Eql(payload)
+		})
+
+		g.It("refuse tampered messages", func() {
+			e := MessageEncryptor{Key: key, Cipher: "aes-256-gcm"}
+			msg, _ := e.EncryptAndSignBytes("data")
+			msg[0] ^= 1
+			var out string
+			g.Assert(e.DecryptAndVerifyBytes(msg, &out) != nil).IsTrue()
+		})
 	})
 }
 
@@ -297,7 +479,7 @@ func ExampleMessageEncryptor_DecryptAndVerify() {
 	// crypto.Person{Id:12, FirstName:"John", LastName:"Doe", Age:42}
 }
 
-func ExampleMessageEncryptor_EncryptAndSignGCM() {
+func ExampleMessageEncryptor_EncryptAndSign_gcm() {
 	type Person struct {
 		Id        int    `json:"id"`
 		FirstName string `json:"firstName"`
@@ -324,7 +506,7 @@ func ExampleMessageEncryptor_EncryptAndSignGCM() {
 	fmt.Println(msg)
 }
 
-func ExampleMessageEncryptor_DecryptAndVerifyGCM() {
+func ExampleMessageEncryptor_DecryptAndVerify_gcm() {
 
 	type Person struct {
 		Id        int    `json:"id"`
@@ -0,0 +1,388 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errMsgpackTruncated = errors.New("messagepack: truncated data")
+
+// msgpackDecoder reads MessagePack data and the ActiveSupport::MessagePack
+// extension types.
+type msgpackDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *msgpackDecoder) read(n int) ([]byte, error) {
+	if n < 0 || d.pos+n > len(d.data) {
+		return nil, errMsgpackTruncated
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// readUint reads a big endian unsigned integer of n bytes.
+func (d *msgpackDecoder) readUint(n int) (uint64, error) {
+	b, err := d.read(n)
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v, nil
+}
+
+func (d *msgpackDecoder) decode() (interface{}, error) {
+	b, err := d.read(1)
+	if err != nil {
+		return nil, err
+	}
+	c := b[0]
+	switch {
+	case c <= 0x7f:
+		return int(c), nil
+	case c >= 0xe0:
+		return int(int8(c)), nil
+	case c&0xf0 == 0x80:
+		return d.decodeMap(int(c & 0x0f))
+	case c&0xf0 == 0x90:
+		return d.decodeArray(int(c & 0x0f))
+	case c&0xe0 == 0xa0:
+		s, err := d.read(int(c & 0x1f))
+		return string(s), err
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.readUint(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.read(int(n))
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), b...), nil
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.readUint(1 << (c - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeExt(int(n))
+	case 0xca:
+		n, err := d.readUint(4)
+		return float64(math.Float32frombits(uint32(n))), err
+	case 0xcb:
+		n, err := d.readUint(8)
+		return math.Float64frombits(n), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		n, err := d.readUint(1 << (c - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		if n > math.MaxInt64 || int64(int(n)) != int64(n) {
+			return new(big.Int).SetUint64(n), nil
+		}
+		return int(n), nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (c - 0xd0)
+		n, err := d.readUint(size)
+		if err != nil {
+			return nil, err
+		}
+		// sign extend the value
+		shift := uint(64 - 8*size)
+		v := int64(n<<shift) >> shift
+		if int64(int(v)) != v {
+			return big.NewInt(v), nil
+		}
+		return int(v), nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.decodeExt(1 << (c - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.readUint(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		s, err := d.read(int(n))
+		return string(s), err
+	case 0xdc, 0xdd:
+		n, err := d.readUint(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(int(n))
+	case 0xde, 0xdf:
+		n, err := d.readUint(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(int(n))
+	}
+	return nil, fmt.Errorf("messagepack: unsupported type 0x%x", c)
+}
+
+func (d *msgpackDecoder) decodeArray(n int) ([]interface{}, error) {
+	if n > len(d.data)-d.pos {
+		return nil, errMsgpackTruncated
+	}
+	array := make([]interface{}, n)
+	for i := range array {
+		var err error
+		if array[i], err = d.decode(); err != nil {
+			return nil, err
+		}
+	}
+	return array, nil
+}
+
+// decodeMap reads a map. The keys which aren't strings or symbols are
+// formatted with fmt.
+func (d *msgpackDecoder) decodeMap(n int) (map[string]interface{}, error) {
+	if n > len(d.data)-d.pos {
+		return nil, errMsgpackTruncated
+	}
+	m := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		key, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		value, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		switch k := key.(type) {
+		case string:
+			m[k] = value
+		case RubySymbol:
+			m[string(k)] = value
+		default:
+			m[fmt.Sprint(k)] = value
+		}
+	}
+	return m, nil
+}
+
+// decodeExt reads an extension type with n bytes of data.
+func (d *msgpackDecoder) decodeExt(n int) (interface{}, error) {
+	typ, err := d.read(1)
+	if err != nil {
+		return nil, err
+	}
+	data, err := d.read(n)
+	if err != nil {
+		return nil, err
+	}
+
+	switch int8(typ[0]) {
+	case msgpackExtSymbol:
+		return RubySymbol(data), nil
+	case msgpackExtBigint:
+		if len(data) == 0 {
+			return nil, errMsgpackTruncated
+		}
+		v := new(big.Int).SetBytes(data[1:])
+		if data[0] == 1 {
+			v.Neg(v)
+		}
+		return v, nil
+	case msgpackExtBigDecimal:
+		// BigDecimal#_dump prefixes the value with its precision
+		i := strings.IndexByte(string(data), ':')
+		return decimalString(string(data[i+1:]))
+	case msgpackExtTimeZone, msgpackExtURI, msgpackExtIPAddr, msgpackExtPathname:
+		return string(data), nil
+	}
+
+	// the data of the other types is a sequence of values
+	values := &msgpackDecoder{data: data}
+	switch int8(typ[0]) {
+	case msgpackExtRational:
+		return values.readRational()
+	case msgpackExtTime:
+		return values.readTime()
+	case msgpackExtTimeWithZone:
+		t, err := values.readTime()
+		if err != nil {
+			return nil, err
+		}
+		zone, err := values.decode()
+		if err != nil {
+			return nil, err
+		}
+		// Rails zone names such as "Eastern Time (US & Canada)" aren't
+		// known by Go and keep the UTC time
+		if name, ok := zone.(string); ok {
+			if loc, err := time.LoadLocation(name); err == nil {
+				t = t.In(loc)
+			}
+		}
+		return t, nil
+	case msgpackExtDate:
+		jd, err := values.readInt()
+		return RubyDate{fromJulianDay(jd, time.UTC)}, err
+	case msgpackExtDateTime:
+		return values.readDateTime()
+	case msgpackExtDuration:
+		value, err := values.decode()
+		if err != nil {
+			return nil, err
+		}
+		switch v := value.(type) {
+		case int:
+			return time.Duration(v) * time.Second, nil
+		case float64:
+			return time.Duration(v * float64(time.Second)), nil
+		}
+		return nil, fmt.Errorf("messagepack: bad duration %v", value)
+	case msgpackExtSet:
+		return values.decode()
+	case msgpackExtHWIA:
+		return values.decode()
+	}
+	return nil, fmt.Errorf("messagepack: unsupported extension type %d", int8(typ[0]))
+}
+
+func (d *msgpackDecoder) readInt() (int64, error) {
+	value, err := d.decode()
+	if err != nil {
+		return 0, err
+	}
+	switch v := value.(type) {
+	case int:
+		return int64(v), nil
+	case *big.Int:
+		if v.IsInt64() {
+			return v.Int64(), nil
+		}
+	}
+	return 0, fmt.Errorf("messagepack: expected an integer, got %v", value)
+}
+
+// readRational reads a numerator, followed by a denominator unless the
+// numerator is zero.
+func (d *msgpackDecoder) readRational() (*big.Rat, error) {
+	num, err := d.readBigInt()
+	if err != nil {
+		return nil, err
+	}
+	if num.Sign() == 0 {
+		return new(big.Rat), nil
+	}
+	denom, err := d.readBigInt()
+	if err != nil {
+		return nil, err
+	}
+	if denom.Sign() == 0 {
+		return nil, errors.New("messagepack: zero denominator")
+	}
+	return new(big.Rat).SetFrac(num, denom), nil
+}
+
+func (d *msgpackDecoder) readBigInt() (*big.Int, error) {
+	value, err := d.decode()
+	if err != nil {
+		return nil, err
+	}
+	switch v := value.(type) {
+	case int:
+		return big.NewInt(int64(v)), nil
+	case *big.Int:
+		return v, nil
+	}
+	return nil, fmt.Errorf("messagepack: expected an integer, got %v", value)
+}
+
+// readTime reads the seconds, nanoseconds and UTC offset of a Time.
+func (d *msgpackDecoder) readTime() (time.Time, error) {
+	var fields [3]int64
+	for i := range fields {
+		var err error
+		if fields[i], err = d.readInt(); err != nil {
+			return time.Time{}, err
+		}
+	}
+	t := time.Unix(fields[0], fields[1]).UTC()
+	if offset := int(fields[2]); offset != 0 {
+		t = t.In(time.FixedZone("", offset))
+	}
+	return t, nil
+}
+
+// readDateTime reads the Julian day, hour, minute, seconds and offset, as
+// a fraction of a day, of a DateTime.
+func (d *msgpackDecoder) readDateTime() (RubyDateTime, error) {
+	var fields [3]int64
+	for i := range fields {
+		var err error
+		if fields[i], err = d.readInt(); err != nil {
+			return RubyDateTime{}, err
+		}
+	}
+	sec, err := d.readRational()
+	if err != nil {
+		return RubyDateTime{}, err
+	}
+	offset, err := d.readRational()
+	if err != nil {
+		return RubyDateTime{}, err
+	}
+
+	offsetSeconds, _ := new(big.Rat).Mul(offset, big.NewRat(24*60*60, 1)).Float64()
+	loc := time.UTC
+	if offsetSeconds != 0 {
+		loc = time.FixedZone("", int(offsetSeconds))
+	}
+	nsec := new(big.Rat).Mul(sec, big.NewRat(int64(time.Second), 1))
+	date := fromJulianDay(fields[0], loc)
+	t := date.Add(time.Duration(fields[1])*time.Hour + time.Duration(fields[2])*time.Minute)
+	return RubyDateTime{t.Add(time.Duration(new(big.Int).Quo(nsec.Num(), nsec.Denom()).Int64()))}, nil
+}
+
+// decimalString converts the scientific notation of BigDecimal#to_s, such
+// as "0.12345e2", to a plain decimal such as "12.345".
+func decimalString(s string) (RubyDecimal, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return "", fmt.Errorf("messagepack: unsupported BigDecimal %q", s)
+	}
+	if r.Sign() == 0 {
+		return "0", nil
+	}
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	mantissa, exponent, found := strings.Cut(strings.TrimPrefix(s, "0."), "e")
+	if !found || !strings.HasPrefix(s, "0.") {
+		return RubyDecimal(sign + s), nil
+	}
+	exp, err := strconv.Atoi(exponent)
+	if err != nil {
+		return "", err
+	}
+	digits := strings.TrimRight(mantissa, "0")
+	switch {
+	case exp <= 0:
+		return RubyDecimal(sign + "0." + strings.Repeat("0", -exp) + digits), nil
+	case exp >= len(digits):
+		return RubyDecimal(sign + digits + strings.Repeat("0", exp-len(digits))), nil
+	}
+	return RubyDecimal(sign + digits[:exp] + "." + digits[exp:]), nil
+}
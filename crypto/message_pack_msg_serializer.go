@@ -0,0 +1,379 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The extension types registered by ActiveSupport::MessagePack.
+const (
+	msgpackExtSymbol       = 0
+	msgpackExtBigint       = 1
+	msgpackExtBigDecimal   = 2
+	msgpackExtRational     = 3
+	msgpackExtDateTime     = 5
+	msgpackExtDate         = 6
+	msgpackExtTimeWithZone = 7
+	msgpackExtTime         = 8
+	msgpackExtTimeZone     = 9
+	msgpackExtDuration     = 10
+	msgpackExtSet          = 12
+	msgpackExtURI          = 13
+	msgpackExtIPAddr       = 14
+	msgpackExtPathname     = 15
+	msgpackExtHWIA         = 17
+)
+
+// msgpackSignature is the integer ActiveSupport::MessagePack writes before
+// the serialized value, 128 packed as an uint8.
+var msgpackSignature = []byte{0xcc, 0x80}
+
+// julianDayUnixEpoch is the Julian day number of 1970-01-01.
+const julianDayUnixEpoch = 2440588
+
+// MessagePackMsgSerializer reads and writes the MessagePack payloads of
+// ActiveSupport::MessagePack, used by Rails 7.1+ when the cookies
+// serializer is set to :message_pack.
+//
+// Besides the MessagePack types, the Rails extension types are
+// unserialized as:
+//
+//   - Symbol as RubySymbol, except for hash keys
+//   - large Integer as *big.Int
+//   - BigDecimal as RubyDecimal and Rational as *big.Rat
+//   - Time and TimeWithZone as time.Time
+//   - Date as RubyDate and DateTime as RubyDateTime
+//   - ActiveSupport::Duration as time.Duration
+//   - HashWithIndifferentAccess as map[string]interface{}
+//   - Set as []interface{}
+//   - TimeZone, URI, IPAddr and Pathname as strings
+//
+// Serialize writes those Go types back using the same extension types so
+// the values keep their Ruby class. Like with MarshalMsgSerializer, the
+// other Go types go through encoding/json.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/MessagePack.html
+type MessagePackMsgSerializer struct{}
+
+// RubyDecimal is a Ruby BigDecimal, kept as its decimal representation
+// such as "12.345" to not lose any precision. It is encoded to JSON as a
+// number.
+type RubyDecimal string
+
+func (d RubyDecimal) MarshalJSON() ([]byte, error) {
+	return []byte(d), nil
+}
+
+// RubyDate is a Ruby Date, a day at midnight UTC.
+type RubyDate struct{ time.Time }
+
+func (d RubyDate) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.Format("2006-01-02") + `"`), nil
+}
+
+// RubyDateTime is a Ruby DateTime.
+type RubyDateTime struct{ time.Time }
+
+func (s MessagePackMsgSerializer) Serialize(v interface{}) (string, error) {
+	var buf bytes.Buffer
+	buf.Write(msgpackSignature)
+	if err := msgpackEncode(&buf, v); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (s MessagePackMsgSerializer) Unserialize(data string, v interface{}) error {
+	if !strings.HasPrefix(data, string(msgpackSignature)) {
+		return errors.New("messagepack: invalid serialization format")
+	}
+	d := &msgpackDecoder{data: []byte(data), pos: len(msgpackSignature)}
+	value, err := d.decode()
+	if err != nil {
+		return err
+	}
+	if d.pos != len(d.data) {
+		return errors.New("messagepack: extra data after the value")
+	}
+	return assignGeneric(value, v)
+}
+
+func msgpackEncode(buf *bytes.Buffer, v interface{}) error {
+	switch val := v.(type) {
+	case nil:
+		buf.WriteByte(0xc0)
+	case bool:
+		if val {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+	case string:
+		msgpackWriteString(buf, val)
+	case []byte:
+		msgpackWriteHeader(buf, len(val), 0, 0xc4, 0xc5, 0xc6)
+		buf.Write(val)
+	case RubySymbol:
+		msgpackWriteExt(buf, msgpackExtSymbol, []byte(val))
+	case RubyDecimal:
+		// BigDecimal#_dump prefixes the value with its precision
+		msgpackWriteExt(buf, msgpackExtBigDecimal, []byte(strconv.Itoa(len(val))+":"+string(val)))
+	case *big.Int:
+		if val.IsInt64() {
+			msgpackWriteInt(buf, val.Int64())
+		} else if val.IsUint64() {
+			msgpackWriteUint(buf, val.Uint64())
+		} else {
+			msgpackWriteExt(buf, msgpackExtBigint, msgpackBigint(val))
+		}
+	case *big.Rat:
+		return msgpackWriteRecursiveExt(buf, msgpackExtRational, msgpackRational(val)...)
+	case json.Number:
+		if n, err := val.Int64(); err == nil {
+			msgpackWriteInt(buf, n)
+			return nil
+		}
+		f, err := val.Float64()
+		if err != nil {
+			return err
+		}
+		msgpackWriteFloat(buf, f)
+	case time.Duration:
+		seconds := val.Seconds()
+		var value interface{} = seconds
+		if val%time.Second == 0 {
+			value = int64(seconds)
+		}
+		// the parts of the duration: years, months, weeks, days, hours,
+		// minutes and seconds
+		parts := []interface{}{nil, nil, nil, nil, nil, nil, value}
+		return msgpackWriteRecursiveExt(buf, msgpackExtDuration, value, parts)
+	case time.Time:
+		_, offset := val.Zone()
+		return msgpackWriteRecursiveExt(buf, msgpackExtTime, val.Unix(), val.Nanosecond(), offset)
+	case RubyDate:
+		return msgpackWriteRecursiveExt(buf, msgpackExtDate, julianDay(val.Time))
+	case RubyDateTime:
+		_, offset := val.Zone()
+		sec := new(big.Rat).SetFrac64(int64(val.Second())*1e9+int64(val.Nanosecond()), 1e9)
+		values := []interface{}{julianDay(val.Time), val.Hour(), val.Minute()}
+		values = append(values, msgpackRational(sec)...)
+		values = append(values, msgpackRational(big.NewRat(int64(offset), 24*60*60))...)
+		return msgpackWriteRecursiveExt(buf, msgpackExtDateTime, values...)
+	default:
+		return msgpackEncodeValue(buf, reflect.ValueOf(v))
+	}
+	return nil
+}
+
+// msgpackEncodeValue encodes the numbers, slices and maps of any type,
+// and converts the other values using encoding/json.
+func msgpackEncodeValue(buf *bytes.Buffer, rv reflect.Value) error {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		msgpackWriteInt(buf, rv.Int())
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		msgpackWriteUint(buf, rv.Uint())
+		return nil
+	case reflect.Float32, reflect.Float64:
+		msgpackWriteFloat(buf, rv.Float())
+		return nil
+	case reflect.Bool:
+		return msgpackEncode(buf, rv.Bool())
+	case reflect.String:
+		return msgpackEncode(buf, rv.String())
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			buf.WriteByte(0xc0)
+			return nil
+		}
+		return msgpackEncode(buf, rv.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			buf.WriteByte(0xc0)
+			return nil
+		}
+		msgpackWriteHeader(buf, rv.Len(), 0x90, 0, 0xdc, 0xdd)
+		for i := 0; i < rv.Len(); i++ {
+			if err := msgpackEncode(buf, rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if rv.IsNil() {
+			buf.WriteByte(0xc0)
+			return nil
+		}
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		msgpackWriteHeader(buf, len(keys), 0x80, 0, 0xde, 0xdf)
+		for _, k := range keys {
+			key := k.Interface()
+			if _, ok := key.(RubySymbol); !ok {
+				key = fmt.Sprint(key)
+			}
+			if err := msgpackEncode(buf, key); err != nil {
+				return err
+			}
+			if err := msgpackEncode(buf, rv.MapIndex(k).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// structs and other types are converted to their JSON representation
+	generic, err := toGeneric(rv.Interface())
+	if err != nil {
+		return err
+	}
+	return msgpackEncode(buf, generic)
+}
+
+// msgpackWriteHeader writes the type and length of a string, binary,
+// array or map. A zero fix byte means the type has no fix format and
+// a zero 8 bit byte means it has no 8 bit format.
+func msgpackWriteHeader(buf *bytes.Buffer, n int, fix, b8, b16, b32 byte) {
+	fixMax := 16
+	if fix == 0xa0 {
+		fixMax = 32
+	}
+	switch {
+	case fix != 0 && n < fixMax:
+		buf.WriteByte(fix | byte(n))
+	case b8 != 0 && n <= math.MaxUint8:
+		buf.Write([]byte{b8, byte(n)})
+	case n <= math.MaxUint16:
+		buf.WriteByte(b16)
+		binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(b32)
+		binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+}
+
+func msgpackWriteString(buf *bytes.Buffer, s string) {
+	msgpackWriteHeader(buf, len(s), 0xa0, 0xd9, 0xda, 0xdb)
+	buf.WriteString(s)
+}
+
+// msgpackWriteInt writes an integer using the smallest format, like
+// msgpack-ruby does.
+func msgpackWriteInt(buf *bytes.Buffer, n int64) {
+	switch {
+	case n >= 0:
+		msgpackWriteUint(buf, uint64(n))
+	case n >= -32:
+		buf.WriteByte(byte(n))
+	case n >= math.MinInt8:
+		buf.Write([]byte{0xd0, byte(n)})
+	case n >= math.MinInt16:
+		buf.WriteByte(0xd1)
+		binary.Write(buf, binary.BigEndian, int16(n))
+	case n >= math.MinInt32:
+		buf.WriteByte(0xd2)
+		binary.Write(buf, binary.BigEndian, int32(n))
+	default:
+		buf.WriteByte(0xd3)
+		binary.Write(buf, binary.BigEndian, n)
+	}
+}
+
+func msgpackWriteUint(buf *bytes.Buffer, n uint64) {
+	switch {
+	case n < 128:
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint8:
+		buf.Write([]byte{0xcc, byte(n)})
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xcd)
+		binary.Write(buf, binary.BigEndian, uint16(n))
+	case n <= math.MaxUint32:
+		buf.WriteByte(0xce)
+		binary.Write(buf, binary.BigEndian, uint32(n))
+	default:
+		buf.WriteByte(0xcf)
+		binary.Write(buf, binary.BigEndian, n)
+	}
+}
+
+func msgpackWriteFloat(buf *bytes.Buffer, f float64) {
+	buf.WriteByte(0xcb)
+	binary.Write(buf, binary.BigEndian, math.Float64bits(f))
+}
+
+func msgpackWriteExt(buf *bytes.Buffer, typ int8, data []byte) {
+	switch len(data) {
+	case 1:
+		buf.WriteByte(0xd4)
+	case 2:
+		buf.WriteByte(0xd5)
+	case 4:
+		buf.WriteByte(0xd6)
+	case 8:
+		buf.WriteByte(0xd7)
+	case 16:
+		buf.WriteByte(0xd8)
+	default:
+		msgpackWriteHeader(buf, len(data), 0, 0xc7, 0xc8, 0xc9)
+	}
+	buf.WriteByte(byte(typ))
+	buf.Write(data)
+}
+
+// msgpackWriteRecursiveExt writes an extension type whose data is itself
+// a sequence of MessagePack values.
+func msgpackWriteRecursiveExt(buf *bytes.Buffer, typ int8, values ...interface{}) error {
+	var data bytes.Buffer
+	for _, v := range values {
+		if err := msgpackEncode(&data, v); err != nil {
+			return err
+		}
+	}
+	msgpackWriteExt(buf, typ, data.Bytes())
+	return nil
+}
+
+// msgpackBigint packs an integer like MessagePack::Bigint: a sign byte
+// followed by the 32 bit chunks of its absolute value, big endian.
+func msgpackBigint(n *big.Int) []byte {
+	abs := new(big.Int).Abs(n).Bytes()
+	padding := (4 - len(abs)%4) % 4
+	data := make([]byte, 1+padding, 1+padding+len(abs))
+	if n.Sign() < 0 {
+		data[0] = 1
+	}
+	return append(data, abs...)
+}
+
+// msgpackRational returns the values Rails writes for a Rational: the
+// numerator, then the denominator unless the numerator is zero.
+func msgpackRational(r *big.Rat) []interface{} {
+	if r.Sign() == 0 {
+		return []interface{}{0}
+	}
+	return []interface{}{new(big.Int).Set(r.Num()), new(big.Int).Set(r.Denom())}
+}
+
+// julianDay returns the Julian day number of the date of t.
+func julianDay(t time.Time) int64 {
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return date.Unix()/(24*60*60) + julianDayUnixEpoch
+}
+
+// fromJulianDay returns the date of a Julian day number, at midnight in loc.
+func fromJulianDay(jd int64, loc *time.Location) time.Time {
+	return time.Date(1970, 1, 1+int(jd-julianDayUnixEpoch), 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,179 @@
+package crypto
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	. "github.com/franela/goblin"
+)
+
+func TestMessagePackSerializer(t *testing.T) {
+	g := Goblin(t)
+	serializer := MessagePackMsgSerializer{}
+
+	g.Describe("ActiveSupport::MessagePack payloads", func() {
+		g.It("decodes the MessagePack types", func() {
+			examples := map[string]interface{}{
+				"\xcc\x80\xc0":             nil,
+				"\xcc\x80\xc3":             true,
+				"\xcc\x80\x2a":             42,
+				"\xcc\x80\xff":             -1,
+				"\xcc\x80\xcd\x01\x2c":     300,
+				"\xcc\x80\xd1\xfe\xd4":     -300,
+				"\xcc\x80\xa3two":          "two",
+				"\xcc\x80\xc4\x02\x00\x01": []byte{0, 1},
+				"\xcc\x80\xcb\x3f\xf8\x00\x00\x00\x00\x00\x00": 1.5,
+				"\xcc\x80\x93\x01\xa1a\xc2":                    []interface{}{1, "a", false},
+				"\xcc\x80\x82\xa1a\x01\xa1b\x90":               map[string]interface{}{"a": 1, "b": []interface{}{}},
+			}
+			for data, expected := range examples {
+				var out interface{}
+				g.Assert(serializer.Unserialize(data, &out)).Eql(nil)
+				g.Assert(out).Eql(expected)
+			}
+		})
+
+		g.It("decodes symbols", func() {
+			var out interface{}
+			g.Assert(serializer.Unserialize("\xcc\x80\xc7\x03\x00foo", &out)).Eql(nil)
+			g.Assert(out).Eql(RubySymbol("foo"))
+
+			// {user_id: 1}
+			g.Assert(serializer.Unserialize("\xcc\x80\x81\xc7\x07\x00user_id\x01", &out)).Eql(nil)
+			g.Assert(out).Eql(map[string]interface{}{"user_id": 1})
+		})
+
+		g.It("decodes Time", func() {
+			var out time.Time
+			g.Assert(serializer.Unserialize("\xcc\x80\xc7\x03\x08\x00\x00\x00", &out)).Eql(nil)
+			g.Assert(out.Equal(time.Unix(0, 0))).IsTrue()
+		})
+
+		g.It("decodes BigDecimal", func() {
+			var out interface{}
+			g.Assert(serializer.Unserialize("\xcc\x80\xc7\x0c\x0218:0.12345e2", &out)).Eql(nil)
+			g.Assert(out).Eql(RubyDecimal("12.345"))
+
+			examples := map[string]RubyDecimal{"0.1e-2": "0.001", "-0.15e4": "-1500", "0.0": "0"}
+			for dumped, expected := range examples {
+				d, err := decimalString(dumped)
+				g.Assert(err).Eql(nil)
+				g.Assert(d).Eql(expected)
+			}
+		})
+
+		g.It("decodes HashWithIndifferentAccess as a hash", func() {
+			var out map[string]int
+			g.Assert(serializer.Unserialize("\xcc\x80\xd4\x11\x80", &out)).Eql(nil)
+			g.Assert(out).Eql(map[string]int{})
+			g.Assert(serializer.Unserialize("\xcc\x80\xc7\x04\x11\x81\xa1a\x01", &out)).Eql(nil)
+			g.Assert(out).Eql(map[string]int{"a": 1})
+		})
+
+		g.It("refuses payloads without the signature", func() {
+			var out interface{}
+			g.Assert(serializer.Unserialize("\x81\xa1a\x01", &out) != nil).IsTrue()
+			g.Assert(serializer.Unserialize("\xcc\x80\x92\x01", &out)).Eql(errMsgpackTruncated)
+		})
+	})
+
+	g.Describe("a MessagePack serialized value", func() {
+		g.It("is encoded like msgpack-ruby does", func() {
+			examples := map[string]interface{}{
+				"\xcc\x80\x2a":                   42,
+				"\xcc\x80\xd1\xfe\xd4":           -300,
+				"\xcc\x80\xcd\x01\x2c":           uint(300),
+				"\xcc\x80\xa3two":                "two",
+				"\xcc\x80\xc7\x03\x00foo":        RubySymbol("foo"),
+				"\xcc\x80\x82\xa1a\x01\xa1b\x90": map[string]interface{}{"a": 1, "b": []int{}},
+			}
+			for expected, value := range examples {
+				output, err := serializer.Serialize(value)
+				g.Assert(err).Eql(nil)
+				g.Assert(output).Eql(expected)
+			}
+		})
+
+		g.It("round trips the Rails extension types", func() {
+			bigint, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+			examples := []interface{}{
+				bigint,
+				big.NewRat(1, 3),
+				new(big.Rat),
+				RubyDecimal("12.345"),
+				RubyDate{time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+				time.Unix(1700000000, 123456789).UTC(),
+				90 * time.Minute,
+				1500 * time.Millisecond,
+			}
+			for _, value := range examples {
+				output, err := serializer.Serialize(value)
+				g.Assert(err).Eql(nil)
+				var out interface{}
+				g.Assert(serializer.Unserialize(output, &out)).Eql(nil)
+				g.Assert(out).Eql(value)
+			}
+		})
+
+		g.It("round trips times and date times with their offset", func() {
+			zone := time.FixedZone("", -5*3600)
+			in := time.Date(2024, 3, 1, 10, 30, 15, 500000000, zone)
+			for _, value := range []interface{}{in, RubyDateTime{in}} {
+				output, err := serializer.Serialize(value)
+				g.Assert(err).Eql(nil)
+				var out interface{}
+				g.Assert(serializer.Unserialize(output, &out)).Eql(nil)
+				var t time.Time
+				switch o := out.(type) {
+				case time.Time:
+					t = o
+				case RubyDateTime:
+					t = o.Time
+				}
+				g.Assert(t.Equal(in)).IsTrue()
+				_, offset := t.Zone()
+				g.Assert(offset).Eql(-5 * 3600)
+			}
+		})
+
+		g.It("round trips structs", func() {
+			type Session struct {
+				SessionID string   `json:"session_id"`
+				UserID    int      `json:"user_id"`
+				Roles     []string `json:"roles"`
+				Balance   float64  `json:"balance"`
+			}
+			data := Session{SessionID: "abc", UserID: 42, Roles: []string{"admin"}, Balance: 12.5}
+			output, err := serializer.Serialize(data)
+			g.Assert(err).Eql(nil)
+			var o Session
+			g.Assert(serializer.Unserialize(output, &o)).Eql(nil)
+			g.Assert(o).Eql(data)
+		})
+
+		g.It("can be used by an encryptor", func() {
+			e := MessageEncryptor{Key: GenerateRandomKey(32), Cipher: "aes-256-gcm", Serializer: serializer}
+			msg, err := e.EncryptAndSign(map[string]interface{}{"expires": RubyDate{time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}})
+			g.Assert(err).Eql(nil)
+			var out map[string]interface{}
+			g.Assert(e.DecryptAndVerify(msg, &out)).Eql(nil)
+			g.Assert(out["expires"]).Eql("2030-01-01")
+		})
+	})
+}
+
+func ExampleMessagePackMsgSerializer() {
+	s := MessagePackMsgSerializer{}
+	data, _ := s.Serialize(map[string]interface{}{
+		"user_id": 42,
+		"role":    RubySymbol("admin"),
+		"balance": RubyDecimal("1234.56"),
+	})
+
+	var session interface{}
+	s.Unserialize(data, &session)
+	fmt.Println(session)
+	// Output: map[balance:1234.56 role:admin user_id:42]
+}
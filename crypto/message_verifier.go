@@ -3,10 +3,7 @@ package crypto
 import (
 	"crypto/hmac"
 	"crypto/sha1"
-	"encoding/base64"
 	"encoding/hex"
-	"errors"
-	"fmt"
 	"hash"
 	"strings"
 )
@@ -21,8 +18,33 @@ type MessageVerifier struct {
 	Secret []byte
 	// Hasher defaults to sha1 if not set.
 	Hasher func() hash.Hash
+	// FallbackHashers are tried in order by Verify when the signature
+	// doesn't match the one of Hasher, for instance sha1.New to keep
+	// accepting the outstanding tokens while signing the new ones using
+	// sha256. OnRotation is called when a message is verified using one of
+	// them.
+	FallbackHashers []func() hash.Hash
 	// Serializer defines the way the data is serializer/deserialized.
 	Serializer MsgSerializer
+	// Compress deflates the serialized messages larger than
+	// CompressThreshold, DefaultCompressThreshold if not set.
+	Compress          bool
+	CompressThreshold int
+	// URLSafe encodes the messages using URL safe base64 without padding,
+	// like the url_safe option Rails 7.1 enables by default. Messages are
+	// decoded whatever their base64 variant.
+	URLSafe bool
+	// Encoding is the encoding of the messages, Base64 by default.
+	Encoding Encoding
+	// Separator joins the data and the digest, "--" by default. A custom
+	// separator must not be a valid hex digit.
+	Separator string
+	// OnRotation is called when a message is verified using one of the
+	// older secrets registered with Rotate, or one of the FallbackHashers.
+	OnRotation func()
+
+	// older configurations tried by Verify, see Rotate.
+	rotations []*MessageVerifier
 }
 
 // Checks that the struct is properly set and ready for use.
@@ -37,51 +59,138 @@ func (crypt *MessageVerifier) IsValid() (bool, error) {
 // Verify() takes a base64 encoded message string joined to a digest by a double dash "--"
 // and returns an error if anything wrong happen.
 // If the verification worked, the target interface object passed is populated.
+// Messages generated with a purpose are refused, use VerifyWithPurpose to
+// read them.
+// The operation is instrumented as "verify.message_verifier".
 func (crypt *MessageVerifier) Verify(msg string, target interface{}) error {
-	// TODO: check that the target is a pointer.
+	return crypt.VerifyWithPurpose(msg, target, "")
+}
+
+// VerifyWithPurpose is like Verify but reads messages with Rails 6+
+// metadata, as generated by GenerateWithOptions. ErrWrongPurpose is
+// returned if the message was generated for another purpose and
+// ErrExpired if it expired.
+func (crypt *MessageVerifier) VerifyWithPurpose(msg string, target interface{}, purpose string) error {
+	return instrument("verify.message_verifier", noPayload, func() error {
+		return crypt.verifyWithRotations(msg, target, purpose)
+	})
+}
+
+// VerifyRaw verifies a message like VerifyWithPurpose but returns the
+// serialized message, out of its metadata envelope, instead of
+// unserializing it. Pass an empty purpose to read the messages generated
+// without one.
+func (crypt *MessageVerifier) VerifyRaw(msg string, purpose string) ([]byte, error) {
+	var raw rawMessage
+	if err := crypt.VerifyWithPurpose(msg, &raw, purpose); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+func (crypt *MessageVerifier) verify(msg string, target interface{}, purpose string) error {
 	err := crypt.checkInit()
 	if err != nil {
 		return err
 	}
-
-	invalid := func(msg string) error {
-		return errors.New("Invalid signature - " + msg)
+	if err := checkTarget(target); err != nil {
+		return err
 	}
+
 	if msg == "" {
-		return invalid("empty message")
+		return malformed("Invalid signature - empty message")
 	}
 
-	dataDigest := strings.Split(msg, "--")
-	if len(dataDigest) != 2 {
-		return invalid("bad data --")
+	if crypt.Encoding == Raw {
+		parts, err := rawSplit([]byte(msg), 2)
+		if err != nil {
+			return malformed("Invalid signature - bad data framing")
+		}
+		if !hmac.Equal(parts[1], crypt.mac(parts[0])) {
+			return invalidSignature("Invalid signature - bad data (compare)")
+		}
+		return unserialize(crypt.Serializer, parts[0], target, purpose)
 	}
 
-	data, digest := dataDigest[0], dataDigest[1]
-	if crypt.secureCompare(digest, crypt.DigestFor(data)) == false {
-		return invalid("bad data (compare)")
+	// the digest is hex encoded but the data can contain the separator
+	// when URL safe
+	sep := separatorOr(crypt.Separator)
+	i := strings.LastIndex(msg, sep)
+	if i < 0 {
+		return malformed("Invalid signature - bad data %s", sep)
+	}
+
+	data, digest := msg[:i], msg[i+len(sep):]
+	// digest and decode the data in a pooled buffer, unserialize copies it
+	buf := getBuffer(len(data) + maxDecodedLen(len(data)))
+	defer putBuffer(buf)
+	src, dst := (*buf)[:len(data)], (*buf)[len(data):]
+	copy(src, data)
+	if !hmac.Equal([]byte(digest), []byte(hex.EncodeToString(crypt.mac(src)))) {
+		return invalidSignature("Invalid signature - bad data (compare)")
+	}
+	n, err := crypt.Encoding.decodeTo(dst, src)
+	if err != nil {
+		return malformed("Invalid signature - bad data encoding")
 	}
-	decodedData, err := base64.StdEncoding.DecodeString(data)
-	err = crypt.Serializer.Unserialize(string(decodedData), target)
-	return err
+	return unserialize(crypt.Serializer, dst[:n], target, purpose)
 }
 
 // Generate() Converts an interface into a string containing the serialized data
 // and a digest.
 // The string can be passed around and tampering can be checked using the digest.
 // See Verify() to extract the data out of the signed string.
-func (crypt *MessageVerifier) Generate(value interface{}) (string, error) {
+// The operation is instrumented as "generate.message_verifier".
+func (crypt *MessageVerifier) Generate(value interface{}) (msg string, err error) {
+	return crypt.GenerateWithOptions(value, MessageOptions{})
+}
+
+// GenerateWithOptions is like Generate but embeds the purpose and
+// expiration date of the message in a Rails 6+ metadata envelope, like
+// ActiveSupport::MessageVerifier#generate with the purpose, expires_in and
+// expires_at options:
+//
+//	token, err := v.GenerateWithOptions(userID, MessageOptions{Purpose: "password_reset", ExpiresIn: 15 * time.Minute})
+//	err = v.VerifyWithPurpose(token, &userID, "password_reset")
+func (crypt *MessageVerifier) GenerateWithOptions(value interface{}, opts MessageOptions) (msg string, err error) {
+	err = instrument("generate.message_verifier", noPayload, func() error {
+		msg, err = crypt.generate(value, opts)
+		return err
+	})
+	return msg, err
+}
+
+func (crypt *MessageVerifier) generate(value interface{}, opts MessageOptions) (string, error) {
 	err := crypt.checkInit()
 	if err != nil {
 		return "", err
 	}
 
-	data, err := crypt.Serializer.Serialize(value)
+	data, err := serialize(crypt.Serializer, value, opts, compressAbove(crypt.Compress, crypt.CompressThreshold))
 	if err != nil {
 		return "", err
 	}
-	str := base64.StdEncoding.EncodeToString([]byte(data))
-	digest := crypt.DigestFor(str)
-	return fmt.Sprintf("%s--%s", str, digest), nil
+	if crypt.Encoding == Raw {
+		return string(rawAppend(nil, data, crypt.mac(data))), nil
+	}
+	// encode the data and its digest in a pooled buffer converted once to
+	// a string
+	sep := separatorOr(crypt.Separator)
+	n := crypt.Encoding.encodedLen(len(data), crypt.URLSafe)
+	buf := getBuffer(n)
+	defer putBuffer(buf)
+	crypt.Encoding.encodeTo(*buf, data, crypt.URLSafe)
+	digest := crypt.mac((*buf)[:n])
+	size := n + len(sep) + hex.EncodedLen(len(digest))
+	if cap(*buf) < size {
+		grown := make([]byte, size)
+		copy(grown, (*buf)[:n])
+		*buf = grown
+	}
+	*buf = (*buf)[:size]
+	copy((*buf)[n:], sep)
+	hex.Encode((*buf)[n+len(sep):], digest)
+	return string(*buf), nil
 }
 
 // DigestFor returns the digest form of a string after hashing it via
@@ -91,32 +200,22 @@ func (crypt *MessageVerifier) DigestFor(data string) string {
 		return "Y U SET NO SECRET???!"
 	}
 
-	mac := hmac.New(crypt.Hasher, crypt.Secret)
-	mac.Write([]byte(data))
-	return hex.EncodeToString(mac.Sum(nil))
+	return hex.EncodeToString(crypt.mac([]byte(data)))
 }
 
-// constant-time comparison algorithm to prevent timing attacks
-func (crypt *MessageVerifier) secureCompare(strA, strB string) bool {
-	a := []byte(strA)
-	b := []byte(strB)
-
-	if len(a) != len(b) {
-		return false
-	}
-	res := 0
-	for i := 0; i < len(a); i++ {
-		res |= int(b[i]) ^ int(a[i])
-	}
-	return res == 0
+// mac returns the HMAC of data using the verifier's digest and secret.
+func (crypt *MessageVerifier) mac(data []byte) []byte {
+	mac := hmac.New(crypt.Hasher, crypt.Secret)
+	mac.Write(data)
+	return mac.Sum(nil)
 }
 
 func (crypt *MessageVerifier) checkInit() error {
 	if crypt == nil {
-		return errors.New("MessageVerifier not set")
+		return notConfigured("MessageVerifier not set")
 	}
 	if crypt.Serializer == nil {
-		return errors.New("Serializer not set")
+		return notConfigured("Serializer not set")
 	}
 
 	if crypt.Hasher == nil {
@@ -125,7 +224,7 @@ func (crypt *MessageVerifier) checkInit() error {
 	}
 
 	if crypt.Secret == nil {
-		return errors.New("Secret not set")
+		return notConfigured("Secret not set")
 	}
 
 	return nil
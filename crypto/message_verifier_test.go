@@ -44,7 +44,7 @@ func TestMessageVerifier(t *testing.T) {
 
 			g.It("won't verify messages", func() {
 				var foo string
-				err := v.Verify("foo", foo)
+				err := v.Verify("foo", &foo)
 				g.Assert(err.Error()).Eql("Serializer not set")
 			})
 
@@ -95,7 +95,7 @@ func TestMessageVerifier(t *testing.T) {
 
 			g.It("won't verify messages", func() {
 				var foo string
-				err := v.Verify("foo", foo)
+				err := v.Verify("foo", &foo)
 				g.Assert(err.Error()).Eql("Secret not set")
 			})
 
@@ -0,0 +1,180 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrWrongPurpose is returned when a message was generated for another
+	// purpose than the one it is verified for.
+	ErrWrongPurpose = errors.New("message purpose mismatch")
+	// ErrExpired is returned when a message is verified after its
+	// expiration date.
+	ErrExpired = errors.New("message expired")
+)
+
+// MessageOptions are the metadata Rails 6+ can embed in encrypted and
+// signed messages. When set, the serialized message is wrapped in a
+// metadata envelope:
+//
+//	{"_rails":{"message":"<base64 serialized message>","exp":"2024-01-01T00:00:00.000Z","pur":"login"}}
+//
+// Rails then refuses to read the message after its expiration date or for
+// another purpose, and so does this package.
+type MessageOptions struct {
+	// Purpose restricts the use of the message, for instance Rails uses
+	// "cookie.<name>" for its cookies (see CookiePurpose).
+	Purpose string
+	// ExpiresAt is the expiration date of the message.
+	ExpiresAt time.Time
+	// ExpiresIn sets the expiration date relative to the generation of
+	// the message. ExpiresAt takes precedence if both are set.
+	ExpiresIn time.Duration
+}
+
+// CookiePurpose returns the purpose Rails 6+ uses for the cookies with the
+// passed name, including the session cookie.
+//
+//	CookiePurpose("_app_session") // => "cookie._app_session"
+func CookiePurpose(name string) string {
+	return "cookie." + name
+}
+
+// isZero reports whether no metadata is set.
+func (opts MessageOptions) isZero() bool {
+	return opts.Purpose == "" && opts.ExpiresAt.IsZero() && opts.ExpiresIn == 0
+}
+
+// expiry returns the expiration date formatted like Rails does, or nil.
+func (opts MessageOptions) expiry() *string {
+	expiresAt := opts.ExpiresAt
+	if expiresAt.IsZero() {
+		if opts.ExpiresIn == 0 {
+			return nil
+		}
+		expiresAt = time.Now().Add(opts.ExpiresIn)
+	}
+	exp := expiresAt.UTC().Format("2006-01-02T15:04:05.000Z07:00")
+	return &exp
+}
+
+// metadataEnvelope is the JSON envelope of the messages with metadata.
+// Rails 7.1 stores the message itself in "data" when the metadata is
+// serialized by the message serializer, and flags deflated messages with
+// "cmp".
+type metadataEnvelope struct {
+	Rails *struct {
+		Message *string         `json:"message,omitempty"`
+		Data    json.RawMessage `json:"data,omitempty"`
+		Exp     *string         `json:"exp"`
+		Pur     *string         `json:"pur"`
+		Cmp     bool            `json:"cmp"`
+	} `json:"_rails"`
+}
+
+// serialize serializes the value and wraps it in a metadata envelope if
+// any metadata is set or if it is compressed. The serialized value is
+// compressed when compressAbove is positive and the value is larger.
+func serialize(s MsgSerializer, value interface{}, opts MessageOptions, compressAbove int) ([]byte, error) {
+	data, err := serializeBytes(s, value)
+	if err != nil {
+		return nil, err
+	}
+	var cmp *bool
+	if compressAbove > 0 && len(data) > compressAbove {
+		if compressed, err := deflate(data); err == nil && len(compressed) < len(data) {
+			data, cmp = compressed, new(bool)
+			*cmp = true
+		}
+	}
+	if opts.isZero() && cmp == nil {
+		return data, nil
+	}
+	message := base64.StdEncoding.EncodeToString(data)
+	var pur *string
+	if opts.Purpose != "" {
+		pur = &opts.Purpose
+	}
+	return json.Marshal(map[string]interface{}{"_rails": struct {
+		Message string  `json:"message"`
+		Exp     *string `json:"exp"`
+		Pur     *string `json:"pur"`
+		Cmp     *bool   `json:"cmp,omitempty"`
+	}{message, opts.expiry(), pur, cmp}})
+}
+
+// rawMessage is the target of the raw methods, which get the serialized
+// message instead of unserializing it.
+type rawMessage []byte
+
+// unserialize extracts the message from its metadata envelope, checking
+// its purpose and expiration date, and unserializes it into target.
+func unserialize(s MsgSerializer, data []byte, target interface{}, purpose string) error {
+	data, err := verifyMetadata(data, purpose)
+	if err != nil {
+		return err
+	}
+	if raw, ok := target.(*rawMessage); ok {
+		*raw = append(rawMessage(nil), data...)
+		return nil
+	}
+	if bs, ok := s.(BytesMsgSerializer); ok {
+		return bs.UnserializeBytes(data, target)
+	}
+	return s.Unserialize(string(data), target)
+}
+
+// serializeBytes serializes value using the bytes method of s when it has
+// one.
+func serializeBytes(s MsgSerializer, value interface{}) ([]byte, error) {
+	if bs, ok := s.(BytesMsgSerializer); ok {
+		return bs.SerializeBytes(value)
+	}
+	str, err := s.Serialize(value)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(str), nil
+}
+
+// verifyMetadata returns the message wrapped in the metadata envelope,
+// inflated if it was compressed, or the data itself if it has no metadata.
+func verifyMetadata(data []byte, purpose string) ([]byte, error) {
+	var envelope metadataEnvelope
+	if !bytes.Contains(data, []byte(`"_rails"`)) || json.Unmarshal(data, &envelope) != nil || envelope.Rails == nil {
+		// like in Rails, messages without metadata have no purpose
+		if purpose != "" {
+			return nil, ErrWrongPurpose
+		}
+		return data, nil
+	}
+
+	metadata := envelope.Rails
+	if pur := metadata.Pur; (pur == nil && purpose != "") || (pur != nil && *pur != purpose) {
+		return nil, ErrWrongPurpose
+	}
+	if metadata.Exp != nil {
+		expiresAt, err := time.Parse(time.RFC3339, *metadata.Exp)
+		if err != nil {
+			return nil, malformed("bad expiry: %v", err)
+		}
+		if !time.Now().Before(expiresAt) {
+			return nil, ErrExpired
+		}
+	}
+	if metadata.Message == nil {
+		return metadata.Data, nil
+	}
+	message, err := base64.StdEncoding.DecodeString(*metadata.Message)
+	if err != nil {
+		return nil, malformed("bad message encoding: %v", err)
+	}
+	if !metadata.Cmp {
+		return message, nil
+	}
+	return inflate(message)
+}
@@ -0,0 +1,165 @@
+package crypto
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/franela/goblin"
+)
+
+func TestMessageMetadata(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("MessageEncryptor metadata", func() {
+		ciphers := []string{"aes-256-gcm", "aes-cbc"}
+		for _, cipher := range ciphers {
+			e := MessageEncryptor{Key: GenerateRandomKey(32), SignKey: []byte("sign secret"), Cipher: cipher}
+
+			g.It("round trips messages with a purpose using "+cipher, func() {
+				msg, err := e.EncryptAndSignWithOptions("data", MessageOptions{Purpose: "login"})
+				g.Assert(err).Eql(nil)
+				var out string
+				g.Assert(e.DecryptAndVerifyWithPurpose(msg, &out, "login")).Eql(nil)
+				g.Assert(out).Eql("data")
+				g.Assert(e.DecryptAndVerifyWithPurpose(msg, &out, "shipping")).Eql(ErrWrongPurpose)
+				g.Assert(e.DecryptAndVerify(msg, &out)).Eql(ErrWrongPurpose)
+			})
+
+			g.It("refuses expired messages using "+cipher, func() {
+				msg, err := e.EncryptAndSignWithOptions("data", MessageOptions{ExpiresIn: time.Hour})
+				g.Assert(err).Eql(nil)
+				var out string
+				g.Assert(e.DecryptAndVerify(msg, &out)).Eql(nil)
+				g.Assert(out).Eql("data")
+
+				msg, _ = e.EncryptAndSignWithOptions("data", MessageOptions{ExpiresAt: time.Now().Add(-time.Second)})
+				g.Assert(e.DecryptAndVerify(msg, &out)).Eql(ErrExpired)
+			})
+
+			g.It("refuses messages without purpose when one is expected using "+cipher, func() {
+				msg, _ := e.EncryptAndSign("data")
+				var out string
+				g.Assert(e.DecryptAndVerifyWithPurpose(msg, &out, "login")).Eql(ErrWrongPurpose)
+			})
+		}
+
+		g.It("writes the Rails envelope", func() {
+			data, err := serialize(JsonMsgSerializer{}, map[string]int{"id": 1}, MessageOptions{
+				Purpose:   "cookie._app_session",
+				ExpiresAt: time.Date(2030, 1, 2, 3, 4, 5, 600000000, time.FixedZone("CET", 3600)),
+			}, 0)
+			g.Assert(err).Eql(nil)
+			g.Assert(string(data)).Eql(`{"_rails":{"message":"eyJpZCI6MX0=","exp":"2030-01-02T02:04:05.600Z","pur":"cookie._app_session"}}`)
+
+			data, _ = serialize(JsonMsgSerializer{}, "hello", MessageOptions{ExpiresIn: time.Minute}, 0)
+			g.Assert(strings.Contains(string(data), `"pur":null`)).IsTrue()
+			data, _ = serialize(JsonMsgSerializer{}, "hello", MessageOptions{}, 0)
+			g.Assert(string(data)).Eql(`"hello"`)
+		})
+
+		g.It("reads the Rails 6 and Rails 7.1 envelopes", func() {
+			e := MessageEncryptor{Key: GenerateRandomKey(32), Cipher: "aes-256-gcm"}
+			rails6 := `{"_rails":{"message":"` + base64.StdEncoding.EncodeToString([]byte(`{"id":1}`)) + `","exp":null,"pur":"cookie.remember"}}`
+			rails71 := `{"_rails":{"data":{"id":1},"exp":"2999-01-01T00:00:00.000Z","pur":"cookie.remember"}}`
+			for _, plaintext := range []string{rails6, rails71} {
+				msg, err := e.appendParts(nil, aesGCM{keySize: 32}, []byte(plaintext))
+				g.Assert(err).Eql(nil)
+				var out map[string]int
+				g.Assert(e.DecryptAndVerifyWithPurpose(string(msg), &out, CookiePurpose("remember"))).Eql(nil)
+				g.Assert(out).Eql(map[string]int{"id": 1})
+			}
+		})
+
+		g.It("refuses the envelopes with a corrupted expiry or message", func() {
+			e := MessageEncryptor{Key: GenerateRandomKey(32), Cipher: "aes-256-gcm"}
+			for _, plaintext := range []string{
+				`{"_rails":{"data":{"id":1},"exp":"tomorrow","pur":null}}`,
+				`{"_rails":{"message":"not base64!","exp":null,"pur":null}}`,
+			} {
+				msg, _ := e.appendParts(nil, aesGCM{keySize: 32}, []byte(plaintext))
+				var out map[string]int
+				err := e.DecryptAndVerify(string(msg), &out)
+				g.Assert(errors.Is(err, ErrMalformedMessage)).IsTrue()
+			}
+		})
+
+		g.It("doesn't mistake regular messages for envelopes", func() {
+			e := MessageEncryptor{Key: GenerateRandomKey(32), Cipher: "aes-256-gcm"}
+			msg, _ := e.EncryptAndSign([]string{"_rails"})
+			var out []string
+			g.Assert(e.DecryptAndVerify(msg, &out)).Eql(nil)
+			g.Assert(out).Eql([]string{"_rails"})
+		})
+	})
+}
+
+func TestMessageVerifierMetadata(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("MessageVerifier metadata", func() {
+		v := MessageVerifier{Secret: []byte("Hey, I'm a secret!"), Serializer: JsonMsgSerializer{}}
+
+		g.It("round trips messages with a purpose", func() {
+			token, err := v.GenerateWithOptions(42, MessageOptions{Purpose: "password_reset"})
+			g.Assert(err).Eql(nil)
+			var id int
+			g.Assert(v.VerifyWithPurpose(token, &id, "password_reset")).Eql(nil)
+			g.Assert(id).Eql(42)
+			g.Assert(v.VerifyWithPurpose(token, &id, "login")).Eql(ErrWrongPurpose)
+			g.Assert(v.Verify(token, &id)).Eql(ErrWrongPurpose)
+		})
+
+		g.It("refuses expired messages", func() {
+			token, _ := v.GenerateWithOptions(42, MessageOptions{ExpiresAt: time.Now().Add(-time.Minute)})
+			var id int
+			g.Assert(v.Verify(token, &id)).Eql(ErrExpired)
+
+			token, _ = v.GenerateWithOptions(42, MessageOptions{ExpiresAt: time.Now().Add(time.Minute), ExpiresIn: -time.Minute})
+			g.Assert(v.Verify(token, &id)).Eql(nil)
+		})
+
+		g.It("signs the Rails envelope", func() {
+			token, _ := v.GenerateWithOptions("hello", MessageOptions{Purpose: "greeting"})
+			data, _ := base64.StdEncoding.DecodeString(strings.Split(token, "--")[0])
+			g.Assert(string(data)).Eql(`{"_rails":{"message":"ImhlbGxvIg==","exp":null,"pur":"greeting"}}`)
+		})
+
+		g.It("still verifies messages without metadata", func() {
+			token, _ := v.Generate("hello")
+			var out string
+			g.Assert(v.Verify(token, &out)).Eql(nil)
+			g.Assert(out).Eql("hello")
+			g.Assert(v.VerifyWithPurpose(token, &out, "greeting")).Eql(ErrWrongPurpose)
+		})
+	})
+}
+
+func ExampleMessageVerifier_GenerateWithOptions() {
+	v := MessageVerifier{Secret: []byte("Hey, I'm a secret!"), Serializer: JsonMsgSerializer{}}
+	token, _ := v.GenerateWithOptions(42, MessageOptions{Purpose: "password_reset", ExpiresIn: 15 * time.Minute})
+
+	var userID int
+	err := v.VerifyWithPurpose(token, &userID, "password_reset")
+	fmt.Println(userID, err)
+	// Output: 42 <nil>
+}
+
+func ExampleMessageEncryptor_EncryptAndSignWithOptions() {
+	e := MessageEncryptor{Key: GenerateRandomKey(32), Cipher: "aes-256-gcm"}
+	msg, _ := e.EncryptAndSignWithOptions(map[string]int{"user_id": 42}, MessageOptions{
+		Purpose:   CookiePurpose("remember_token"),
+		ExpiresIn: 14 * 24 * time.Hour,
+	})
+
+	var session map[string]int
+	err := e.DecryptAndVerifyWithPurpose(msg, &session, "cookie.remember_token")
+	fmt.Println(session, err)
+	err = e.DecryptAndVerifyWithPurpose(msg, &session, "cookie.other")
+	fmt.Println(err)
+	// Output: map[user_id:42] <nil>
+	// message purpose mismatch
+}
@@ -1,24 +1,65 @@
 package crypto
 
 import (
-	"errors"
 	"fmt"
 	"reflect"
 )
 
+// NullMsgSerializer passes strings through as is, for the messages which
+// are already serialized, like the ones signed by the verifier of an
+// encryptor.
 type NullMsgSerializer struct{}
 
+// Serialize returns strings and []byte as is, and the String method of the
+// fmt.Stringer values. The other values are formatted by fmt.Sprint.
 func (s NullMsgSerializer) Serialize(vptr interface{}) (string, error) {
+	switch v := vptr.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	}
 	return fmt.Sprint(vptr), nil
 }
 
-// Can only deserialize to a string.
+// Can deserialize to a pointer to a string, a []byte or an interface{},
+// including the types defined from them.
 func (s NullMsgSerializer) Unserialize(data string, vptr interface{}) error {
-	typ := reflect.TypeOf(vptr)
-	if typ.Kind() != reflect.Ptr {
-		errors.New("You passed an interface which isn't a pointer")
+	if err := checkTarget(vptr); err != nil {
+		return err
 	}
 	v := reflect.ValueOf(vptr).Elem()
-	v.SetString(data)
+	switch {
+	case v.Kind() == reflect.String:
+		v.SetString(data)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		v.SetBytes([]byte(data))
+	case v.Kind() == reflect.Interface && v.NumMethod() == 0:
+		v.Set(reflect.ValueOf(data))
+	default:
+		return fmt.Errorf("crypto: NullMsgSerializer can't unserialize to a %T, only to a *string, a *[]byte or an *interface{}", vptr)
+	}
 	return nil
 }
+
+// SerializeBytes passes []byte values through as is, so binary payloads
+// aren't formatted.
+func (s NullMsgSerializer) SerializeBytes(vptr interface{}) ([]byte, error) {
+	if b, ok := vptr.([]byte); ok {
+		// cap b so the padding of the ciphers isn't appended to it
+		return b[:len(b):len(b)], nil
+	}
+	str, err := s.Serialize(vptr)
+	return []byte(str), err
+}
+
+// Can deserialize to a string or a []byte.
+func (s NullMsgSerializer) UnserializeBytes(data []byte, vptr interface{}) error {
+	if b, ok := vptr.(*[]byte); ok {
+		*b = append([]byte(nil), data...)
+		return nil
+	}
+	return s.Unserialize(string(data), vptr)
+}
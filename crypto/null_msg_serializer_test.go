@@ -1,7 +1,10 @@
 package crypto
 
 import (
+	"errors"
+	"io"
 	"testing"
+	"time"
 
 	. "github.com/franela/goblin"
 )
@@ -40,4 +43,62 @@ func TestNullSerializerSerializer(t *testing.T) {
 		})
 	})
 
+	g.Describe("a null serialized value", func() {
+		g.It("uses the String method of the fmt.Stringer values", func() {
+			output, err := serializer.Serialize(time.Duration(90) * time.Second)
+			g.Assert(err).Eql(nil)
+			g.Assert(output).Eql("1m30s")
+		})
+
+		g.It("keeps the []byte values as is", func() {
+			output, err := serializer.Serialize([]byte("raw"))
+			g.Assert(err).Eql(nil)
+			g.Assert(output).Eql("raw")
+		})
+	})
+
+	g.Describe("unserializing", func() {
+		g.It("supports the strings, []byte and interface{} targets", func() {
+			type token string
+			var tok token
+			g.Assert(serializer.Unserialize("abc", &tok)).Eql(nil)
+			g.Assert(tok).Eql(token("abc"))
+			var b []byte
+			g.Assert(serializer.Unserialize("abc", &b)).Eql(nil)
+			g.Assert(b).Eql([]byte("abc"))
+			var i interface{}
+			g.Assert(serializer.Unserialize("abc", &i)).Eql(nil)
+			g.Assert(i).Eql("abc")
+		})
+
+		g.It("reports the unsupported targets", func() {
+			var o string
+			err := serializer.Unserialize("abc", o)
+			g.Assert(errors.Is(err, ErrNotAPointer)).IsTrue()
+			var n int
+			err = serializer.Unserialize("abc", &n)
+			g.Assert(err.Error()).Eql("crypto: NullMsgSerializer can't unserialize to a *int, only to a *string, a *[]byte or an *interface{}")
+			var r io.Reader
+			g.Assert(serializer.Unserialize("abc", &r) != nil).IsTrue()
+		})
+	})
+
+	g.Describe("a null serialized []byte", func() {
+		data := []byte{0, 1, 0xff}
+
+		g.It("is passed through", func() {
+			output, err := serializer.SerializeBytes(data)
+			g.Assert(err).Eql(nil)
+			g.Assert(output).Eql(data)
+		})
+
+		g.It("can be deserialized to a copy", func() {
+			var o []byte
+			g.Assert(serializer.UnserializeBytes(data, &o)).Eql(nil)
+			g.Assert(o).Eql(data)
+			o[0] = 2
+			g.Assert(data[0]).Eql(byte(0))
+		})
+	})
+
 }
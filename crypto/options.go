@@ -0,0 +1,159 @@
+package crypto
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"hash"
+)
+
+// Option configures the encryptors and verifiers built by
+// NewMessageEncryptor and NewMessageVerifier. The options which don't
+// apply to a type are ignored.
+type Option func(*options)
+
+type options struct {
+	cipher     string
+	serializer MsgSerializer
+	signKey    []byte
+	signDigest func() hash.Hash
+	hasher     func() hash.Hash
+	fallbacks  []func() hash.Hash
+	encoding   Encoding
+	separator  string
+	rotations  []rotation
+}
+
+type rotation struct {
+	key        []byte
+	cipher     string
+	serializer MsgSerializer
+}
+
+// WithCipher sets the cipher, aes-cbc by default.
+func WithCipher(name string) Option { return func(o *options) { o.cipher = name } }
+
+// WithSerializer sets the serializer, JsonMsgSerializer by default.
+func WithSerializer(s MsgSerializer) Option {
+	return func(o *options) { o.serializer = s }
+}
+
+// WithSignKey sets the key signing the messages of the ciphers which don't
+// authenticate them, like aes-cbc, and its digest, sha1 if nil.
+func WithSignKey(key []byte, digest func() hash.Hash) Option {
+	return func(o *options) { o.signKey, o.signDigest = key, digest }
+}
+
+// WithHasher sets the digest of the verifiers, sha1 by default.
+func WithHasher(h func() hash.Hash) Option { return func(o *options) { o.hasher = h } }
+
+// WithFallbackHashers sets the digests the verifiers still accept, see
+// MessageVerifier.FallbackHashers.
+func WithFallbackHashers(h ...func() hash.Hash) Option {
+	return func(o *options) { o.fallbacks = append(o.fallbacks, h...) }
+}
+
+// WithEncoding sets the encoding of the messages, Base64 by default.
+func WithEncoding(e Encoding) Option { return func(o *options) { o.encoding = e } }
+
+// WithSeparator sets the separator of the message parts, "--" by default.
+func WithSeparator(sep string) Option { return func(o *options) { o.separator = sep } }
+
+// WithRotation registers an older key, cipher and serializer still accepted
+// when decrypting, see MessageEncryptor.Rotate.
+func WithRotation(key []byte, cipher string, serializer MsgSerializer) Option {
+	return func(o *options) { o.rotations = append(o.rotations, rotation{key, cipher, serializer}) }
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{serializer: JsonMsgSerializer{}, hasher: sha1.New}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// NewMessageEncryptor returns an encryptor using key and the options,
+// checking upfront that the cipher is supported, that the keys have the
+// size of the ciphers and that a signature key is set when the cipher
+// needs one:
+//
+//	e, err := NewMessageEncryptor(key, WithCipher("aes-256-gcm"), WithRotation(oldKey, "aes-cbc", nil))
+//
+// The returned encryptor uses StrictKeys.
+func NewMessageEncryptor(key []byte, opts ...Option) (*MessageEncryptor, error) {
+	o := newOptions(opts)
+	if o.serializer == nil {
+		return nil, notConfigured("crypto: serializer not set")
+	}
+	crypt := &MessageEncryptor{
+		Key:        key,
+		SignKey:    o.signKey,
+		SignDigest: o.signDigest,
+		Cipher:     o.cipher,
+		StrictKeys: true,
+		Serializer: o.serializer,
+		Encoding:   o.encoding,
+		Separator:  o.separator,
+	}
+	if err := crypt.validate(); err != nil {
+		return nil, err
+	}
+	for _, r := range o.rotations {
+		crypt.Rotate(r.key, r.cipher, r.serializer)
+		rotated := crypt.rotations[len(crypt.rotations)-1]
+		rotated.StrictKeys = true
+		if err := rotated.validate(); err != nil {
+			return nil, fmt.Errorf("%w (rotation)", err)
+		}
+	}
+	return crypt, nil
+}
+
+// validate returns an error if the encryptor can't encrypt messages.
+func (crypt *MessageEncryptor) validate() error {
+	c, err := lookupCipher(crypt.Cipher)
+	if err != nil {
+		return notConfigured("crypto: unsupported cipher %q", crypt.Cipher)
+	}
+	if _, err := crypt.cipherKey(c); err != nil {
+		return err
+	}
+	if crypt.Serializer == nil {
+		return notConfigured("crypto: serializer not set")
+	}
+	if c.TagSize() == 0 && crypt.Verifier == nil && len(crypt.SignKey) == 0 {
+		return notConfigured("crypto: the %s cipher needs a signature key", crypt.cipherName())
+	}
+	return nil
+}
+
+// NewMessageVerifier returns a verifier signing messages with secret and
+// the options, by default using sha1 and the JSON serializer like Rails:
+//
+//	v, err := NewMessageVerifier(secret, WithHasher(sha256.New))
+//	token, err := v.Generate(userID)
+func NewMessageVerifier(secret []byte, opts ...Option) (*MessageVerifier, error) {
+	o := newOptions(opts)
+	if len(secret) == 0 {
+		return nil, notConfigured("crypto: secret not set")
+	}
+	if o.serializer == nil {
+		return nil, notConfigured("crypto: serializer not set")
+	}
+	if o.hasher == nil {
+		return nil, notConfigured("crypto: hasher not set")
+	}
+	for _, h := range o.fallbacks {
+		if h == nil {
+			return nil, notConfigured("crypto: fallback hasher not set")
+		}
+	}
+	return &MessageVerifier{
+		Secret:          secret,
+		Hasher:          o.hasher,
+		FallbackHashers: o.fallbacks,
+		Serializer:      o.serializer,
+		Encoding:        o.encoding,
+		Separator:       o.separator,
+	}, nil
+}
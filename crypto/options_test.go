@@ -0,0 +1,142 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestNewMessageEncryptor(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("NewMessageEncryptor", func() {
+		key := GenerateRandomKey(32)
+
+		g.It("builds working encryptors", func() {
+			e, err := NewMessageEncryptor(key, WithCipher("aes-256-gcm"), WithEncoding(Hex), WithSeparator("."))
+			g.Assert(err).Eql(nil)
+			msg, err := e.EncryptAndSign("data")
+			g.Assert(err).Eql(nil)
+			g.Assert(strings.Count(msg, ".")).Eql(2)
+			var out string
+			g.Assert(e.DecryptAndVerify(msg, &out)).Eql(nil)
+			g.Assert(out).Eql("data")
+		})
+
+		g.It("signs the aes-cbc messages with the sign key and digest", func() {
+			signKey := GenerateRandomKey(64)
+			e, err := NewMessageEncryptor(key, WithSignKey(signKey, sha256.New), WithSerializer(NullMsgSerializer{}))
+			g.Assert(err).Eql(nil)
+			msg, _ := e.EncryptAndSign("data")
+			legacy := MessageEncryptor{Key: key, SignKey: signKey, SignDigest: sha256.New, Serializer: NullMsgSerializer{}}
+			var out string
+			g.Assert(legacy.DecryptAndVerify(msg, &out)).Eql(nil)
+			g.Assert(out).Eql("data")
+		})
+
+		g.It("reads the messages of the rotations", func() {
+			oldKey := GenerateRandomKey(16)
+			old := MessageEncryptor{Key: oldKey, Cipher: "aes-128-gcm"}
+			msg, _ := old.EncryptAndSign("data")
+			e, err := NewMessageEncryptor(key, WithCipher("aes-256-gcm"), WithRotation(oldKey, "aes-128-gcm", nil))
+			g.Assert(err).Eql(nil)
+			var out string
+			g.Assert(e.DecryptAndVerify(msg, &out)).Eql(nil)
+			g.Assert(out).Eql("data")
+		})
+
+		g.It("refuses unsupported ciphers", func() {
+			_, err := NewMessageEncryptor(key, WithCipher("rot13"))
+			g.Assert(err.Error()).Eql(`crypto: unsupported cipher "rot13"`)
+		})
+
+		g.It("refuses keys of the wrong size", func() {
+			_, err := NewMessageEncryptor(GenerateRandomKey(64), WithCipher("aes-256-gcm"))
+			g.Assert(err.Error()).Eql("crypto: the aes-256-gcm cipher needs a 32 byte key, got 64 bytes")
+			_, err = NewMessageEncryptor(key, WithCipher("aes-256-gcm"), WithRotation(key, "aes-128-gcm", nil))
+			g.Assert(err.Error()).Eql("crypto: the aes-128-gcm cipher needs a 16 byte key, got 32 bytes (rotation)")
+		})
+
+		g.It("refuses nil serializers", func() {
+			_, err := NewMessageEncryptor(key, WithCipher("aes-256-gcm"), WithSerializer(nil))
+			g.Assert(err.Error()).Eql("crypto: serializer not set")
+		})
+
+		g.It("refuses aes-cbc without a sign key", func() {
+			_, err := NewMessageEncryptor(key)
+			g.Assert(err.Error()).Eql("crypto: the aes-cbc cipher needs a signature key")
+		})
+	})
+}
+
+func TestNewMessageVerifier(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("NewMessageVerifier", func() {
+		secret := []byte("secret")
+
+		g.It("uses sha1 and JSON by default like Rails", func() {
+			v, err := NewMessageVerifier(secret)
+			g.Assert(err).Eql(nil)
+			token, _ := v.Generate("hello")
+			g.Assert(token).Eql("ImhlbGxvIg==--" + v.DigestFor("ImhlbGxvIg=="))
+			legacy := MessageVerifier{Secret: secret, Serializer: JsonMsgSerializer{}}
+			var out string
+			g.Assert(legacy.Verify(token, &out)).Eql(nil)
+			g.Assert(out).Eql("hello")
+		})
+
+		g.It("uses the options", func() {
+			v, err := NewMessageVerifier(secret, WithHasher(sha256.New), WithSerializer(NullMsgSerializer{}), WithEncoding(Hex))
+			g.Assert(err).Eql(nil)
+			token, _ := v.Generate("hello")
+			g.Assert(strings.HasPrefix(token, "68656c6c6f--")).IsTrue()
+			g.Assert(len(token)).Eql(10 + 2 + 64)
+			var out string
+			g.Assert(v.Verify(token, &out)).Eql(nil)
+			g.Assert(out).Eql("hello")
+		})
+
+		g.It("refuses incomplete configurations", func() {
+			_, err := NewMessageVerifier(nil)
+			g.Assert(err.Error()).Eql("crypto: secret not set")
+			_, err = NewMessageVerifier(secret, WithSerializer(nil))
+			g.Assert(err.Error()).Eql("crypto: serializer not set")
+			_, err = NewMessageVerifier(secret, WithHasher(nil))
+			g.Assert(err.Error()).Eql("crypto: hasher not set")
+		})
+	})
+}
+
+func ExampleNewMessageVerifier() {
+	v, err := NewMessageVerifier([]byte("Hey, I'm a secret!"))
+	if err != nil {
+		panic(err)
+	}
+	token, _ := v.Generate("hello")
+	var data string
+	v.Verify(token, &data)
+	fmt.Println(data)
+	// Output: hello
+}
+
+func ExampleNewMessageEncryptor() {
+	key := make([]byte, 32)
+	e, err := NewMessageEncryptor(key, WithCipher("aes-256-gcm"))
+	if err != nil {
+		panic(err)
+	}
+	msg, _ := e.EncryptAndSign("secret data")
+	var data string
+	e.DecryptAndVerify(msg, &data)
+	fmt.Println(data)
+
+	_, err = NewMessageEncryptor(key[:20], WithCipher("aes-256-gcm"))
+	fmt.Println(err)
+	// Output:
+	// secret data
+	// crypto: the aes-256-gcm cipher needs a 32 byte key, got 20 bytes
+}
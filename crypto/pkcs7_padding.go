@@ -1,7 +1,19 @@
 package crypto
 
+import (
+	"crypto/subtle"
+	"fmt"
+)
+
+// ErrInvalidPadding is returned by PKCS7UnpadN when the padding bytes are
+// inconsistent or out of range.
+var ErrInvalidPadding = malformed("invalid padding")
+
 // PKCS7Pad() pads an byte array to be a multiple of 16
 // http://tools.ietf.org/html/rfc5652#section-6.3
+//
+// Deprecated: data which is already a multiple of 16 isn't padded, so the
+// padding can't be removed reliably. Use PKCS7PadN.
 func PKCS7Pad(data []byte) []byte {
 	dataLen := len(data)
 
@@ -24,6 +36,8 @@ func PKCS7Pad(data []byte) []byte {
 }
 
 // PKCS7Unpad() removes any potential PKCS7 padding added.
+//
+// Deprecated: invalid paddings are silently kept. Use PKCS7UnpadN.
 func PKCS7Unpad(data []byte) []byte {
 	dataLen := len(data)
     // Edge case
@@ -39,3 +53,44 @@ func PKCS7Unpad(data []byte) []byte {
 	}
 	return data
 }
+
+// PKCS7PadN pads data to a multiple of blockSize, which must be between 1
+// and 255, as specified by http://tools.ietf.org/html/rfc5652#section-6.3:
+// 1 to blockSize bytes are always added, a whole block if data is already a
+// multiple of blockSize. The padding is appended to data.
+func PKCS7PadN(data []byte, blockSize int) ([]byte, error) {
+	if blockSize < 1 || blockSize > 255 {
+		return nil, fmt.Errorf("crypto: invalid block size %d", blockSize)
+	}
+	paddingLen := blockSize - len(data)%blockSize
+	for i := 0; i < paddingLen; i++ {
+		data = append(data, byte(paddingLen))
+	}
+	return data, nil
+}
+
+// PKCS7UnpadN removes the padding added by PKCS7PadN with blockSize. It
+// returns ErrInvalidPadding if data isn't a multiple of blockSize or if its
+// padding bytes are inconsistent or out of range. The padding is checked in
+// constant time: the whole last block is read whatever the padding, so the
+// timing doesn't tell which byte is invalid.
+func PKCS7UnpadN(data []byte, blockSize int) ([]byte, error) {
+	if blockSize < 1 || blockSize > 255 {
+		return nil, fmt.Errorf("crypto: invalid block size %d", blockSize)
+	}
+	if len(data) == 0 || len(data)%blockSize != 0 {
+		return nil, ErrInvalidPadding
+	}
+	last := data[len(data)-1]
+	paddingLen := int(last)
+	good := subtle.ConstantTimeLessOrEq(1, paddingLen) & subtle.ConstantTimeLessOrEq(paddingLen, blockSize)
+	for i := 1; i <= blockSize; i++ {
+		// the bytes before the padding can have any value
+		inPadding := subtle.ConstantTimeLessOrEq(i, paddingLen)
+		good &= subtle.ConstantTimeSelect(inPadding, subtle.ConstantTimeByteEq(data[len(data)-i], last), 1)
+	}
+	if good != 1 {
+		return nil, ErrInvalidPadding
+	}
+	return data[:len(data)-paddingLen], nil
+}
@@ -2,6 +2,8 @@ package crypto
 
 import (
 	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
 	"testing"
 )
 
@@ -61,3 +63,57 @@ func TestPKCS7Unpad(t *testing.T) {
 		}
 	}
 }
+
+func TestPKCS7PadN(t *testing.T) {
+	for i, c := range []struct {
+		in        []byte
+		blockSize int
+		out       []byte
+	}{
+		0: {nil, 4, []byte{4, 4, 4, 4}},
+		1: {[]byte{0}, 4, []byte{0, 3, 3, 3}},
+		2: {[]byte{0, 0, 0}, 4, []byte{0, 0, 0, 1}},
+		3: {[]byte{0, 0, 0, 0}, 4, []byte{0, 0, 0, 0, 4, 4, 4, 4}},
+		4: {[]byte{0}, 1, []byte{0, 1}},
+		5: {bytes.Repeat([]byte{0}, 16), 16, append(bytes.Repeat([]byte{0}, 16), bytes.Repeat([]byte{16}, 16)...)},
+	} {
+		got, err := PKCS7PadN(c.in, c.blockSize)
+		if err != nil || !bytes.Equal(c.out, got) {
+			t.Errorf("%d: expected %x, got %x (%v)", i, c.out, got, err)
+		}
+		unpadded, err := PKCS7UnpadN(got, c.blockSize)
+		if err != nil || !bytes.Equal(c.in, unpadded) {
+			t.Errorf("%d: expected %x, got %x (%v)", i, c.in, unpadded, err)
+		}
+	}
+	for _, blockSize := range []int{0, 256} {
+		if _, err := PKCS7PadN(nil, blockSize); err == nil {
+			t.Errorf("block size %d: expected an error", blockSize)
+		}
+	}
+}
+
+func TestPKCS7UnpadNInvalid(t *testing.T) {
+	for i, in := range [][]byte{
+		0: nil,
+		1: {0, 0, 1},
+		2: {0, 0, 0, 0},
+		3: {0, 0, 0, 5},
+		4: {0, 2, 3, 3},
+		5: {1, 4, 4, 4},
+	} {
+		if _, err := PKCS7UnpadN(in, 4); err != ErrInvalidPadding {
+			t.Errorf("%d: expected ErrInvalidPadding, got %v", i, err)
+		}
+	}
+}
+
+func TestAesCbcInvalidPadding(t *testing.T) {
+	key, iv := GenerateRandomKey(32), GenerateRandomKey(aes.BlockSize)
+	block, _ := aes.NewCipher(key)
+	ciphertext := make([]byte, aes.BlockSize)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, bytes.Repeat([]byte{'x'}, aes.BlockSize))
+	if _, err := (aesCBC{keySize: 32}).Decrypt(key, iv, ciphertext, nil, nil); err != ErrInvalidPadding {
+		t.Errorf("expected ErrInvalidPadding, got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package crypto
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/franela/goblin"
+)
+
+func TestRawMessages(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("DecryptAndVerifyRaw", func() {
+		key := GenerateRandomKey(32)
+
+		g.It("returns the serialized message", func() {
+			for _, cipher := range []string{"aes-cbc", "aes-256-gcm"} {
+				e := MessageEncryptor{Key: key, SignKey: key, Cipher: cipher}
+				msg, _ := e.EncryptAndSign(map[string]interface{}{"v": 2, "user_id": "42"})
+				data, err := e.DecryptAndVerifyRaw(msg, "")
+				g.Assert(err).Eql(nil)
+				g.Assert(string(data)).Eql(`{"user_id":"42","v":2}`)
+			}
+		})
+
+		g.It("strips the metadata and checks the purpose", func() {
+			e := MessageEncryptor{Key: key, Cipher: "aes-256-gcm", Compress: true, CompressThreshold: 10}
+			value := map[string]string{"payload": "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"}
+			msg, _ := e.EncryptAndSignWithOptions(value, MessageOptions{Purpose: "cookie._app_session", ExpiresIn: time.Hour})
+			data, err := e.DecryptAndVerifyRaw(msg, "cookie._app_session")
+			g.Assert(err).Eql(nil)
+			var out map[string]string
+			g.Assert(json.Unmarshal(data, &out)).Eql(nil)
+			g.Assert(out).Eql(value)
+
+			_, err = e.DecryptAndVerifyRaw(msg, "")
+			g.Assert(errors.Is(err, ErrWrongPurpose)).IsTrue()
+		})
+
+		g.It("uses the rotations", func() {
+			oldKey := GenerateRandomKey(32)
+			old := MessageEncryptor{Key: oldKey, SignKey: oldKey}
+			msg, _ := old.EncryptAndSign("data")
+			e := MessageEncryptor{Key: key, Cipher: "aes-256-gcm"}
+			e.RotateEncryptor(&old)
+			data, err := e.DecryptAndVerifyRaw(msg, "")
+			g.Assert(err).Eql(nil)
+			g.Assert(string(data)).Eql(`"data"`)
+		})
+
+		g.It("returns the decryption errors", func() {
+			e := MessageEncryptor{Key: key, Cipher: "aes-256-gcm"}
+			data, err := e.DecryptAndVerifyRaw("bad", "")
+			g.Assert(errors.Is(err, ErrMalformedMessage)).IsTrue()
+			g.Assert(data == nil).IsTrue()
+		})
+	})
+
+	g.Describe("VerifyRaw", func() {
+		g.It("returns the serialized message", func() {
+			v := MessageVerifier{Secret: []byte("secret"), Serializer: JsonMsgSerializer{}}
+			token, _ := v.GenerateWithOptions([]int{1, 2}, MessageOptions{Purpose: "ids"})
+			data, err := v.VerifyRaw(token, "ids")
+			g.Assert(err).Eql(nil)
+			g.Assert(string(data)).Eql("[1,2]")
+		})
+	})
+}
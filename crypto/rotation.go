@@ -0,0 +1,135 @@
+package crypto
+
+import (
+	"context"
+	"errors"
+	"hash"
+)
+
+// Rotate registers an older key, cipher and serializer still accepted by
+// DecryptAndVerify, while new messages are encrypted with the current
+// configuration. This allows rolling secret_key_base or changing the cipher
+// without invalidating every session. An empty cipher or a nil serializer
+// means the one of the encryptor, and aes-cbc rotations use the signature
+// key or verifier of the encryptor. The other options, like AuthData, are
+// the ones of the encryptor.
+//
+//	e := MessageEncryptor{Key: newKey, Cipher: "aes-256-gcm"}
+//	e.Rotate(oldKey, "aes-cbc", nil)
+//
+// Rotations are tried in the order they were added. Rotate isn't safe to
+// call while the encryptor is in use.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/MessageEncryptor.html#method-i-rotate
+func (crypt *MessageEncryptor) Rotate(key []byte, cipher string, serializer MsgSerializer) {
+	if cipher == "" {
+		cipher = crypt.Cipher
+	}
+	if serializer == nil {
+		serializer = crypt.Serializer
+	}
+	crypt.RotateEncryptor(&MessageEncryptor{
+		Key:              key,
+		SignKey:          crypt.SignKey,
+		SignDigest:       crypt.SignDigest,
+		Cipher:           cipher,
+		StrictKeys:       crypt.StrictKeys,
+		Verifier:         crypt.Verifier,
+		Serializer:       serializer,
+		Encoding:         crypt.Encoding,
+		Separator:        crypt.Separator,
+		AuthData:         crypt.AuthData,
+		AllowUnsignedCBC: crypt.AllowUnsignedCBC,
+		LegacyPadding:    crypt.LegacyPadding,
+	})
+}
+
+// RotateEncryptor is like Rotate but registers a fully configured
+// encryptor, for instance to use another signature key.
+func (crypt *MessageEncryptor) RotateEncryptor(old *MessageEncryptor) {
+	crypt.rotations = append(crypt.rotations, old)
+}
+
+// decryptAndVerifyWithRotations decrypts the message with the current
+// configuration, then with the rotations. The error of the current
+// configuration is returned if none of them can decrypt the message.
+func (crypt *MessageEncryptor) decryptAndVerifyWithRotations(ctx context.Context, msg []byte, target interface{}, purpose string) error {
+	err := crypt.decryptAndVerify(ctx, msg, target, purpose)
+	if err == nil {
+		return err
+	}
+	for _, rotation := range crypt.rotations {
+		if rotation.decryptAndVerify(ctx, msg, target, purpose) == nil {
+			if crypt.OnRotation != nil {
+				crypt.OnRotation()
+			}
+			return nil
+		}
+	}
+	return err
+}
+
+// Rotate registers an older secret and hasher still accepted by Verify,
+// while new messages are signed with the current configuration. A nil
+// hasher means the one of the verifier. OnRotation is called when a message
+// is verified using a rotated secret, which is the time to generate a
+// new message:
+//
+//	v := MessageVerifier{Secret: newSecret, Hasher: sha256.New, Serializer: JsonMsgSerializer{}}
+//	v.Rotate(oldSecret, sha1.New)
+//	v.OnRotation = func() { log.Println("legacy remember-me token") }
+//
+// Rotations are tried in the order they were added. Rotate isn't safe to
+// call while the verifier is in use.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/MessageVerifier.html#method-i-rotate
+func (crypt *MessageVerifier) Rotate(secret []byte, hasher func() hash.Hash) {
+	if hasher == nil {
+		hasher = crypt.Hasher
+	}
+	crypt.RotateVerifier(&MessageVerifier{
+		Secret:     secret,
+		Hasher:     hasher,
+		Serializer: crypt.Serializer,
+		Encoding:   crypt.Encoding,
+		Separator:  crypt.Separator,
+	})
+}
+
+// RotateVerifier is like Rotate but registers a fully configured verifier,
+// for instance to use another serializer.
+func (crypt *MessageVerifier) RotateVerifier(old *MessageVerifier) {
+	crypt.rotations = append(crypt.rotations, old)
+}
+
+// verifyWithRotations verifies the message with the current configuration,
+// then with the fallback hashers and the rotations. The error of the current configuration is
+// returned if none of them can verify the message.
+func (crypt *MessageVerifier) verifyWithRotations(msg string, target interface{}, purpose string) error {
+	err := crypt.verify(msg, target, purpose)
+	if err == nil || crypt == nil {
+		return err
+	}
+	// the fallback hashers can only help if the signature doesn't match
+	if errors.Is(err, ErrInvalidSignature) {
+		for _, hasher := range crypt.FallbackHashers {
+			fallback := *crypt
+			fallback.Hasher = hasher
+			if fallback.verify(msg, target, purpose) == nil {
+				if crypt.OnRotation != nil {
+					crypt.OnRotation()
+				}
+				return nil
+			}
+		}
+	}
+	for _, rotation := range crypt.rotations {
+		if rotation.verify(msg, target, purpose) == nil {
+			if crypt.OnRotation != nil {
+				crypt.OnRotation()
+			}
+			return nil
+		}
+	}
+	return err
+}
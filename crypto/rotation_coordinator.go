@@ -0,0 +1,160 @@
+package crypto
+
+import (
+	"crypto/sha1"
+	"hash"
+	"sync"
+)
+
+// RotationConfig is a secret configuration of a RotationCoordinator.
+type RotationConfig struct {
+	// SecretKeyBase is the secret the keys are derived from.
+	SecretKeyBase string
+	// Iterations is the number of PBKDF2 iterations used to derive the
+	// keys, 1000 like Rails if not set.
+	Iterations int
+	// HashDigest is the digest used to derive the keys, sha1 if not set.
+	// Rails 7+ apps derive their keys using sha256.
+	HashDigest func() hash.Hash
+	// Hasher is the digest of the verifiers, sha1 like Rails if not set.
+	Hasher func() hash.Hash
+	// Cipher is the cipher of the encryptors, aes-256-gcm like Rails if not
+	// set.
+	Cipher string
+	// Serializer is the serializer of the verifiers and encryptors, JSON if
+	// not set.
+	Serializer MsgSerializer
+}
+
+// RotationCoordinator builds the verifiers and encryptors of an
+// application, deriving a key per purpose from secret_key_base and
+// registering the older configurations as rotations, like
+// Rails.application.message_verifiers and message_encryptors:
+//
+//	coordinator := NewRotationCoordinator(secretKeyBase, RotationConfig{SecretKeyBase: oldSecretKeyBase})
+//	verifier := coordinator.Verifier("active_storage")
+//	encryptor := coordinator.Encryptor("my_feature")
+//
+// The verifiers and encryptors are built once per purpose and can be used
+// concurrently.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/MessageVerifiers.html
+type RotationCoordinator struct {
+	// Current is the configuration used to generate new messages.
+	Current RotationConfig
+	// Rotations are the older configurations still accepted, tried in
+	// order. Like Current, their empty fields use the Rails defaults.
+	Rotations []RotationConfig
+	// OnRotation is called when a message is read using one of the
+	// rotations.
+	OnRotation func()
+
+	mu         sync.Mutex
+	verifiers  map[string]*MessageVerifier
+	encryptors map[string]*MessageEncryptor
+}
+
+// NewRotationCoordinator returns a coordinator using secretKeyBase and the
+// Rails defaults for new messages and accepting the older configurations.
+func NewRotationCoordinator(secretKeyBase string, older ...RotationConfig) *RotationCoordinator {
+	return &RotationCoordinator{
+		Current:   RotationConfig{SecretKeyBase: secretKeyBase},
+		Rotations: older,
+	}
+}
+
+// Rotate adds an older configuration. The verifiers and encryptors
+// already built aren't affected.
+func (c *RotationCoordinator) Rotate(config RotationConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Rotations = append(c.Rotations, config)
+}
+
+// Verifier returns the verifier of the purpose, its secret being derived
+// using the purpose as salt, like Rails.application.message_verifier.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Rails/Application.html#method-i-message_verifier
+func (c *RotationCoordinator) Verifier(purpose string) *MessageVerifier {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v, ok := c.verifiers[purpose]; ok {
+		return v
+	}
+	v := c.Current.withDefaults().verifier(purpose)
+	v.OnRotation = c.OnRotation
+	for _, config := range c.Rotations {
+		v.RotateVerifier(config.withDefaults().verifier(purpose))
+	}
+	if c.verifiers == nil {
+		c.verifiers = map[string]*MessageVerifier{}
+	}
+	c.verifiers[purpose] = v
+	return v
+}
+
+// Encryptor returns the encryptor of the purpose, its key being derived
+// using the purpose as salt.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/MessageEncryptors.html
+func (c *RotationCoordinator) Encryptor(purpose string) *MessageEncryptor {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.encryptors[purpose]; ok {
+		return e
+	}
+	e := c.Current.withDefaults().encryptor(purpose)
+	e.OnRotation = c.OnRotation
+	for _, config := range c.Rotations {
+		e.RotateEncryptor(config.withDefaults().encryptor(purpose))
+	}
+	if c.encryptors == nil {
+		c.encryptors = map[string]*MessageEncryptor{}
+	}
+	c.encryptors[purpose] = e
+	return e
+}
+
+// withDefaults fills the empty fields of the configuration with the Rails
+// defaults.
+func (config RotationConfig) withDefaults() RotationConfig {
+	if config.Iterations == 0 {
+		config.Iterations = 1000
+	}
+	if config.Hasher == nil {
+		config.Hasher = sha1.New
+	}
+	if config.Cipher == "" {
+		config.Cipher = "aes-256-gcm"
+	}
+	if config.Serializer == nil {
+		config.Serializer = JsonMsgSerializer{}
+	}
+	return config
+}
+
+func (config RotationConfig) keyGenerator() *KeyGenerator {
+	return &KeyGenerator{Secret: config.SecretKeyBase, Iterations: config.Iterations, HashDigest: config.HashDigest}
+}
+
+func (config RotationConfig) verifier(purpose string) *MessageVerifier {
+	return &MessageVerifier{
+		Secret:     config.keyGenerator().Generate([]byte(purpose), 64),
+		Hasher:     config.Hasher,
+		Serializer: config.Serializer,
+	}
+}
+
+// encryptor returns the encryptor of the purpose. Like in Rails, aes-cbc
+// messages are signed with the encryption key.
+func (config RotationConfig) encryptor(purpose string) *MessageEncryptor {
+	key := config.keyGenerator().Generate([]byte(purpose), 32)
+	return &MessageEncryptor{
+		Key:     key,
+		SignKey: key,
+		Cipher:  config.Cipher,
+		// set upfront so the encryptor can be used concurrently
+		Verifier:   &MessageVerifier{Secret: key, Hasher: sha1.New, Serializer: NullMsgSerializer{}},
+		Serializer: config.Serializer,
+	}
+}
@@ -0,0 +1,106 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestRotationCoordinator(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("RotationCoordinator", func() {
+		oldBase := "old secret key base"
+		newBase := "new secret key base"
+
+		g.It("derives a verifier per purpose", func() {
+			c := NewRotationCoordinator(newBase)
+			v := c.Verifier("active_storage")
+			g.Assert(c.Verifier("active_storage") == v).IsTrue()
+			kg := KeyGenerator{Secret: newBase, Iterations: 1000}
+			g.Assert(v.Secret).Eql(kg.Generate([]byte("active_storage"), 64))
+
+			token, _ := v.Generate("blob")
+			var out string
+			g.Assert(c.Verifier("other").Verify(token, &out) != nil).IsTrue()
+			g.Assert(c.Verifier("active_storage").Verify(token, &out)).Eql(nil)
+			g.Assert(out).Eql("blob")
+		})
+
+		g.It("derives an encryptor per purpose", func() {
+			c := NewRotationCoordinator(newBase)
+			e := c.Encryptor("my_feature")
+			g.Assert(e.Cipher).Eql("aes-256-gcm")
+			kg := KeyGenerator{Secret: newBase, Iterations: 1000}
+			g.Assert(e.Key).Eql(kg.Generate([]byte("my_feature"), 32))
+
+			msg, _ := e.EncryptAndSign("secret")
+			var out string
+			g.Assert(c.Encryptor("other").DecryptAndVerify(msg, &out) != nil).IsTrue()
+			g.Assert(e.DecryptAndVerify(msg, &out)).Eql(nil)
+		})
+
+		g.It("accepts the messages of the older configurations", func() {
+			old := NewRotationCoordinator(oldBase)
+			old.Current.Cipher = "aes-cbc"
+			token, _ := old.Verifier("remember_me").Generate(42)
+			msg, _ := old.Encryptor("remember_me").EncryptAndSign(42)
+
+			rotations := 0
+			c := NewRotationCoordinator(newBase, RotationConfig{SecretKeyBase: oldBase, Cipher: "aes-cbc"})
+			c.Current.Hasher = sha256.New
+			c.OnRotation = func() { rotations++ }
+			var id int
+			g.Assert(c.Verifier("remember_me").Verify(token, &id)).Eql(nil)
+			g.Assert(id).Eql(42)
+			g.Assert(c.Encryptor("remember_me").DecryptAndVerify(msg, &id)).Eql(nil)
+			g.Assert(rotations).Eql(2)
+
+			token, _ = c.Verifier("remember_me").Generate(43)
+			g.Assert(old.Verifier("remember_me").Verify(token, &id) != nil).IsTrue()
+		})
+
+		g.It("applies the rotations added later to new purposes", func() {
+			c := NewRotationCoordinator(newBase)
+			c.Rotate(RotationConfig{SecretKeyBase: oldBase})
+			token, _ := NewRotationCoordinator(oldBase).Verifier("late").Generate("ok")
+			var out string
+			g.Assert(c.Verifier("late").Verify(token, &out)).Eql(nil)
+		})
+
+		g.It("can be used concurrently", func() {
+			c := NewRotationCoordinator(newBase)
+			c.Current.Cipher = "aes-cbc"
+			var wg sync.WaitGroup
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					e := c.Encryptor("concurrent")
+					msg, err := e.EncryptAndSign("data")
+					if err != nil {
+						t.Error(err)
+					}
+					var out string
+					if err := e.DecryptAndVerify(msg, &out); err != nil {
+						t.Error(err)
+					}
+				}()
+			}
+			wg.Wait()
+		})
+	})
+}
+
+func ExampleRotationCoordinator() {
+	coordinator := NewRotationCoordinator("new secret key base", RotationConfig{SecretKeyBase: "old secret key base"})
+	legacy, _ := NewRotationCoordinator("old secret key base").Verifier("unsubscribe").Generate("user/42")
+
+	var id string
+	err := coordinator.Verifier("unsubscribe").Verify(legacy, &id)
+	fmt.Println(id, err)
+	// Output: user/42 <nil>
+}
@@ -0,0 +1,212 @@
+package crypto
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestMessageEncryptorRotation(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("MessageEncryptor rotation", func() {
+		oldKey, newKey := GenerateRandomKey(32), GenerateRandomKey(32)
+		signKey := []byte("signature secret")
+
+		g.It("decrypts messages encrypted with a rotated key", func() {
+			old := MessageEncryptor{Key: oldKey, Cipher: "aes-256-gcm"}
+			msg, _ := old.EncryptAndSign("legacy")
+
+			e := MessageEncryptor{Key: newKey, Cipher: "aes-256-gcm"}
+			var out string
+			g.Assert(e.DecryptAndVerify(msg, &out) != nil).IsTrue()
+			e.Rotate(oldKey, "", nil)
+			g.Assert(e.DecryptAndVerify(msg, &out)).Eql(nil)
+			g.Assert(out).Eql("legacy")
+		})
+
+		g.It("decrypts messages bound to AuthData with a rotated key", func() {
+			old := MessageEncryptor{Key: oldKey, Cipher: "aes-256-gcm", AuthData: []byte("tenant 1")}
+			msg, _ := old.EncryptAndSign("legacy")
+
+			e := MessageEncryptor{Key: newKey, Cipher: "aes-256-gcm", AuthData: []byte("tenant 1")}
+			e.Rotate(oldKey, "", nil)
+			var out string
+			g.Assert(e.DecryptAndVerify(msg, &out)).Eql(nil)
+			g.Assert(out).Eql("legacy")
+
+			other := MessageEncryptor{Key: newKey, Cipher: "aes-256-gcm", AuthData: []byte("tenant 2")}
+			other.Rotate(oldKey, "", nil)
+			g.Assert(other.DecryptAndVerify(msg, &out) != nil).IsTrue()
+		})
+
+		g.It("decrypts messages encrypted with a rotated cipher", func() {
+			old := MessageEncryptor{Key: oldKey, SignKey: signKey, Cipher: "aes-cbc"}
+			msg, _ := old.EncryptAndSign(map[string]int{"id": 1})
+
+			e := MessageEncryptor{Key: newKey, SignKey: signKey, Cipher: "aes-256-gcm"}
+			e.Rotate(GenerateRandomKey(32), "aes-cbc", nil)
+			e.Rotate(oldKey, "aes-cbc", JsonMsgSerializer{})
+			var out map[string]int
+			g.Assert(e.DecryptAndVerify(msg, &out)).Eql(nil)
+			g.Assert(out).Eql(map[string]int{"id": 1})
+		})
+
+		g.It("encrypts new messages with the current configuration", func() {
+			e := MessageEncryptor{Key: newKey, Cipher: "aes-256-gcm"}
+			e.Rotate(oldKey, "", nil)
+			msg, _ := e.EncryptAndSign("new")
+			current := MessageEncryptor{Key: newKey, Cipher: "aes-256-gcm"}
+			var out string
+			g.Assert(current.DecryptAndVerify(msg, &out)).Eql(nil)
+			g.Assert(out).Eql("new")
+		})
+
+		g.It("supports fully configured rotations", func() {
+			old := MessageEncryptor{Key: oldKey, SignKey: []byte("old signature secret")}
+			msg, _ := old.EncryptAndSign("legacy")
+
+			e := MessageEncryptor{Key: newKey, SignKey: signKey}
+			e.Rotate(oldKey, "", nil)
+			var out string
+			g.Assert(e.DecryptAndVerify(msg, &out) != nil).IsTrue()
+			e.RotateEncryptor(&MessageEncryptor{Key: oldKey, SignKey: []byte("old signature secret")})
+			g.Assert(e.DecryptAndVerify(msg, &out)).Eql(nil)
+			g.Assert(out).Eql("legacy")
+		})
+
+		g.It("calls OnRotation", func() {
+			old := MessageEncryptor{Key: oldKey, Cipher: "aes-256-gcm"}
+			legacy, _ := old.EncryptAndSign("legacy")
+			rotations := 0
+			e := MessageEncryptor{Key: newKey, Cipher: "aes-256-gcm", OnRotation: func() { rotations++ }}
+			e.Rotate(oldKey, "", nil)
+			current, _ := e.EncryptAndSign("current")
+			var out string
+			g.Assert(e.DecryptAndVerify(current, &out)).Eql(nil)
+			g.Assert(rotations).Eql(0)
+			g.Assert(e.DecryptAndVerify(legacy, &out)).Eql(nil)
+			g.Assert(rotations).Eql(1)
+		})
+
+		g.It("returns the error of the current configuration", func() {
+			e := MessageEncryptor{Key: newKey, Cipher: "aes-256-gcm"}
+			e.Rotate(oldKey, "", nil)
+			var out string
+			err := e.DecryptAndVerify("bad", &out)
+			g.Assert(err.Error()).Eql("missing vectors, want 3, got 1")
+		})
+	})
+}
+
+func TestMessageVerifierRotation(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("MessageVerifier rotation", func() {
+		oldSecret, newSecret := []byte("old secret"), []byte("new secret")
+
+		g.It("verifies messages signed with rotated secrets and hashers", func() {
+			old := MessageVerifier{Secret: oldSecret, Hasher: sha1.New, Serializer: JsonMsgSerializer{}}
+			legacy, _ := old.Generate("remember me")
+
+			rotations := 0
+			v := MessageVerifier{Secret: newSecret, Hasher: sha256.New, Serializer: JsonMsgSerializer{}, OnRotation: func() { rotations++ }}
+			var out string
+			g.Assert(v.Verify(legacy, &out) != nil).IsTrue()
+			v.Rotate(oldSecret, nil)
+			g.Assert(v.Verify(legacy, &out) != nil).IsTrue()
+			v.Rotate(oldSecret, sha1.New)
+			g.Assert(v.Verify(legacy, &out)).Eql(nil)
+			g.Assert(out).Eql("remember me")
+			g.Assert(rotations).Eql(1)
+
+			current, _ := v.Generate("current")
+			g.Assert(v.Verify(current, &out)).Eql(nil)
+			g.Assert(rotations).Eql(1)
+			g.Assert(old.Verify(current, &out) != nil).IsTrue()
+		})
+
+		g.It("supports fully configured rotations", func() {
+			old := MessageVerifier{Secret: oldSecret, Serializer: NullMsgSerializer{}}
+			legacy, _ := old.Generate("plain")
+
+			v := MessageVerifier{Secret: newSecret, Serializer: JsonMsgSerializer{}}
+			v.RotateVerifier(&old)
+			var out string
+			g.Assert(v.Verify(legacy, &out)).Eql(nil)
+			g.Assert(out).Eql("plain")
+		})
+
+		g.It("returns the error of the current configuration", func() {
+			v := MessageVerifier{Secret: newSecret, Serializer: JsonMsgSerializer{}}
+			v.Rotate(oldSecret, nil)
+			var out string
+			g.Assert(v.Verify("bad", &out).Error()).Eql("Invalid signature - bad data --")
+		})
+
+		g.It("verifies messages signed with a fallback hasher", func() {
+			legacy := MessageVerifier{Secret: newSecret, Hasher: sha1.New, Serializer: JsonMsgSerializer{}}
+			token, _ := legacy.Generate("legacy")
+
+			rotated := false
+			v := MessageVerifier{Secret: newSecret, Hasher: sha256.New, Serializer: JsonMsgSerializer{}}
+			v.OnRotation = func() { rotated = true }
+			var out string
+			g.Assert(v.Verify(token, &out) != nil).IsTrue()
+			v.FallbackHashers = []func() hash.Hash{sha1.New}
+			g.Assert(v.Verify(token, &out)).Eql(nil)
+			g.Assert(out).Eql("legacy")
+			g.Assert(rotated).IsTrue()
+
+			fresh, _ := v.Generate("fresh")
+			g.Assert(legacy.Verify(fresh, &out) != nil).IsTrue()
+			sha256Only := MessageVerifier{Secret: newSecret, Hasher: sha256.New, Serializer: JsonMsgSerializer{}}
+			g.Assert(sha256Only.Verify(fresh, &out)).Eql(nil)
+		})
+
+		g.It("sets the fallback hashers with an option", func() {
+			legacy, _ := NewMessageVerifier(newSecret, WithHasher(sha1.New))
+			token, _ := legacy.Generate("legacy")
+			v, err := NewMessageVerifier(newSecret, WithHasher(sha256.New), WithFallbackHashers(sha1.New))
+			g.Assert(err).Eql(nil)
+			var out string
+			g.Assert(v.Verify(token, &out)).Eql(nil)
+			g.Assert(out).Eql("legacy")
+
+			_, err = NewMessageVerifier(newSecret, WithFallbackHashers(nil))
+			g.Assert(errors.Is(err, ErrNotConfigured)).IsTrue()
+		})
+	})
+}
+
+func ExampleMessageVerifier_Rotate() {
+	old := MessageVerifier{Secret: []byte("old secret"), Hasher: sha1.New, Serializer: JsonMsgSerializer{}}
+	token, _ := old.Generate(42)
+
+	v := MessageVerifier{Secret: []byte("new secret"), Hasher: sha256.New, Serializer: JsonMsgSerializer{}}
+	v.Rotate([]byte("old secret"), sha1.New)
+	v.OnRotation = func() { fmt.Println("verified with an old secret") }
+	var userID int
+	err := v.Verify(token, &userID)
+	fmt.Println(userID, err)
+	// Output: verified with an old secret
+	// 42 <nil>
+}
+
+func ExampleMessageEncryptor_Rotate() {
+	oldKey, newKey := GenerateRandomKey(32), GenerateRandomKey(32)
+	old := MessageEncryptor{Key: oldKey, SignKey: []byte("signature secret"), Cipher: "aes-cbc"}
+	msg, _ := old.EncryptAndSign("hello")
+
+	e := MessageEncryptor{Key: newKey, Cipher: "aes-256-gcm"}
+	e.RotateEncryptor(&old)
+	var out string
+	err := e.DecryptAndVerify(msg, &out)
+	fmt.Println(out, err)
+	// Output: hello <nil>
+}
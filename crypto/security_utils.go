@@ -0,0 +1,27 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+)
+
+// SecureCompare compares two strings in constant time, like
+// ActiveSupport::SecurityUtils.secure_compare, for instance to check an API
+// token or a signature. The strings are hashed using SHA256 before being
+// compared, so the comparison doesn't leak their length either.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/SecurityUtils.html#method-c-secure_compare
+func SecureCompare(a, b string) bool {
+	ha, hb := sha256.Sum256([]byte(a)), sha256.Sum256([]byte(b))
+	return subtle.ConstantTimeCompare(ha[:], hb[:]) == 1 && a == b
+}
+
+// FixedLengthSecureCompare compares two strings of the same length in
+// constant time, like ActiveSupport::SecurityUtils.fixed_length_secure_compare.
+// Strings of different lengths are unequal, but the comparison returns
+// early, which leaks their length: use SecureCompare if it is secret.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/SecurityUtils.html#method-c-fixed_length_secure_compare
+func FixedLengthSecureCompare(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
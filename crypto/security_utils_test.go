@@ -0,0 +1,38 @@
+package crypto
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestSecureCompare(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("SecureCompare", func() {
+		g.It("compares strings of any length", func() {
+			g.Assert(SecureCompare("secret", "secret")).IsTrue()
+			g.Assert(SecureCompare("secret", "secreT")).IsFalse()
+			g.Assert(SecureCompare("secret", "secret!")).IsFalse()
+			g.Assert(SecureCompare("", "")).IsTrue()
+			g.Assert(SecureCompare("", "secret")).IsFalse()
+		})
+	})
+
+	g.Describe("FixedLengthSecureCompare", func() {
+		g.It("compares strings of the same length", func() {
+			g.Assert(FixedLengthSecureCompare("secret", "secret")).IsTrue()
+			g.Assert(FixedLengthSecureCompare("secret", "secreT")).IsFalse()
+		})
+
+		g.It("refuses strings of different lengths", func() {
+			g.Assert(FixedLengthSecureCompare("secret", "secret!")).IsFalse()
+		})
+	})
+}
+
+func ExampleSecureCompare() {
+	fmt.Println(SecureCompare("pX27zsMN2ViQKta1bGfLmVJE", "pX27zsMN2ViQKta1bGfLmVJE"))
+	// Output: true
+}
@@ -0,0 +1,66 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"strings"
+
+	"github.com/mattetti/goRailsYourself/inflector"
+)
+
+// SignedIDSalt is the salt Rails derives the signed ids secret from.
+const SignedIDSalt = "active_record/signed_id"
+
+// SignedID generates and verifies the signed ids of Active Record models,
+// the tokens of record.signed_id and Model.find_signed, so password reset
+// or unsubscribe links can be shared between Rails and Go.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveRecord/SignedId.html
+type SignedID struct {
+	// Verifier signs the ids, set up by NewSignedID like Rails does.
+	Verifier *MessageVerifier
+}
+
+// NewSignedID returns the signed ids of an app, deriving their secret from
+// the app's key generator. The key generator must use the app's
+// secret_key_base and hash digest.
+//
+//	kg := &crypto.KeyGenerator{Secret: secretKeyBase, HashDigest: sha256.New}
+//	signedID := crypto.NewSignedID(kg)
+func NewSignedID(kg *KeyGenerator) *SignedID {
+	return &SignedID{Verifier: &MessageVerifier{
+		Secret:     kg.Generate([]byte(SignedIDSalt), 64),
+		Hasher:     sha256.New,
+		Serializer: JsonMsgSerializer{},
+		URLSafe:    true,
+	}}
+}
+
+// Generate returns the signed id of the record of a model, such as "User",
+// like record.signed_id(purpose:, expires_in:). The purpose of the options
+// is combined with the model name.
+func (s *SignedID) Generate(model string, id interface{}, opts MessageOptions) (string, error) {
+	opts.Purpose = SignedIDPurpose(model, opts.Purpose)
+	return s.Verifier.GenerateWithOptions(id, opts)
+}
+
+// Verify reads the id of a model's record from a signed id generated for
+// purpose, like Model.find_signed(signed_id, purpose:) before finding the
+// record. ErrWrongPurpose is returned if the signed id was generated for
+// another model or purpose and ErrExpired if it expired.
+func (s *SignedID) Verify(signedID, model, purpose string, id interface{}) error {
+	return s.Verifier.VerifyWithPurpose(signedID, id, SignedIDPurpose(model, purpose))
+}
+
+// SignedIDPurpose returns the purpose Rails embeds in the signed ids of a
+// model for the passed purpose.
+//
+//	SignedIDPurpose("Admin::User", "password_reset") // => "admin/user/password_reset"
+func SignedIDPurpose(model, purpose string) string {
+	parts := []string{}
+	for _, part := range []string{inflector.Underscore(model), purpose} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,65 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/franela/goblin"
+)
+
+const signedIDSecretKeyBase = "f7b5763636f4c1f3ff4bd444eacccca295d87b990cc104124017ad70550edcfd22b8e89465338254e0b608592a9aac29025440bfd9ce53579835ba06a86f85f9"
+
+// User.find(42).signed_id(purpose: :password_reset) in a Rails 7.1 app
+const railsSignedID = "eyJfcmFpbHMiOnsiZGF0YSI6NDIsInB1ciI6InVzZXIvcGFzc3dvcmRfcmVzZXQifX0--caa4f89bd06eeeb1b8f45666393315f67bf536035cdf2bc332cbd360c885a5f6"
+
+func TestSignedID(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("SignedID", func() {
+		signedID := NewSignedID(&KeyGenerator{Secret: signedIDSecretKeyBase, HashDigest: sha256.New})
+
+		g.It("verifies the signed ids generated by Rails", func() {
+			var id int
+			g.Assert(signedID.Verify(railsSignedID, "User", "password_reset", &id)).Eql(nil)
+			g.Assert(id).Eql(42)
+		})
+
+		g.It("refuses the signed ids of other models and purposes", func() {
+			var id int
+			g.Assert(signedID.Verify(railsSignedID, "Account", "password_reset", &id)).Eql(ErrWrongPurpose)
+			g.Assert(signedID.Verify(railsSignedID, "User", "", &id)).Eql(ErrWrongPurpose)
+		})
+
+		g.It("round trips signed ids", func() {
+			token, err := signedID.Generate("Admin::User", 7, MessageOptions{Purpose: "unsubscribe"})
+			g.Assert(err).Eql(nil)
+			var id int
+			g.Assert(signedID.Verify(token, "Admin::User", "unsubscribe", &id)).Eql(nil)
+			g.Assert(id).Eql(7)
+		})
+
+		g.It("refuses expired signed ids", func() {
+			token, _ := signedID.Generate("User", 7, MessageOptions{ExpiresIn: -time.Minute})
+			var id int
+			g.Assert(signedID.Verify(token, "User", "", &id)).Eql(ErrExpired)
+		})
+	})
+
+	g.Describe("SignedIDPurpose", func() {
+		g.It("combines the model name and the purpose", func() {
+			g.Assert(SignedIDPurpose("User", "")).Eql("user")
+			g.Assert(SignedIDPurpose("Admin::User", "password_reset")).Eql("admin/user/password_reset")
+		})
+	})
+}
+
+func ExampleSignedID() {
+	signedID := NewSignedID(&KeyGenerator{Secret: signedIDSecretKeyBase, HashDigest: sha256.New})
+
+	var id int
+	err := signedID.Verify(railsSignedID, "User", "password_reset", &id)
+	fmt.Println(id, err)
+	// Output: 42 <nil>
+}
@@ -0,0 +1,187 @@
+package crypto
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// DefaultStreamChunkSize is the size of the chunks encrypted by
+// EncryptStream when StreamChunkSize isn't set.
+const DefaultStreamChunkSize = 64 << 10
+
+const (
+	streamVersion = 1
+	// maxStreamChunkSize limits the size of the chunks DecryptStream
+	// allocates.
+	maxStreamChunkSize = 16 << 20
+	// lastChunk flags the length of the last chunk of a stream.
+	lastChunk = 1 << 31
+)
+
+// EncryptStream encrypts r into w, chunk by chunk, so large contents like
+// exported files don't need to fit in memory. The cipher must authenticate
+// the messages, like aes-256-gcm.
+//
+// The stream starts with a header holding the format version, the chunk
+// size and a random nonce prefix, followed by the encrypted chunks, each
+// prefixed by its length. The nonce of a chunk is made of the prefix, the
+// index of the chunk and a flag set for the last one, so chunks can't be
+// reordered, dropped or truncated without DecryptStream noticing. The
+// streams can only be read by DecryptStream, Rails has no equivalent.
+func (crypt *MessageEncryptor) EncryptStream(w io.Writer, r io.Reader) error {
+	crypt, err := crypt.withProvidedKeys(context.Background())
+	if err != nil {
+		return err
+	}
+	c, key, err := crypt.streamCipher()
+	if err != nil {
+		return err
+	}
+	chunkSize := crypt.StreamChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultStreamChunkSize
+	}
+	if chunkSize > maxStreamChunkSize {
+		return notConfigured("crypto: the stream chunk size can't exceed %d bytes", maxStreamChunkSize)
+	}
+
+	// the nonce of the chunks ends with their index and last flag
+	header := make([]byte, 1+4+c.NonceSize()-5)
+	header[0] = streamVersion
+	binary.BigEndian.PutUint32(header[1:5], uint32(chunkSize))
+	prefix := header[5:]
+	if _, err := io.ReadFull(rand.Reader, prefix); err != nil {
+		return err
+	}
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	br := bufio.NewReader(r)
+	buf := make([]byte, chunkSize)
+	for i := uint32(0); ; i++ {
+		n, err := io.ReadFull(br, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		last := err != nil
+		if !last {
+			if _, err := br.Peek(1); err == io.EOF {
+				last = true
+			}
+		}
+		ciphertext, tag, err := c.Encrypt(key, streamNonce(prefix, i, last), buf[:n], crypt.AuthData)
+		if err != nil {
+			return err
+		}
+		length := uint32(len(ciphertext) + len(tag))
+		if last {
+			length |= lastChunk
+		}
+		var size [4]byte
+		binary.BigEndian.PutUint32(size[:], length)
+		for _, b := range [][]byte{size[:], ciphertext, tag} {
+			if _, err := w.Write(b); err != nil {
+				return err
+			}
+		}
+		if last {
+			return nil
+		}
+		if i == 1<<32-1 {
+			return errors.New("crypto: stream too long")
+		}
+	}
+}
+
+// DecryptStream decrypts a stream encrypted by EncryptStream from r into
+// w. The chunks are written to w as soon as they are authenticated, so w
+// must be discarded if an error is returned: the stream may have been
+// truncated or tampered with after the written chunks.
+func (crypt *MessageEncryptor) DecryptStream(w io.Writer, r io.Reader) error {
+	crypt, err := crypt.withProvidedKeys(context.Background())
+	if err != nil {
+		return err
+	}
+	c, key, err := crypt.streamCipher()
+	if err != nil {
+		return err
+	}
+
+	br := bufio.NewReader(r)
+	header := make([]byte, 1+4+c.NonceSize()-5)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return malformed("crypto: truncated stream header")
+	}
+	if header[0] != streamVersion {
+		return malformed("crypto: unsupported stream version %d", header[0])
+	}
+	chunkSize := binary.BigEndian.Uint32(header[1:5])
+	if chunkSize == 0 || chunkSize > maxStreamChunkSize {
+		return malformed("crypto: bad stream chunk size %d", chunkSize)
+	}
+	prefix := header[5:]
+
+	buf := make([]byte, int(chunkSize)+c.TagSize())
+	for i := uint32(0); ; i++ {
+		var size [4]byte
+		if _, err := io.ReadFull(br, size[:]); err != nil {
+			return malformed("crypto: truncated stream")
+		}
+		length := binary.BigEndian.Uint32(size[:])
+		last := length&lastChunk != 0
+		length &^= lastChunk
+		if length < uint32(c.TagSize()) || length > uint32(len(buf)) {
+			return malformed("crypto: bad stream chunk length %d", length)
+		}
+		chunk := buf[:length]
+		if _, err := io.ReadFull(br, chunk); err != nil {
+			return malformed("crypto: truncated stream")
+		}
+		tagStart := len(chunk) - c.TagSize()
+		plaintext, err := c.Decrypt(key, streamNonce(prefix, i, last), chunk[:tagStart], chunk[tagStart:], crypt.AuthData)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(plaintext); err != nil {
+			return err
+		}
+		if last {
+			if _, err := br.Peek(1); err != io.EOF {
+				return malformed("crypto: data after the end of the stream")
+			}
+			return nil
+		}
+		if i == 1<<32-1 {
+			return malformed("crypto: stream too long")
+		}
+	}
+}
+
+// streamCipher returns the cipher and the key of the streams.
+func (crypt *MessageEncryptor) streamCipher() (Cipher, []byte, error) {
+	c, err := lookupCipher(crypt.Cipher)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.TagSize() == 0 || c.NonceSize() < 12 {
+		return nil, nil, notConfigured("crypto: streams need an authenticated cipher, %s isn't one", crypt.cipherName())
+	}
+	return crypt.boundCipher(c)
+}
+
+// streamNonce returns the nonce of the i-th chunk of a stream: the random
+// prefix of the stream, the big endian index and the last chunk flag.
+func streamNonce(prefix []byte, i uint32, last bool) []byte {
+	nonce := make([]byte, len(prefix)+5)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[len(prefix):], i)
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
@@ -0,0 +1,99 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestStreams(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("EncryptStream", func() {
+		key := GenerateRandomKey(32)
+		e := MessageEncryptor{Key: key, Cipher: "aes-256-gcm", StreamChunkSize: 16}
+
+		encrypt := func(data []byte) []byte {
+			var buf bytes.Buffer
+			g.Assert(e.EncryptStream(&buf, bytes.NewReader(data))).Eql(nil)
+			return buf.Bytes()
+		}
+
+		g.It("round trips contents of any size", func() {
+			for _, size := range []int{0, 1, 15, 16, 17, 32, 100} {
+				data := GenerateRandomKey(size)
+				var out bytes.Buffer
+				g.Assert(e.DecryptStream(&out, bytes.NewReader(encrypt(data)))).Eql(nil)
+				g.Assert(out.Len()).Eql(size)
+				g.Assert(bytes.Equal(out.Bytes(), data)).IsTrue()
+			}
+		})
+
+		g.It("frames the chunks", func() {
+			stream := encrypt(make([]byte, 40))
+			// header, then 3 chunks of up to 16 bytes with their length and tag
+			g.Assert(len(stream)).Eql(12 + 3*(4+16) + 40)
+		})
+
+		g.It("detects truncated streams", func() {
+			stream := encrypt(make([]byte, 40))
+			for _, n := range []int{len(stream) - 1, 12 + 2*(4+16+16), 5} {
+				err := e.DecryptStream(&bytes.Buffer{}, bytes.NewReader(stream[:n]))
+				g.Assert(errors.Is(err, ErrMalformedMessage)).IsTrue()
+			}
+		})
+
+		g.It("detects streams cut at the end of a chunk", func() {
+			stream := encrypt(make([]byte, 40))
+			cut := stream[:12+4+16+16]
+			// flag the first chunk as the last one
+			cut[12] |= 0x80
+			err := e.DecryptStream(&bytes.Buffer{}, bytes.NewReader(cut))
+			g.Assert(errors.Is(err, ErrInvalidSignature)).IsTrue()
+		})
+
+		g.It("detects reordered chunks", func() {
+			stream := encrypt(make([]byte, 40))
+			first, second := stream[12:12+36], stream[12+36:12+72]
+			swapped := append(append(append([]byte{}, stream[:12]...), second...), first...)
+			swapped = append(swapped, stream[12+72:]...)
+			err := e.DecryptStream(&bytes.Buffer{}, bytes.NewReader(swapped))
+			g.Assert(errors.Is(err, ErrInvalidSignature)).IsTrue()
+		})
+
+		g.It("detects tampered chunks and appended data", func() {
+			stream := encrypt(make([]byte, 40))
+			stream[20] ^= 1
+			err := e.DecryptStream(&bytes.Buffer{}, bytes.NewReader(stream))
+			g.Assert(errors.Is(err, ErrInvalidSignature)).IsTrue()
+
+			stream = append(encrypt(make([]byte, 40)), 0)
+			err = e.DecryptStream(&bytes.Buffer{}, bytes.NewReader(stream))
+			g.Assert(errors.Is(err, ErrMalformedMessage)).IsTrue()
+		})
+
+		g.It("refuses ciphers which don't authenticate", func() {
+			cbc := MessageEncryptor{Key: key, SignKey: key}
+			err := cbc.EncryptStream(&bytes.Buffer{}, strings.NewReader("data"))
+			g.Assert(errors.Is(err, ErrNotConfigured)).IsTrue()
+		})
+	})
+}
+
+func ExampleMessageEncryptor_EncryptStream() {
+	e := MessageEncryptor{Key: GenerateRandomKey(32), Cipher: "aes-256-gcm"}
+	var encrypted bytes.Buffer
+	if err := e.EncryptStream(&encrypted, strings.NewReader("a large report")); err != nil {
+		panic(err)
+	}
+	var report strings.Builder
+	if err := e.DecryptStream(&report, &encrypted); err != nil {
+		panic(err)
+	}
+	fmt.Println(report.String())
+	// Output: a large report
+}
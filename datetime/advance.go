@@ -0,0 +1,134 @@
+package datetime
+
+import (
+	"math"
+	"time"
+)
+
+// AdvanceOptions lists the amounts a time is moved by in Advance. Years and
+// months can't be fractional, the other parts can.
+type AdvanceOptions struct {
+	Years   int
+	Months  int
+	Weeks   float64
+	Days    float64
+	Hours   float64
+	Minutes float64
+	Seconds float64
+}
+
+// Advance moves t by the passed amounts the way Time#advance does: the
+// calendar parts are applied first on the wall clock, clamping the day to
+// the end of the month, then the hours, minutes and seconds are added.
+// Negative amounts move back in time.
+//
+//	Advance(jan31, AdvanceOptions{Months: 1})         // => February 28th (or 29th)
+//	Advance(t, AdvanceOptions{Days: 1, Hours: -2})
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Time.html#method-i-advance
+func Advance(t time.Time, opts AdvanceOptions) time.Time {
+	weeks, partialWeeks := math.Modf(opts.Weeks)
+	days, partialDays := math.Modf(opts.Days + 7*partialWeeks)
+	seconds := opts.Seconds + opts.Minutes*60 + (opts.Hours+24*partialDays)*3600
+
+	year, month, day := t.Date()
+	months := int(month) - 1 + opts.Months + 12*opts.Years
+	year += months / 12
+	months %= 12
+	if months < 0 {
+		months += 12
+		year--
+	}
+	month = time.Month(months + 1)
+	if last := daysInMonth(year, month); day > last {
+		day = last
+	}
+	day += int(weeks)*7 + int(days)
+
+	t = time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if seconds != 0 {
+		t = t.Add(time.Duration(math.Round(seconds * float64(time.Second))))
+	}
+	return t
+}
+
+// ChangeOption sets one of the components replaced by Change.
+type ChangeOption func(*change)
+
+type change struct {
+	year, day, hour, min, sec, nsec *int
+	month                           *time.Month
+	loc                             *time.Location
+}
+
+// ChangeYear replaces the year.
+func ChangeYear(year int) ChangeOption { return func(c *change) { c.year = &year } }
+
+// ChangeMonth replaces the month.
+func ChangeMonth(month time.Month) ChangeOption { return func(c *change) { c.month = &month } }
+
+// ChangeDay replaces the day of the month.
+func ChangeDay(day int) ChangeOption { return func(c *change) { c.day = &day } }
+
+// ChangeHour replaces the hour, the minutes, seconds and nanoseconds are
+// reset unless they are also changed.
+func ChangeHour(hour int) ChangeOption { return func(c *change) { c.hour = &hour } }
+
+// ChangeMin replaces the minutes, the seconds and nanoseconds are reset
+// unless they are also changed.
+func ChangeMin(min int) ChangeOption { return func(c *change) { c.min = &min } }
+
+// ChangeSec replaces the seconds, the nanoseconds are reset unless they
+// are also changed.
+func ChangeSec(sec int) ChangeOption { return func(c *change) { c.sec = &sec } }
+
+// ChangeNsec replaces the nanoseconds.
+func ChangeNsec(nsec int) ChangeOption { return func(c *change) { c.nsec = &nsec } }
+
+// ChangeLocation replaces the location, keeping the wall clock.
+func ChangeLocation(loc *time.Location) ChangeOption { return func(c *change) { c.loc = loc } }
+
+// Change returns a new time where the passed components have been replaced.
+// Like in Rails, the smaller time components are reset when a larger one
+// is changed: changing the hour resets the minutes, seconds and
+// nanoseconds, changing the minutes resets the seconds and nanoseconds.
+//
+//	Change(t, ChangeHour(9))                // => same day at 09:00:00
+//	Change(t, ChangeDay(1), ChangeMin(30))  // => first of the month, same hour, 30 minutes
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Time.html#method-i-change
+func Change(t time.Time, opts ...ChangeOption) time.Time {
+	var c change
+	for _, opt := range opts {
+		opt(&c)
+	}
+	year, month, day := t.Date()
+	hour, min, sec, nsec := t.Hour(), t.Minute(), t.Second(), t.Nanosecond()
+	loc := t.Location()
+
+	if c.year != nil {
+		year = *c.year
+	}
+	if c.month != nil {
+		month = *c.month
+	}
+	if c.day != nil {
+		day = *c.day
+	}
+	if c.hour != nil {
+		hour, min, sec, nsec = *c.hour, 0, 0, 0
+	}
+	if c.min != nil {
+		min, sec, nsec = *c.min, 0, 0
+	}
+	if c.sec != nil {
+		sec, nsec = *c.sec, 0
+	}
+	if c.nsec != nil {
+		nsec = *c.nsec
+	}
+	if c.loc != nil {
+		loc = c.loc
+	}
+	return time.Date(year, month, day, hour, min, sec, nsec, loc)
+}
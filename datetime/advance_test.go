@@ -0,0 +1,100 @@
+package datetime
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleAdvance() {
+	jan31 := time.Date(2023, 1, 31, 10, 0, 0, 0, time.UTC)
+	fmt.Println(Advance(jan31, AdvanceOptions{Months: 1}))
+	fmt.Println(Advance(jan31, AdvanceOptions{Years: -1, Days: 1, Hours: 2}))
+	// Output: 2023-02-28 10:00:00 +0000 UTC
+	// 2022-02-01 12:00:00 +0000 UTC
+}
+
+func ExampleChange() {
+	t := time.Date(2023, 1, 31, 10, 42, 12, 0, time.UTC)
+	fmt.Println(Change(t, ChangeHour(9)))
+	fmt.Println(Change(t, ChangeDay(1), ChangeMin(30)))
+	// Output: 2023-01-31 09:00:00 +0000 UTC
+	// 2023-01-01 10:30:00 +0000 UTC
+}
+
+func TestAdvance(t *testing.T) {
+	g := Goblin(t)
+	date := func(y int, m time.Month, d, h, min, s int) time.Time {
+		return time.Date(y, m, d, h, min, s, 0, time.UTC)
+	}
+	t0 := date(2005, 2, 22, 15, 15, 10)
+
+	g.Describe("Advance", func() {
+		g.It("Should advance each component", func() {
+			expectations := map[AdvanceOptions]time.Time{
+				{Years: 1}:                      date(2006, 2, 22, 15, 15, 10),
+				{Months: 4}:                     date(2005, 6, 22, 15, 15, 10),
+				{Weeks: 3}:                      date(2005, 3, 15, 15, 15, 10),
+				{Weeks: 3.5}:                    date(2005, 3, 19, 3, 15, 10),
+				{Days: 5}:                       date(2005, 2, 27, 15, 15, 10),
+				{Days: 5.5}:                     date(2005, 2, 28, 3, 15, 10),
+				{Years: 7, Months: 7}:           date(2012, 9, 22, 15, 15, 10),
+				{Years: 7, Months: 19, Days: 5}: date(2013, 9, 27, 15, 15, 10),
+				{Years: 7, Months: 19, Weeks: 2, Days: 5}: date(2013, 10, 11, 15, 15, 10),
+				{Years: -3, Months: -2, Days: -1}:         date(2001, 12, 21, 15, 15, 10),
+				{Hours: 5}:                                date(2005, 2, 22, 20, 15, 10),
+				{Minutes: 7}:                              date(2005, 2, 22, 15, 22, 10),
+				{Seconds: 9}:                              date(2005, 2, 22, 15, 15, 19),
+				{Hours: 5, Minutes: 7, Seconds: 9}:        date(2005, 2, 22, 20, 22, 19),
+				{Hours: -5, Minutes: -7, Seconds: -9}:     date(2005, 2, 22, 10, 8, 1),
+			}
+			for opts, expected := range expectations {
+				g.Assert(Advance(t0, opts)).Eql(expected)
+			}
+		})
+
+		g.It("Should clamp the day to the end of the month", func() {
+			g.Assert(Advance(date(2005, 1, 31, 0, 0, 0), AdvanceOptions{Months: 1})).Eql(date(2005, 2, 28, 0, 0, 0))
+			g.Assert(Advance(date(2004, 2, 29, 0, 0, 0), AdvanceOptions{Years: 1})).Eql(date(2005, 2, 28, 0, 0, 0))
+			g.Assert(Advance(date(2005, 3, 31, 0, 0, 0), AdvanceOptions{Months: -1})).Eql(date(2005, 2, 28, 0, 0, 0))
+			g.Assert(Advance(date(2005, 1, 31, 0, 0, 0), AdvanceOptions{Months: 1, Days: 1})).Eql(date(2005, 3, 1, 0, 0, 0))
+		})
+
+		g.It("Should keep the wall clock across DST changes", func() {
+			ny, err := time.LoadLocation("America/New_York")
+			if err != nil {
+				return
+			}
+			before := time.Date(2024, 3, 9, 12, 0, 0, 0, ny)
+			g.Assert(Advance(before, AdvanceOptions{Days: 1})).Eql(time.Date(2024, 3, 10, 12, 0, 0, 0, ny))
+			g.Assert(Advance(before, AdvanceOptions{Hours: 24})).Eql(time.Date(2024, 3, 10, 13, 0, 0, 0, ny))
+		})
+	})
+
+	g.Describe("Change", func() {
+		t1 := time.Date(2005, 2, 22, 15, 15, 10, 500, time.UTC)
+
+		g.It("Should replace the passed components", func() {
+			g.Assert(Change(t1, ChangeYear(2006))).Eql(time.Date(2006, 2, 22, 15, 15, 10, 500, time.UTC))
+			g.Assert(Change(t1, ChangeMonth(6))).Eql(time.Date(2005, 6, 22, 15, 15, 10, 500, time.UTC))
+			g.Assert(Change(t1, ChangeYear(2012), ChangeMonth(9))).Eql(time.Date(2012, 9, 22, 15, 15, 10, 500, time.UTC))
+			g.Assert(Change(t1, ChangeDay(1))).Eql(time.Date(2005, 2, 1, 15, 15, 10, 500, time.UTC))
+			g.Assert(Change(t1, ChangeNsec(42))).Eql(time.Date(2005, 2, 22, 15, 15, 10, 42, time.UTC))
+		})
+
+		g.It("Should reset the smaller components", func() {
+			g.Assert(Change(t1, ChangeHour(16))).Eql(time.Date(2005, 2, 22, 16, 0, 0, 0, time.UTC))
+			g.Assert(Change(t1, ChangeHour(16), ChangeMin(45))).Eql(time.Date(2005, 2, 22, 16, 45, 0, 0, time.UTC))
+			g.Assert(Change(t1, ChangeMin(45))).Eql(time.Date(2005, 2, 22, 15, 45, 0, 0, time.UTC))
+			g.Assert(Change(t1, ChangeSec(30))).Eql(time.Date(2005, 2, 22, 15, 15, 30, 0, time.UTC))
+			g.Assert(Change(t1, ChangeHour(0))).Eql(time.Date(2005, 2, 22, 0, 0, 0, 0, time.UTC))
+		})
+
+		g.It("Should change the location keeping the wall clock", func() {
+			loc := time.FixedZone("EST", -5*3600)
+			g.Assert(Change(t1, ChangeLocation(loc))).Eql(time.Date(2005, 2, 22, 15, 15, 10, 500, loc))
+		})
+	})
+}
@@ -0,0 +1,181 @@
+package datetime
+
+import "time"
+
+// The calculations below keep the location of the passed time and work on
+// its wall clock, so they are not affected by DST changes.
+
+// BeginningOfMinute returns the start of the minute (hh:mm:00).
+func BeginningOfMinute(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, t.Location())
+}
+
+// EndOfMinute returns the end of the minute (hh:mm:59.999999999).
+func EndOfMinute(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 59, 999999999, t.Location())
+}
+
+// BeginningOfHour returns the start of the hour (hh:00:00).
+func BeginningOfHour(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+}
+
+// EndOfHour returns the end of the hour (hh:59:59.999999999).
+func EndOfHour(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 59, 59, 999999999, t.Location())
+}
+
+// BeginningOfDay returns the start of the day (00:00:00).
+//
+// Rails documentation: http://api.rubyonrails.org/classes/DateAndTime/Calculations.html#method-i-beginning_of_day
+func BeginningOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// MiddleOfDay returns the middle of the day (12:00:00).
+func MiddleOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 12, 0, 0, 0, t.Location())
+}
+
+// EndOfDay returns the end of the day (23:59:59.999999999).
+func EndOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 999999999, t.Location())
+}
+
+// BeginningOfWeek returns the start of the week. Weeks start on the day set
+// with SetBeginningOfWeek (Monday by default) unless another start day is
+// passed.
+//
+//	BeginningOfWeek(t)              // => Monday 00:00:00
+//	BeginningOfWeek(t, time.Sunday) // => Sunday 00:00:00
+//
+// Rails documentation: http://api.rubyonrails.org/classes/DateAndTime/Calculations.html#method-i-beginning_of_week
+func BeginningOfWeek(t time.Time, startDay ...time.Weekday) time.Time {
+	start := weekStart(startDay)
+	t = BeginningOfDay(t)
+	return t.AddDate(0, 0, -daysToWeekStart(t, start))
+}
+
+// EndOfWeek returns the end of the week. Weeks start on the day set with
+// SetBeginningOfWeek unless another start day is passed.
+func EndOfWeek(t time.Time, startDay ...time.Weekday) time.Time {
+	start := weekStart(startDay)
+	return EndOfDay(t.AddDate(0, 0, 6-daysToWeekStart(t, start)))
+}
+
+// BeginningOfMonth returns the start of the month.
+func BeginningOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// EndOfMonth returns the end of the month.
+func EndOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), daysInMonth(t.Year(), t.Month()), 23, 59, 59, 999999999, t.Location())
+}
+
+// BeginningOfQuarter returns the start of the quarter (January, April,
+// July or October 1st).
+func BeginningOfQuarter(t time.Time) time.Time {
+	month := time.Month((int(t.Month())-1)/3*3 + 1)
+	return time.Date(t.Year(), month, 1, 0, 0, 0, 0, t.Location())
+}
+
+// EndOfQuarter returns the end of the quarter (March 31st, June 30th,
+// September 30th or December 31st).
+func EndOfQuarter(t time.Time) time.Time {
+	month := time.Month((int(t.Month())-1)/3*3 + 3)
+	return time.Date(t.Year(), month, daysInMonth(t.Year(), month), 23, 59, 59, 999999999, t.Location())
+}
+
+// BeginningOfYear returns the start of the year.
+func BeginningOfYear(t time.Time) time.Time {
+	return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+}
+
+// EndOfYear returns the end of the year.
+func EndOfYear(t time.Time) time.Time {
+	return time.Date(t.Year(), time.December, 31, 23, 59, 59, 999999999, t.Location())
+}
+
+func weekStart(startDay []time.Weekday) time.Weekday {
+	if len(startDay) > 0 {
+		return startDay[0]
+	}
+	return BeginningOfWeekDay()
+}
+
+// daysToWeekStart returns the number of days since the start of the week.
+func daysToWeekStart(t time.Time, start time.Weekday) int {
+	return (int(t.Weekday()) - int(start) + 7) % 7
+}
+
+// daysInMonth returns the number of days of the month.
+func daysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// Range is an inclusive time range, like the ones returned by all_day or
+// all_week in Rails.
+type Range struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Cover reports whether t is within the range, bounds included.
+func (r Range) Cover(t time.Time) bool {
+	return !t.Before(r.Start) && !t.After(r.End)
+}
+
+// AllDay returns the range from the beginning to the end of the day.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/DateAndTime/Calculations.html#method-i-all_day
+func AllDay(t time.Time) Range {
+	return Range{BeginningOfDay(t), EndOfDay(t)}
+}
+
+// AllWeek returns the range from the beginning to the end of the week.
+// Weeks start on the day set with SetBeginningOfWeek unless another start
+// day is passed.
+func AllWeek(t time.Time, startDay ...time.Weekday) Range {
+	return Range{BeginningOfWeek(t, startDay...), EndOfWeek(t, startDay...)}
+}
+
+// AllMonth returns the range from the beginning to the end of the month.
+func AllMonth(t time.Time) Range {
+	return Range{BeginningOfMonth(t), EndOfMonth(t)}
+}
+
+// AllQuarter returns the range from the beginning to the end of the
+// quarter.
+func AllQuarter(t time.Time) Range {
+	return Range{BeginningOfQuarter(t), EndOfQuarter(t)}
+}
+
+// AllYear returns the range from the beginning to the end of the year.
+func AllYear(t time.Time) Range {
+	return Range{BeginningOfYear(t), EndOfYear(t)}
+}
+
+// NextOccurring returns the next occurrence of the weekday after t, keeping
+// the time of the day.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/DateAndTime/Calculations.html#method-i-next_occurring
+func NextOccurring(t time.Time, day time.Weekday) time.Time {
+	fromNow := int(day) - int(t.Weekday())
+	if fromNow <= 0 {
+		fromNow += 7
+	}
+	return Advance(t, AdvanceOptions{Days: float64(fromNow)})
+}
+
+// PrevOccurring returns the previous occurrence of the weekday before t,
+// keeping the time of the day.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/DateAndTime/Calculations.html#method-i-prev_occurring
+func PrevOccurring(t time.Time, day time.Weekday) time.Time {
+	ago := int(t.Weekday()) - int(day)
+	if ago <= 0 {
+		ago += 7
+	}
+	return Advance(t, AdvanceOptions{Days: float64(-ago)})
+}
@@ -0,0 +1,121 @@
+package datetime
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleBeginningOfWeek() {
+	t := time.Date(2024, 1, 3, 15, 4, 5, 0, time.UTC) // a Wednesday
+	fmt.Println(BeginningOfWeek(t))
+	fmt.Println(BeginningOfWeek(t, time.Sunday))
+	fmt.Println(EndOfWeek(t))
+	// Output: 2024-01-01 00:00:00 +0000 UTC
+	// 2023-12-31 00:00:00 +0000 UTC
+	// 2024-01-07 23:59:59.999999999 +0000 UTC
+}
+
+func TestCalculations(t *testing.T) {
+	g := Goblin(t)
+	date := func(y int, m time.Month, d, h, min, s, ns int) time.Time {
+		return time.Date(y, m, d, h, min, s, ns, time.UTC)
+	}
+	// Wednesday
+	now := date(2024, 2, 14, 15, 4, 5, 123)
+
+	g.Describe("Beginning and end of periods", func() {
+		g.It("Should compute minutes and hours", func() {
+			g.Assert(BeginningOfMinute(now)).Eql(date(2024, 2, 14, 15, 4, 0, 0))
+			g.Assert(EndOfMinute(now)).Eql(date(2024, 2, 14, 15, 4, 59, 999999999))
+			g.Assert(BeginningOfHour(now)).Eql(date(2024, 2, 14, 15, 0, 0, 0))
+			g.Assert(EndOfHour(now)).Eql(date(2024, 2, 14, 15, 59, 59, 999999999))
+		})
+
+		g.It("Should compute days", func() {
+			g.Assert(BeginningOfDay(now)).Eql(date(2024, 2, 14, 0, 0, 0, 0))
+			g.Assert(MiddleOfDay(now)).Eql(date(2024, 2, 14, 12, 0, 0, 0))
+			g.Assert(EndOfDay(now)).Eql(date(2024, 2, 14, 23, 59, 59, 999999999))
+		})
+
+		g.It("Should compute weeks", func() {
+			g.Assert(BeginningOfWeek(now)).Eql(date(2024, 2, 12, 0, 0, 0, 0))
+			g.Assert(EndOfWeek(now)).Eql(date(2024, 2, 18, 23, 59, 59, 999999999))
+			g.Assert(BeginningOfWeek(now, time.Sunday)).Eql(date(2024, 2, 11, 0, 0, 0, 0))
+			g.Assert(EndOfWeek(now, time.Sunday)).Eql(date(2024, 2, 17, 23, 59, 59, 999999999))
+			g.Assert(BeginningOfWeek(now, time.Wednesday)).Eql(date(2024, 2, 14, 0, 0, 0, 0))
+			sunday := date(2024, 2, 18, 10, 0, 0, 0)
+			g.Assert(BeginningOfWeek(sunday)).Eql(date(2024, 2, 12, 0, 0, 0, 0))
+			g.Assert(BeginningOfWeek(sunday, time.Sunday)).Eql(date(2024, 2, 18, 0, 0, 0, 0))
+		})
+
+		g.It("Should compute months", func() {
+			g.Assert(BeginningOfMonth(now)).Eql(date(2024, 2, 1, 0, 0, 0, 0))
+			g.Assert(EndOfMonth(now)).Eql(date(2024, 2, 29, 23, 59, 59, 999999999))
+			g.Assert(EndOfMonth(date(2023, 2, 14, 0, 0, 0, 0))).Eql(date(2023, 2, 28, 23, 59, 59, 999999999))
+			g.Assert(EndOfMonth(date(2023, 12, 14, 0, 0, 0, 0))).Eql(date(2023, 12, 31, 23, 59, 59, 999999999))
+		})
+
+		g.It("Should compute quarters", func() {
+			g.Assert(BeginningOfQuarter(now)).Eql(date(2024, 1, 1, 0, 0, 0, 0))
+			g.Assert(EndOfQuarter(now)).Eql(date(2024, 3, 31, 23, 59, 59, 999999999))
+			g.Assert(BeginningOfQuarter(date(2024, 6, 30, 0, 0, 0, 0))).Eql(date(2024, 4, 1, 0, 0, 0, 0))
+			g.Assert(EndOfQuarter(date(2024, 8, 1, 0, 0, 0, 0))).Eql(date(2024, 9, 30, 23, 59, 59, 999999999))
+			g.Assert(EndOfQuarter(date(2024, 10, 1, 0, 0, 0, 0))).Eql(date(2024, 12, 31, 23, 59, 59, 999999999))
+		})
+
+		g.It("Should compute years", func() {
+			g.Assert(BeginningOfYear(now)).Eql(date(2024, 1, 1, 0, 0, 0, 0))
+			g.Assert(EndOfYear(now)).Eql(date(2024, 12, 31, 23, 59, 59, 999999999))
+		})
+
+		g.It("Should keep the wall clock across DST changes", func() {
+			ny, err := time.LoadLocation("America/New_York")
+			if err != nil {
+				return
+			}
+			// DST starts on Sunday March 10th 2024
+			t := time.Date(2024, 3, 12, 15, 0, 0, 0, ny)
+			g.Assert(BeginningOfWeek(t)).Eql(time.Date(2024, 3, 11, 0, 0, 0, 0, ny))
+			g.Assert(BeginningOfWeek(t, time.Sunday)).Eql(time.Date(2024, 3, 10, 0, 0, 0, 0, ny))
+			g.Assert(BeginningOfMonth(t)).Eql(time.Date(2024, 3, 1, 0, 0, 0, 0, ny))
+			g.Assert(EndOfDay(t).Hour()).Equal(23)
+		})
+	})
+
+	g.Describe("Ranges", func() {
+		g.It("Should cover whole periods", func() {
+			g.Assert(AllDay(now)).Eql(Range{date(2024, 2, 14, 0, 0, 0, 0), date(2024, 2, 14, 23, 59, 59, 999999999)})
+			g.Assert(AllWeek(now)).Eql(Range{date(2024, 2, 12, 0, 0, 0, 0), date(2024, 2, 18, 23, 59, 59, 999999999)})
+			g.Assert(AllWeek(now, time.Sunday)).Eql(Range{date(2024, 2, 11, 0, 0, 0, 0), date(2024, 2, 17, 23, 59, 59, 999999999)})
+			g.Assert(AllMonth(now)).Eql(Range{date(2024, 2, 1, 0, 0, 0, 0), date(2024, 2, 29, 23, 59, 59, 999999999)})
+			g.Assert(AllQuarter(now)).Eql(Range{date(2024, 1, 1, 0, 0, 0, 0), date(2024, 3, 31, 23, 59, 59, 999999999)})
+			g.Assert(AllYear(now)).Eql(Range{date(2024, 1, 1, 0, 0, 0, 0), date(2024, 12, 31, 23, 59, 59, 999999999)})
+		})
+
+		g.It("Should include their bounds", func() {
+			r := AllDay(now)
+			g.Assert(r.Cover(now)).IsTrue()
+			g.Assert(r.Cover(r.Start)).IsTrue()
+			g.Assert(r.Cover(r.End)).IsTrue()
+			g.Assert(r.Cover(r.End.Add(time.Nanosecond))).IsFalse()
+			g.Assert(r.Cover(r.Start.Add(-time.Nanosecond))).IsFalse()
+		})
+	})
+
+	g.Describe("Occurrences", func() {
+		g.It("Should find the next occurrence of a weekday", func() {
+			g.Assert(NextOccurring(now, time.Thursday)).Eql(date(2024, 2, 15, 15, 4, 5, 123))
+			g.Assert(NextOccurring(now, time.Wednesday)).Eql(date(2024, 2, 21, 15, 4, 5, 123))
+			g.Assert(NextOccurring(now, time.Monday)).Eql(date(2024, 2, 19, 15, 4, 5, 123))
+		})
+
+		g.It("Should find the previous occurrence of a weekday", func() {
+			g.Assert(PrevOccurring(now, time.Tuesday)).Eql(date(2024, 2, 13, 15, 4, 5, 123))
+			g.Assert(PrevOccurring(now, time.Wednesday)).Eql(date(2024, 2, 7, 15, 4, 5, 123))
+			g.Assert(PrevOccurring(now, time.Friday)).Eql(date(2024, 2, 9, 15, 4, 5, 123))
+		})
+	})
+}
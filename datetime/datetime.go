@@ -0,0 +1,122 @@
+// The datetime package ports ActiveSupport's date and time extensions,
+// so timestamps exchanged with a Rails app can be parsed and manipulated
+// the same way in Go.
+//
+// Rails documentation http://api.rubyonrails.org/classes/DateAndTime/Calculations.html
+package datetime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// timeLayouts are the formats accepted when parsing a string into a time.
+// Layouts without zone information are interpreted in the location passed
+// to ToTime.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05.999999999 -0700",
+	"2006-01-02 15:04:05.999999999 -07:00",
+	"2006-01-02 15:04:05.999999999 MST",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04 -0700",
+	"2006-01-02 15:04 MST",
+	"2006-01-02 15:04",
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 MST",
+	time.RubyDate,
+	time.UnixDate,
+	"2006-01-02",
+	"2006/01/02",
+	"20060102",
+	"2 Jan 2006",
+	"Jan 2 2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"Mon, 2 Jan 2006",
+}
+
+// ToTime parses a string the way Ruby's String#to_time does and returns the
+// matching time. ISO8601, RFC2822, Ruby's Time#to_s ("2024-01-02 15:04:05
+// UTC") and date only formats are supported. Strings without zone
+// information are interpreted in the passed location (local time if nil).
+//
+// Rails documentation: http://api.rubyonrails.org/classes/String.html#method-i-to_time
+func ToTime(str string, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	str = strings.TrimSpace(str)
+	if str == "" {
+		return time.Time{}, fmt.Errorf("can't parse a blank string into a time")
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, str, loc); err == nil {
+			return resolveZoneAbbreviation(t)
+		}
+	}
+	return time.Time{}, fmt.Errorf("can't parse %q into a time", str)
+}
+
+// zoneAbbreviations are the offsets in seconds of the zone abbreviations
+// Ruby's Time.parse understands and Go doesn't know unless they belong to
+// the location passed to ToTime.
+var zoneAbbreviations = map[string]int{
+	"EST": -5 * 3600,
+	"EDT": -4 * 3600,
+	"CST": -6 * 3600,
+	"CDT": -5 * 3600,
+	"MST": -7 * 3600,
+	"MDT": -6 * 3600,
+	"PST": -8 * 3600,
+	"PDT": -7 * 3600,
+}
+
+// resolveZoneAbbreviation fixes the times parsed with a zone abbreviation
+// unknown to the location, which time.ParseInLocation gives a zero offset:
+// the common US abbreviations get their offset and the others are refused
+// instead of being silently read as UTC.
+func resolveZoneAbbreviation(t time.Time) (time.Time, error) {
+	name, offset := t.Zone()
+	// the abbreviations known to the location keep its location
+	if offset != 0 || t.Location().String() != name || name == "UTC" || name == "GMT" {
+		return t, nil
+	}
+	offset, ok := zoneAbbreviations[name]
+	if !ok {
+		return time.Time{}, fmt.Errorf("can't parse the %q zone abbreviation", name)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.FixedZone(name, offset)), nil
+}
+
+// ToDatetime parses a string the same way ToTime does, but strings without
+// zone information are interpreted as UTC, like Ruby's String#to_datetime.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/String.html#method-i-to_datetime
+func ToDatetime(str string) (time.Time, error) {
+	return ToTime(str, time.UTC)
+}
+
+// ToDate parses a string and returns the date it contains, as midnight UTC.
+// The time and zone information, if any, are discarded.
+//
+//	ToDate("2024-01-02 23:30:00 -0500") // => 2024-01-02 00:00:00 +0000 UTC
+//
+// Rails documentation: http://api.rubyonrails.org/classes/String.html#method-i-to_date
+func ToDate(str string) (time.Time, error) {
+	t, err := ToTime(str, time.UTC)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("can't parse %q into a date", strings.TrimSpace(str))
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+}
@@ -0,0 +1,118 @@
+package datetime
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleToTime() {
+	t, _ := ToTime("2024-01-02 15:04:05 UTC", nil)
+	fmt.Println(t)
+	t, _ = ToTime("2024-01-02T15:04:05+02:00", nil)
+	fmt.Println(t.UTC())
+	// Output: 2024-01-02 15:04:05 +0000 UTC
+	// 2024-01-02 13:04:05 +0000 UTC
+}
+
+func TestToTime(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("ToTime", func() {
+		expected := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+		g.It("Should parse the formats Rails produces", func() {
+			inputs := []string{
+				"2024-01-02T15:04:05Z",
+				"2024-01-02T15:04:05.000Z",
+				"2024-01-02T17:04:05+02:00",
+				"2024-01-02 15:04:05 UTC",
+				"2024-01-02 10:04:05 -0500",
+				"2024-01-02 15:04:05",
+				"Tue, 02 Jan 2024 15:04:05 +0000",
+				"Tue, 2 Jan 2024 10:04:05 -0500",
+				"Tue Jan 02 15:04:05 +0000 2024",
+			}
+			for _, input := range inputs {
+				parsed, err := ToTime(input, time.UTC)
+				g.Assert(err).Eql(nil)
+				g.Assert(parsed.Equal(expected)).IsTrue()
+			}
+		})
+
+		g.It("Should use the passed location when no zone is given", func() {
+			loc := time.FixedZone("EST", -5*3600)
+			parsed, err := ToTime("2024-01-02 10:04:05", loc)
+			g.Assert(err).Eql(nil)
+			g.Assert(parsed.Equal(expected)).IsTrue()
+		})
+
+		g.It("Should keep fractional seconds", func() {
+			parsed, err := ToTime("2024-01-02T15:04:05.123456Z", nil)
+			g.Assert(err).Eql(nil)
+			g.Assert(parsed.Nanosecond()).Equal(123456000)
+		})
+
+		g.It("Should parse dates", func() {
+			parsed, err := ToTime("2024-01-02", time.UTC)
+			g.Assert(err).Eql(nil)
+			g.Assert(parsed).Eql(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+		})
+
+		g.It("Should reject invalid strings", func() {
+			_, err := ToTime("", nil)
+			g.Assert(err != nil).IsTrue()
+			_, err = ToTime("not a time", nil)
+			g.Assert(err != nil).IsTrue()
+		})
+
+		g.It("Should apply the offset of the US zone abbreviations", func() {
+			parsed, err := ToTime("2024-01-02 15:04:05 EST", time.UTC)
+			g.Assert(err).Eql(nil)
+			g.Assert(parsed.UTC()).Eql(time.Date(2024, 1, 2, 20, 4, 5, 0, time.UTC))
+			parsed, err = ToTime("Tue, 02 Jan 2024 15:04:05 PST", time.UTC)
+			g.Assert(err).Eql(nil)
+			g.Assert(parsed.UTC()).Eql(time.Date(2024, 1, 2, 23, 4, 5, 0, time.UTC))
+			parsed, err = ToTime("2024-01-02 15:04:05 GMT", time.UTC)
+			g.Assert(err).Eql(nil)
+			g.Assert(parsed.Equal(expected)).IsTrue()
+		})
+
+		g.It("Should use the abbreviations of the location", func() {
+			loc, _ := time.LoadLocation("Europe/Lisbon")
+			parsed, err := ToTime("2024-01-02 15:04:05 WET", loc)
+			g.Assert(err).Eql(nil)
+			g.Assert(parsed.Equal(expected)).IsTrue()
+		})
+
+		g.It("Should refuse the unknown zone abbreviations", func() {
+			_, err := ToTime("2024-01-02 15:04:05 XYZ", time.UTC)
+			g.Assert(err != nil).IsTrue()
+		})
+	})
+
+	g.Describe("ToDatetime", func() {
+		g.It("Should default to UTC", func() {
+			parsed, err := ToDatetime("2024-01-02 15:04:05")
+			g.Assert(err).Eql(nil)
+			g.Assert(parsed.Location()).Eql(time.UTC)
+		})
+	})
+
+	g.Describe("ToDate", func() {
+		g.It("Should only keep the date", func() {
+			expectations := []string{"2024-01-02", "2024/01/02", "2 Jan 2024", "January 2, 2024", "2024-01-02 23:30:00 -0500"}
+			for _, input := range expectations {
+				parsed, err := ToDate(input)
+				g.Assert(err).Eql(nil)
+				g.Assert(parsed).Eql(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+			}
+		})
+
+		g.It("Should reject invalid strings", func() {
+			_, err := ToDate("2024-13-45")
+			g.Assert(err != nil).IsTrue()
+		})
+	})
+}
@@ -0,0 +1,147 @@
+package datetime
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	minutesInYear              = 525600
+	minutesInQuarterYear       = 131400
+	minutesInThreeQuartersYear = 394200
+)
+
+// distanceInWords holds the English "one" and "other" forms of the
+// datetime.distance_in_words translations.
+var distanceInWords = map[string][2]string{
+	"half_a_minute":       {"half a minute", "half a minute"},
+	"less_than_x_seconds": {"less than 1 second", "less than %{count} seconds"},
+	"x_seconds":           {"1 second", "%{count} seconds"},
+	"less_than_x_minutes": {"less than a minute", "less than %{count} minutes"},
+	"x_minutes":           {"1 minute", "%{count} minutes"},
+	"about_x_hours":       {"about 1 hour", "about %{count} hours"},
+	"x_days":              {"1 day", "%{count} days"},
+	"about_x_months":      {"about 1 month", "about %{count} months"},
+	"x_months":            {"1 month", "%{count} months"},
+	"about_x_years":       {"about 1 year", "about %{count} years"},
+	"over_x_years":        {"over 1 year", "over %{count} years"},
+	"almost_x_years":      {"almost 1 year", "almost %{count} years"},
+}
+
+func distanceWords(key string, count int) string {
+	forms := distanceInWords[key]
+	if count == 1 {
+		return forms[0]
+	}
+	return strings.Replace(forms[1], "%{count}", strconv.Itoa(count), -1)
+}
+
+// DistanceOptions customizes DistanceOfTimeInWords.
+type DistanceOptions struct {
+	// IncludeSeconds gives a more detailed approximation when the distance
+	// is less than a minute and 29 seconds.
+	IncludeSeconds bool
+}
+
+// DistanceOfTimeInWords reports the approximate distance in time between
+// two times, using the same thresholds and wording as ActionView.
+// The order of the times doesn't matter.
+//
+//	DistanceOfTimeInWords(from, from.Add(50*time.Minute), DistanceOptions{}) // => "about 1 hour"
+//	DistanceOfTimeInWords(from, from.AddDate(3, 10, 0), DistanceOptions{})   // => "almost 4 years"
+//	DistanceOfTimeInWords(from, from.Add(15*time.Second), DistanceOptions{IncludeSeconds: true})
+//	// => "less than 20 seconds"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActionView/Helpers/DateHelper.html#method-i-distance_of_time_in_words
+func DistanceOfTimeInWords(from, to time.Time, opts DistanceOptions) string {
+	if from.After(to) {
+		from, to = to, from
+	}
+	seconds := to.Sub(from).Seconds()
+	minutes := int(math.Round(seconds / 60))
+	secs := int(math.Round(seconds))
+
+	switch {
+	case minutes <= 1:
+		if !opts.IncludeSeconds {
+			if minutes == 0 {
+				return distanceWords("less_than_x_minutes", 1)
+			}
+			return distanceWords("x_minutes", minutes)
+		}
+		switch {
+		case secs <= 4:
+			return distanceWords("less_than_x_seconds", 5)
+		case secs <= 9:
+			return distanceWords("less_than_x_seconds", 10)
+		case secs <= 19:
+			return distanceWords("less_than_x_seconds", 20)
+		case secs <= 39:
+			return distanceWords("half_a_minute", 1)
+		case secs <= 59:
+			return distanceWords("less_than_x_minutes", 1)
+		}
+		return distanceWords("x_minutes", 1)
+	case minutes < 45:
+		return distanceWords("x_minutes", minutes)
+	case minutes < 90:
+		return distanceWords("about_x_hours", 1)
+	case minutes < 1440:
+		// 90 mins up to 24 hours
+		return distanceWords("about_x_hours", int(math.Round(float64(minutes)/60)))
+	case minutes < 2520:
+		// 24 hours up to 42 hours
+		return distanceWords("x_days", 1)
+	case minutes < 43200:
+		// 42 hours up to 30 days
+		return distanceWords("x_days", int(math.Round(float64(minutes)/1440)))
+	case minutes < 86400:
+		// 30 days up to 60 days
+		return distanceWords("about_x_months", int(math.Round(float64(minutes)/43200)))
+	case minutes < 525600:
+		// 60 days up to 365 days
+		return distanceWords("x_months", int(math.Round(float64(minutes)/43200)))
+	}
+
+	// Discount the leap year days when calculating the year distance, 80
+	// years apart reads better as "about 80 years" than "over 80 years".
+	fromYear := from.Year()
+	if from.Month() >= 3 {
+		fromYear++
+	}
+	toYear := to.Year()
+	if to.Month() < 3 {
+		toYear--
+	}
+	leapYears := 0
+	for y := fromYear; y <= toYear; y++ {
+		if isLeap(y) {
+			leapYears++
+		}
+	}
+	minutesWithOffset := minutes - leapYears*1440
+	remainder := minutesWithOffset % minutesInYear
+	years := minutesWithOffset / minutesInYear
+	switch {
+	case remainder < minutesInQuarterYear:
+		return distanceWords("about_x_years", years)
+	case remainder < minutesInThreeQuartersYear:
+		return distanceWords("over_x_years", years)
+	}
+	return distanceWords("almost_x_years", years+1)
+}
+
+// TimeAgoInWords is like DistanceOfTimeInWords, where to is now.
+//
+//	TimeAgoInWords(time.Now().Add(-3 * time.Minute)) // => "3 minutes"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActionView/Helpers/DateHelper.html#method-i-time_ago_in_words
+func TimeAgoInWords(t time.Time) string {
+	return DistanceOfTimeInWords(t, time.Now(), DistanceOptions{})
+}
+
+func isLeap(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
@@ -0,0 +1,102 @@
+package datetime
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleDistanceOfTimeInWords() {
+	from := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	fmt.Println(DistanceOfTimeInWords(from, from.Add(50*time.Minute), DistanceOptions{}))
+	fmt.Println(DistanceOfTimeInWords(from, from.Add(15*time.Second), DistanceOptions{IncludeSeconds: true}))
+	fmt.Println(DistanceOfTimeInWords(from, from.AddDate(3, 10, 0), DistanceOptions{}))
+	// Output: about 1 hour
+	// less than 20 seconds
+	// almost 4 years
+}
+
+func TestDistanceOfTimeInWords(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("DistanceOfTimeInWords", func() {
+		from := time.Date(2004, 6, 6, 21, 45, 0, 0, time.UTC)
+		distance := func(d time.Duration) string {
+			return DistanceOfTimeInWords(from, from.Add(d), DistanceOptions{})
+		}
+		seconds := func(s int) string {
+			return DistanceOfTimeInWords(from, from.Add(time.Duration(s)*time.Second), DistanceOptions{IncludeSeconds: true})
+		}
+
+		g.It("Should match the ActionView thresholds", func() {
+			expectations := map[time.Duration]string{
+				0:                                               "less than a minute",
+				29 * time.Second:                                "less than a minute",
+				30 * time.Second:                                "1 minute",
+				(60 + 29) * time.Second:                         "1 minute",
+				(60 + 30) * time.Second:                         "2 minutes",
+				(44*60 + 29) * time.Second:                      "44 minutes",
+				(44*60 + 30) * time.Second:                      "about 1 hour",
+				(89*60 + 29) * time.Second:                      "about 1 hour",
+				(89*60 + 30) * time.Second:                      "about 2 hours",
+				(23*3600 + 59*60 + 29) * time.Second:            "about 24 hours",
+				(23*3600 + 59*60 + 30) * time.Second:            "1 day",
+				(41*3600 + 59*60 + 29) * time.Second:            "1 day",
+				(41*3600 + 59*60 + 30) * time.Second:            "2 days",
+				(29*86400 + 23*3600 + 59*60 + 29) * time.Second: "30 days",
+				(29*86400 + 23*3600 + 59*60 + 30) * time.Second: "about 1 month",
+				(59*86400 + 23*3600 + 59*60 + 29) * time.Second: "about 2 months",
+				(59*86400 + 23*3600 + 59*60 + 30) * time.Second: "2 months",
+			}
+			for d, output := range expectations {
+				g.Assert(distance(d)).Equal(output)
+			}
+		})
+
+		g.It("Should describe years", func() {
+			expectations := map[[3]int]string{
+				{1, 0, 0}:   "about 1 year",
+				{1, 3, 0}:   "over 1 year",
+				{1, 10, 0}:  "almost 2 years",
+				{2, 0, 0}:   "about 2 years",
+				{3, 10, 0}:  "almost 4 years",
+				{80, 0, 0}:  "about 80 years",
+				{0, 12, -1}: "12 months",
+			}
+			for d, output := range expectations {
+				g.Assert(DistanceOfTimeInWords(from, from.AddDate(d[0], d[1], d[2]), DistanceOptions{})).Equal(output)
+			}
+		})
+
+		g.It("Should include seconds", func() {
+			expectations := map[int]string{
+				0:  "less than 5 seconds",
+				4:  "less than 5 seconds",
+				5:  "less than 10 seconds",
+				9:  "less than 10 seconds",
+				10: "less than 20 seconds",
+				19: "less than 20 seconds",
+				20: "half a minute",
+				39: "half a minute",
+				40: "less than a minute",
+				59: "less than a minute",
+				60: "1 minute",
+				89: "1 minute",
+			}
+			for s, output := range expectations {
+				g.Assert(seconds(s)).Equal(output)
+			}
+		})
+
+		g.It("Should ignore the order", func() {
+			g.Assert(DistanceOfTimeInWords(from.Add(time.Hour), from, DistanceOptions{})).Equal("about 1 hour")
+		})
+	})
+
+	g.Describe("TimeAgoInWords", func() {
+		g.It("Should compare with now", func() {
+			g.Assert(TimeAgoInWords(time.Now().Add(-3 * time.Minute))).Equal("3 minutes")
+		})
+	})
+}
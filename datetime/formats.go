@@ -0,0 +1,94 @@
+package datetime
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// A Formatter renders a time using a named format.
+type Formatter func(t time.Time) string
+
+var (
+	formatsMu sync.RWMutex
+	// formats mirrors Time::DATE_FORMATS.
+	formats = map[string]Formatter{
+		"db":      layout("2006-01-02 15:04:05"),
+		"inspect": layout("2006-01-02 15:04:05.000000000 -0700"),
+		"number":  layout("20060102150405"),
+		"nsec": func(t time.Time) string {
+			return t.Format("20060102150405") + fmt.Sprintf("%09d", t.Nanosecond())
+		},
+		"usec": func(t time.Time) string {
+			return t.Format("20060102150405") + fmt.Sprintf("%06d", t.Nanosecond()/1000)
+		},
+		"time":  layout("15:04"),
+		"short": layout("02 Jan 15:04"),
+		"long":  layout("January 02, 2006 15:04"),
+		"long_ordinal": func(t time.Time) string {
+			return t.Format("January ") + ordinalize(t.Day()) + t.Format(", 2006 15:04")
+		},
+		"rfc822":  layout("Mon, 02 Jan 2006 15:04:05 -0700"),
+		"iso8601": layout("2006-01-02T15:04:05Z07:00"),
+	}
+)
+
+// defaultFormat is the layout used by Time#to_s and for unknown formats.
+const defaultFormat = "2006-01-02 15:04:05 -0700"
+
+func layout(l string) Formatter {
+	return func(t time.Time) string { return t.Format(l) }
+}
+
+// RegisterFormat adds or replaces a named format using a Go layout, the
+// same way an entry is added to Time::DATE_FORMATS in a Rails initializer.
+//
+//	datetime.RegisterFormat("month_and_year", "January 2006")
+func RegisterFormat(name, layout string) {
+	RegisterFormatFunc(name, func(t time.Time) string { return t.Format(layout) })
+}
+
+// RegisterFormatFunc adds or replaces a named format rendered by f, for the
+// formats Rails defines with a lambda.
+func RegisterFormatFunc(name string, f Formatter) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	formats[name] = f
+}
+
+// FormatNamed renders t using the named format. Unknown names fall back to
+// the default Time#to_s format.
+//
+//	FormatNamed(t, "db")     // => "2007-01-18 06:10:17"
+//	FormatNamed(t, "short")  // => "18 Jan 06:10"
+//	FormatNamed(t, "rfc822") // => "Thu, 18 Jan 2007 06:10:17 -0600"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Time.html#method-i-to_fs
+func FormatNamed(t time.Time, name string) string {
+	formatsMu.RLock()
+	f, ok := formats[name]
+	formatsMu.RUnlock()
+	if !ok {
+		return t.Format(defaultFormat)
+	}
+	return f(t)
+}
+
+// ordinalize returns the number followed by its English ordinal suffix.
+func ordinalize(n int) string {
+	suffix := "th"
+	switch n % 100 {
+	case 11, 12, 13:
+	default:
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(n) + suffix
+}
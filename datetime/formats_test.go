@@ -0,0 +1,60 @@
+package datetime
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleFormatNamed() {
+	t := time.Date(2007, 1, 18, 6, 10, 17, 0, time.FixedZone("CST", -6*3600))
+	fmt.Println(FormatNamed(t, "db"))
+	fmt.Println(FormatNamed(t, "short"))
+	fmt.Println(FormatNamed(t, "long_ordinal"))
+	fmt.Println(FormatNamed(t, "rfc822"))
+	// Output: 2007-01-18 06:10:17
+	// 18 Jan 06:10
+	// January 18th, 2007 06:10
+	// Thu, 18 Jan 2007 06:10:17 -0600
+}
+
+func TestFormatNamed(t *testing.T) {
+	g := Goblin(t)
+	tm := time.Date(2007, 1, 3, 6, 10, 17, 123456789, time.FixedZone("CST", -6*3600))
+
+	g.Describe("FormatNamed", func() {
+		g.It("Should render the Rails default formats", func() {
+			g.Assert(FormatNamed(tm, "db")).Equal("2007-01-03 06:10:17")
+			g.Assert(FormatNamed(tm, "inspect")).Equal("2007-01-03 06:10:17.123456789 -0600")
+			g.Assert(FormatNamed(tm, "number")).Equal("20070103061017")
+			g.Assert(FormatNamed(tm, "nsec")).Equal("20070103061017123456789")
+			g.Assert(FormatNamed(tm, "usec")).Equal("20070103061017123456")
+			g.Assert(FormatNamed(tm, "time")).Equal("06:10")
+			g.Assert(FormatNamed(tm, "short")).Equal("03 Jan 06:10")
+			g.Assert(FormatNamed(tm, "long")).Equal("January 03, 2007 06:10")
+			g.Assert(FormatNamed(tm, "long_ordinal")).Equal("January 3rd, 2007 06:10")
+			g.Assert(FormatNamed(tm, "rfc822")).Equal("Wed, 03 Jan 2007 06:10:17 -0600")
+			g.Assert(FormatNamed(tm, "iso8601")).Equal("2007-01-03T06:10:17-06:00")
+			g.Assert(FormatNamed(tm.UTC(), "iso8601")).Equal("2007-01-03T12:10:17Z")
+		})
+
+		g.It("Should fall back to the default format", func() {
+			g.Assert(FormatNamed(tm, "unknown")).Equal("2007-01-03 06:10:17 -0600")
+		})
+
+		g.It("Should support custom formats", func() {
+			RegisterFormat("month_and_year", "January 2006")
+			g.Assert(FormatNamed(tm, "month_and_year")).Equal("January 2007")
+			RegisterFormatFunc("unix", func(t time.Time) string { return fmt.Sprint(t.Unix()) })
+			g.Assert(FormatNamed(tm, "unix")).Equal("1167826217")
+		})
+
+		g.It("Should ordinalize days", func() {
+			for n, s := range map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 22: "22nd", 31: "31st"} {
+				g.Assert(ordinalize(n)).Equal(s)
+			}
+		})
+	})
+}
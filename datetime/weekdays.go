@@ -0,0 +1,79 @@
+package datetime
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	weekendMu   sync.RWMutex
+	weekendDays = []time.Weekday{time.Saturday, time.Sunday}
+)
+
+// SetWeekend changes the days considered as the weekend by OnWeekend,
+// OnWeekday, NextWeekday and PrevWeekday. The weekend defaults to Saturday
+// and Sunday like in ActiveSupport.
+//
+//	datetime.SetWeekend(time.Friday, time.Saturday)
+func SetWeekend(days ...time.Weekday) {
+	weekendMu.Lock()
+	defer weekendMu.Unlock()
+	weekendDays = append([]time.Weekday(nil), days...)
+}
+
+// Weekend returns the days currently considered as the weekend.
+func Weekend() []time.Weekday {
+	weekendMu.RLock()
+	defer weekendMu.RUnlock()
+	return append([]time.Weekday(nil), weekendDays...)
+}
+
+// OnWeekend reports whether t falls on a weekend day.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/DateAndTime/Calculations.html#method-i-on_weekend-3F
+func OnWeekend(t time.Time) bool {
+	weekendMu.RLock()
+	defer weekendMu.RUnlock()
+	for _, d := range weekendDays {
+		if t.Weekday() == d {
+			return true
+		}
+	}
+	return false
+}
+
+// OnWeekday reports whether t falls on a week day.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/DateAndTime/Calculations.html#method-i-on_weekday-3F
+func OnWeekday(t time.Time) bool {
+	return !OnWeekend(t)
+}
+
+// NextWeekday returns the next week day after t, skipping the weekend and
+// keeping the time of the day.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/DateAndTime/Calculations.html#method-i-next_weekday
+func NextWeekday(t time.Time) time.Time {
+	return stepToWeekday(t, 1)
+}
+
+// PrevWeekday returns the previous week day before t, skipping the weekend
+// and keeping the time of the day.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/DateAndTime/Calculations.html#method-i-prev_weekday
+func PrevWeekday(t time.Time) time.Time {
+	return stepToWeekday(t, -1)
+}
+
+// stepToWeekday moves t one day at a time in the given direction until it
+// lands on a week day. If every day is part of the weekend, the next day in
+// that direction is returned.
+func stepToWeekday(t time.Time, step int) time.Time {
+	for i := 1; i <= 7; i++ {
+		d := t.AddDate(0, 0, i*step)
+		if OnWeekday(d) {
+			return d
+		}
+	}
+	return t.AddDate(0, 0, step)
+}
@@ -0,0 +1,45 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/franela/goblin"
+)
+
+func TestWeekdays(t *testing.T) {
+	g := Goblin(t)
+	date := func(d int) time.Time {
+		return time.Date(2024, 2, d, 9, 30, 0, 0, time.UTC)
+	}
+	// February 2024: the 16th is a Friday, the 17th and 18th are the weekend.
+	friday, saturday, sunday, monday := date(16), date(17), date(18), date(19)
+
+	g.Describe("Weekdays", func() {
+		g.It("Should detect the weekend", func() {
+			g.Assert(OnWeekend(saturday)).IsTrue()
+			g.Assert(OnWeekend(sunday)).IsTrue()
+			g.Assert(OnWeekend(friday)).IsFalse()
+			g.Assert(OnWeekday(monday)).IsTrue()
+			g.Assert(OnWeekday(sunday)).IsFalse()
+		})
+
+		g.It("Should skip the weekend", func() {
+			g.Assert(NextWeekday(friday)).Eql(monday)
+			g.Assert(NextWeekday(saturday)).Eql(monday)
+			g.Assert(NextWeekday(monday)).Eql(date(20))
+			g.Assert(PrevWeekday(monday)).Eql(friday)
+			g.Assert(PrevWeekday(sunday)).Eql(friday)
+			g.Assert(PrevWeekday(friday)).Eql(date(15))
+		})
+
+		g.It("Should support a custom weekend", func() {
+			defer SetWeekend(Weekend()...)
+			SetWeekend(time.Friday, time.Saturday)
+			g.Assert(OnWeekend(friday)).IsTrue()
+			g.Assert(OnWeekend(sunday)).IsFalse()
+			g.Assert(NextWeekday(date(15))).Eql(sunday)
+			g.Assert(PrevWeekday(sunday)).Eql(date(15))
+		})
+	})
+}
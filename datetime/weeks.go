@@ -0,0 +1,63 @@
+package datetime
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// beginningOfWeek stores the default week start day, Monday unless changed.
+var beginningOfWeek int32 = int32(time.Monday)
+
+// SetBeginningOfWeek changes the default start of the week used by
+// BeginningOfWeek, EndOfWeek and AllWeek when no start day is passed. It is
+// the equivalent of setting config.beginning_of_week in a Rails app.
+//
+//	datetime.SetBeginningOfWeek(time.Sunday)
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Date.html#method-c-beginning_of_week-3D
+func SetBeginningOfWeek(day time.Weekday) {
+	atomic.StoreInt32(&beginningOfWeek, int32(day))
+}
+
+// BeginningOfWeekDay returns the default start of the week.
+func BeginningOfWeekDay() time.Weekday {
+	return time.Weekday(atomic.LoadInt32(&beginningOfWeek))
+}
+
+// CWeek returns the ISO 8601 week number (1-53) of t, like Date#cweek.
+func CWeek(t time.Time) int {
+	_, week := t.ISOWeek()
+	return week
+}
+
+// CWYear returns the ISO 8601 week-numbering year of t, like Date#cwyear.
+// It differs from the calendar year for the first and last days of some
+// years.
+func CWYear(t time.Time) int {
+	year, _ := t.ISOWeek()
+	return year
+}
+
+// CWDay returns the ISO 8601 day of the week of t, Monday being 1 and
+// Sunday 7, like Date#cwday.
+func CWDay(t time.Time) int {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(t.Weekday())
+}
+
+// Commercial returns midnight of the passed ISO 8601 year, week and day
+// (Monday being 1) in loc, like Date.commercial. A nil location means UTC.
+//
+//	Commercial(2024, 1, 1, nil) // => 2024-01-01 00:00:00 UTC
+//
+// Ruby documentation: http://ruby-doc.org/stdlib/libdoc/date/rdoc/Date.html#method-c-commercial
+func Commercial(year, week, day int, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	// January 4th is always in the first ISO week.
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	return jan4.AddDate(0, 0, (week-1)*7+(day-CWDay(jan4)))
+}
@@ -0,0 +1,53 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/franela/goblin"
+)
+
+func TestWeeks(t *testing.T) {
+	g := Goblin(t)
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	g.Describe("Beginning of week configuration", func() {
+		g.It("Should default to Monday", func() {
+			g.Assert(BeginningOfWeekDay()).Equal(time.Monday)
+		})
+
+		g.It("Should change the default start of the week", func() {
+			defer SetBeginningOfWeek(BeginningOfWeekDay())
+			SetBeginningOfWeek(time.Sunday)
+			wednesday := date(2024, 2, 14)
+			g.Assert(BeginningOfWeek(wednesday)).Eql(date(2024, 2, 11))
+			g.Assert(EndOfWeek(wednesday)).Eql(date(2024, 2, 17).Add(24*time.Hour - time.Nanosecond))
+			g.Assert(AllWeek(wednesday).Start).Eql(date(2024, 2, 11))
+			g.Assert(BeginningOfWeek(wednesday, time.Monday)).Eql(date(2024, 2, 12))
+		})
+	})
+
+	g.Describe("ISO weeks", func() {
+		g.It("Should number weeks", func() {
+			g.Assert(CWeek(date(2024, 1, 1))).Equal(1)
+			g.Assert(CWeek(date(2020, 12, 31))).Equal(53)
+			g.Assert(CWeek(date(2021, 1, 3))).Equal(53)
+			g.Assert(CWYear(date(2021, 1, 3))).Equal(2020)
+			g.Assert(CWYear(date(2024, 12, 30))).Equal(2025)
+		})
+
+		g.It("Should number days", func() {
+			g.Assert(CWDay(date(2024, 2, 12))).Equal(1)
+			g.Assert(CWDay(date(2024, 2, 18))).Equal(7)
+		})
+
+		g.It("Should build dates from ISO weeks", func() {
+			g.Assert(Commercial(2024, 1, 1, nil)).Eql(date(2024, 1, 1))
+			g.Assert(Commercial(2020, 53, 7, nil)).Eql(date(2021, 1, 3))
+			g.Assert(Commercial(2025, 1, 1, nil)).Eql(date(2024, 12, 30))
+			g.Assert(Commercial(2024, 7, 3, nil)).Eql(date(2024, 2, 14))
+		})
+	})
+}
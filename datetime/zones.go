@@ -0,0 +1,269 @@
+package datetime
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ZoneMapping maps the friendly zone names used by ActiveSupport::TimeZone
+// (and stored by Rails apps in sessions, settings and database columns) to
+// their IANA zone identifiers.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/TimeZone.html
+var ZoneMapping = map[string]string{
+	"International Date Line West": "Etc/GMT+12",
+	"Midway Island":                "Pacific/Midway",
+	"American Samoa":               "Pacific/Pago_Pago",
+	"Hawaii":                       "Pacific/Honolulu",
+	"Alaska":                       "America/Juneau",
+	"Pacific Time (US & Canada)":   "America/Los_Angeles",
+	"Tijuana":                      "America/Tijuana",
+	"Mountain Time (US & Canada)":  "America/Denver",
+	"Arizona":                      "America/Phoenix",
+	"Chihuahua":                    "America/Chihuahua",
+	"Mazatlan":                     "America/Mazatlan",
+	"Central Time (US & Canada)":   "America/Chicago",
+	"Saskatchewan":                 "America/Regina",
+	"Guadalajara":                  "America/Mexico_City",
+	"Mexico City":                  "America/Mexico_City",
+	"Monterrey":                    "America/Monterrey",
+	"Central America":              "America/Guatemala",
+	"Eastern Time (US & Canada)":   "America/New_York",
+	"Indiana (East)":               "America/Indiana/Indianapolis",
+	"Bogota":                       "America/Bogota",
+	"Lima":                         "America/Lima",
+	"Quito":                        "America/Lima",
+	"Atlantic Time (Canada)":       "America/Halifax",
+	"Caracas":                      "America/Caracas",
+	"La Paz":                       "America/La_Paz",
+	"Santiago":                     "America/Santiago",
+	"Newfoundland":                 "America/St_Johns",
+	"Brasilia":                     "America/Sao_Paulo",
+	"Buenos Aires":                 "America/Argentina/Buenos_Aires",
+	"Montevideo":                   "America/Montevideo",
+	"Georgetown":                   "America/Guyana",
+	"Puerto Rico":                  "America/Puerto_Rico",
+	"Greenland":                    "America/Godthab",
+	"Mid-Atlantic":                 "Atlantic/South_Georgia",
+	"Azores":                       "Atlantic/Azores",
+	"Cape Verde Is.":               "Atlantic/Cape_Verde",
+	"Dublin":                       "Europe/Dublin",
+	"Edinburgh":                    "Europe/London",
+	"Lisbon":                       "Europe/Lisbon",
+	"London":                       "Europe/London",
+	"Casablanca":                   "Africa/Casablanca",
+	"Monrovia":                     "Africa/Monrovia",
+	"UTC":                          "Etc/UTC",
+	"Belgrade":                     "Europe/Belgrade",
+	"Bratislava":                   "Europe/Bratislava",
+	"Budapest":                     "Europe/Budapest",
+	"Ljubljana":                    "Europe/Ljubljana",
+	"Prague":                       "Europe/Prague",
+	"Sarajevo":                     "Europe/Sarajevo",
+	"Skopje":                       "Europe/Skopje",
+	"Warsaw":                       "Europe/Warsaw",
+	"Zagreb":                       "Europe/Zagreb",
+	"Brussels":                     "Europe/Brussels",
+	"Copenhagen":                   "Europe/Copenhagen",
+	"Madrid":                       "Europe/Madrid",
+	"Paris":                        "Europe/Paris",
+	"Amsterdam":                    "Europe/Amsterdam",
+	"Berlin":                       "Europe/Berlin",
+	"Bern":                         "Europe/Zurich",
+	"Zurich":                       "Europe/Zurich",
+	"Rome":                         "Europe/Rome",
+	"Stockholm":                    "Europe/Stockholm",
+	"Vienna":                       "Europe/Vienna",
+	"West Central Africa":          "Africa/Algiers",
+	"Bucharest":                    "Europe/Bucharest",
+	"Cairo":                        "Africa/Cairo",
+	"Helsinki":                     "Europe/Helsinki",
+	"Kyiv":                         "Europe/Kiev",
+	"Riga":                         "Europe/Riga",
+	"Sofia":                        "Europe/Sofia",
+	"Tallinn":                      "Europe/Tallinn",
+	"Vilnius":                      "Europe/Vilnius",
+	"Athens":                       "Europe/Athens",
+	"Istanbul":                     "Europe/Istanbul",
+	"Minsk":                        "Europe/Minsk",
+	"Jerusalem":                    "Asia/Jerusalem",
+	"Harare":                       "Africa/Harare",
+	"Pretoria":                     "Africa/Johannesburg",
+	"Kaliningrad":                  "Europe/Kaliningrad",
+	"Moscow":                       "Europe/Moscow",
+	"St. Petersburg":               "Europe/Moscow",
+	"Volgograd":                    "Europe/Volgograd",
+	"Samara":                       "Europe/Samara",
+	"Kuwait":                       "Asia/Kuwait",
+	"Riyadh":                       "Asia/Riyadh",
+	"Nairobi":                      "Africa/Nairobi",
+	"Baghdad":                      "Asia/Baghdad",
+	"Tehran":                       "Asia/Tehran",
+	"Abu Dhabi":                    "Asia/Muscat",
+	"Muscat":                       "Asia/Muscat",
+	"Baku":                         "Asia/Baku",
+	"Tbilisi":                      "Asia/Tbilisi",
+	"Yerevan":                      "Asia/Yerevan",
+	"Kabul":                        "Asia/Kabul",
+	"Ekaterinburg":                 "Asia/Yekaterinburg",
+	"Islamabad":                    "Asia/Karachi",
+	"Karachi":                      "Asia/Karachi",
+	"Tashkent":                     "Asia/Tashkent",
+	"Chennai":                      "Asia/Kolkata",
+	"Kolkata":                      "Asia/Kolkata",
+	"Mumbai":                       "Asia/Kolkata",
+	"New Delhi":                    "Asia/Kolkata",
+	"Kathmandu":                    "Asia/Kathmandu",
+	"Astana":                       "Asia/Dhaka",
+	"Dhaka":                        "Asia/Dhaka",
+	"Sri Jayawardenepura":          "Asia/Colombo",
+	"Almaty":                       "Asia/Almaty",
+	"Novosibirsk":                  "Asia/Novosibirsk",
+	"Rangoon":                      "Asia/Rangoon",
+	"Bangkok":                      "Asia/Bangkok",
+	"Hanoi":                        "Asia/Bangkok",
+	"Jakarta":                      "Asia/Jakarta",
+	"Krasnoyarsk":                  "Asia/Krasnoyarsk",
+	"Beijing":                      "Asia/Shanghai",
+	"Chongqing":                    "Asia/Chongqing",
+	"Hong Kong":                    "Asia/Hong_Kong",
+	"Urumqi":                       "Asia/Urumqi",
+	"Kuala Lumpur":                 "Asia/Kuala_Lumpur",
+	"Singapore":                    "Asia/Singapore",
+	"Taipei":                       "Asia/Taipei",
+	"Perth":                        "Australia/Perth",
+	"Irkutsk":                      "Asia/Irkutsk",
+	"Ulaanbaatar":                  "Asia/Ulaanbaatar",
+	"Seoul":                        "Asia/Seoul",
+	"Osaka":                        "Asia/Tokyo",
+	"Sapporo":                      "Asia/Tokyo",
+	"Tokyo":                        "Asia/Tokyo",
+	"Yakutsk":                      "Asia/Yakutsk",
+	"Darwin":                       "Australia/Darwin",
+	"Adelaide":                     "Australia/Adelaide",
+	"Canberra":                     "Australia/Canberra",
+	"Melbourne":                    "Australia/Melbourne",
+	"Sydney":                       "Australia/Sydney",
+	"Brisbane":                     "Australia/Brisbane",
+	"Hobart":                       "Australia/Hobart",
+	"Vladivostok":                  "Asia/Vladivostok",
+	"Guam":                         "Pacific/Guam",
+	"Port Moresby":                 "Pacific/Port_Moresby",
+	"Magadan":                      "Asia/Magadan",
+	"Srednekolymsk":                "Asia/Srednekolymsk",
+	"Solomon Is.":                  "Pacific/Guadalcanal",
+	"New Caledonia":                "Pacific/Noumea",
+	"Fiji":                         "Pacific/Fiji",
+	"Kamchatka":                    "Asia/Kamchatka",
+	"Marshall Is.":                 "Pacific/Majuro",
+	"Auckland":                     "Pacific/Auckland",
+	"Wellington":                   "Pacific/Auckland",
+	"Nuku'alofa":                   "Pacific/Tongatapu",
+	"Tokelau Is.":                  "Pacific/Fakaofo",
+	"Chatham Is.":                  "Pacific/Chatham",
+	"Samoa":                        "Pacific/Apia",
+}
+
+// LoadZone returns the location for a Rails zone name ("Eastern Time (US &
+// Canada)") or an IANA zone identifier ("America/New_York").
+func LoadZone(name string) (*time.Location, error) {
+	if iana, ok := ZoneMapping[name]; ok {
+		name = iana
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return loc, nil
+}
+
+// ZoneNameFor returns the Rails zone name of an IANA zone identifier, or an
+// empty string if Rails doesn't know the zone. When several Rails names
+// share the same identifier, the first one in alphabetical order is
+// returned.
+//
+//	ZoneNameFor("America/New_York") // => "Eastern Time (US & Canada)"
+func ZoneNameFor(iana string) string {
+	var names []string
+	for name, id := range ZoneMapping {
+		if id == iana {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return names[0]
+}
+
+// TimeWithZone is a time associated with a Rails time zone, mirroring
+// ActiveSupport::TimeWithZone.
+type TimeWithZone struct {
+	t    time.Time
+	zone string
+}
+
+// InTimeZone converts t to the passed Rails zone name or IANA identifier.
+//
+//	InTimeZone(t, "Eastern Time (US & Canada)")
+//
+// Rails documentation: http://api.rubyonrails.org/classes/DateAndTime/Zones.html#method-i-in_time_zone
+func InTimeZone(t time.Time, zone string) (TimeWithZone, error) {
+	loc, err := LoadZone(zone)
+	if err != nil {
+		return TimeWithZone{}, err
+	}
+	return TimeWithZone{t: t.In(loc), zone: zone}, nil
+}
+
+// InTimeZone converts the time to another zone.
+func (t TimeWithZone) InTimeZone(zone string) (TimeWithZone, error) {
+	return InTimeZone(t.t, zone)
+}
+
+// Time returns the time in the zone.
+func (t TimeWithZone) Time() time.Time {
+	return t.t
+}
+
+// UTC returns the time in UTC.
+func (t TimeWithZone) UTC() time.Time {
+	return t.t.UTC()
+}
+
+// Zone returns the zone name the time was created with.
+func (t TimeWithZone) Zone() string {
+	return t.zone
+}
+
+// Abbreviation returns the zone abbreviation (EST, CEST...) at that time.
+func (t TimeWithZone) Abbreviation() string {
+	abbr, _ := t.t.Zone()
+	return abbr
+}
+
+// FormattedOffset returns the UTC offset of the zone at that time, formatted
+// as "-05:00".
+func (t TimeWithZone) FormattedOffset() string {
+	return t.t.Format("-07:00")
+}
+
+// String returns the time the way TimeWithZone#to_s does:
+// "2024-01-02 10:04:05 -0500".
+func (t TimeWithZone) String() string {
+	return t.t.Format("2006-01-02 15:04:05 -0700")
+}
+
+// Inspect returns the time the way TimeWithZone#inspect does:
+// "Tue, 02 Jan 2024 10:04:05.000000000 EST -05:00".
+func (t TimeWithZone) Inspect() string {
+	return t.t.Format("Mon, 02 Jan 2006 15:04:05.000000000 MST -07:00")
+}
+
+// MarshalJSON encodes the time the way Rails does, as an ISO8601 string with
+// milliseconds: "2024-01-02T10:04:05.000-05:00".
+func (t TimeWithZone) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + t.t.Format("2006-01-02T15:04:05.000Z07:00") + `"`), nil
+}
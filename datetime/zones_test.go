@@ -0,0 +1,80 @@
+package datetime
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleInTimeZone() {
+	t := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	eastern, _ := InTimeZone(t, "Eastern Time (US & Canada)")
+	fmt.Println(eastern)
+	fmt.Println(eastern.Inspect())
+	// Output: 2024-01-02 10:04:05 -0500
+	// Tue, 02 Jan 2024 10:04:05.000000000 EST -05:00
+}
+
+func TestZones(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Rails zone names", func() {
+		g.It("Should all resolve to a location", func() {
+			for name := range ZoneMapping {
+				_, err := LoadZone(name)
+				g.Assert(err).Eql(nil)
+			}
+		})
+
+		g.It("Should accept IANA identifiers", func() {
+			loc, err := LoadZone("Europe/Paris")
+			g.Assert(err).Eql(nil)
+			g.Assert(loc.String()).Equal("Europe/Paris")
+		})
+
+		g.It("Should reject unknown zones", func() {
+			_, err := LoadZone("Middle Earth")
+			g.Assert(err != nil).IsTrue()
+		})
+
+		g.It("Should find the Rails name of a zone", func() {
+			g.Assert(ZoneNameFor("America/New_York")).Equal("Eastern Time (US & Canada)")
+			g.Assert(ZoneNameFor("Europe/London")).Equal("Edinburgh")
+			g.Assert(ZoneNameFor("Mars/Olympus_Mons")).Equal("")
+		})
+	})
+
+	g.Describe("TimeWithZone", func() {
+		utc := time.Date(2024, 7, 2, 15, 4, 5, 0, time.UTC)
+
+		g.It("Should convert between zones", func() {
+			paris, err := InTimeZone(utc, "Paris")
+			g.Assert(err).Eql(nil)
+			g.Assert(paris.Time().Hour()).Equal(17)
+			g.Assert(paris.Zone()).Equal("Paris")
+			g.Assert(paris.Abbreviation()).Equal("CEST")
+			g.Assert(paris.FormattedOffset()).Equal("+02:00")
+			g.Assert(paris.UTC()).Eql(utc)
+
+			tokyo, err := paris.InTimeZone("Tokyo")
+			g.Assert(err).Eql(nil)
+			g.Assert(tokyo.String()).Equal("2024-07-03 00:04:05 +0900")
+			g.Assert(tokyo.Time().Equal(utc)).IsTrue()
+		})
+
+		g.It("Should fail on unknown zones", func() {
+			_, err := InTimeZone(utc, "Nowhere")
+			g.Assert(err != nil).IsTrue()
+		})
+
+		g.It("Should encode to JSON like Rails", func() {
+			eastern, _ := InTimeZone(utc, "Eastern Time (US & Canada)")
+			b, err := json.Marshal(eastern)
+			g.Assert(err).Eql(nil)
+			g.Assert(string(b)).Equal(`"2024-07-02T11:04:05.000-04:00"`)
+		})
+	})
+}
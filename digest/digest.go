@@ -0,0 +1,49 @@
+// The digest package ports ActiveSupport::Digest and the Digest::UUID
+// extension, so cache keys, ETags and namespaced UUIDs computed in Go match
+// the ones computed by a Rails app.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Digest.html
+package digest
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"hash"
+	"sync"
+)
+
+var (
+	hashDigestMu sync.RWMutex
+	hashDigest   = md5.New
+)
+
+// SetHashDigest changes the hash function used by Hexdigest, the
+// equivalent of config.active_support.hash_digest_class. ActiveSupport
+// defaults to MD5, apps using the Rails 6.0 framework defaults
+// (use_sha1_digests) use SHA1 and the ones using the Rails 7.0 defaults use
+// SHA256:
+//
+//	digest.SetHashDigest(sha256.New)
+func SetHashDigest(h func() hash.Hash) {
+	hashDigestMu.Lock()
+	defer hashDigestMu.Unlock()
+	hashDigest = h
+}
+
+// Hexdigest returns the hexadecimal digest of the string, truncated to 32
+// characters, as used by Rails for cache keys and ETags.
+//
+//	Hexdigest("hello") // => "5d41402abc4b2a76b9719d911017c592"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Digest.html#method-c-hexdigest
+func Hexdigest(s string) string {
+	hashDigestMu.RLock()
+	h := hashDigest()
+	hashDigestMu.RUnlock()
+	h.Write([]byte(s))
+	hexdigest := hex.EncodeToString(h.Sum(nil))
+	if len(hexdigest) > 32 {
+		hexdigest = hexdigest[:32]
+	}
+	return hexdigest
+}
@@ -0,0 +1,33 @@
+package digest
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleHexdigest() {
+	fmt.Println(Hexdigest("hello"))
+	// Output: 5d41402abc4b2a76b9719d911017c592
+}
+
+func TestHexdigest(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Hexdigest", func() {
+		g.It("Should default to MD5", func() {
+			g.Assert(Hexdigest("hello")).Equal("5d41402abc4b2a76b9719d911017c592")
+		})
+
+		g.It("Should truncate longer digests", func() {
+			defer SetHashDigest(hashDigest)
+			SetHashDigest(sha1.New)
+			g.Assert(Hexdigest("hello")).Equal("aaf4c61ddcc5e8a2dabede0f3b482cd9")
+			SetHashDigest(sha256.New)
+			g.Assert(Hexdigest("hello")).Equal("2cf24dba5fb0a30e26e83b2ac5b9e29e")
+		})
+	})
+}
@@ -0,0 +1,101 @@
+package digest
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"sync"
+)
+
+// The namespaces defined by RFC 4122, in their binary form like the
+// Digest::UUID constants.
+const (
+	DNSNamespace  = "k\xa7\xb8\x10\x9d\xad\x11\xd1\x80\xb4\x00\xc0O\xd40\xc8"
+	URLNamespace  = "k\xa7\xb8\x11\x9d\xad\x11\xd1\x80\xb4\x00\xc0O\xd40\xc8"
+	OIDNamespace  = "k\xa7\xb8\x12\x9d\xad\x11\xd1\x80\xb4\x00\xc0O\xd40\xc8"
+	X500Namespace = "k\xa7\xb8\x14\x9d\xad\x11\xd1\x80\xb4\x00\xc0O\xd40\xc8"
+)
+
+var (
+	rfc4122NamespacesMu sync.RWMutex
+	rfc4122Namespaces   bool
+)
+
+// SetUseRFC4122NamespacedUUIDs changes how UUIDv3 and UUIDv5 hash the
+// namespaces which aren't predefined, the equivalent of
+// config.active_support.use_rfc4122_namespaced_uuids. By default they are
+// hashed as text like the legacy Rails behavior. When set, like in the apps
+// using the Rails 7.0 framework defaults, they must be formatted UUIDs and
+// are hashed as the 16 bytes they represent, as RFC 4122 specifies:
+//
+//	digest.SetUseRFC4122NamespacedUUIDs(true)
+func SetUseRFC4122NamespacedUUIDs(use bool) {
+	rfc4122NamespacesMu.Lock()
+	defer rfc4122NamespacesMu.Unlock()
+	rfc4122Namespaces = use
+}
+
+// UUIDv3 returns the version 3 (MD5) UUID of the name in the namespace.
+//
+// Like Rails, the namespace is used as is by default: the predefined
+// namespaces are binary strings, but a namespace given as a formatted UUID
+// string is hashed as text, not as the 16 bytes it represents, unless
+// SetUseRFC4122NamespacedUUIDs is set. It then panics if the namespace
+// isn't a UUID, like Rails raises an ArgumentError.
+//
+//	UUIDv3(DNSNamespace, "www.widgets.com") // => "3d813cbb-47fb-32ba-91df-831e1593ac29"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Digest/UUID.html#method-c-uuid_v3
+func UUIDv3(namespace, name string) string {
+	return uuidFromHash(md5.New, 3, namespace, name)
+}
+
+// UUIDv5 returns the version 5 (SHA1) UUID of the name in the namespace.
+// The namespace is packed like by UUIDv3.
+//
+//	UUIDv5(DNSNamespace, "python.org") // => "886313e1-3b8a-5372-9b90-0c9aee199e5d"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Digest/UUID.html#method-c-uuid_v5
+func UUIDv5(namespace, name string) string {
+	return uuidFromHash(sha1.New, 5, namespace, name)
+}
+
+func uuidFromHash(h func() hash.Hash, version byte, namespace, name string) string {
+	d := h()
+	d.Write(packUUIDNamespace(namespace))
+	d.Write([]byte(name))
+	sum := d.Sum(nil)
+	sum[6] = (sum[6] & 0x0f) | version<<4
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// packUUIDNamespace returns the bytes of the namespace which are hashed.
+func packUUIDNamespace(namespace string) []byte {
+	switch namespace {
+	case DNSNamespace, URLNamespace, OIDNamespace, X500Namespace:
+		return []byte(namespace)
+	}
+	rfc4122NamespacesMu.RLock()
+	use := rfc4122Namespaces
+	rfc4122NamespacesMu.RUnlock()
+	if !use {
+		return []byte(namespace)
+	}
+	packed, ok := parseUUID(namespace)
+	if !ok {
+		panic("digest: only UUIDs are valid namespace identifiers")
+	}
+	return packed
+}
+
+// parseUUID returns the bytes of a formatted UUID.
+func parseUUID(s string) ([]byte, bool) {
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return nil, false
+	}
+	b, err := hex.DecodeString(s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:])
+	return b, err == nil
+}
@@ -0,0 +1,51 @@
+package digest
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleUUIDv5() {
+	fmt.Println(UUIDv5(DNSNamespace, "python.org"))
+	// Output: 886313e1-3b8a-5372-9b90-0c9aee199e5d
+}
+
+func TestUUID(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Namespaced UUIDs", func() {
+		g.It("Should generate v3 UUIDs", func() {
+			g.Assert(UUIDv3(DNSNamespace, "www.widgets.com")).Equal("3d813cbb-47fb-32ba-91df-831e1593ac29")
+			g.Assert(UUIDv3(URLNamespace, "http://www.ruby-lang.org")).Equal("21f48b22-f26c-384f-bf28-a159c4c6b50a")
+		})
+
+		g.It("Should generate v5 UUIDs", func() {
+			g.Assert(UUIDv5(DNSNamespace, "python.org")).Equal("886313e1-3b8a-5372-9b90-0c9aee199e5d")
+			g.Assert(UUIDv5(OIDNamespace, "1.2.3")).Equal("42d5e23b-3a02-5135-85c6-52d1102f1f00")
+			g.Assert(UUIDv5(X500Namespace, "cn=x")).Equal("a951fb6d-5aab-5a72-8e2e-9aa8df8d1f8f")
+		})
+
+		g.It("Should hash custom namespaces as text like Rails", func() {
+			g.Assert(UUIDv5("6ba7b810-9dad-11d1-80b4-00c04fd430c8", "python.org")).Equal("07db3470-2422-5948-81a9-8f9c8bd56171")
+		})
+
+		g.It("Should pack custom namespaces in the RFC 4122 mode", func() {
+			defer SetUseRFC4122NamespacedUUIDs(false)
+			SetUseRFC4122NamespacedUUIDs(true)
+			g.Assert(UUIDv5("6ba7b810-9dad-11d1-80b4-00c04fd430c8", "python.org")).Equal("886313e1-3b8a-5372-9b90-0c9aee199e5d")
+			g.Assert(UUIDv3("6BA7B811-9DAD-11D1-80B4-00C04FD430C8", "http://www.ruby-lang.org")).Equal("21f48b22-f26c-384f-bf28-a159c4c6b50a")
+			g.Assert(UUIDv5(DNSNamespace, "python.org")).Equal("886313e1-3b8a-5372-9b90-0c9aee199e5d")
+		})
+
+		g.It("Should refuse namespaces which aren't UUIDs in the RFC 4122 mode", func() {
+			defer SetUseRFC4122NamespacedUUIDs(false)
+			SetUseRFC4122NamespacedUUIDs(true)
+			defer func() {
+				g.Assert(recover()).Equal("digest: only UUIDs are valid namespace identifiers")
+			}()
+			UUIDv5("widgets", "python.org")
+		})
+	})
+}
@@ -0,0 +1,189 @@
+// The duration package ports ActiveSupport::Duration, a span of time
+// expressed in calendar units. Unlike time.Duration, it knows that a month
+// isn't a fixed number of seconds: adding a month to January 31st returns
+// February 28th (or 29th), adding a day across a DST change keeps the wall
+// clock.
+//
+// Rails documentation http://api.rubyonrails.org/classes/ActiveSupport/Duration.html
+package duration
+
+import (
+	"math"
+	"time"
+
+	"github.com/mattetti/goRailsYourself/datetime"
+)
+
+// Number of seconds in each unit, as defined by ActiveSupport. Years and
+// months use the average length of a Gregorian year.
+const (
+	SecondsPerMinute = 60
+	SecondsPerHour   = 3600
+	SecondsPerDay    = 86400
+	SecondsPerWeek   = 604800
+	SecondsPerMonth  = 2629746  // 1/12 of a gregorian year
+	SecondsPerYear   = 31556952 // length of a gregorian year (365.2425 days)
+)
+
+// Duration is a span of time made of calendar parts. Years and months can't
+// be fractional, the other parts can.
+type Duration struct {
+	Years   int
+	Months  int
+	Weeks   float64
+	Days    float64
+	Hours   float64
+	Minutes float64
+	Seconds float64
+}
+
+// Years returns a duration of n years.
+func Years(n int) Duration { return Duration{Years: n} }
+
+// Months returns a duration of n months.
+func Months(n int) Duration { return Duration{Months: n} }
+
+// Weeks returns a duration of n weeks.
+func Weeks(n float64) Duration { return Duration{Weeks: n} }
+
+// Days returns a duration of n days.
+func Days(n float64) Duration { return Duration{Days: n} }
+
+// Hours returns a duration of n hours.
+func Hours(n float64) Duration { return Duration{Hours: n} }
+
+// Minutes returns a duration of n minutes.
+func Minutes(n float64) Duration { return Duration{Minutes: n} }
+
+// Seconds returns a duration of n seconds.
+func Seconds(n float64) Duration { return Duration{Seconds: n} }
+
+// Add returns the sum of both durations, part by part.
+//
+//	Days(1).Add(Hours(2)) // => 1 day and 2 hours
+func (d Duration) Add(o Duration) Duration {
+	return Duration{
+		Years:   d.Years + o.Years,
+		Months:  d.Months + o.Months,
+		Weeks:   d.Weeks + o.Weeks,
+		Days:    d.Days + o.Days,
+		Hours:   d.Hours + o.Hours,
+		Minutes: d.Minutes + o.Minutes,
+		Seconds: d.Seconds + o.Seconds,
+	}
+}
+
+// Sub returns the difference of both durations, part by part.
+func (d Duration) Sub(o Duration) Duration {
+	return d.Add(o.Neg())
+}
+
+// Neg returns the negated duration.
+func (d Duration) Neg() Duration {
+	return Duration{
+		Years:   -d.Years,
+		Months:  -d.Months,
+		Weeks:   -d.Weeks,
+		Days:    -d.Days,
+		Hours:   -d.Hours,
+		Minutes: -d.Minutes,
+		Seconds: -d.Seconds,
+	}
+}
+
+// Mul multiplies every part of the duration. Fractional years and months
+// are truncated.
+func (d Duration) Mul(n float64) Duration {
+	return Duration{
+		Years:   int(float64(d.Years) * n),
+		Months:  int(float64(d.Months) * n),
+		Weeks:   d.Weeks * n,
+		Days:    d.Days * n,
+		Hours:   d.Hours * n,
+		Minutes: d.Minutes * n,
+		Seconds: d.Seconds * n,
+	}
+}
+
+// IsZero reports whether the duration has no length.
+func (d Duration) IsZero() bool {
+	return d.InSeconds() == 0
+}
+
+// Equal reports whether both durations have the same length in seconds.
+//
+//	Days(1).Equal(Hours(24)) // => true
+//	Months(1).Equal(Days(30)) // => false
+func (d Duration) Equal(o Duration) bool {
+	return d.InSeconds() == o.InSeconds()
+}
+
+// InSeconds returns the number of seconds the duration represents.
+func (d Duration) InSeconds() float64 {
+	return float64(d.Years)*SecondsPerYear +
+		float64(d.Months)*SecondsPerMonth +
+		d.Weeks*SecondsPerWeek +
+		d.Days*SecondsPerDay +
+		d.Hours*SecondsPerHour +
+		d.Minutes*SecondsPerMinute +
+		d.Seconds
+}
+
+// InMinutes returns the amount of minutes the duration covers.
+func (d Duration) InMinutes() float64 { return d.InSeconds() / SecondsPerMinute }
+
+// InHours returns the amount of hours the duration covers.
+func (d Duration) InHours() float64 { return d.InSeconds() / SecondsPerHour }
+
+// InDays returns the amount of days the duration covers.
+func (d Duration) InDays() float64 { return d.InSeconds() / SecondsPerDay }
+
+// InWeeks returns the amount of weeks the duration covers.
+func (d Duration) InWeeks() float64 { return d.InSeconds() / SecondsPerWeek }
+
+// InMonths returns the amount of months the duration covers.
+func (d Duration) InMonths() float64 { return d.InSeconds() / SecondsPerMonth }
+
+// InYears returns the amount of years the duration covers.
+func (d Duration) InYears() float64 { return d.InSeconds() / SecondsPerYear }
+
+// Std converts the duration into a time.Duration using the average length
+// of each unit. Use Since/Until for calendar correct arithmetic.
+func (d Duration) Std() time.Duration {
+	return time.Duration(math.Round(d.InSeconds() * float64(time.Second)))
+}
+
+// Since returns the time the duration after t.
+//
+//	Months(1).Since(time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)) // => 2023-02-28
+func (d Duration) Since(t time.Time) time.Time {
+	return advance(t, d)
+}
+
+// Until returns the time the duration before t.
+func (d Duration) Until(t time.Time) time.Time {
+	return advance(t, d.Neg())
+}
+
+// FromNow returns the time the duration from now.
+func (d Duration) FromNow() time.Time {
+	return d.Since(time.Now())
+}
+
+// Ago returns the time the duration ago.
+func (d Duration) Ago() time.Time {
+	return d.Until(time.Now())
+}
+
+// advance moves t by the duration parts the way Time#advance does.
+func advance(t time.Time, d Duration) time.Time {
+	return datetime.Advance(t, datetime.AdvanceOptions{
+		Years:   d.Years,
+		Months:  d.Months,
+		Weeks:   d.Weeks,
+		Days:    d.Days,
+		Hours:   d.Hours,
+		Minutes: d.Minutes,
+		Seconds: d.Seconds,
+	})
+}
@@ -0,0 +1,82 @@
+package duration
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleDuration_Since() {
+	t := time.Date(2023, 1, 31, 10, 0, 0, 0, time.UTC)
+	fmt.Println(Months(1).Since(t))
+	fmt.Println(Years(1).Add(Days(1)).Until(t))
+	// Output: 2023-02-28 10:00:00 +0000 UTC
+	// 2022-01-30 10:00:00 +0000 UTC
+}
+
+func TestDuration(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Duration arithmetic", func() {
+		g.It("Should add and subtract parts", func() {
+			d := Days(1).Add(Hours(2)).Add(Days(2))
+			g.Assert(d).Eql(Duration{Days: 3, Hours: 2})
+			g.Assert(d.Sub(Hours(2))).Eql(Duration{Days: 3})
+			g.Assert(Weeks(2).Mul(1.5)).Eql(Weeks(3))
+			g.Assert(Seconds(0).IsZero()).IsTrue()
+		})
+
+		g.It("Should compare lengths", func() {
+			g.Assert(Days(1).Equal(Hours(24))).IsTrue()
+			g.Assert(Weeks(1).Equal(Days(7))).IsTrue()
+			g.Assert(Months(1).Equal(Days(30))).IsFalse()
+			g.Assert(Years(1).Equal(Months(12))).IsTrue()
+		})
+
+		g.It("Should convert to units", func() {
+			g.Assert(Hours(1).InSeconds()).Equal(float64(3600))
+			g.Assert(Days(2).InHours()).Equal(float64(48))
+			g.Assert(Minutes(90).InHours()).Equal(1.5)
+			g.Assert(Years(1).InDays()).Equal(365.2425)
+			g.Assert(Hours(1.5).Std()).Equal(90 * time.Minute)
+		})
+	})
+
+	g.Describe("Calendar arithmetic", func() {
+		g.It("Should clamp to the end of the month", func() {
+			jan31 := time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)
+			g.Assert(Months(1).Since(jan31)).Eql(time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC))
+			g.Assert(Months(1).Since(jan31.AddDate(1, 0, 0))).Eql(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC))
+			g.Assert(Months(1).Until(time.Date(2023, 3, 31, 0, 0, 0, 0, time.UTC))).Eql(time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC))
+			g.Assert(Years(1).Since(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC))).Eql(time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC))
+		})
+
+		g.It("Should cross years", func() {
+			g.Assert(Months(13).Since(time.Date(2023, 12, 15, 0, 0, 0, 0, time.UTC))).Eql(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC))
+			g.Assert(Months(14).Until(time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC))).Eql(time.Date(2021, 11, 15, 0, 0, 0, 0, time.UTC))
+		})
+
+		g.It("Should keep the wall clock across DST changes", func() {
+			ny, err := time.LoadLocation("America/New_York")
+			if err != nil {
+				return
+			}
+			before := time.Date(2023, 3, 11, 12, 0, 0, 0, ny)
+			g.Assert(Days(1).Since(before)).Eql(time.Date(2023, 3, 12, 12, 0, 0, 0, ny))
+			g.Assert(Hours(24).Since(before)).Eql(time.Date(2023, 3, 12, 13, 0, 0, 0, ny))
+		})
+
+		g.It("Should handle fractional days and weeks", func() {
+			start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+			g.Assert(Days(1.5).Since(start)).Eql(time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC))
+			g.Assert(Weeks(0.5).Since(start)).Eql(time.Date(2023, 1, 4, 12, 0, 0, 0, time.UTC))
+		})
+
+		g.It("Should be relative to now", func() {
+			g.Assert(Hours(1).FromNow().After(time.Now())).IsTrue()
+			g.Assert(Hours(1).Ago().Before(time.Now())).IsTrue()
+		})
+	})
+}
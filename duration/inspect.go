@@ -0,0 +1,102 @@
+package duration
+
+import (
+	"strconv"
+
+	"github.com/mattetti/goRailsYourself/i18n"
+	"github.com/mattetti/goRailsYourself/texthelper"
+)
+
+// unitKeys are the i18n keys of the parts, in the order the parts are
+// displayed.
+var unitKeys = [7]string{"years", "months", "weeks", "days", "hours", "minutes", "seconds"}
+
+// unitNames holds the singular and plural names of each part, in the order
+// the parts are displayed.
+var unitNames = map[string][7][2]string{
+	"en": {{"year", "years"}, {"month", "months"}, {"week", "weeks"}, {"day", "days"}, {"hour", "hours"}, {"minute", "minutes"}, {"second", "seconds"}},
+	"fr": {{"an", "ans"}, {"mois", "mois"}, {"semaine", "semaines"}, {"jour", "jours"}, {"heure", "heures"}, {"minute", "minutes"}, {"seconde", "secondes"}},
+	"de": {{"Jahr", "Jahre"}, {"Monat", "Monate"}, {"Woche", "Wochen"}, {"Tag", "Tage"}, {"Stunde", "Stunden"}, {"Minute", "Minuten"}, {"Sekunde", "Sekunden"}},
+	"es": {{"año", "años"}, {"mes", "meses"}, {"semana", "semanas"}, {"día", "días"}, {"hora", "horas"}, {"minuto", "minutos"}, {"segundo", "segundos"}},
+}
+
+// Inspect returns an English description of the duration, listing its
+// non zero parts from the largest to the smallest.
+//
+//	Days(2).Add(Hours(3)).Add(Minutes(5)).Inspect() // => "2 days, 3 hours, and 5 minutes"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Duration.html#method-i-inspect
+func (d Duration) Inspect() string {
+	// like Rails, Inspect isn't localized
+	return d.humanize(func(i int, v float64) string { return unitName(unitNames["en"][i], v) },
+		texthelper.SentenceOptions{WordsConnector: ", ", TwoWordsConnector: " and ", LastWordConnector: ", and "})
+}
+
+// Humanize is like Inspect but uses the unit names and sentence connectors
+// of the passed locale. Unknown locales fall back to English.
+//
+//	Days(2).Add(Hours(3)).Humanize("fr") // => "2 jours et 3 heures"
+//
+// When translations were loaded in the i18n package for the locale, the
+// unit names are looked up under the duration.units key, pluralized using
+// the count:
+//
+//	it:
+//	  duration:
+//	    units:
+//	      days:
+//	        one: giorno
+//	        other: giorni
+func (d Duration) Humanize(locale string) string {
+	names, ok := unitNames[locale]
+	localized := i18n.Available(locale)
+	if !ok {
+		names = unitNames["en"]
+		if !localized {
+			locale = "en"
+		}
+	}
+	return d.humanize(func(i int, v float64) string {
+		if localized {
+			name, err := i18n.Translate(locale, "duration.units."+unitKeys[i], map[string]interface{}{"count": v})
+			if err == nil {
+				return name
+			}
+		}
+		return unitName(names[i], v)
+	}, texthelper.SentenceOptions{Locale: locale})
+}
+
+// humanize lists the non zero parts of the duration, using name to get
+// the name of the unit of each part.
+func (d Duration) humanize(name func(i int, v float64) string, opts texthelper.SentenceOptions) string {
+	values := [7]float64{float64(d.Years), float64(d.Months), d.Weeks, d.Days, d.Hours, d.Minutes, d.Seconds}
+	var parts []string
+	for i, v := range values {
+		if v == 0 {
+			continue
+		}
+		parts = append(parts, formatPart(v, name(i, v)))
+	}
+	if len(parts) == 0 {
+		return formatPart(0, name(6, 0))
+	}
+	return texthelper.ToSentence(parts, opts)
+}
+
+// String returns the English description of the duration.
+func (d Duration) String() string {
+	return d.Inspect()
+}
+
+// unitName returns the singular or plural name of the unit.
+func unitName(name [2]string, v float64) string {
+	if v == 1 {
+		return name[0]
+	}
+	return name[1]
+}
+
+func formatPart(v float64, unit string) string {
+	return strconv.FormatFloat(v, 'f', -1, 64) + " " + unit
+}
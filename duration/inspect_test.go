@@ -0,0 +1,60 @@
+package duration
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+	"github.com/mattetti/goRailsYourself/i18n"
+)
+
+func ExampleDuration_Inspect() {
+	d := Days(2).Add(Hours(3)).Add(Minutes(5))
+	fmt.Println(d.Inspect())
+	fmt.Println(d.Humanize("fr"))
+	// Output: 2 days, 3 hours, and 5 minutes
+	// 2 jours, 3 heures et 5 minutes
+}
+
+func TestInspect(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("Duration Inspect", func() {
+		g.It("Should list the parts in order", func() {
+			g.Assert(Minutes(5).Add(Years(1)).Inspect()).Equal("1 year and 5 minutes")
+			g.Assert(Seconds(1).Inspect()).Equal("1 second")
+			g.Assert(Hours(1.5).Inspect()).Equal("1.5 hours")
+			g.Assert(Weeks(2).Add(Months(1)).Add(Days(1)).Inspect()).Equal("1 month, 2 weeks, and 1 day")
+		})
+
+		g.It("Should describe empty durations", func() {
+			g.Assert(Duration{}.Inspect()).Equal("0 seconds")
+			g.Assert(Days(1).Sub(Days(1)).Inspect()).Equal("0 seconds")
+		})
+
+		g.It("Should be used by fmt", func() {
+			g.Assert(fmt.Sprint(Months(2))).Equal("2 months")
+		})
+	})
+
+	g.Describe("Duration Humanize", func() {
+		g.It("Should use the locale", func() {
+			d := Years(1).Add(Days(2))
+			g.Assert(d.Humanize("de")).Equal("1 Jahr und 2 Tage")
+			g.Assert(d.Humanize("es")).Equal("1 año y 2 días")
+			g.Assert(d.Humanize("unknown")).Equal("1 year and 2 days")
+		})
+
+		g.It("Should use the i18n unit names", func() {
+			i18n.Store("it", map[string]interface{}{
+				"support": map[string]interface{}{"array": map[string]interface{}{"two_words_connector": " e "}},
+				"duration": map[string]interface{}{"units": map[string]interface{}{
+					"years": map[string]interface{}{"one": "anno", "other": "anni"},
+					"days":  map[string]interface{}{"one": "giorno", "other": "giorni"},
+				}},
+			})
+			g.Assert(Years(1).Add(Days(2)).Humanize("it")).Equal("1 anno e 2 giorni")
+			g.Assert(Years(2).Add(Hours(1)).Humanize("it")).Equal("2 anni e 1 hour")
+			g.Assert(Years(2).Add(Days(1)).Inspect()).Equal("2 years and 1 day")
+		})
+	})
+}
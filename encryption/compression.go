@@ -0,0 +1,50 @@
+package encryption
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+)
+
+// compressionThreshold is the size above which Rails compresses the
+// values, THRESHOLD_TO_JUSTIFY_COMPRESSION.
+const compressionThreshold = 140
+
+// compressIfWorthIt deflates data larger than the threshold and returns
+// the compressed data if it is smaller.
+func compressIfWorthIt(data []byte) ([]byte, bool) {
+	if len(data) <= compressionThreshold {
+		return data, false
+	}
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return data, false
+	}
+	if err := w.Close(); err != nil || buf.Len() >= len(data) {
+		return data, false
+	}
+	return buf.Bytes(), true
+}
+
+// maxInflatedSize limits the size of the inflated values so a small
+// compressed value can't exhaust the memory. It is a variable so the tests
+// can lower it.
+var maxInflatedSize = 64 << 20
+
+// inflate decompresses zlib data, limited to maxInflatedSize bytes.
+func inflate(data []byte) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	inflated, err := io.ReadAll(io.LimitReader(r, int64(maxInflatedSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(inflated) > maxInflatedSize {
+		return nil, ErrDecryption
+	}
+	return inflated, nil
+}
@@ -0,0 +1,255 @@
+// The encryption package ports Active Record Encryption, the encrypts
+// attribute macro of Rails 7, so Go services reading the database of a
+// Rails app can decrypt and encrypt its columns.
+//
+// Rails documentation: https://guides.rubyonrails.org/active_record_encryption.html
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"hash"
+	"io"
+
+	"github.com/mattetti/goRailsYourself/crypto"
+)
+
+const (
+	// KeyIterations is the number of PBKDF2 iterations deriving the keys.
+	KeyIterations = 1 << 16
+	// KeyLength is the length of the aes-256-gcm keys.
+	KeyLength = 32
+
+	ivLength = 12
+)
+
+// ErrDecryption is returned when a value can't be decrypted, like
+// ActiveRecord::Encryption::Errors::Decryption.
+var ErrDecryption = errors.New("encryption: can't decrypt the value")
+
+// Config is the Active Record Encryption configuration of an app, the
+// active_record_encryption section of its credentials.
+type Config struct {
+	// PrimaryKey is active_record_encryption.primary_key.
+	PrimaryKey string
+	// PreviousPrimaryKeys are the primary keys used before PrimaryKey, from
+	// the oldest one, when active_record_encryption.primary_key is a list.
+	PreviousPrimaryKeys []string
+	// DeterministicKey is active_record_encryption.deterministic_key.
+	DeterministicKey string
+	// KeyDerivationSalt is active_record_encryption.key_derivation_salt.
+	KeyDerivationSalt string
+	// HashDigest is config.active_record.encryption.hash_digest_class,
+	// sha1 if not set. Apps using the Rails 7.1 defaults use sha256.
+	HashDigest func() hash.Hash
+	// StoreKeyReferences stores the id of the encryption key in the
+	// encrypted values, config.active_record.encryption.store_key_references.
+	StoreKeyReferences bool
+}
+
+// DeriveKey derives an encryption key from a password like
+// ActiveRecord::Encryption::KeyGenerator#derive_key_from.
+func (c Config) DeriveKey(password string) []byte {
+	digest := c.HashDigest
+	if digest == nil {
+		digest = sha1.New
+	}
+	kg := crypto.KeyGenerator{Secret: password, Iterations: KeyIterations, HashDigest: digest}
+	return kg.Generate([]byte(c.KeyDerivationSalt), KeyLength)
+}
+
+// Encryptor encrypts and decrypts the values of encrypted attributes:
+//
+//	e := encryption.NewEncryptor(encryption.Config{
+//		PrimaryKey:        primaryKey,
+//		KeyDerivationSalt: keyDerivationSalt,
+//		HashDigest:        sha256.New,
+//	})
+//	email, err := e.Decrypt(row.Email)
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveRecord/Encryption/Encryptor.html
+type Encryptor struct {
+	// Compress deflates the values larger than 140 bytes when it makes them
+	// smaller, like Rails does by default.
+	Compress bool
+
+	keys               *DerivedSecretKeyProvider
+	deterministic      bool
+	storeKeyReferences bool
+}
+
+// NewEncryptor returns the encryptor of an app's configuration. The values
+// encrypted with the previous primary keys are decrypted too.
+func NewEncryptor(config Config) *Encryptor {
+	passwords := append(append([]string{}, config.PreviousPrimaryKeys...), config.PrimaryKey)
+	return &Encryptor{
+		Compress:           true,
+		keys:               NewDerivedSecretKeyProvider(config, passwords...),
+		storeKeyReferences: config.StoreKeyReferences,
+	}
+}
+
+// NewDeterministicEncryptor returns the encryptor of the attributes
+// declared with encrypts :email, deterministic: true. A value is always
+// encrypted into the same message, which can be used to query the
+// encrypted column:
+//
+//	e := encryption.NewDeterministicEncryptor(config)
+//	email, _ := e.Encrypt("jane@example.com")
+//	db.QueryRow("SELECT id FROM users WHERE email = $1", email)
+//
+// Go's zlib output differs from Ruby's, so the compressed values, larger
+// than 140 bytes, don't match the ones Rails generates.
+func NewDeterministicEncryptor(config Config) *Encryptor {
+	return &Encryptor{
+		Compress:           true,
+		keys:               NewDerivedSecretKeyProvider(config, config.DeterministicKey),
+		deterministic:      true,
+		storeKeyReferences: config.StoreKeyReferences,
+	}
+}
+
+// Encrypt encrypts a value into the JSON message Rails stores in the
+// database.
+func (e *Encryptor) Encrypt(clearText string) (string, error) {
+	data, compressed := []byte(clearText), false
+	if e.Compress {
+		data, compressed = compressIfWorthIt(data)
+	}
+	key := e.keys.EncryptionKey()
+	iv, err := e.iv(key, data)
+	if err != nil {
+		return "", err
+	}
+	msg, err := seal(key.Secret, iv, data)
+	if err != nil {
+		return "", err
+	}
+	if e.storeKeyReferences {
+		msg.Headers.KeyID = base64.StdEncoding.EncodeToString([]byte(key.ID()))
+	}
+	msg.Headers.Compressed = compressed
+	return msg.serialize()
+}
+
+// iv returns the initialization vector of the data, random unless the
+// encryption is deterministic.
+func (e *Encryptor) iv(key Key, data []byte) ([]byte, error) {
+	if e.deterministic {
+		mac := hmac.New(sha256.New, key.Secret)
+		mac.Write(data)
+		return mac.Sum(nil)[:ivLength], nil
+	}
+	iv := make([]byte, ivLength)
+	_, err := io.ReadFull(rand.Reader, iv)
+	return iv, err
+}
+
+// Decrypt decrypts a value stored in the database. ErrDecryption is
+// returned if the value isn't a message encrypted with one of the
+// encryptor's keys, which are tried in order.
+func (e *Encryptor) Decrypt(encryptedText string) (string, error) {
+	msg, err := parseMessage(encryptedText)
+	if err != nil {
+		return "", err
+	}
+	// like all the string headers, Rails stores the key id base64 encoded
+	keyID, err := base64.StdEncoding.DecodeString(msg.Headers.KeyID)
+	if err != nil {
+		return "", ErrDecryption
+	}
+	for _, key := range e.keys.DecryptionKeys(string(keyID)) {
+		if data, err := msg.open(key.Secret); err == nil {
+			return string(data), nil
+		}
+	}
+	return "", ErrDecryption
+}
+
+// message is the JSON envelope of the encrypted values. The fields are
+// ordered like Rails writes them.
+type message struct {
+	Payload string  `json:"p"`
+	Headers headers `json:"h"`
+}
+
+type headers struct {
+	IV         string `json:"iv,omitempty"`
+	AuthTag    string `json:"at,omitempty"`
+	Encoding   string `json:"e,omitempty"`
+	KeyID      string `json:"i,omitempty"`
+	Compressed bool   `json:"c,omitempty"`
+}
+
+func (m *message) serialize() (string, error) {
+	data, err := json.Marshal(m)
+	return string(data), err
+}
+
+func parseMessage(encryptedText string) (*message, error) {
+	var msg message
+	if err := json.Unmarshal([]byte(encryptedText), &msg); err != nil || msg.Headers.IV == "" || msg.Headers.AuthTag == "" {
+		return nil, ErrDecryption
+	}
+	return &msg, nil
+}
+
+// seal encrypts data using aes-256-gcm, storing the auth tag in the
+// headers like Rails.
+func seal(key, iv, data []byte) (*message, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	sealed := aead.Seal(nil, iv, data, nil)
+	tagStart := len(sealed) - aead.Overhead()
+	return &message{
+		Payload: base64.StdEncoding.EncodeToString(sealed[:tagStart]),
+		Headers: headers{
+			IV:      base64.StdEncoding.EncodeToString(iv),
+			AuthTag: base64.StdEncoding.EncodeToString(sealed[tagStart:]),
+		},
+	}, nil
+}
+
+// open decrypts the message, inflating it if it was compressed.
+func (m *message) open(key []byte) ([]byte, error) {
+	payload, err1 := base64.StdEncoding.DecodeString(m.Payload)
+	iv, err2 := base64.StdEncoding.DecodeString(m.Headers.IV)
+	tag, err3 := base64.StdEncoding.DecodeString(m.Headers.AuthTag)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return nil, ErrDecryption
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aead.NonceSize() {
+		return nil, ErrDecryption
+	}
+	data, err := aead.Open(nil, iv, append(payload, tag...), nil)
+	if err != nil {
+		return nil, ErrDecryption
+	}
+	if m.Headers.Compressed {
+		if data, err = inflate(data); err != nil {
+			return nil, ErrDecryption
+		}
+	}
+	return data, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,123 @@
+package encryption
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+// the example keys of bin/rails db:encryption:init
+var testConfig = Config{
+	PrimaryKey:        "EGY8WhulUOXixybod7ZWwMIL68R9o5kC",
+	DeterministicKey:  "aPA5XyALhf75NNnMzaspW7akTfZp0lPY",
+	KeyDerivationSalt: "xEY0dt6TZcAMg52K7O84wYzkjvbA62Hz",
+	HashDigest:        sha256.New,
+}
+
+// "Hello, Rails!" encrypted by Rails 7.1 using testConfig
+const railsEncrypted = `{"p":"vtZBj5uUZ4Em1hlMZg==","h":{"iv":"MDEyMzQ1Njc4OWFi","at":"KIyeSQ83g3cN7TtB+gBpLQ=="}}`
+
+// "jane@example.com" encrypted deterministically by Rails 7.1 using testConfig
+const railsDeterministic = `{"p":"R8u/Szkt5JI2UBszQhqbMw==","h":{"iv":"PVGaceNN6j6W/PjB","at":"jiPa7uA31fpq/PkNkUc8IA=="}}`
+
+func TestEncryptor(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Encryptor", func() {
+		e := NewEncryptor(testConfig)
+
+		g.It("decrypts the values encrypted by Rails", func() {
+			clearText, err := e.Decrypt(railsEncrypted)
+			g.Assert(err).Eql(nil)
+			g.Assert(clearText).Eql("Hello, Rails!")
+		})
+
+		g.It("round trips values", func() {
+			for _, clearText := range []string{"", "jane@example.com", strings.Repeat("long text ", 50)} {
+				encrypted, err := e.Encrypt(clearText)
+				g.Assert(err).Eql(nil)
+				decrypted, err := e.Decrypt(encrypted)
+				g.Assert(err).Eql(nil)
+				g.Assert(decrypted).Eql(clearText)
+			}
+		})
+
+		g.It("compresses the large values", func() {
+			encrypted, _ := e.Encrypt(strings.Repeat("long text ", 50))
+			var msg message
+			json.Unmarshal([]byte(encrypted), &msg)
+			g.Assert(msg.Headers.Compressed).IsTrue()
+			g.Assert(len(msg.Payload) < 500).IsTrue()
+
+			encrypted, _ = e.Encrypt("short")
+			g.Assert(strings.Contains(encrypted, `"c"`)).IsFalse()
+		})
+
+		g.It("refuses compressed values inflating too much", func() {
+			defer func(size int) { maxInflatedSize = size }(maxInflatedSize)
+			encrypted, _ := e.Encrypt(strings.Repeat("long text ", 50))
+			maxInflatedSize = 100
+			_, err := e.Decrypt(encrypted)
+			g.Assert(err).Eql(ErrDecryption)
+		})
+
+		g.It("refuses values encrypted with other keys", func() {
+			other := NewEncryptor(Config{PrimaryKey: "other", KeyDerivationSalt: testConfig.KeyDerivationSalt, HashDigest: sha256.New})
+			_, err := other.Decrypt(railsEncrypted)
+			g.Assert(err).Eql(ErrDecryption)
+			// Rails 7.0 derives its keys using sha1
+			sha1Config := testConfig
+			sha1Config.HashDigest = nil
+			_, err = NewEncryptor(sha1Config).Decrypt(railsEncrypted)
+			g.Assert(err).Eql(ErrDecryption)
+		})
+
+		g.It("refuses malformed values", func() {
+			for _, value := range []string{"", "clear text", `{"p":"abc","h":{}}`, `{"p":"!","h":{"iv":"MDEyMzQ1Njc4OWFi","at":"KIyeSQ83g3cN7TtB+gBpLQ=="}}`} {
+				_, err := e.Decrypt(value)
+				g.Assert(err).Eql(ErrDecryption)
+			}
+		})
+	})
+}
+
+func TestDeterministicEncryptor(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("deterministic Encryptor", func() {
+		e := NewDeterministicEncryptor(testConfig)
+
+		g.It("encrypts values exactly like Rails", func() {
+			encrypted, err := e.Encrypt("jane@example.com")
+			g.Assert(err).Eql(nil)
+			g.Assert(encrypted).Eql(railsDeterministic)
+		})
+
+		g.It("decrypts the values encrypted by Rails", func() {
+			clearText, err := e.Decrypt(railsDeterministic)
+			g.Assert(err).Eql(nil)
+			g.Assert(clearText).Eql("jane@example.com")
+		})
+
+		g.It("uses the deterministic key", func() {
+			_, err := NewEncryptor(testConfig).Decrypt(railsDeterministic)
+			g.Assert(err).Eql(ErrDecryption)
+		})
+	})
+}
+
+func ExampleEncryptor() {
+	e := NewEncryptor(Config{
+		PrimaryKey:        "EGY8WhulUOXixybod7ZWwMIL68R9o5kC",
+		KeyDerivationSalt: "xEY0dt6TZcAMg52K7O84wYzkjvbA62Hz",
+		HashDigest:        sha256.New,
+	})
+
+	clearText, err := e.Decrypt(`{"p":"vtZBj5uUZ4Em1hlMZg==","h":{"iv":"MDEyMzQ1Njc4OWFi","at":"KIyeSQ83g3cN7TtB+gBpLQ=="}}`)
+	fmt.Println(clearText, err)
+	// Output: Hello, Rails! <nil>
+}
@@ -0,0 +1,64 @@
+package encryption
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+)
+
+// Key is an encryption key.
+type Key struct {
+	Secret []byte
+}
+
+// ID returns the id of the key stored in the messages when key references
+// are enabled, the first 4 characters of the sha1 hex digest of the secret.
+func (k Key) ID() string {
+	sum := sha1.Sum(k.Secret)
+	return hex.EncodeToString(sum[:])[:4]
+}
+
+// DerivedSecretKeyProvider provides the keys derived from a list of
+// passwords, the last one encrypting the new values and all of them
+// decrypting the stored ones. This is how Rails rotates the primary key:
+//
+//	active_record_encryption:
+//	  primary_key:
+//	    - old key
+//	    - new key
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveRecord/Encryption/DerivedSecretKeyProvider.html
+type DerivedSecretKeyProvider struct {
+	keys []Key
+}
+
+// NewDerivedSecretKeyProvider derives the keys of passwords, listed from
+// the oldest to the current one, using the configuration's salt and
+// digest.
+func NewDerivedSecretKeyProvider(config Config, passwords ...string) *DerivedSecretKeyProvider {
+	p := &DerivedSecretKeyProvider{keys: make([]Key, len(passwords))}
+	for i, password := range passwords {
+		p.keys[i] = Key{Secret: config.DeriveKey(password)}
+	}
+	return p
+}
+
+// EncryptionKey returns the key encrypting new values, the last one.
+func (p *DerivedSecretKeyProvider) EncryptionKey() Key {
+	return p.keys[len(p.keys)-1]
+}
+
+// DecryptionKeys returns the keys to try, in order, to decrypt a value: the
+// ones matching the key id stored in the value, or all of them if it has
+// none.
+func (p *DerivedSecretKeyProvider) DecryptionKeys(keyID string) []Key {
+	if keyID == "" {
+		return p.keys
+	}
+	var keys []Key
+	for _, key := range p.keys {
+		if key.ID() == keyID {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
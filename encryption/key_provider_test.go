@@ -0,0 +1,85 @@
+package encryption
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+// encrypted by Rails 7.1 before rotating its primary key to testConfig's
+const railsEncryptedWithOldKey = `{"p":"gV++sEUXMbVZ+KXw7MZEPKHEAlb7ZgEpNz/7","h":{"iv":"YWIwMTIzNDU2Nzg5","at":"ELzGg/QQTFBvuRTgPYhdDQ=="}}`
+
+// "Hello, Rails!" encrypted by Rails 7.1 using testConfig with
+// store_key_references, referencing the key "039e"
+const railsEncryptedWithKeyReference = `{"p":"vtZBj5uUZ4Em1hlMZg==","h":{"iv":"MDEyMzQ1Njc4OWFi","at":"KIyeSQ83g3cN7TtB+gBpLQ==","i":"MDM5ZQ=="}}`
+
+func TestDerivedSecretKeyProvider(t *testing.T) {
+	g := Goblin(t)
+
+	rotatedConfig := testConfig
+	rotatedConfig.PreviousPrimaryKeys = []string{"OldPrimaryKeyBeforeTheRotation00"}
+
+	g.Describe("DerivedSecretKeyProvider", func() {
+		p := NewDerivedSecretKeyProvider(testConfig, "old", "new")
+
+		g.It("encrypts using the last key", func() {
+			g.Assert(p.EncryptionKey().Secret).Eql(testConfig.DeriveKey("new"))
+		})
+
+		g.It("decrypts using the keys matching the key id", func() {
+			g.Assert(len(p.DecryptionKeys(""))).Eql(2)
+			keys := p.DecryptionKeys(p.EncryptionKey().ID())
+			g.Assert(len(keys)).Eql(1)
+			g.Assert(keys[0].Secret).Eql(testConfig.DeriveKey("new"))
+			g.Assert(len(p.DecryptionKeys("none"))).Eql(0)
+		})
+	})
+
+	g.Describe("Encryptor with previous keys", func() {
+		e := NewEncryptor(rotatedConfig)
+
+		g.It("decrypts the values encrypted before the rotation", func() {
+			clearText, err := e.Decrypt(railsEncryptedWithOldKey)
+			g.Assert(err).Eql(nil)
+			g.Assert(clearText).Eql("written before the rotation")
+			clearText, err = e.Decrypt(railsEncrypted)
+			g.Assert(err).Eql(nil)
+			g.Assert(clearText).Eql("Hello, Rails!")
+		})
+
+		g.It("encrypts using the current key", func() {
+			encrypted, _ := e.Encrypt("new value")
+			clearText, err := NewEncryptor(testConfig).Decrypt(encrypted)
+			g.Assert(err).Eql(nil)
+			g.Assert(clearText).Eql("new value")
+		})
+
+		g.It("stores key references", func() {
+			config := rotatedConfig
+			config.StoreKeyReferences = true
+			e := NewEncryptor(config)
+			encrypted, _ := e.Encrypt("new value")
+			var msg message
+			json.Unmarshal([]byte(encrypted), &msg)
+			g.Assert(msg.Headers.KeyID).Eql("MDM5ZQ==")
+			clearText, err := e.Decrypt(encrypted)
+			g.Assert(err).Eql(nil)
+			g.Assert(clearText).Eql("new value")
+		})
+
+		g.It("reads the key references stored by Rails", func() {
+			clearText, err := e.Decrypt(railsEncryptedWithKeyReference)
+			g.Assert(err).Eql(nil)
+			g.Assert(clearText).Eql("Hello, Rails!")
+
+			unknownKey := strings.Replace(railsEncryptedWithKeyReference, "MDM5ZQ==", "YWJjZA==", 1)
+			_, err = e.Decrypt(unknownKey)
+			g.Assert(err).Eql(ErrDecryption)
+			notEncoded := strings.Replace(railsEncryptedWithKeyReference, "MDM5ZQ==", "039e", 1)
+			_, err = e.Decrypt(notEncoded)
+			g.Assert(err).Eql(ErrDecryption)
+		})
+	})
+}
@@ -0,0 +1,139 @@
+// The erbutil package ports ERB::Util's HTML escaping helpers and
+// ActiveSupport's html_safe marker, so HTML fragments rendered by Go
+// services follow the same escaping rules as Rails views.
+//
+// Rails documentation http://api.rubyonrails.org/classes/ERB/Util.html
+package erbutil
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SafeString is a string that was marked as already escaped and that can
+// therefore be rendered as is. It mirrors ActiveSupport::SafeBuffer.
+type SafeString string
+
+// HTMLSafe marks a string as safe, it will not be escaped anymore.
+// Only mark strings you trust.
+func HTMLSafe(str string) SafeString {
+	return SafeString(str)
+}
+
+// Concat appends the passed values to the safe string. Values which aren't
+// a SafeString are escaped before being appended.
+//
+//	HTMLSafe("<p>").Concat("<script>", HTMLSafe("</p>")) // => "<p>&lt;script&gt;</p>"
+func (s SafeString) Concat(values ...interface{}) SafeString {
+	var buf bytes.Buffer
+	buf.WriteString(string(s))
+	for _, v := range values {
+		buf.WriteString(string(HTMLEscape(v)))
+	}
+	return SafeString(buf.String())
+}
+
+// HTML converts the safe string so it can be used in a html/template
+// without being escaped again.
+func (s SafeString) HTML() template.HTML {
+	return template.HTML(s)
+}
+
+func (s SafeString) String() string {
+	return string(s)
+}
+
+var htmlEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+	"'", "&#39;",
+)
+
+// HTMLEscape escapes the HTML special characters of the passed value
+// (&"'><). A SafeString is returned untouched, other values are converted
+// to a string first.
+//
+//	HTMLEscape("is a > 0 & a < 10?") // => "is a &gt; 0 &amp; a &lt; 10?"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ERB/Util.html#method-c-html_escape
+func HTMLEscape(v interface{}) SafeString {
+	switch t := v.(type) {
+	case SafeString:
+		return t
+	case string:
+		return SafeString(htmlEscaper.Replace(t))
+	case fmt.Stringer:
+		return SafeString(htmlEscaper.Replace(t.String()))
+	case nil:
+		return ""
+	}
+	return SafeString(htmlEscaper.Replace(fmt.Sprint(v)))
+}
+
+var entityRegexp = regexp.MustCompile(`^&(?:[a-zA-Z]+|#\d+|#[xX][\dA-Fa-f]+);`)
+
+// HTMLEscapeOnce escapes the HTML special characters of the passed string
+// without affecting existing escaped entities.
+//
+//	HTMLEscapeOnce("1 < 2 &amp; 3") // => "1 &lt; 2 &amp; 3"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ERB/Util.html#method-c-html_escape_once
+func HTMLEscapeOnce(str string) SafeString {
+	var buf bytes.Buffer
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		if c == '&' {
+			if entity := entityRegexp.FindString(str[i:]); entity != "" {
+				buf.WriteString(entity)
+				i += len(entity) - 1
+				continue
+			}
+		}
+		switch c {
+		case '&', '<', '>', '"', '\'':
+			buf.WriteString(htmlEscaper.Replace(string(c)))
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return SafeString(buf.String())
+}
+
+var unescapeRegexp = regexp.MustCompile(`&(?:apos|amp|quot|gt|lt|#\d+|#[xX][\dA-Fa-f]+);`)
+
+// HTMLUnescape unescapes the entities produced by HTMLEscape as well as
+// numeric character references, the same way Ruby's CGI.unescapeHTML does.
+//
+//	HTMLUnescape("Usage: foo &quot;bar&quot; &lt;baz&gt;") // => `Usage: foo "bar" <baz>`
+func HTMLUnescape(str string) string {
+	return unescapeRegexp.ReplaceAllStringFunc(str, func(entity string) string {
+		switch entity {
+		case "&apos;":
+			return "'"
+		case "&amp;":
+			return "&"
+		case "&quot;":
+			return `"`
+		case "&gt;":
+			return ">"
+		case "&lt;":
+			return "<"
+		}
+		num := entity[2 : len(entity)-1]
+		base := 10
+		if num[0] == 'x' || num[0] == 'X' {
+			num, base = num[1:], 16
+		}
+		code, err := strconv.ParseInt(num, base, 32)
+		if err != nil || code > 0x10FFFF {
+			return entity
+		}
+		return string(rune(code))
+	})
+}
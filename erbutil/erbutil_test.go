@@ -0,0 +1,78 @@
+package erbutil
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleHTMLEscape() {
+	fmt.Println(HTMLEscape("is a > 0 & a < 10?"))
+	fmt.Println(HTMLEscape(HTMLSafe("<b>already safe</b>")))
+	// Output: is a &gt; 0 &amp; a &lt; 10?
+	// <b>already safe</b>
+}
+
+func ExampleSafeString_Concat() {
+	fmt.Println(HTMLSafe("<p>").Concat("<script>", HTMLSafe("</p>")))
+	// Output: <p>&lt;script&gt;</p>
+}
+
+func TestHTMLEscape(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("HTMLEscape", func() {
+		g.It("Should escape the special characters", func() {
+			g.Assert(string(HTMLEscape(`<>&"'`))).Equal("&lt;&gt;&amp;&quot;&#39;")
+		})
+
+		g.It("Should not escape safe strings", func() {
+			g.Assert(string(HTMLEscape(HTMLSafe("<br>")))).Equal("<br>")
+		})
+
+		g.It("Should convert other values", func() {
+			g.Assert(string(HTMLEscape(42))).Equal("42")
+			g.Assert(string(HTMLEscape(nil))).Equal("")
+		})
+	})
+
+	g.Describe("HTMLEscapeOnce", func() {
+		g.It("Should leave existing entities alone", func() {
+			expectations := map[string]string{
+				"1 < 2 &amp; 3":           "1 &lt; 2 &amp; 3",
+				"&lt;&gt;&amp;&quot;":     "&lt;&gt;&amp;&quot;",
+				"&#39; &#x27; &foo":       "&#39; &#x27; &amp;foo",
+				"<script>&nbsp;</script>": "&lt;script&gt;&nbsp;&lt;/script&gt;",
+			}
+			for input, output := range expectations {
+				g.Assert(string(HTMLEscapeOnce(input))).Equal(output)
+			}
+		})
+	})
+
+	g.Describe("HTMLUnescape", func() {
+		g.It("Should round trip escaped strings", func() {
+			str := `<a href="/?a=1&b='2'">`
+			g.Assert(HTMLUnescape(string(HTMLEscape(str)))).Equal(str)
+		})
+
+		g.It("Should unescape numeric references", func() {
+			g.Assert(HTMLUnescape("&#233;t&#xE9;")).Equal("été")
+		})
+
+		g.It("Should leave unknown entities alone", func() {
+			g.Assert(HTMLUnescape("&nbsp;&copy;")).Equal("&nbsp;&copy;")
+		})
+	})
+
+	g.Describe("SafeString", func() {
+		g.It("Should escape unsafe concatenated values", func() {
+			s := HTMLSafe("<ul>").Concat(HTMLSafe("<li>"), "Tom & Jerry", HTMLSafe("</li></ul>"))
+			g.Assert(string(s)).Equal("<ul><li>Tom &amp; Jerry</li></ul>")
+		})
+
+		g.It("Should convert to template.HTML", func() {
+			g.Assert(string(HTMLSafe("<b>").HTML())).Equal("<b>")
+		})
+	})
+}
@@ -0,0 +1,68 @@
+// The globalid package ports the GlobalID gem Rails uses to reference
+// records, for instance in the arguments of Active Job, so Go workers can
+// consume the references minted by a Rails app and the other way around.
+//
+// GlobalID documentation: https://github.com/rails/globalid
+package globalid
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalid is returned when parsing a string which isn't a global id.
+var ErrInvalid = errors.New("globalid: invalid global id")
+
+// GlobalID references a record of an app: gid://app/Model/id.
+type GlobalID struct {
+	// App is the name of the app owning the record, GlobalID.app in Rails.
+	App string
+	// ModelName is the class name of the record, such as "Admin::User".
+	ModelName string
+	// ModelID is the id of the record.
+	ModelID string
+	// Params are optional parameters passed in the query string.
+	Params url.Values
+}
+
+// New returns the global id of a record.
+//
+//	gid := globalid.New("bcx", "Person", "1")
+//	gid.String() // => "gid://bcx/Person/1"
+func New(app, modelName, modelID string) GlobalID {
+	return GlobalID{App: app, ModelName: modelName, ModelID: modelID}
+}
+
+// Parse parses a gid:// URI.
+func Parse(gid string) (GlobalID, error) {
+	u, err := url.Parse(gid)
+	if err != nil || u.Scheme != "gid" || u.Host == "" || u.User != nil || u.Port() != "" {
+		return GlobalID{}, ErrInvalid
+	}
+	modelName, modelID, ok := strings.Cut(strings.TrimPrefix(u.EscapedPath(), "/"), "/")
+	if !ok || modelName == "" || modelID == "" {
+		return GlobalID{}, ErrInvalid
+	}
+	if modelID, err = url.QueryUnescape(modelID); err != nil {
+		return GlobalID{}, ErrInvalid
+	}
+	g := GlobalID{App: u.Host, ModelName: modelName, ModelID: modelID}
+	if u.RawQuery != "" {
+		if g.Params, err = url.ParseQuery(u.RawQuery); err != nil {
+			return GlobalID{}, ErrInvalid
+		}
+	}
+	return g, nil
+}
+
+// String returns the gid:// URI of the global id, escaping its model id
+// like Ruby's CGI.escape.
+func (g GlobalID) String() string {
+	s := fmt.Sprintf("gid://%s/%s/%s", g.App, g.ModelName, url.QueryEscape(g.ModelID))
+	if len(g.Params) > 0 {
+		s += "?" + g.Params.Encode()
+	}
+	return s
+}
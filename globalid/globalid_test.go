@@ -0,0 +1,45 @@
+package globalid
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestGlobalID(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("GlobalID", func() {
+		g.It("formats gid URIs", func() {
+			g.Assert(New("bcx", "Person", "1").String()).Eql("gid://bcx/Person/1")
+			g.Assert(New("bcx", "Admin::User", "a b/c").String()).Eql("gid://bcx/Admin::User/a+b%2Fc")
+			gid := New("bcx", "Person", "1")
+			gid.Params = url.Values{"tenant": {"acme"}}
+			g.Assert(gid.String()).Eql("gid://bcx/Person/1?tenant=acme")
+		})
+
+		g.It("parses gid URIs", func() {
+			gid, err := Parse("gid://bcx/Admin::User/a+b%2Fc?tenant=acme")
+			g.Assert(err).Eql(nil)
+			g.Assert(gid.App).Eql("bcx")
+			g.Assert(gid.ModelName).Eql("Admin::User")
+			g.Assert(gid.ModelID).Eql("a b/c")
+			g.Assert(gid.Params.Get("tenant")).Eql("acme")
+		})
+
+		g.It("refuses invalid URIs", func() {
+			for _, s := range []string{"", "http://bcx/Person/1", "gid://bcx/Person", "gid://bcx//1", "gid:///Person/1", "gid://bcx:3000/Person/1"} {
+				_, err := Parse(s)
+				g.Assert(err).Eql(ErrInvalid)
+			}
+		})
+	})
+}
+
+func ExampleParse() {
+	gid, _ := Parse("gid://bcx/Person/1")
+	fmt.Println(gid.App, gid.ModelName, gid.ModelID)
+	// Output: bcx Person 1
+}
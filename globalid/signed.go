@@ -0,0 +1,77 @@
+package globalid
+
+import (
+	"time"
+
+	"github.com/mattetti/goRailsYourself/crypto"
+)
+
+const (
+	// Salt is the salt Rails derives the secret of the signed global ids
+	// from.
+	Salt = "signed_global_ids"
+	// DefaultPurpose is the purpose of the signed global ids generated
+	// without one.
+	DefaultPurpose = "default"
+	// DefaultExpiresIn is the lifetime of the signed global ids, one month
+	// in Rails.
+	DefaultExpiresIn = 30 * 24 * time.Hour
+)
+
+// Signer generates and verifies signed global ids, the tamper proof
+// references of SignedGlobalID.
+//
+// GlobalID documentation: https://github.com/rails/globalid#signed-global-ids
+type Signer struct {
+	// Verifier signs the global ids, set up by NewSigner like the GlobalID
+	// railtie does. Its serializer is Marshal, set it to
+	// crypto.JsonMsgSerializer{} for the apps using the Rails 7.1 message
+	// serializer defaults.
+	Verifier *crypto.MessageVerifier
+	// ExpiresIn is the lifetime of the generated signed global ids when no
+	// expiration is passed, SignedGlobalID.expires_in in Rails. Zero
+	// disables the expiration.
+	ExpiresIn time.Duration
+}
+
+// NewSigner returns the signer of an app, deriving its secret from the
+// app's key generator.
+//
+//	kg := &crypto.KeyGenerator{Secret: secretKeyBase, HashDigest: sha256.New}
+//	signer := globalid.NewSigner(kg)
+func NewSigner(kg *crypto.KeyGenerator) *Signer {
+	return &Signer{
+		Verifier: &crypto.MessageVerifier{
+			Secret:     kg.Generate([]byte(Salt), 64),
+			Serializer: crypto.MarshalMsgSerializer{},
+			URLSafe:    true,
+		},
+		ExpiresIn: DefaultExpiresIn,
+	}
+}
+
+// Sign returns the signed global id of gid for the options' purpose,
+// DefaultPurpose if not set, like gid.to_sgid(for:, expires_in:).
+func (s *Signer) Sign(gid GlobalID, opts crypto.MessageOptions) (string, error) {
+	if opts.Purpose == "" {
+		opts.Purpose = DefaultPurpose
+	}
+	if opts.ExpiresAt.IsZero() && opts.ExpiresIn == 0 {
+		opts.ExpiresIn = s.ExpiresIn
+	}
+	return s.Verifier.GenerateWithOptions(gid.String(), opts)
+}
+
+// Verify returns the global id of a signed global id generated for
+// purpose, DefaultPurpose if empty, like GlobalID::Locator.locate_signed
+// before finding the record.
+func (s *Signer) Verify(sgid, purpose string) (GlobalID, error) {
+	if purpose == "" {
+		purpose = DefaultPurpose
+	}
+	var gid string
+	if err := s.Verifier.VerifyWithPurpose(sgid, &gid, purpose); err != nil {
+		return GlobalID{}, err
+	}
+	return Parse(gid)
+}
@@ -0,0 +1,58 @@
+package globalid
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mattetti/goRailsYourself/crypto"
+
+	. "github.com/franela/goblin"
+)
+
+const secretKeyBase = "f7b5763636f4c1f3ff4bd444eacccca295d87b990cc104124017ad70550edcfd22b8e89465338254e0b608592a9aac29025440bfd9ce53579835ba06a86f85f9"
+
+// Person.find(1).to_sgid(expires_at: Time.utc(2099)).to_s in a Rails 7.0 app
+const railsSGID = "eyJfcmFpbHMiOnsibWVzc2FnZSI6IkJBaEpJaGRuYVdRNkx5OWlZM2d2VUdWeWMyOXVMekVHT2daRlZBPT0iLCJleHAiOiIyMDk5LTAxLTAxVDAwOjAwOjAwLjAwMFoiLCJwdXIiOiJkZWZhdWx0In19--9995b66ee8325de097c3692fe013bab7735f3988"
+
+func TestSigner(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Signer", func() {
+		signer := NewSigner(&crypto.KeyGenerator{Secret: secretKeyBase, HashDigest: sha256.New})
+
+		g.It("verifies the signed global ids generated by Rails", func() {
+			gid, err := signer.Verify(railsSGID, "")
+			g.Assert(err).Eql(nil)
+			g.Assert(gid).Eql(New("bcx", "Person", "1"))
+		})
+
+		g.It("refuses the signed global ids of other purposes", func() {
+			_, err := signer.Verify(railsSGID, "login")
+			g.Assert(err).Eql(crypto.ErrWrongPurpose)
+		})
+
+		g.It("round trips signed global ids", func() {
+			sgid, err := signer.Sign(New("bcx", "Person", "2"), crypto.MessageOptions{Purpose: "login"})
+			g.Assert(err).Eql(nil)
+			gid, err := signer.Verify(sgid, "login")
+			g.Assert(err).Eql(nil)
+			g.Assert(gid.ModelID).Eql("2")
+		})
+
+		g.It("expires the signed global ids", func() {
+			sgid, _ := signer.Sign(New("bcx", "Person", "2"), crypto.MessageOptions{ExpiresIn: -time.Minute})
+			_, err := signer.Verify(sgid, "")
+			g.Assert(err).Eql(crypto.ErrExpired)
+		})
+	})
+}
+
+func ExampleSigner() {
+	signer := NewSigner(&crypto.KeyGenerator{Secret: secretKeyBase, HashDigest: sha256.New})
+
+	gid, err := signer.Verify(railsSGID, "")
+	fmt.Println(gid, err)
+	// Output: gid://bcx/Person/1 <nil>
+}
@@ -1,9 +1,19 @@
 module github.com/mattetti/goRailsYourself
 
-go 1.15
+go 1.18
 
 require (
+	github.com/andybalholm/brotli v1.0.5
 	github.com/fiam/gounidecode v0.0.0-20150629112515-8deddbd03fec
 	github.com/franela/goblin v0.0.0-20201006155558-6240afcb2eb7
+	github.com/gorilla/sessions v1.2.2
+	github.com/klauspost/compress v1.16.7
 	golang.org/x/crypto v0.0.0-20201221181555-eec23a3978ad
+	golang.org/x/text v0.14.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/gorilla/securecookie v1.1.2 // indirect
+	golang.org/x/sys v0.5.0 // indirect
 )
@@ -0,0 +1,60 @@
+package hashes
+
+import "reflect"
+
+// DeepDup returns a deep copy of the passed value: maps, slices and arrays
+// are copied recursively so the copy can be mutated without affecting the
+// original. Other values, including strings and pointers, are immutable or
+// meant to be shared and are reused as is, which keeps the copy of large
+// structures cheap.
+//
+//	params := map[string]interface{}{"user": map[string]interface{}{"name": "Matt"}}
+//	dup := DeepDup(params)
+//	dup["user"].(map[string]interface{})["name"] = "John"
+//	params["user"].(map[string]interface{})["name"] // => "Matt"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Hash.html#method-i-deep_dup
+func DeepDup[T any](v T) T {
+	rv := reflect.ValueOf(&v).Elem()
+	dup := reflect.New(rv.Type())
+	dup.Elem().Set(deepDup(rv))
+	return *dup.Interface().(*T)
+}
+
+func deepDup(rv reflect.Value) reflect.Value {
+	switch rv.Kind() {
+	case reflect.Interface:
+		if rv.IsNil() {
+			return rv
+		}
+		dup := reflect.New(rv.Type()).Elem()
+		dup.Set(deepDup(rv.Elem()))
+		return dup
+	case reflect.Map:
+		if rv.IsNil() {
+			return rv
+		}
+		dup := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			dup.SetMapIndex(iter.Key(), deepDup(iter.Value()))
+		}
+		return dup
+	case reflect.Slice:
+		if rv.IsNil() {
+			return rv
+		}
+		dup := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			dup.Index(i).Set(deepDup(rv.Index(i)))
+		}
+		return dup
+	case reflect.Array:
+		dup := reflect.New(rv.Type()).Elem()
+		for i := 0; i < rv.Len(); i++ {
+			dup.Index(i).Set(deepDup(rv.Index(i)))
+		}
+		return dup
+	}
+	return rv
+}
@@ -0,0 +1,67 @@
+package hashes
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleDeepDup() {
+	params := map[string]interface{}{"user": map[string]interface{}{"name": "Matt"}}
+	dup := DeepDup(params)
+	dup["user"].(map[string]interface{})["name"] = "John"
+	fmt.Println(params["user"].(map[string]interface{})["name"])
+	fmt.Println(dup["user"].(map[string]interface{})["name"])
+	// Output: Matt
+	// John
+}
+
+func TestDeepDup(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("DeepDup", func() {
+		g.It("Should copy nested maps and slices", func() {
+			original := map[string]interface{}{
+				"list":   []interface{}{1, map[string]interface{}{"a": "b"}},
+				"nested": map[interface{}]interface{}{"c": []string{"d"}},
+			}
+			dup := DeepDup(original)
+			g.Assert(dup).Eql(original)
+
+			dup["list"].([]interface{})[1].(map[string]interface{})["a"] = "changed"
+			dup["nested"].(map[interface{}]interface{})["c"].([]string)[0] = "changed"
+			dup["new"] = true
+			g.Assert(original["list"].([]interface{})[1].(map[string]interface{})["a"]).Equal("b")
+			g.Assert(original["nested"].(map[interface{}]interface{})["c"].([]string)[0]).Equal("d")
+			g.Assert(len(original)).Equal(2)
+		})
+
+		g.It("Should keep the types", func() {
+			m := WithIndifferentAccess(map[string]interface{}{"a": map[string]interface{}{"b": 1}})
+			dup := DeepDup(m)
+			dup.Dig("a").(IndifferentMap).Set("b", 2)
+			g.Assert(m.Dig("a", "b")).Equal(1)
+
+			arr := [2][]int{{1}, {2}}
+			dupArr := DeepDup(arr)
+			dupArr[0][0] = 42
+			g.Assert(arr[0][0]).Equal(1)
+		})
+
+		g.It("Should share pointers and keep nil values", func() {
+			one := 1
+			dup := DeepDup(map[string]interface{}{"p": &one, "nil": nil})
+			g.Assert(dup["p"].(*int) == &one).IsTrue()
+			g.Assert(dup["nil"] == nil).IsTrue()
+
+			var nilMap map[string]int
+			g.Assert(DeepDup(nilMap) == nil).IsTrue()
+			var nilSlice []int
+			g.Assert(DeepDup(nilSlice) == nil).IsTrue()
+			var nothing interface{}
+			g.Assert(DeepDup(nothing) == nil).IsTrue()
+			g.Assert(DeepDup("foo")).Equal("foo")
+		})
+	})
+}
@@ -0,0 +1,88 @@
+package hashes
+
+import (
+	"reflect"
+
+	"github.com/mattetti/goRailsYourself/blank"
+)
+
+// Except returns a copy of the map without the passed keys.
+//
+//	Except(map[string]int{"a": 1, "b": 2, "c": 3}, "a", "c") // => map[b:2]
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Hash.html#method-i-except
+func Except[M ~map[K]V, K comparable, V any](m M, keys ...K) M {
+	excluded := make(map[K]struct{}, len(keys))
+	for _, k := range keys {
+		excluded[k] = struct{}{}
+	}
+	result := make(M, len(m))
+	for k, v := range m {
+		if _, ok := excluded[k]; !ok {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// Slice returns a copy of the map with only the passed keys. Missing keys
+// are ignored.
+//
+//	Slice(map[string]int{"a": 1, "b": 2, "c": 3}, "a", "d") // => map[a:1]
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Hash.html#method-i-slice
+func Slice[M ~map[K]V, K comparable, V any](m M, keys ...K) M {
+	result := make(M, len(keys))
+	for _, k := range keys {
+		if v, ok := m[k]; ok {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// Compact returns a copy of the map without its nil values.
+//
+//	Compact(map[string]interface{}{"a": 1, "b": nil}) // => map[a:1]
+//
+// Rails documentation: http://ruby-doc.org/core/Hash.html#method-i-compact
+func Compact[M ~map[K]V, K comparable, V any](m M) M {
+	result := make(M, len(m))
+	for k, v := range m {
+		if !isNil(v) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// CompactBlank returns a copy of the map without its blank values, as
+// defined by blank.IsBlank.
+//
+//	CompactBlank(map[string]interface{}{"a": "", "b": 1, "c": nil, "d": []string{}, "e": false})
+//	// => map[b:1]
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Hash.html#method-i-compact_blank
+func CompactBlank[M ~map[K]V, K comparable, V any](m M) M {
+	result := make(M, len(m))
+	for k, v := range m {
+		if blank.IsPresent(v) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// isNil reports whether v is nil or a nil pointer, map, slice, function,
+// channel or interface.
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
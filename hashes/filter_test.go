@@ -0,0 +1,77 @@
+package hashes
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleExcept() {
+	params := map[string]string{"name": "Matt", "password": "secret", "admin": "1"}
+	fmt.Println(Except(params, "password", "admin"))
+	fmt.Println(Slice(params, "name", "email"))
+	// Output: map[name:Matt]
+	// map[name:Matt]
+}
+
+func ExampleCompactBlank() {
+	fmt.Println(CompactBlank(map[string]interface{}{"a": "", "b": 1, "c": nil, "d": []string{}, "e": false}))
+	// Output: map[b:1]
+}
+
+func TestFilters(t *testing.T) {
+	g := Goblin(t)
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+
+	g.Describe("Except", func() {
+		g.It("Should remove the keys", func() {
+			g.Assert(Except(m, "a", "c")).Eql(map[string]int{"b": 2})
+			g.Assert(Except(m, "z")).Eql(m)
+			g.Assert(Except(m)).Eql(m)
+		})
+
+		g.It("Should not modify the map", func() {
+			Except(m, "a")
+			g.Assert(len(m)).Equal(3)
+		})
+
+		g.It("Should keep the map type", func() {
+			im := WithIndifferentAccess(map[string]interface{}{"a": 1, "b": 2})
+			var result IndifferentMap = Except(im, "a")
+			g.Assert(result.Get("b")).Equal(2)
+		})
+	})
+
+	g.Describe("Slice", func() {
+		g.It("Should keep the keys", func() {
+			g.Assert(Slice(m, "a", "c")).Eql(map[string]int{"a": 1, "c": 3})
+			g.Assert(Slice(m, "a", "z")).Eql(map[string]int{"a": 1})
+			g.Assert(Slice(m)).Eql(map[string]int{})
+		})
+	})
+
+	g.Describe("Compact", func() {
+		g.It("Should remove nil values", func() {
+			var nilSlice []int
+			var nilPtr *int
+			one := 1
+			in := map[string]interface{}{"a": 1, "b": nil, "c": nilSlice, "d": nilPtr, "e": &one, "f": ""}
+			g.Assert(Compact(in)).Eql(map[string]interface{}{"a": 1, "e": &one, "f": ""})
+		})
+	})
+
+	g.Describe("CompactBlank", func() {
+		g.It("Should remove blank values", func() {
+			in := map[string]interface{}{
+				"nil": nil, "false": false, "empty": "", "spaces": " \t\n",
+				"slice": []interface{}{}, "map": map[string]interface{}{},
+				"true": true, "zero": 0, "string": "a", "list": []int{0},
+			}
+			g.Assert(CompactBlank(in)).Eql(map[string]interface{}{
+				"true": true, "zero": 0, "string": "a", "list": []int{0},
+			})
+			g.Assert(CompactBlank(map[string]string{"a": "", "b": "b"})).Eql(map[string]string{"b": "b"})
+		})
+	})
+}
@@ -0,0 +1,198 @@
+// The hashes package ports ActiveSupport's Hash extensions, making it easier
+// to work with the loosely typed maps decoded from Rails payloads such as
+// params, sessions or YAML configuration files.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Hash.html
+package hashes
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// IndifferentMap is a map whose keys are normalized so string and
+// symbol-style keys ("name" and ":name") are considered the same. Nested
+// maps are converted when they are added so the whole structure can be
+// accessed indifferently.
+//
+//	m := WithIndifferentAccess(map[interface{}]interface{}{":user": map[string]interface{}{"name": "Matt"}})
+//	m.Get("user")          // => IndifferentMap{"name": "Matt"}
+//	m.Dig("user", ":name") // => "Matt"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/HashWithIndifferentAccess.html
+type IndifferentMap map[string]interface{}
+
+// WithIndifferentAccess converts a map[string]interface{}, a
+// map[interface{}]interface{} (as decoded by some YAML libraries) or an
+// IndifferentMap into an IndifferentMap, converting nested maps, including
+// the ones found in slices. Any other value returns an empty map.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Hash.html#method-i-with_indifferent_access
+func WithIndifferentAccess(v interface{}) IndifferentMap {
+	if m, ok := convertValue(v).(IndifferentMap); ok {
+		return m
+	}
+	return IndifferentMap{}
+}
+
+// Get returns the value for the key or nil.
+func (m IndifferentMap) Get(key string) interface{} {
+	return m[normalizeKey(key)]
+}
+
+// Fetch returns the value for the key and whether it was found.
+func (m IndifferentMap) Fetch(key string) (interface{}, bool) {
+	v, ok := m[normalizeKey(key)]
+	return v, ok
+}
+
+// Set sets the value for the key, converting nested maps.
+func (m IndifferentMap) Set(key string, value interface{}) {
+	m[normalizeKey(key)] = convertValue(value)
+}
+
+// Delete removes the key and returns its value.
+func (m IndifferentMap) Delete(key string) interface{} {
+	key = normalizeKey(key)
+	v := m[key]
+	delete(m, key)
+	return v
+}
+
+// HasKey reports whether the key is set.
+func (m IndifferentMap) HasKey(key string) bool {
+	_, ok := m[normalizeKey(key)]
+	return ok
+}
+
+// Dig extracts the nested value following the passed keys. Strings index
+// maps and ints index slices, negative indexes counting from the end. nil
+// is returned as soon as a step can't be followed.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/HashWithIndifferentAccess.html#method-i-dig
+func (m IndifferentMap) Dig(keys ...interface{}) interface{} {
+	var current interface{} = m
+	for _, key := range keys {
+		switch c := current.(type) {
+		case IndifferentMap:
+			k, ok := key.(string)
+			if !ok {
+				return nil
+			}
+			current = c.Get(k)
+		case []interface{}:
+			i, ok := key.(int)
+			if !ok {
+				return nil
+			}
+			if i < 0 {
+				i += len(c)
+			}
+			if i < 0 || i >= len(c) {
+				return nil
+			}
+			current = c[i]
+		default:
+			return nil
+		}
+	}
+	return current
+}
+
+// Merge returns a new map with the content of m and other, other's values
+// taking precedence.
+func (m IndifferentMap) Merge(other map[string]interface{}) IndifferentMap {
+	merged := make(IndifferentMap, len(m)+len(other))
+	for k, v := range m {
+		merged[k] = v
+	}
+	for k, v := range other {
+		merged.Set(k, v)
+	}
+	return merged
+}
+
+// ToMap converts the map and its nested IndifferentMaps back to plain
+// map[string]interface{} values.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/HashWithIndifferentAccess.html#method-i-to_hash
+func (m IndifferentMap) ToMap() map[string]interface{} {
+	return plainValue(m).(map[string]interface{})
+}
+
+// UnmarshalJSON decodes a JSON object, converting nested objects to
+// IndifferentMaps.
+func (m *IndifferentMap) UnmarshalJSON(data []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*m = WithIndifferentAccess(raw)
+	return nil
+}
+
+// normalizeKey turns symbol-style keys into strings.
+func normalizeKey(key string) string {
+	if len(key) > 1 && key[0] == ':' {
+		return key[1:]
+	}
+	return key
+}
+
+// convertValue converts the maps found in v to IndifferentMaps.
+func convertValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case IndifferentMap:
+		m := make(IndifferentMap, len(val))
+		for k, v := range val {
+			m.Set(k, v)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(IndifferentMap, len(val))
+		for k, v := range val {
+			m.Set(k, v)
+		}
+		return m
+	case map[interface{}]interface{}:
+		m := make(IndifferentMap, len(val))
+		for k, v := range val {
+			m.Set(keyString(k), v)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(val))
+		for i, v := range val {
+			s[i] = convertValue(v)
+		}
+		return s
+	}
+	return v
+}
+
+// keyString converts a non string map key.
+func keyString(k interface{}) string {
+	if s, ok := k.(string); ok {
+		return s
+	}
+	return fmt.Sprint(k)
+}
+
+// plainValue converts the IndifferentMaps found in v to plain maps.
+func plainValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case IndifferentMap:
+		m := make(map[string]interface{}, len(val))
+		for k, v := range val {
+			m[k] = plainValue(v)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(val))
+		for i, v := range val {
+			s[i] = plainValue(v)
+		}
+		return s
+	}
+	return v
+}
@@ -0,0 +1,92 @@
+package hashes
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleWithIndifferentAccess() {
+	m := WithIndifferentAccess(map[interface{}]interface{}{
+		":user": map[string]interface{}{"name": "Matt", "roles": []interface{}{"admin"}},
+	})
+	fmt.Println(m.Dig("user", "name"))
+	fmt.Println(m.Dig(":user", ":roles", 0))
+	// Output: Matt
+	// admin
+}
+
+func TestIndifferentMap(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("IndifferentMap", func() {
+		g.It("Should normalize symbol-style keys", func() {
+			m := WithIndifferentAccess(map[string]interface{}{":a": 1, "b": 2})
+			g.Assert(m.Get("a")).Equal(1)
+			g.Assert(m.Get(":a")).Equal(1)
+			g.Assert(m.Get(":b")).Equal(2)
+			g.Assert(m.HasKey("c")).IsFalse()
+			m.Set(":c", 3)
+			v, ok := m.Fetch("c")
+			g.Assert(ok).IsTrue()
+			g.Assert(v).Equal(3)
+			g.Assert(m.Delete(":c")).Equal(3)
+			g.Assert(m.HasKey("c")).IsFalse()
+			g.Assert(m.Get(":")).Equal(nil)
+		})
+
+		g.It("Should convert nested maps", func() {
+			m := WithIndifferentAccess(map[interface{}]interface{}{
+				"user": map[interface{}]interface{}{":name": "Matt", 42: "answer"},
+				"list": []interface{}{map[string]interface{}{":id": 1}},
+			})
+			g.Assert(m.Get("user")).Eql(IndifferentMap{"name": "Matt", "42": "answer"})
+			g.Assert(m.Dig("list", 0, "id")).Equal(1)
+			m.Set("nested", map[string]interface{}{":x": "y"})
+			g.Assert(m.Dig("nested", "x")).Equal("y")
+		})
+
+		g.It("Should dig safely", func() {
+			m := WithIndifferentAccess(map[string]interface{}{"a": []interface{}{1, 2, 3}, "b": "c"})
+			g.Assert(m.Dig("a", -1)).Equal(3)
+			g.Assert(m.Dig("a", 3) == nil).IsTrue()
+			g.Assert(m.Dig("a", "x") == nil).IsTrue()
+			g.Assert(m.Dig("b", "c") == nil).IsTrue()
+			g.Assert(m.Dig("missing", "c") == nil).IsTrue()
+			g.Assert(m.Dig()).Eql(m)
+		})
+
+		g.It("Should merge maps", func() {
+			m := WithIndifferentAccess(map[string]interface{}{"a": 1, "b": 2})
+			merged := m.Merge(map[string]interface{}{":b": 3, "c": map[string]interface{}{"d": 4}})
+			g.Assert(merged.Get("b")).Equal(3)
+			g.Assert(merged.Dig("c", "d")).Equal(4)
+			g.Assert(m.Get("b")).Equal(2)
+		})
+
+		g.It("Should convert back to plain maps", func() {
+			m := WithIndifferentAccess(map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{map[string]interface{}{"c": 1}}}})
+			plain := m.ToMap()
+			g.Assert(plain).Eql(map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{map[string]interface{}{"c": 1}}}})
+		})
+
+		g.It("Should ignore values that aren't maps", func() {
+			g.Assert(WithIndifferentAccess(nil)).Eql(IndifferentMap{})
+			g.Assert(WithIndifferentAccess("foo")).Eql(IndifferentMap{})
+		})
+
+		g.It("Should encode and decode JSON", func() {
+			var m IndifferentMap
+			err := json.Unmarshal([]byte(`{"user":{"name":"Matt","tags":[{"id":1}]}}`), &m)
+			g.Assert(err).Equal(nil)
+			g.Assert(m.Dig("user", "name")).Equal("Matt")
+			g.Assert(m.Dig("user", "tags", 0, ":id")).Equal(float64(1))
+			data, err := json.Marshal(m)
+			g.Assert(err).Equal(nil)
+			g.Assert(string(data)).Equal(`{"user":{"name":"Matt","tags":[{"id":1}]}}`)
+			g.Assert(json.Unmarshal([]byte(`[1]`), &m) != nil).IsTrue()
+		})
+	})
+}
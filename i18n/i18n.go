@@ -0,0 +1,326 @@
+// The i18n package is a minimal port of the Ruby I18n gem's simple backend.
+// It loads the YAML locale files a Rails app maintains and is consulted by
+// the localized helpers of this repository (number, texthelper, duration,
+// inflector), so Go output can be localized with the same translations.
+//
+//	i18n.LoadFile("config/locales/fr.yml")
+//	i18n.Translate("fr", "support.array.two_words_connector", nil) // => " et "
+//
+// Ruby documentation: https://github.com/ruby-i18n/i18n
+package i18n
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Backend stores translations per locale and resolves them using fallbacks
+// and pluralization rules. The package functions use the backend returned
+// by Default().
+type Backend struct {
+	mu           sync.RWMutex
+	translations map[string]map[string]interface{}
+	fallbacks    map[string][]string
+	pluralRules  map[string]PluralRule
+	// defaultLocale is used when no locale is passed and is the last
+	// fallback of every locale.
+	defaultLocale string
+}
+
+var defaultBackend = NewBackend()
+
+// NewBackend returns a backend without translations, using "en" as its
+// default locale.
+func NewBackend() *Backend {
+	return &Backend{
+		translations:  map[string]map[string]interface{}{},
+		fallbacks:     map[string][]string{},
+		pluralRules:   map[string]PluralRule{},
+		defaultLocale: "en",
+	}
+}
+
+// Default returns the backend used by the package level functions and the
+// localized helpers.
+func Default() *Backend {
+	return defaultBackend
+}
+
+// SetDefaultLocale changes the default locale, I18n.default_locale in Ruby.
+func (b *Backend) SetDefaultLocale(locale string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.defaultLocale = locale
+}
+
+// DefaultLocale returns the default locale.
+func (b *Backend) DefaultLocale() string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.defaultLocale
+}
+
+// Load loads the translations of a YAML locale file, whose top level keys
+// are locales:
+//
+//	fr:
+//	  support:
+//	    array:
+//	      two_words_connector: " et "
+func (b *Backend) Load(data []byte) error {
+	var locales map[string]map[string]interface{}
+	if err := yaml.Unmarshal(data, &locales); err != nil {
+		return err
+	}
+	for locale, translations := range locales {
+		b.Store(locale, translations)
+	}
+	return nil
+}
+
+// LoadFile loads the translations of a YAML locale file.
+func (b *Backend) LoadFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return b.Load(data)
+}
+
+// Store deep merges the translations into the ones of the locale, like
+// I18n.backend.store_translations.
+func (b *Backend) Store(locale string, translations map[string]interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	existing, ok := b.translations[locale]
+	if !ok {
+		existing = map[string]interface{}{}
+		b.translations[locale] = existing
+	}
+	deepMerge(existing, normalize(translations).(map[string]interface{}))
+}
+
+// Available reports whether translations were loaded for the locale or for
+// its language ("fr" for "fr-CA").
+func (b *Backend) Available(locale string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if _, ok := b.translations[locale]; ok {
+		return true
+	}
+	if lang := language(locale); lang != locale {
+		_, ok := b.translations[lang]
+		return ok
+	}
+	return false
+}
+
+// SetFallbacks sets the locales to look into, in order, when a translation
+// is missing for the locale. The language of the locale and the default
+// locale are always tried last.
+func (b *Backend) SetFallbacks(locale string, fallbacks ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.fallbacks[locale] = fallbacks
+}
+
+// Fallbacks returns the locales looked into for the locale: the locale
+// itself, its configured fallbacks, its language and the default locale.
+func (b *Backend) Fallbacks(locale string) []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if locale == "" {
+		locale = b.defaultLocale
+	}
+	chain := []string{locale}
+	add := func(l string) {
+		for _, existing := range chain {
+			if existing == l {
+				return
+			}
+		}
+		chain = append(chain, l)
+	}
+	for _, l := range b.fallbacks[locale] {
+		add(l)
+	}
+	add(language(locale))
+	add(b.defaultLocale)
+	return chain
+}
+
+// Lookup returns the raw translation stored for the dotted key, which can
+// be a string, a nested map or a list, trying the fallbacks of the locale.
+func (b *Backend) Lookup(locale, key string) (interface{}, bool) {
+	for _, l := range b.Fallbacks(locale) {
+		if v, ok := b.lookup(l, key); ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func (b *Backend) lookup(locale, key string) (interface{}, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	var current interface{} = b.translations[locale]
+	for _, part := range strings.Split(key, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, current != nil
+}
+
+// Translate returns the translation of the dotted key, interpolating the
+// %{name} placeholders with the passed values. If values contains a
+// "count", the translation is pluralized using the rule of the locale.
+//
+//	Translate("en", "datetime.distance_in_words.x_days", map[string]interface{}{"count": 2}) // => "2 days"
+func (b *Backend) Translate(locale, key string, values map[string]interface{}) (string, error) {
+	if locale == "" {
+		locale = b.DefaultLocale()
+	}
+	entry, ok := b.Lookup(locale, key)
+	if !ok {
+		return "", fmt.Errorf("translation missing: %s.%s", locale, key)
+	}
+	if count, ok := values["count"]; ok {
+		if forms, ok := entry.(map[string]interface{}); ok {
+			if entry, ok = b.pluralize(locale, forms, count); !ok {
+				return "", fmt.Errorf("translation missing: %s.%s (no plural form for %v)", locale, key, count)
+			}
+		}
+	}
+	str, ok := entry.(string)
+	if !ok {
+		return "", fmt.Errorf("translation %s.%s is not a string", locale, key)
+	}
+	return interpolate(str, values), nil
+}
+
+// Exists reports whether a translation exists for the key.
+func (b *Backend) Exists(locale, key string) bool {
+	_, ok := b.Lookup(locale, key)
+	return ok
+}
+
+// language returns the language part of the locale.
+func language(locale string) string {
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		return locale[:i]
+	}
+	return locale
+}
+
+// deepMerge merges src into dst, nested maps being merged recursively.
+func deepMerge(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if srcMap, ok := v.(map[string]interface{}); ok {
+			if dstMap, ok := dst[k].(map[string]interface{}); ok {
+				deepMerge(dstMap, srcMap)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
+
+// normalize converts the maps with non string keys, as decoded from YAML
+// keys like "1" or "true", to map[string]interface{}.
+func normalize(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, v := range val {
+			m[k] = normalize(v)
+		}
+		return m
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, v := range val {
+			m[fmt.Sprint(k)] = normalize(v)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(val))
+		for i, v := range val {
+			s[i] = normalize(v)
+		}
+		return s
+	}
+	return v
+}
+
+// interpolate replaces the %{name} placeholders by their values, leaving
+// unknown placeholders untouched.
+func interpolate(str string, values map[string]interface{}) string {
+	if len(values) == 0 || !strings.Contains(str, "%{") {
+		return str
+	}
+	var b strings.Builder
+	for {
+		start := strings.Index(str, "%{")
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(str[start:], '}')
+		if end < 0 {
+			break
+		}
+		name := str[start+2 : start+end]
+		b.WriteString(str[:start])
+		if v, ok := values[name]; ok {
+			b.WriteString(fmt.Sprint(v))
+		} else {
+			b.WriteString(str[start : start+end+1])
+		}
+		str = str[start+end+1:]
+	}
+	b.WriteString(str)
+	return b.String()
+}
+
+// Load loads a YAML locale file content into the default backend.
+func Load(data []byte) error {
+	return defaultBackend.Load(data)
+}
+
+// LoadFile loads a YAML locale file into the default backend.
+func LoadFile(path string) error {
+	return defaultBackend.LoadFile(path)
+}
+
+// Store deep merges translations into the default backend.
+func Store(locale string, translations map[string]interface{}) {
+	defaultBackend.Store(locale, translations)
+}
+
+// Available reports whether the default backend has translations for the
+// locale.
+func Available(locale string) bool {
+	return defaultBackend.Available(locale)
+}
+
+// Lookup returns a raw translation from the default backend.
+func Lookup(locale, key string) (interface{}, bool) {
+	return defaultBackend.Lookup(locale, key)
+}
+
+// Translate translates the key using the default backend.
+func Translate(locale, key string, values map[string]interface{}) (string, error) {
+	return defaultBackend.Translate(locale, key, values)
+}
+
+// Exists reports whether the default backend has a translation for the key.
+func Exists(locale, key string) bool {
+	return defaultBackend.Exists(locale, key)
+}
@@ -0,0 +1,127 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+const localeFile = `
+en:
+  hello: "Hello %{name}!"
+  inbox:
+    zero: "no messages"
+    one: "1 message"
+    other: "%{count} messages"
+  support:
+    array:
+      two_words_connector: " and "
+fr:
+  hello: "Bonjour %{name} !"
+  inbox:
+    one: "%{count} message"
+    other: "%{count} messages"
+  1: numeric key
+`
+
+func ExampleTranslate() {
+	b := NewBackend()
+	b.Load([]byte(localeFile))
+	fmt.Println(b.Translate("fr", "hello", map[string]interface{}{"name": "Matt"}))
+	fmt.Println(b.Translate("fr", "inbox", map[string]interface{}{"count": 0}))
+	fmt.Println(b.Translate("en", "inbox", map[string]interface{}{"count": 0}))
+	// Output: Bonjour Matt ! <nil>
+	// 0 message <nil>
+	// no messages <nil>
+}
+
+func TestBackend(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Backend", func() {
+		b := NewBackend()
+		g.Assert(b.Load([]byte(localeFile))).Equal(nil)
+
+		g.It("Should translate and interpolate", func() {
+			str, err := b.Translate("en", "hello", map[string]interface{}{"name": "Matt"})
+			g.Assert(err).Equal(nil)
+			g.Assert(str).Equal("Hello Matt!")
+			str, _ = b.Translate("en", "hello", nil)
+			g.Assert(str).Equal("Hello %{name}!")
+			str, _ = b.Translate("", "support.array.two_words_connector", nil)
+			g.Assert(str).Equal(" and ")
+			str, _ = b.Translate("fr", "1", nil)
+			g.Assert(str).Equal("numeric key")
+		})
+
+		g.It("Should pluralize", func() {
+			tr := func(locale string, count interface{}) string {
+				str, _ := b.Translate(locale, "inbox", map[string]interface{}{"count": count})
+				return str
+			}
+			g.Assert(tr("en", 0)).Equal("no messages")
+			g.Assert(tr("en", 1)).Equal("1 message")
+			g.Assert(tr("en", 2)).Equal("2 messages")
+			g.Assert(tr("en", 1.5)).Equal("1.5 messages")
+			g.Assert(tr("fr", 0)).Equal("0 message")
+			g.Assert(tr("fr", 1.5)).Equal("1.5 message")
+			g.Assert(tr("fr", 2)).Equal("2 messages")
+		})
+
+		g.It("Should fall back", func() {
+			g.Assert(b.Fallbacks("fr-CA")).Eql([]string{"fr-CA", "fr", "en"})
+			str, _ := b.Translate("fr-CA", "hello", map[string]interface{}{"name": "Matt"})
+			g.Assert(str).Equal("Bonjour Matt !")
+			str, _ = b.Translate("fr", "support.array.two_words_connector", nil)
+			g.Assert(str).Equal(" and ")
+			b.SetFallbacks("es", "fr")
+			g.Assert(b.Fallbacks("es")).Eql([]string{"es", "fr", "en"})
+			str, _ = b.Translate("es", "hello", map[string]interface{}{"name": "Matt"})
+			g.Assert(str).Equal("Bonjour Matt !")
+		})
+
+		g.It("Should report missing translations", func() {
+			_, err := b.Translate("fr", "missing.key", nil)
+			g.Assert(err.Error()).Equal("translation missing: fr.missing.key")
+			_, err = b.Translate("en", "support.array", nil)
+			g.Assert(err != nil).IsTrue()
+			g.Assert(b.Exists("en", "support.array")).IsTrue()
+			g.Assert(b.Exists("en", "support.nope")).IsFalse()
+		})
+
+		g.It("Should report the available locales", func() {
+			g.Assert(b.Available("fr")).IsTrue()
+			g.Assert(b.Available("fr-CA")).IsTrue()
+			g.Assert(b.Available("de")).IsFalse()
+		})
+
+		g.It("Should merge stored translations", func() {
+			b.Store("en", map[string]interface{}{"support": map[string]interface{}{"array": map[string]interface{}{"words_connector": ", "}}})
+			g.Assert(b.Exists("en", "support.array.words_connector")).IsTrue()
+			g.Assert(b.Exists("en", "support.array.two_words_connector")).IsTrue()
+		})
+
+		g.It("Should change the default locale", func() {
+			other := NewBackend()
+			other.Load([]byte(localeFile))
+			other.SetDefaultLocale("fr")
+			str, _ := other.Translate("", "hello", map[string]interface{}{"name": "Matt"})
+			g.Assert(str).Equal("Bonjour Matt !")
+		})
+
+		g.It("Should load files", func() {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "de.yml")
+			os.WriteFile(path, []byte("de:\n  hello: Hallo %{name}!\n"), 0644)
+			other := NewBackend()
+			g.Assert(other.LoadFile(path)).Equal(nil)
+			str, _ := other.Translate("de", "hello", map[string]interface{}{"name": "Matt"})
+			g.Assert(str).Equal("Hallo Matt!")
+			g.Assert(other.LoadFile(filepath.Join(dir, "missing.yml")) != nil).IsTrue()
+			g.Assert(other.Load([]byte("- not a locale file")) != nil).IsTrue()
+		})
+	})
+}
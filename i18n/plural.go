@@ -0,0 +1,116 @@
+package i18n
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// PluralRule returns the plural key ("zero", "one", "few", "many" or
+// "other") to use for the count.
+type PluralRule func(count float64) string
+
+// pluralRules holds the rules of the locales whose plural forms differ from
+// English, as defined by the rails-i18n pluralization files.
+var pluralRules = map[string]PluralRule{
+	// one for 0 and 1 (and everything in between)
+	"fr": oneUptoTwo,
+	"pt": oneUptoTwo,
+	// no plural forms
+	"ja": otherOnly,
+	"ko": otherOnly,
+	"zh": otherOnly,
+	// East Slavic
+	"ru": eastSlavic,
+	"uk": eastSlavic,
+}
+
+func oneOther(n float64) string {
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+func oneUptoTwo(n float64) string {
+	if n >= 0 && n < 2 {
+		return "one"
+	}
+	return "other"
+}
+
+func otherOnly(float64) string {
+	return "other"
+}
+
+func eastSlavic(n float64) string {
+	if n != math.Trunc(n) {
+		return "other"
+	}
+	mod10, mod100 := math.Mod(n, 10), math.Mod(n, 100)
+	switch {
+	case mod10 == 1 && mod100 != 11:
+		return "one"
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return "few"
+	}
+	return "many"
+}
+
+// SetPluralRule sets the pluralization rule of the locale.
+func (b *Backend) SetPluralRule(locale string, rule PluralRule) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pluralRules[locale] = rule
+}
+
+// pluralRule returns the rule of the locale or of its language, English's
+// one/other rule being the default.
+func (b *Backend) pluralRule(locale string) PluralRule {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, l := range []string{locale, language(locale)} {
+		if rule, ok := b.pluralRules[l]; ok {
+			return rule
+		}
+		if rule, ok := pluralRules[l]; ok {
+			return rule
+		}
+	}
+	return oneOther
+}
+
+// pluralize picks the form matching the count. Like Ruby I18n, a "zero"
+// form is used for 0 when present and "other" is used when the form picked
+// by the rule is missing.
+func (b *Backend) pluralize(locale string, forms map[string]interface{}, count interface{}) (interface{}, bool) {
+	n, ok := toFloat(count)
+	if !ok {
+		return nil, false
+	}
+	if n == 0 {
+		if form, ok := forms["zero"]; ok {
+			return form, true
+		}
+	}
+	if form, ok := forms[b.pluralRule(locale)(n)]; ok {
+		return form, true
+	}
+	form, ok := forms["other"]
+	return form, ok
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	}
+	f, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+	return f, err == nil
+}
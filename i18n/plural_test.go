@@ -0,0 +1,43 @@
+package i18n
+
+import (
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestPluralRules(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Plural rules", func() {
+		b := NewBackend()
+
+		g.It("Should default to English", func() {
+			g.Assert(b.pluralRule("en")(1)).Equal("one")
+			g.Assert(b.pluralRule("xx")(0)).Equal("other")
+		})
+
+		g.It("Should know the usual rules", func() {
+			g.Assert(b.pluralRule("fr-CA")(0)).Equal("one")
+			g.Assert(b.pluralRule("ja")(1)).Equal("other")
+			ru := b.pluralRule("ru")
+			g.Assert(ru(1)).Equal("one")
+			g.Assert(ru(21)).Equal("one")
+			g.Assert(ru(11)).Equal("many")
+			g.Assert(ru(3)).Equal("few")
+			g.Assert(ru(13)).Equal("many")
+			g.Assert(ru(5)).Equal("many")
+			g.Assert(ru(1.5)).Equal("other")
+		})
+
+		g.It("Should support custom rules", func() {
+			b.SetPluralRule("xx", func(float64) string { return "few" })
+			g.Assert(b.pluralRule("xx")(1)).Equal("few")
+			form, ok := b.pluralize("xx", map[string]interface{}{"other": "others"}, 1)
+			g.Assert(ok).IsTrue()
+			g.Assert(form).Equal("others")
+			_, ok = b.pluralize("xx", map[string]interface{}{"other": "others"}, "not a number")
+			g.Assert(ok).IsFalse()
+		})
+	})
+}
@@ -0,0 +1,59 @@
+package inflector
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// UpcaseLocale upper cases the string using the case mapping rules of the
+// locale, such as the Turkish and Azeri dotted i. Unknown locales use the
+// default Unicode rules.
+//
+//	UpcaseLocale("istanbul", "tr") // => "İSTANBUL"
+//	UpcaseLocale("istanbul", "en") // => "ISTANBUL"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Multibyte/Chars.html#method-i-upcase
+func UpcaseLocale(str, locale string) string {
+	return cases.Upper(localeTag(locale)).String(str)
+}
+
+// DowncaseLocale lower cases the string using the case mapping rules of
+// the locale. Unknown locales use the default Unicode rules.
+//
+//	DowncaseLocale("DİYARBAKIR", "tr") // => "diyarbakır"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Multibyte/Chars.html#method-i-downcase
+func DowncaseLocale(str, locale string) string {
+	return cases.Lower(localeTag(locale)).String(str)
+}
+
+// CapitalizeLocale title cases the first character of the string and lower
+// cases the rest, using the case mapping rules of the locale.
+//
+//	CapitalizeLocale("izmir", "tr") // => "İzmir"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Multibyte/Chars.html#method-i-capitalize
+func CapitalizeLocale(str, locale string) string {
+	if str == "" {
+		return str
+	}
+	tag := localeTag(locale)
+	_, size := utf8.DecodeRuneInString(str)
+	var b strings.Builder
+	b.WriteString(cases.Title(tag).String(str[:size]))
+	b.WriteString(cases.Lower(tag).String(str[size:]))
+	return b.String()
+}
+
+// localeTag returns the language tag of the locale, or the undetermined
+// language if it can't be parsed.
+func localeTag(locale string) language.Tag {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return language.Und
+	}
+	return tag
+}
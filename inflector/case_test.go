@@ -0,0 +1,42 @@
+package inflector
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleUpcaseLocale() {
+	fmt.Println(UpcaseLocale("istanbul", "tr"))
+	fmt.Println(UpcaseLocale("istanbul", "en"))
+	// Output: İSTANBUL
+	// ISTANBUL
+}
+
+func TestLocaleCase(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("Locale case mapping", func() {
+		g.It("Should upcase using the locale rules", func() {
+			g.Assert(UpcaseLocale("diyarbakır", "tr")).Equal("DİYARBAKIR")
+			g.Assert(UpcaseLocale("iı", "az")).Equal("İI")
+			g.Assert(UpcaseLocale("straße", "de")).Equal("STRASSE")
+			g.Assert(UpcaseLocale("iı", "unknown locale")).Equal("II")
+		})
+
+		g.It("Should downcase using the locale rules", func() {
+			g.Assert(DowncaseLocale("DİYARBAKIR", "tr")).Equal("diyarbakır")
+			g.Assert(DowncaseLocale("DIYARBAKIR", "en")).Equal("diyarbakir")
+			// Lithuanian keeps the dot of i when adding an accent
+			g.Assert(DowncaseLocale("Ì", "lt")).Equal("i̇̀")
+			g.Assert(DowncaseLocale("Ì", "en")).Equal("ì")
+		})
+
+		g.It("Should capitalize using the locale rules", func() {
+			g.Assert(CapitalizeLocale("izmir", "tr")).Equal("İzmir")
+			g.Assert(CapitalizeLocale("IZMIR", "tr")).Equal("Izmır")
+			g.Assert(CapitalizeLocale("élan VITAL", "fr")).Equal("Élan vital")
+			g.Assert(CapitalizeLocale("", "tr")).Equal("")
+		})
+	})
+}
@@ -0,0 +1,58 @@
+package inflector
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Go can't look up a type by its name at runtime, so constants have to be
+// registered before they can be resolved by Constantize.
+var constants = struct {
+	sync.RWMutex
+	m map[string]interface{}
+}{m: map[string]interface{}{}}
+
+// RegisterConstant registers a value (usually a factory function or a zero
+// value of a type) under a Ruby constant name so it can later be resolved
+// using Constantize.
+//
+//	RegisterConstant("Admin::User", func() interface{} { return &AdminUser{} })
+func RegisterConstant(name string, value interface{}) {
+	constants.Lock()
+	defer constants.Unlock()
+	constants.m[normalizeConstantName(name)] = value
+}
+
+// Constantize returns the value registered for the passed constant name or
+// an error if nothing was registered under that name.
+//
+//	RegisterConstant("Module", ModuleFactory)
+//	Constantize("Module")   // => ModuleFactory
+//	Constantize("::Module") // => ModuleFactory
+//	Constantize("Unknown")  // => error
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Inflector.html#method-i-constantize
+func Constantize(camelCasedWord string) (interface{}, error) {
+	name := normalizeConstantName(camelCasedWord)
+	constants.RLock()
+	defer constants.RUnlock()
+	v, ok := constants.m[name]
+	if !ok {
+		return nil, fmt.Errorf("uninitialized constant %s", name)
+	}
+	return v, nil
+}
+
+// SafeConstantize returns the value registered for the passed constant name
+// or nil if nothing was registered under that name.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Inflector.html#method-i-safe_constantize
+func SafeConstantize(camelCasedWord string) interface{} {
+	v, _ := Constantize(camelCasedWord)
+	return v
+}
+
+func normalizeConstantName(name string) string {
+	return strings.TrimPrefix(strings.TrimSpace(name), "::")
+}
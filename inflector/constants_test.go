@@ -0,0 +1,44 @@
+package inflector
+
+import (
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestConstantize(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("Constantize", func() {
+		type user struct{ name string }
+		factory := func() interface{} { return &user{name: "new"} }
+		RegisterConstant("Admin::User", factory)
+
+		g.It("Should resolve registered constants", func() {
+			v, err := Constantize("Admin::User")
+			g.Assert(err).Eql(nil)
+			g.Assert(v.(func() interface{})().(*user).name).Equal("new")
+		})
+
+		g.It("Should resolve top level references", func() {
+			_, err := Constantize("::Admin::User")
+			g.Assert(err).Eql(nil)
+		})
+
+		g.It("Should resolve camelized names", func() {
+			_, err := Constantize(Camelize("admin/user", true))
+			g.Assert(err).Eql(nil)
+		})
+
+		g.It("Should fail on unknown constants", func() {
+			_, err := Constantize("Admin::Unknown")
+			g.Assert(err.Error()).Equal("uninitialized constant Admin::Unknown")
+		})
+	})
+
+	g.Describe("SafeConstantize", func() {
+		g.It("Should return nil on unknown constants", func() {
+			g.Assert(SafeConstantize("Unknown") == nil).IsTrue()
+			g.Assert(SafeConstantize("Admin::User") != nil).IsTrue()
+		})
+	})
+}
@@ -0,0 +1,255 @@
+package inflector
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Inflections holds the rules used by the inflector functions.
+// The package functions use the rules returned by Default(), rules can be
+// added at init time the same way they are added in a Rails initializer:
+//
+//	inflector.Default().Acronym("HTML")
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Inflector/Inflections.html
+type Inflections struct {
+	mu sync.RWMutex
+	// acronyms maps the downcased version of an acronym to its expected
+	// form, acronymList keeps the registration order.
+	acronyms    map[string]string
+	acronymList []string
+	// rules are stored in the order they were added but are applied
+	// starting with the most recent one.
+	plurals      []rule
+	singulars    []rule
+	uncountables map[string]*regexp.Regexp
+}
+
+type rule struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+var defaultInflections = newEnglishInflections()
+
+// NewInflections returns an empty set of inflection rules.
+func NewInflections() *Inflections {
+	return &Inflections{
+		acronyms:     map[string]string{},
+		uncountables: map[string]*regexp.Regexp{},
+	}
+}
+
+// Default returns the inflection rules used by the package level functions.
+func Default() *Inflections {
+	return defaultInflections
+}
+
+// Acronym specifies a new acronym. An acronym must be specified as it will
+// appear in a camelized string. An underscore string that contains the
+// acronym will retain the acronym when passed to Camelize and a camelized
+// string that contains the acronym will maintain the acronym when passed to
+// Underscore.
+//
+//	Default().Acronym("HTML")
+//	Camelize("html_api", true) // => "HTMLApi"
+//	Underscore("MyHTML")       // => "my_html"
+func (in *Inflections) Acronym(word string) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	key := strings.ToLower(word)
+	if _, ok := in.acronyms[key]; !ok {
+		in.acronymList = append(in.acronymList, word)
+	} else {
+		for i, a := range in.acronymList {
+			if strings.ToLower(a) == key {
+				in.acronymList[i] = word
+			}
+		}
+	}
+	in.acronyms[key] = word
+}
+
+// acronymFor returns the registered acronym matching the passed word, if any.
+func (in *Inflections) acronymFor(word string) (string, bool) {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+	a, ok := in.acronyms[word]
+	return a, ok
+}
+
+// acronymAt returns the first registered acronym found at the start of str.
+func (in *Inflections) acronymAt(str string) (string, bool) {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+	for _, a := range in.acronymList {
+		if strings.HasPrefix(str, a) {
+			return a, true
+		}
+	}
+	return "", false
+}
+
+// Plural specifies a new pluralization rule and its replacement. The rule is
+// a regular expression and the replacement can refer to its submatches
+// using ${1}. The rule will be applied before the existing rules.
+func (in *Inflections) Plural(pattern, replacement string) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	delete(in.uncountables, strings.ToLower(replacement))
+	in.plurals = append(in.plurals, rule{regexp.MustCompile(pattern), replacement})
+}
+
+// Singular specifies a new singularization rule and its replacement. The
+// rule is a regular expression and the replacement can refer to its
+// submatches using ${1}. The rule will be applied before the existing rules.
+func (in *Inflections) Singular(pattern, replacement string) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	delete(in.uncountables, strings.ToLower(replacement))
+	in.singulars = append(in.singulars, rule{regexp.MustCompile(pattern), replacement})
+}
+
+// Irregular specifies a new irregular that applies to both pluralization
+// and singularization at the same time. This can only be used for strings,
+// not regular expressions.
+//
+//	Default().Irregular("octopus", "octopi")
+//	Default().Irregular("person", "people")
+func (in *Inflections) Irregular(singular, plural string) {
+	in.mu.Lock()
+	delete(in.uncountables, strings.ToLower(singular))
+	delete(in.uncountables, strings.ToLower(plural))
+	in.mu.Unlock()
+
+	s0, srest := singular[:1], regexp.QuoteMeta(singular[1:])
+	p0, prest := plural[:1], regexp.QuoteMeta(plural[1:])
+	if strings.ToUpper(s0) == strings.ToUpper(p0) {
+		in.Plural("(?i)("+regexp.QuoteMeta(s0)+")"+srest+"$", "${1}"+plural[1:])
+		in.Plural("(?i)("+regexp.QuoteMeta(p0)+")"+prest+"$", "${1}"+plural[1:])
+		in.Singular("(?i)("+regexp.QuoteMeta(s0)+")"+srest+"$", "${1}"+singular[1:])
+		in.Singular("(?i)("+regexp.QuoteMeta(p0)+")"+prest+"$", "${1}"+singular[1:])
+		return
+	}
+	upper := func(str string) string { return regexp.QuoteMeta(strings.ToUpper(str)) }
+	lower := func(str string) string { return regexp.QuoteMeta(strings.ToLower(str)) }
+	in.Plural(upper(s0)+"(?i)"+srest+"$", strings.ToUpper(p0)+plural[1:])
+	in.Plural(lower(s0)+"(?i)"+srest+"$", strings.ToLower(p0)+plural[1:])
+	in.Plural(upper(p0)+"(?i)"+prest+"$", strings.ToUpper(p0)+plural[1:])
+	in.Plural(lower(p0)+"(?i)"+prest+"$", strings.ToLower(p0)+plural[1:])
+	in.Singular(upper(s0)+"(?i)"+srest+"$", strings.ToUpper(s0)+singular[1:])
+	in.Singular(lower(s0)+"(?i)"+srest+"$", strings.ToLower(s0)+singular[1:])
+	in.Singular(upper(p0)+"(?i)"+prest+"$", strings.ToUpper(s0)+singular[1:])
+	in.Singular(lower(p0)+"(?i)"+prest+"$", strings.ToLower(s0)+singular[1:])
+}
+
+// Uncountable specifies words that are uncountable and should not be
+// inflected.
+//
+//	Default().Uncountable("money", "information")
+func (in *Inflections) Uncountable(words ...string) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	for _, word := range words {
+		word = strings.ToLower(word)
+		in.uncountables[word] = regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(word) + `\z`)
+	}
+}
+
+// isUncountable reports whether the passed word ends with an uncountable word.
+func (in *Inflections) isUncountable(word string) bool {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+	for _, re := range in.uncountables {
+		if re.MatchString(word) {
+			return true
+		}
+	}
+	return false
+}
+
+// apply runs the first matching rule, starting from the most recent one.
+func (in *Inflections) apply(word string, rules func(*Inflections) []rule) string {
+	if word == "" || in.isUncountable(word) {
+		return word
+	}
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+	list := rules(in)
+	for i := len(list) - 1; i >= 0; i-- {
+		r := list[i]
+		if loc := r.re.FindStringSubmatchIndex(word); loc != nil {
+			dst := r.re.ExpandString(nil, r.replacement, word, loc)
+			return word[:loc[0]] + string(dst) + word[loc[1]:]
+		}
+	}
+	return word
+}
+
+// newEnglishInflections returns the default English rules shipped with
+// ActiveSupport.
+func newEnglishInflections() *Inflections {
+	in := NewInflections()
+
+	in.Plural(`$`, "s")
+	in.Plural(`(?i)s$`, "s")
+	in.Plural(`(?i)^(ax|test)is$`, "${1}es")
+	in.Plural(`(?i)(octop|vir)us$`, "${1}i")
+	in.Plural(`(?i)(octop|vir)i$`, "${1}i")
+	in.Plural(`(?i)(alias|status)$`, "${1}es")
+	in.Plural(`(?i)(bu)s$`, "${1}ses")
+	in.Plural(`(?i)(buffal|tomat)o$`, "${1}oes")
+	in.Plural(`(?i)([ti])um$`, "${1}a")
+	in.Plural(`(?i)([ti])a$`, "${1}a")
+	in.Plural(`(?i)sis$`, "ses")
+	in.Plural(`(?i)(?:([^f])fe|([lr])f)$`, "${1}${2}ves")
+	in.Plural(`(?i)(hive)$`, "${1}s")
+	in.Plural(`(?i)([^aeiouy]|qu)y$`, "${1}ies")
+	in.Plural(`(?i)(x|ch|ss|sh)$`, "${1}es")
+	in.Plural(`(?i)(matr|vert|ind)(?:ix|ex)$`, "${1}ices")
+	in.Plural(`(?i)^(m|l)ouse$`, "${1}ice")
+	in.Plural(`(?i)^(m|l)ice$`, "${1}ice")
+	in.Plural(`(?i)^(ox)$`, "${1}en")
+	in.Plural(`(?i)^(oxen)$`, "${1}")
+	in.Plural(`(?i)(quiz)$`, "${1}zes")
+
+	in.Singular(`(?i)s$`, "")
+	in.Singular(`(?i)(ss)$`, "${1}")
+	in.Singular(`(?i)(n)ews$`, "${1}ews")
+	in.Singular(`(?i)([ti])a$`, "${1}um")
+	in.Singular(`(?i)((a)naly|(b)a|(d)iagno|(p)arenthe|(p)rogno|(s)ynop|(t)he)(sis|ses)$`, "${1}sis")
+	in.Singular(`(?i)(^analy)(sis|ses)$`, "${1}sis")
+	in.Singular(`(?i)([^f])ves$`, "${1}fe")
+	in.Singular(`(?i)(hive)s$`, "${1}")
+	in.Singular(`(?i)(tive)s$`, "${1}")
+	in.Singular(`(?i)([lr])ves$`, "${1}f")
+	in.Singular(`(?i)([^aeiouy]|qu)ies$`, "${1}y")
+	in.Singular(`(?i)(s)eries$`, "${1}eries")
+	in.Singular(`(?i)(m)ovies$`, "${1}ovie")
+	in.Singular(`(?i)(x|ch|ss|sh)es$`, "${1}")
+	in.Singular(`(?i)^(m|l)ice$`, "${1}ouse")
+	in.Singular(`(?i)(bus)(es)?$`, "${1}")
+	in.Singular(`(?i)(o)es$`, "${1}")
+	in.Singular(`(?i)(shoe)s$`, "${1}")
+	in.Singular(`(?i)(cris|test)(is|es)$`, "${1}is")
+	in.Singular(`(?i)^(a)x[ie]s$`, "${1}xis")
+	in.Singular(`(?i)(octop|vir)(us|i)$`, "${1}us")
+	in.Singular(`(?i)(alias|status)(es)?$`, "${1}")
+	in.Singular(`(?i)^(ox)en`, "${1}")
+	in.Singular(`(?i)(vert|ind)ices$`, "${1}ex")
+	in.Singular(`(?i)(matr)ices$`, "${1}ix")
+	in.Singular(`(?i)(quiz)zes$`, "${1}")
+	in.Singular(`(?i)(database)s$`, "${1}")
+
+	in.Irregular("person", "people")
+	in.Irregular("man", "men")
+	in.Irregular("child", "children")
+	in.Irregular("sex", "sexes")
+	in.Irregular("move", "moves")
+	in.Irregular("zombie", "zombies")
+
+	in.Uncountable("equipment", "information", "rice", "money", "species", "series", "fish", "sheep", "jeans", "police")
+
+	return in
+}
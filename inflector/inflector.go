@@ -5,7 +5,10 @@
 package inflector
 
 import (
+	"bytes"
+
 	"github.com/fiam/gounidecode/unidecode"
+	"github.com/mattetti/goRailsYourself/internal/recache"
 	"regexp"
 	"strings"
 )
@@ -18,14 +21,17 @@ var parameterizeReplacementRegexp = regexp.MustCompile("(?i)[^a-z0-9-_]+")
 // Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Inflector.html#method-i-parameterize
 func Parameterize(str, sep string) string {
 	// replace accented chars with their ascii equivalents
-	str = Transliterate(str)
+	return parameterize(Transliterate(str), sep)
+}
+
+func parameterize(str, sep string) string {
 	// Turn unwanted chars into the separator
 	strB := parameterizeReplacementRegexp.ReplaceAllLiteral([]byte(str), []byte(sep))
 	// No more than one of the separator in a row.
-	re := regexp.MustCompile(sep + `{2,}`)
+	re := recache.MustCompile(sep + `{2,}`)
 	strB = re.ReplaceAllLiteral(strB, []byte(sep))
 	// Remove leading/trailing separator
-	re = regexp.MustCompile(`(?i)^` + sep + `|` + sep + `$`)
+	re = recache.MustCompile(`(?i)^` + sep + `|` + sep + `$`)
 	strB = re.ReplaceAllLiteral(strB, []byte{})
 	str = string(strB)
 	// return a lower case version
@@ -38,3 +44,160 @@ func Parameterize(str, sep string) string {
 func Transliterate(str string) string {
 	return unidecode.Unidecode(str)
 }
+
+var (
+	underscoreAcronymBoundaryRegexp = regexp.MustCompile(`([A-Z\d]+)([A-Z][a-z])`)
+	underscoreWordBoundaryRegexp    = regexp.MustCompile(`([a-z\d])([A-Z])`)
+	camelizeFirstWordRegexp         = regexp.MustCompile(`^[a-z\d]*`)
+	camelizeSegmentRegexp           = regexp.MustCompile(`(?i)(_|/)([a-z\d]*)`)
+)
+
+// Converts strings to UpperCamelCase. If uppercaseFirstLetter is false,
+// converts strings to lowerCamelCase.
+// Camelize also converts '/' to '::' which is useful for converting paths to
+// namespaces. Acronyms registered via Default().Acronym() are respected.
+//
+//	Camelize("active_model", true)        // => "ActiveModel"
+//	Camelize("active_model", false)       // => "activeModel"
+//	Camelize("active_model/errors", true) // => "ActiveModel::Errors"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Inflector.html#method-i-camelize
+func Camelize(term string, uppercaseFirstLetter bool) string {
+	in := Default()
+	if uppercaseFirstLetter {
+		term = camelizeFirstWordRegexp.ReplaceAllStringFunc(term, func(word string) string {
+			if acronym, ok := in.acronymFor(word); ok {
+				return acronym
+			}
+			return capitalize(word)
+		})
+	} else {
+		term = downcaseFirstWord(in, term)
+	}
+	return camelizeSegmentRegexp.ReplaceAllStringFunc(term, func(match string) string {
+		sep, word := match[:1], match[1:]
+		if acronym, ok := in.acronymFor(word); ok {
+			word = acronym
+		} else {
+			word = capitalize(word)
+		}
+		if sep == "/" {
+			return "::" + word
+		}
+		return word
+	})
+}
+
+// Makes an underscored, lowercase form from the expression in the string.
+// Changes '::' to '/' to convert namespaces to paths. Acronyms registered via
+// Default().Acronym() are kept together.
+//
+//	Underscore("ActiveModel")         // => "active_model"
+//	Underscore("ActiveModel::Errors") // => "active_model/errors"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Inflector.html#method-i-underscore
+func Underscore(camelCasedWord string) string {
+	if !strings.ContainsAny(camelCasedWord, "ABCDEFGHIJKLMNOPQRSTUVWXYZ-") && !strings.Contains(camelCasedWord, "::") {
+		return camelCasedWord
+	}
+	word := strings.Replace(camelCasedWord, "::", "/", -1)
+	word = underscoreAcronyms(Default(), word)
+	word = underscoreAcronymBoundaryRegexp.ReplaceAllString(word, "${1}_${2}")
+	word = underscoreWordBoundaryRegexp.ReplaceAllString(word, "${1}_${2}")
+	word = strings.Replace(word, "-", "_", -1)
+	return strings.ToLower(word)
+}
+
+// Replaces underscores with dashes in the string.
+//
+//	Dasherize("puni_puni") // => "puni-puni"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Inflector.html#method-i-dasherize
+func Dasherize(underscoredWord string) string {
+	return strings.Replace(underscoredWord, "_", "-", -1)
+}
+
+// capitalize upper cases the first character and lower cases the rest,
+// the same way Ruby's String#capitalize does.
+func capitalize(str string) string {
+	for i, r := range str {
+		return strings.ToUpper(string(r)) + strings.ToLower(str[i+len(string(r)):])
+	}
+	return str
+}
+
+// downcaseFirstWord lower cases a leading acronym or, if none, the first
+// character of the string.
+func downcaseFirstWord(in *Inflections, str string) string {
+	if acronym, ok := in.acronymAt(str); ok {
+		rest := str[len(acronym):]
+		if rest == "" || !isWordChar(rest[0]) || isUpper(rest[0]) || rest[0] == '_' {
+			return strings.ToLower(acronym) + rest
+		}
+	}
+	if str != "" && isWordChar(str[0]) {
+		return strings.ToLower(str[:1]) + str[1:]
+	}
+	return str
+}
+
+// underscoreAcronyms lower cases the registered acronyms found in str and
+// prefixes them with an underscore when they follow a letter or a digit.
+func underscoreAcronyms(in *Inflections, str string) string {
+	var buf bytes.Buffer
+	for i := 0; i < len(str); {
+		acronym, ok := in.acronymAt(str[i:])
+		if ok {
+			end := i + len(acronym)
+			followed := end == len(str) || !isLower(str[end])
+			var prev byte
+			if i > 0 {
+				prev = str[i-1]
+			}
+			if followed && prev != '_' {
+				if isWordChar(prev) {
+					buf.WriteByte('_')
+				}
+				buf.WriteString(strings.ToLower(acronym))
+				i = end
+				continue
+			}
+		}
+		buf.WriteByte(str[i])
+		i++
+	}
+	return buf.String()
+}
+
+func isLower(c byte) bool { return c >= 'a' && c <= 'z' }
+func isUpper(c byte) bool { return c >= 'A' && c <= 'Z' }
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+func isWordChar(c byte) bool {
+	return isLower(c) || isUpper(c) || isDigit(c) || c == '_'
+}
+
+// Returns the plural form of the word in the string. If a count is passed
+// and equals 1, the word is returned unchanged, mirroring String#pluralize.
+//
+//	Pluralize("post")     // => "posts"
+//	Pluralize("octopus")  // => "octopi"
+//	Pluralize("person", 1) // => "person"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Inflector.html#method-i-pluralize
+func Pluralize(word string, count ...int) string {
+	if len(count) > 0 && count[0] == 1 {
+		return word
+	}
+	return Default().apply(word, func(in *Inflections) []rule { return in.plurals })
+}
+
+// The reverse of Pluralize, returns the singular form of a word in a string.
+//
+//	Singularize("posts")   // => "post"
+//	Singularize("octopi")  // => "octopus"
+//	Singularize("sheep")   // => "sheep"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Inflector.html#method-i-singularize
+func Singularize(word string) string {
+	return Default().apply(word, func(in *Inflections) []rule { return in.singulars })
+}
@@ -64,3 +64,203 @@ func ExampleTransliterate() {
 	// Output: AEroskobing
 	// Ma soeur va a l'ecole
 }
+
+func ExampleCamelize() {
+	fmt.Println(Camelize("active_model", true))
+	fmt.Println(Camelize("active_model", false))
+	fmt.Println(Camelize("active_model/errors", true))
+	// Output: ActiveModel
+	// activeModel
+	// ActiveModel::Errors
+}
+
+func TestCamelize(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("Camelize", func() {
+
+		g.It("Should convert underscored words to UpperCamelCase", func() {
+			expectations := map[string]string{
+				"product":                "Product",
+				"special_guest":          "SpecialGuest",
+				"application_controller": "ApplicationController",
+				"area51_controller":      "Area51Controller",
+				"admin/user":             "Admin::User",
+			}
+			for input, output := range expectations {
+				g.Assert(Camelize(input, true)).Equal(output)
+			}
+		})
+
+		g.It("Should convert underscored words to lowerCamelCase", func() {
+			g.Assert(Camelize("capital_city", false)).Equal("capitalCity")
+			g.Assert(Camelize("Capital_city", false)).Equal("capitalCity")
+		})
+
+		g.It("Should respect acronyms", func() {
+			in := Default()
+			in.Acronym("API")
+			in.Acronym("HTML")
+			g.Assert(Camelize("html_api", true)).Equal("HTMLAPI")
+			g.Assert(Camelize("api_user", true)).Equal("APIUser")
+			g.Assert(Camelize("html", false)).Equal("html")
+			g.Assert(Camelize("HTML_parser", false)).Equal("htmlParser")
+		})
+	})
+}
+
+func TestUnderscore(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("Underscore", func() {
+
+		g.It("Should convert camel cased words", func() {
+			expectations := map[string]string{
+				"Product":               "product",
+				"SpecialGuest":          "special_guest",
+				"ApplicationController": "application_controller",
+				"Area51Controller":      "area51_controller",
+				"Admin::User":           "admin/user",
+				"HTMLTidy":              "html_tidy",
+				"already_done":          "already_done",
+				"with-dashes":           "with_dashes",
+			}
+			for input, output := range expectations {
+				g.Assert(Underscore(input)).Equal(output)
+			}
+		})
+
+		g.It("Should keep acronyms together", func() {
+			Default().Acronym("RESTful")
+			g.Assert(Underscore("RESTfulController")).Equal("restful_controller")
+			g.Assert(Underscore("MyRESTfulController")).Equal("my_restful_controller")
+		})
+	})
+}
+
+func TestDasherize(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("Dasherize", func() {
+		g.It("Should replace underscores with dashes", func() {
+			g.Assert(Dasherize("street_address")).Equal("street-address")
+			g.Assert(Dasherize(Underscore("AdminUser"))).Equal("admin-user")
+		})
+	})
+}
+
+func ExamplePluralize() {
+	fmt.Println(Pluralize("post"))
+	fmt.Println(Pluralize("octopus"))
+	fmt.Println(Pluralize("person", 1))
+	fmt.Println(Pluralize("person", 2))
+	// Output: posts
+	// octopi
+	// person
+	// people
+}
+
+func TestPluralize(t *testing.T) {
+	g := Goblin(t)
+	// singular => plural
+	expectations := map[string]string{
+		"search":      "searches",
+		"switch":      "switches",
+		"fix":         "fixes",
+		"box":         "boxes",
+		"process":     "processes",
+		"address":     "addresses",
+		"case":        "cases",
+		"stack":       "stacks",
+		"wish":        "wishes",
+		"fish":        "fish",
+		"jeans":       "jeans",
+		"category":    "categories",
+		"query":       "queries",
+		"ability":     "abilities",
+		"agency":      "agencies",
+		"movie":       "movies",
+		"archive":     "archives",
+		"index":       "indices",
+		"wife":        "wives",
+		"safe":        "saves",
+		"half":        "halves",
+		"move":        "moves",
+		"salesperson": "salespeople",
+		"person":      "people",
+		"spokesman":   "spokesmen",
+		"man":         "men",
+		"woman":       "women",
+		"basis":       "bases",
+		"diagnosis":   "diagnoses",
+		"datum":       "data",
+		"medium":      "media",
+		"analysis":    "analyses",
+		"node_child":  "node_children",
+		"child":       "children",
+		"experience":  "experiences",
+		"day":         "days",
+		"comment":     "comments",
+		"status":      "statuses",
+		"virus":       "viri",
+		"alias":       "aliases",
+		"bus":         "buses",
+		"matrix":      "matrices",
+		"vertex":      "vertices",
+		"ox":          "oxen",
+		"mouse":       "mice",
+		"louse":       "lice",
+		"house":       "houses",
+		"quiz":        "quizzes",
+		"tomato":      "tomatoes",
+		"shoe":        "shoes",
+		"horse":       "horses",
+		"prize":       "prizes",
+		"edge":        "edges",
+		"database":    "databases",
+		"Person":      "People",
+	}
+
+	g.Describe("Pluralize", func() {
+		g.It("Should pluralize singular words", func() {
+			for singular, plural := range expectations {
+				g.Assert(Pluralize(singular)).Equal(plural)
+			}
+		})
+
+		g.It("Should keep plurals plural", func() {
+			for _, plural := range expectations {
+				g.Assert(Pluralize(plural)).Equal(plural)
+			}
+		})
+
+		g.It("Should return the word when the count is 1", func() {
+			g.Assert(Pluralize("post", 1)).Equal("post")
+			g.Assert(Pluralize("post", 0)).Equal("posts")
+			g.Assert(Pluralize("post", 2)).Equal("posts")
+		})
+
+		g.It("Should not pluralize uncountable words", func() {
+			g.Assert(Pluralize("sheep")).Equal("sheep")
+			g.Assert(Pluralize("information")).Equal("information")
+			g.Assert(Pluralize("")).Equal("")
+		})
+	})
+
+	g.Describe("Singularize", func() {
+		g.It("Should singularize plural words", func() {
+			for singular, plural := range expectations {
+				g.Assert(Singularize(plural)).Equal(singular)
+			}
+		})
+
+		g.It("Should keep singulars singular", func() {
+			for singular := range expectations {
+				g.Assert(Singularize(singular)).Equal(singular)
+			}
+		})
+	})
+}
+
+func BenchmarkParameterize(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Parameterize("Random text with *(bad)* characters", "-")
+	}
+}
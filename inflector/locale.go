@@ -0,0 +1,51 @@
+package inflector
+
+import (
+	"strings"
+
+	"github.com/fiam/gounidecode/unidecode"
+	"github.com/mattetti/goRailsYourself/i18n"
+)
+
+// TransliterateLocale is like Transliterate but first applies the
+// i18n.transliterate.rule translations of the locale, loaded with the i18n
+// package, the same way Rails does when a locale is passed:
+//
+//	de:
+//	  i18n:
+//	    transliterate:
+//	      rule:
+//	        ü: "ue"
+//	        ö: "oe"
+//
+//	TransliterateLocale("Jürgen Müller", "de") // => "Juergen Mueller"
+//	Transliterate("Jürgen Müller")             // => "Jurgen Muller"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Inflector.html#method-i-transliterate
+func TransliterateLocale(str, locale string) string {
+	rule, ok := i18n.Lookup(locale, "i18n.transliterate.rule")
+	if !ok {
+		return Transliterate(str)
+	}
+	replacements, ok := rule.(map[string]interface{})
+	if !ok {
+		return Transliterate(str)
+	}
+	var b strings.Builder
+	for _, r := range str {
+		if replacement, ok := replacements[string(r)].(string); ok {
+			b.WriteString(replacement)
+			continue
+		}
+		b.WriteString(unidecode.Unidecode(string(r)))
+	}
+	return b.String()
+}
+
+// ParameterizeLocale is like Parameterize but transliterates the string
+// using TransliterateLocale.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Inflector.html#method-i-parameterize
+func ParameterizeLocale(str, sep, locale string) string {
+	return parameterize(TransliterateLocale(str, locale), sep)
+}
@@ -0,0 +1,30 @@
+package inflector
+
+import (
+	"testing"
+
+	. "github.com/franela/goblin"
+	"github.com/mattetti/goRailsYourself/i18n"
+)
+
+func TestLocaleTransliteration(t *testing.T) {
+	g := Goblin(t)
+	i18n.Store("de", map[string]interface{}{
+		"i18n": map[string]interface{}{"transliterate": map[string]interface{}{"rule": map[string]interface{}{
+			"ü": "ue", "ö": "oe", "Ü": "Ue",
+		}}},
+	})
+
+	g.Describe("Locale transliteration", func() {
+		g.It("Should use the rules of the locale", func() {
+			g.Assert(TransliterateLocale("Jürgen Müller", "de")).Equal("Juergen Mueller")
+			g.Assert(TransliterateLocale("Über Köln Ærø", "de")).Equal("Ueber Koeln AEro")
+			g.Assert(ParameterizeLocale("Jürgen Müller", "-", "de")).Equal("juergen-mueller")
+		})
+
+		g.It("Should fall back to the default transliteration", func() {
+			g.Assert(TransliterateLocale("Jürgen Müller", "xx")).Equal("Jurgen Muller")
+			g.Assert(ParameterizeLocale("Jürgen Müller", "_", "xx")).Equal("jurgen_muller")
+		})
+	})
+}
@@ -0,0 +1,51 @@
+package inquirer
+
+// ArrayInquirer wraps a list of names and offers predicates checking its
+// content, like request.variant.phone? in Rails.
+//
+//	variants := ArrayInquiry("phone", "tablet")
+//	variants.Is("phone")             // => true
+//	variants.Any("phone", "desktop") // => true
+//	variants.Any("desktop", "watch") // => false
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/ArrayInquirer.html
+type ArrayInquirer []string
+
+// ArrayInquiry wraps the passed names in an ArrayInquirer.
+func ArrayInquiry(names ...string) ArrayInquirer {
+	return ArrayInquirer(names)
+}
+
+// Is reports whether the list contains name.
+func (a ArrayInquirer) Is(name string) bool {
+	for _, n := range a {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Any reports whether the list contains any of the passed names. Without
+// names, it reports whether the list isn't empty.
+func (a ArrayInquirer) Any(names ...string) bool {
+	if len(names) == 0 {
+		return len(a) > 0
+	}
+	for _, name := range names {
+		if a.Is(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ArrayPredicate is the ArrayInquirer version of Predicate:
+//
+//	var Phone = inquirer.ArrayPredicate("phone")
+//	Phone(variants) // => true if variants contains "phone"
+func ArrayPredicate(name string) func(ArrayInquirer) bool {
+	return func(a ArrayInquirer) bool {
+		return a.Is(name)
+	}
+}
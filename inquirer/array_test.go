@@ -0,0 +1,43 @@
+package inquirer
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleArrayInquiry() {
+	variants := ArrayInquiry("phone", "tablet")
+	fmt.Println(variants.Is("phone"))
+	fmt.Println(variants.Any("desktop", "tablet"))
+	fmt.Println(variants.Any("desktop", "watch"))
+	// Output: true
+	// true
+	// false
+}
+
+func TestArrayInquirer(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("ArrayInquirer", func() {
+		variants := ArrayInquiry("phone", "tablet")
+
+		g.It("Should check the content of the list", func() {
+			g.Assert(variants.Is("phone")).IsTrue()
+			g.Assert(variants.Is("desktop")).IsFalse()
+		})
+
+		g.It("Should check for any of the names", func() {
+			g.Assert(variants.Any("desktop", "phone")).IsTrue()
+			g.Assert(variants.Any("desktop", "watch")).IsFalse()
+			g.Assert(variants.Any()).IsTrue()
+			g.Assert(ArrayInquiry().Any()).IsFalse()
+		})
+
+		g.It("Should support custom predicates", func() {
+			phone := ArrayPredicate("phone")
+			g.Assert(phone(variants)).IsTrue()
+			g.Assert(phone(ArrayInquiry("desktop"))).IsFalse()
+		})
+	})
+}
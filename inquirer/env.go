@@ -0,0 +1,23 @@
+package inquirer
+
+// EnvInquirer is the StringInquirer of a Rails environment. It adds the
+// Local predicate to the ones of StringInquirer.
+//
+//	env := EnvInquiry("test")
+//	env.Test()  // => true
+//	env.Local() // => true
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/EnvironmentInquirer.html
+type EnvInquirer struct {
+	StringInquirer
+}
+
+// EnvInquiry wraps the name of an environment in an EnvInquirer.
+func EnvInquiry(env string) EnvInquirer {
+	return EnvInquirer{Inquiry(env)}
+}
+
+// Local reports whether the environment is "development" or "test".
+func (e EnvInquirer) Local() bool {
+	return e.Development() || e.Test()
+}
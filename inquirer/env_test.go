@@ -0,0 +1,31 @@
+package inquirer
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleEnvInquiry() {
+	env := EnvInquiry("test")
+	fmt.Println(env.Test(), env.Local(), env.Production())
+	// Output: true true false
+}
+
+func TestEnvInquirer(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("EnvInquirer", func() {
+		g.It("Should check the environment", func() {
+			g.Assert(EnvInquiry("production").Production()).IsTrue()
+			g.Assert(EnvInquiry("staging").Is("staging")).IsTrue()
+			g.Assert(EnvInquiry("staging").String()).Equal("staging")
+		})
+
+		g.It("Should know the local environments", func() {
+			g.Assert(EnvInquiry("development").Local()).IsTrue()
+			g.Assert(EnvInquiry("test").Local()).IsTrue()
+			g.Assert(EnvInquiry("production").Local()).IsFalse()
+		})
+	})
+}
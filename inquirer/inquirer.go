@@ -0,0 +1,55 @@
+// The inquirer package ports ActiveSupport's StringInquirer, a string
+// wrapper offering a nicer way to test for equality, and ArrayInquirer, its
+// list counterpart. It's mostly useful to share the Rails.env.production?
+// ergonomics with Go configuration code.
+//
+// Rails documentation http://api.rubyonrails.org/classes/ActiveSupport/StringInquirer.html
+package inquirer
+
+// StringInquirer wraps a string and offers predicates checking its value.
+//
+//	env := Inquiry("production")
+//	env.Production()  // => true
+//	env.Is("staging") // => false
+type StringInquirer string
+
+// Inquiry wraps the passed string in a StringInquirer.
+func Inquiry(str string) StringInquirer {
+	return StringInquirer(str)
+}
+
+// Is reports whether the wrapped value equals name.
+func (s StringInquirer) Is(name string) bool {
+	return string(s) == name
+}
+
+// Production reports whether the wrapped value is "production".
+func (s StringInquirer) Production() bool {
+	return s.Is("production")
+}
+
+// Development reports whether the wrapped value is "development".
+func (s StringInquirer) Development() bool {
+	return s.Is("development")
+}
+
+// Test reports whether the wrapped value is "test".
+func (s StringInquirer) Test() bool {
+	return s.Is("test")
+}
+
+func (s StringInquirer) String() string {
+	return string(s)
+}
+
+// Predicate registers a predicate for name and returns it. Since Go can't
+// generate methods at runtime, custom predicates are declared once and then
+// used like the built-in ones:
+//
+//	var Staging = inquirer.Predicate("staging")
+//	Staging(env) // => true if env is "staging"
+func Predicate(name string) func(StringInquirer) bool {
+	return func(s StringInquirer) bool {
+		return s.Is(name)
+	}
+}
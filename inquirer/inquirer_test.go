@@ -0,0 +1,50 @@
+package inquirer
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleInquiry() {
+	env := Inquiry("production")
+	fmt.Println(env.Production())
+	fmt.Println(env.Development())
+	fmt.Println(env.Is("production"))
+	// Output: true
+	// false
+	// true
+}
+
+func ExamplePredicate() {
+	staging := Predicate("staging")
+	fmt.Println(staging(Inquiry("staging")))
+	fmt.Println(staging(Inquiry("production")))
+	// Output: true
+	// false
+}
+
+func TestStringInquirer(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("StringInquirer", func() {
+		env := Inquiry("development")
+
+		g.It("Should compare its value", func() {
+			g.Assert(env.Is("development")).IsTrue()
+			g.Assert(env.Is("Development")).IsFalse()
+			g.Assert(env.Is("")).IsFalse()
+		})
+
+		g.It("Should answer the built-in predicates", func() {
+			g.Assert(env.Development()).IsTrue()
+			g.Assert(env.Production()).IsFalse()
+			g.Assert(env.Test()).IsFalse()
+			g.Assert(Inquiry("test").Test()).IsTrue()
+		})
+
+		g.It("Should convert back to a string", func() {
+			g.Assert(env.String()).Equal("development")
+		})
+	})
+}
@@ -0,0 +1,81 @@
+// Package recache provides a bounded cache of compiled regular expressions
+// shared by the helpers which take patterns as strings, so calling them in
+// a loop doesn't recompile the same pattern over and over.
+package recache
+
+import (
+	"regexp"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultSize is the maximum number of patterns kept by the shared cache.
+const DefaultSize = 512
+
+// Cache stores compiled regular expressions keyed by their pattern. When
+// the cache is full it is flushed, keeping the implementation lock free for
+// the common case of a small set of hot patterns.
+type Cache struct {
+	max  int64
+	size int64
+	m    sync.Map
+}
+
+// New returns a cache holding at most max patterns.
+func New(max int) *Cache {
+	return &Cache{max: int64(max)}
+}
+
+var shared = New(DefaultSize)
+
+// Compile returns the compiled version of the pattern using the shared
+// cache.
+func Compile(pattern string) (*regexp.Regexp, error) {
+	return shared.Compile(pattern)
+}
+
+// MustCompile is like Compile but panics if the pattern can't be compiled.
+func MustCompile(pattern string) *regexp.Regexp {
+	return shared.MustCompile(pattern)
+}
+
+// Compile returns the compiled version of the pattern, compiling and
+// caching it if needed.
+func (c *Cache) Compile(pattern string) (*regexp.Regexp, error) {
+	if re, ok := c.m.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if atomic.AddInt64(&c.size, 1) > c.max {
+		c.Purge()
+		atomic.AddInt64(&c.size, 1)
+	}
+	c.m.Store(pattern, re)
+	return re, nil
+}
+
+// MustCompile is like Compile but panics if the pattern can't be compiled.
+func (c *Cache) MustCompile(pattern string) *regexp.Regexp {
+	re, err := c.Compile(pattern)
+	if err != nil {
+		panic(`recache: Compile(` + pattern + `): ` + err.Error())
+	}
+	return re
+}
+
+// Len returns the approximate number of cached patterns.
+func (c *Cache) Len() int {
+	return int(atomic.LoadInt64(&c.size))
+}
+
+// Purge empties the cache.
+func (c *Cache) Purge() {
+	c.m.Range(func(k, _ interface{}) bool {
+		c.m.Delete(k)
+		return true
+	})
+	atomic.StoreInt64(&c.size, 0)
+}
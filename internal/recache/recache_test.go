@@ -0,0 +1,37 @@
+package recache
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	c := New(2)
+	a := c.MustCompile(`a+`)
+	if a != c.MustCompile(`a+`) {
+		t.Error("expected the compiled pattern to be cached")
+	}
+	c.MustCompile(`b+`)
+	if c.Len() != 2 {
+		t.Errorf("expected 2 cached patterns, got %d", c.Len())
+	}
+	c.MustCompile(`c+`)
+	if c.Len() > 2 {
+		t.Errorf("expected the cache to be bounded, got %d patterns", c.Len())
+	}
+	if _, err := c.Compile(`(`); err == nil {
+		t.Error("expected an invalid pattern to return an error")
+	}
+}
+
+func BenchmarkCompile(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		regexp.MustCompile(`(?i)^-|-$`)
+	}
+}
+
+func BenchmarkCachedCompile(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		MustCompile(`(?i)^-|-$`)
+	}
+}
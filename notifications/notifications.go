@@ -0,0 +1,189 @@
+// The notifications package ports the ActiveSupport::Notifications
+// instrumentation API: code instruments named events and subscribers get
+// notified with the event payload and timing, giving a single subscription
+// point for logging, metrics and error reporting.
+//
+// The other packages of this repository instrument their operations using
+// the default notifier, their events are named after the operation and the
+// type performing it ("decrypt_and_verify.message_encryptor").
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Notifications.html
+package notifications
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Event is passed to the subscribers once an instrumented block completed.
+type Event struct {
+	Name    string
+	Payload map[string]interface{}
+	Start   time.Time
+	End     time.Time
+	// Err is the error returned by the instrumented block, if any.
+	Err error
+}
+
+// Duration returns the time spent in the instrumented block.
+func (e Event) Duration() time.Duration {
+	return e.End.Sub(e.Start)
+}
+
+// Subscriber is returned when subscribing and used to unsubscribe.
+type Subscriber struct {
+	name    string
+	re      *regexp.Regexp
+	handler func(Event)
+}
+
+// Matches reports whether the subscriber listens to the named event.
+func (s *Subscriber) Matches(name string) bool {
+	if s.re != nil {
+		return s.re.MatchString(name)
+	}
+	return s.name == "" || s.name == name
+}
+
+// Notifier dispatches the instrumented events to its subscribers. Most
+// code uses the package level functions relying on the default notifier.
+type Notifier struct {
+	mu          sync.RWMutex
+	subscribers []*Subscriber
+}
+
+var defaultNotifier = NewNotifier()
+
+// NewNotifier returns a notifier without subscribers.
+func NewNotifier() *Notifier {
+	return &Notifier{}
+}
+
+// Default returns the notifier used by the package level functions.
+func Default() *Notifier {
+	return defaultNotifier
+}
+
+// Subscribe registers a handler called for each event with the passed
+// name, or for all events if the name is empty.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Notifications.html#method-c-subscribe
+func (n *Notifier) Subscribe(name string, handler func(Event)) *Subscriber {
+	return n.add(&Subscriber{name: name, handler: handler})
+}
+
+// SubscribeRegexp registers a handler called for each event whose name
+// matches the regular expression.
+func (n *Notifier) SubscribeRegexp(re *regexp.Regexp, handler func(Event)) *Subscriber {
+	return n.add(&Subscriber{re: re, handler: handler})
+}
+
+func (n *Notifier) add(s *Subscriber) *Subscriber {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.subscribers = append(n.subscribers, s)
+	return s
+}
+
+// Unsubscribe removes the subscriber.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Notifications.html#method-c-unsubscribe
+func (n *Notifier) Unsubscribe(s *Subscriber) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for i, sub := range n.subscribers {
+		if sub == s {
+			n.subscribers = append(n.subscribers[:i:i], n.subscribers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Listening reports whether any subscriber listens to the named event, so
+// callers can skip building expensive payloads.
+func (n *Notifier) Listening(name string) bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	for _, s := range n.subscribers {
+		if s.Matches(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Instrument runs fn, passing it the payload so it can be completed, and
+// then notifies the subscribers of the event. The error returned by fn is
+// returned and set on the event. If fn panics, the subscribers are notified
+// before the panic is propagated. fn can be nil to publish an event without
+// instrumenting a block.
+//
+//	err := notifications.Instrument("render", map[string]interface{}{"extra": "information"}, func(payload map[string]interface{}) error {
+//		return render()
+//	})
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveSupport/Notifications.html#method-c-instrument
+func (n *Notifier) Instrument(name string, payload map[string]interface{}, fn func(payload map[string]interface{}) error) (err error) {
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+	event := Event{Name: name, Payload: payload, Start: time.Now()}
+	defer func() {
+		r := recover()
+		if r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+		event.End = time.Now()
+		event.Err = err
+		n.publish(event)
+		if r != nil {
+			panic(r)
+		}
+	}()
+	if fn != nil {
+		err = fn(payload)
+	}
+	return err
+}
+
+func (n *Notifier) publish(event Event) {
+	n.mu.RLock()
+	subscribers := make([]*Subscriber, 0, len(n.subscribers))
+	for _, s := range n.subscribers {
+		if s.Matches(event.Name) {
+			subscribers = append(subscribers, s)
+		}
+	}
+	n.mu.RUnlock()
+	for _, s := range subscribers {
+		s.handler(event)
+	}
+}
+
+// Subscribe registers a handler on the default notifier.
+func Subscribe(name string, handler func(Event)) *Subscriber {
+	return defaultNotifier.Subscribe(name, handler)
+}
+
+// SubscribeRegexp registers a handler on the default notifier.
+func SubscribeRegexp(re *regexp.Regexp, handler func(Event)) *Subscriber {
+	return defaultNotifier.SubscribeRegexp(re, handler)
+}
+
+// Unsubscribe removes a subscriber from the default notifier.
+func Unsubscribe(s *Subscriber) {
+	defaultNotifier.Unsubscribe(s)
+}
+
+// Listening reports whether the default notifier has subscribers for the
+// named event.
+func Listening(name string) bool {
+	return defaultNotifier.Listening(name)
+}
+
+// Instrument instruments fn using the default notifier.
+func Instrument(name string, payload map[string]interface{}, fn func(payload map[string]interface{}) error) error {
+	return defaultNotifier.Instrument(name, payload, fn)
+}
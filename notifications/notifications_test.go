@@ -0,0 +1,82 @@
+package notifications
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleSubscribe() {
+	sub := Subscribe("render", func(e Event) {
+		fmt.Println(e.Name, e.Payload["template"], e.Err)
+	})
+	defer Unsubscribe(sub)
+
+	Instrument("render", map[string]interface{}{"template": "index"}, func(payload map[string]interface{}) error {
+		return nil
+	})
+	// Output: render index <nil>
+}
+
+func TestNotifications(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Notifier", func() {
+		g.It("Should notify the matching subscribers", func() {
+			n := NewNotifier()
+			var names, all, matched []string
+			n.Subscribe("sql.active_record", func(e Event) { names = append(names, e.Name) })
+			n.Subscribe("", func(e Event) { all = append(all, e.Name) })
+			n.SubscribeRegexp(regexp.MustCompile(`\.action_controller$`), func(e Event) { matched = append(matched, e.Name) })
+
+			n.Instrument("sql.active_record", nil, nil)
+			n.Instrument("process_action.action_controller", nil, nil)
+			g.Assert(names).Eql([]string{"sql.active_record"})
+			g.Assert(all).Eql([]string{"sql.active_record", "process_action.action_controller"})
+			g.Assert(matched).Eql([]string{"process_action.action_controller"})
+		})
+
+		g.It("Should pass the payload, timing and error", func() {
+			n := NewNotifier()
+			var event Event
+			n.Subscribe("job", func(e Event) { event = e })
+			failure := errors.New("failed")
+			err := n.Instrument("job", map[string]interface{}{"id": 1}, func(payload map[string]interface{}) error {
+				payload["attempts"] = 2
+				return failure
+			})
+			g.Assert(err).Equal(failure)
+			g.Assert(event.Err).Equal(failure)
+			g.Assert(event.Payload).Eql(map[string]interface{}{"id": 1, "attempts": 2})
+			g.Assert(event.End.Before(event.Start)).IsFalse()
+			g.Assert(event.Duration() >= 0).IsTrue()
+		})
+
+		g.It("Should notify before propagating panics", func() {
+			n := NewNotifier()
+			var event Event
+			n.Subscribe("job", func(e Event) { event = e })
+			defer func() {
+				g.Assert(recover()).Equal("boom")
+				g.Assert(event.Err.Error()).Equal("panic: boom")
+			}()
+			n.Instrument("job", nil, func(map[string]interface{}) error { panic("boom") })
+		})
+
+		g.It("Should unsubscribe", func() {
+			n := NewNotifier()
+			calls := 0
+			sub := n.Subscribe("job", func(Event) { calls++ })
+			g.Assert(n.Listening("job")).IsTrue()
+			g.Assert(n.Listening("other")).IsFalse()
+			n.Instrument("job", nil, nil)
+			n.Unsubscribe(sub)
+			n.Instrument("job", nil, nil)
+			g.Assert(calls).Equal(1)
+			g.Assert(n.Listening("job")).IsFalse()
+		})
+	})
+}
@@ -0,0 +1,69 @@
+package number
+
+import "strings"
+
+// CurrencyOptions customizes NumberToCurrency. Empty options use the
+// defaults of the Locale (English if not set or unknown).
+type CurrencyOptions struct {
+	// Precision is the number of decimals, 2 in English.
+	Precision *int
+	// Unit is the currency denomination, "$" in English.
+	Unit string
+	// Separator separates the integer and the decimals, "." in English.
+	Separator string
+	// Delimiter separates the groups of thousands, "," in English.
+	Delimiter string
+	// Format places the unit (%u) and the number (%n), "%u%n" in English.
+	Format string
+	// NegativeFormat is the format used for negative numbers, "-" followed
+	// by the format by default.
+	NegativeFormat string
+	// Locale selects the default options.
+	Locale string
+}
+
+// NumberToCurrency formats the number as a currency.
+//
+//	NumberToCurrency(1234567890.50, CurrencyOptions{})                 // => "$1,234,567,890.50"
+//	NumberToCurrency(-1234567890.50, CurrencyOptions{})                // => "-$1,234,567,890.50"
+//	NumberToCurrency(1234567890.506, CurrencyOptions{Locale: "fr"})    // => "1 234 567 890,51 €"
+//	NumberToCurrency(1234.5, CurrencyOptions{Precision: Precision(0)}) // => "$1,235"
+//
+// NaN and infinite numbers are returned as is, "NaN", "Inf" or "-Inf".
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActionView/Helpers/NumberHelper.html#method-i-number_to_currency
+func NumberToCurrency(value float64, opts CurrencyOptions) string {
+	if s, ok := nonFinite(value); ok {
+		return s
+	}
+	defaults := lookupLocale(opts.Locale).Currency
+	precision := defaults.Precision
+	if opts.Precision != nil {
+		precision = *opts.Precision
+	}
+	if opts.Unit == "" {
+		opts.Unit = defaults.Unit
+	}
+	if opts.Separator == "" {
+		opts.Separator = defaults.Separator
+	}
+	if opts.Delimiter == "" {
+		opts.Delimiter = defaults.Delimiter
+	}
+	if opts.Format == "" {
+		opts.Format = defaults.Format
+	}
+	if opts.NegativeFormat == "" {
+		opts.NegativeFormat = "-" + opts.Format
+	}
+
+	format := opts.Format
+	// Like Rails, a negative number rounded to zero keeps its sign unless
+	// there are no decimals.
+	if value < 0 && (precision != 0 || -value > 0.5) {
+		format = opts.NegativeFormat
+	}
+	intPart, fracPart := round(value, precision)
+	n := join(intPart, fracPart, opts.Delimiter, opts.Separator)
+	return strings.NewReplacer("%n", n, "%u", opts.Unit).Replace(format)
+}
@@ -0,0 +1,61 @@
+package number
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleNumberToCurrency() {
+	fmt.Println(NumberToCurrency(1234567890.50, CurrencyOptions{}))
+	fmt.Println(NumberToCurrency(1234567890.506, CurrencyOptions{Locale: "fr"}))
+	fmt.Println(NumberToCurrency(-1234567890.50, CurrencyOptions{Unit: "R$", Separator: ",", Delimiter: "", NegativeFormat: "(%u%n)"}))
+	// Output: $1,234,567,890.50
+	// 1 234 567 890,51 €
+	// (R$1,234,567,890,50)
+}
+
+func TestNumberToCurrency(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("NumberToCurrency", func() {
+		g.It("Should use the English defaults", func() {
+			g.Assert(NumberToCurrency(1234567890.50, CurrencyOptions{})).Equal("$1,234,567,890.50")
+			g.Assert(NumberToCurrency(1234567890.506, CurrencyOptions{})).Equal("$1,234,567,890.51")
+			g.Assert(NumberToCurrency(-1234567890.50, CurrencyOptions{})).Equal("-$1,234,567,890.50")
+			g.Assert(NumberToCurrency(0, CurrencyOptions{})).Equal("$0.00")
+		})
+
+		g.It("Should support custom options", func() {
+			g.Assert(NumberToCurrency(1234567890.50, CurrencyOptions{Precision: Precision(0)})).Equal("$1,234,567,891")
+			g.Assert(NumberToCurrency(1234567890.50, CurrencyOptions{Unit: "&pound;", Separator: ",", Delimiter: "."})).Equal("&pound;1.234.567.890,50")
+			g.Assert(NumberToCurrency(1234567890.50, CurrencyOptions{Unit: "&pound;", Format: "%n %u"})).Equal("1,234,567,890.50 &pound;")
+			g.Assert(NumberToCurrency(-1234567890.50, CurrencyOptions{NegativeFormat: "(%u%n)"})).Equal("($1,234,567,890.50)")
+			g.Assert(NumberToCurrency(-1234567890.50, CurrencyOptions{Format: "%n %u"})).Equal("-1,234,567,890.50 $")
+		})
+
+		g.It("Should handle negative numbers rounded to zero like Rails", func() {
+			g.Assert(NumberToCurrency(-0.001, CurrencyOptions{})).Equal("-$0.00")
+			g.Assert(NumberToCurrency(-0.4, CurrencyOptions{Precision: Precision(0)})).Equal("$0")
+			g.Assert(NumberToCurrency(-0.6, CurrencyOptions{Precision: Precision(0)})).Equal("-$1")
+		})
+
+		g.It("Should return the non-finite numbers as is", func() {
+			g.Assert(NumberToCurrency(math.Inf(1), CurrencyOptions{})).Equal("Inf")
+			g.Assert(NumberToCurrency(math.Inf(-1), CurrencyOptions{})).Equal("-Inf")
+			g.Assert(NumberToCurrency(math.NaN(), CurrencyOptions{})).Equal("NaN")
+			g.Assert(NumberToCurrency(math.NaN(), CurrencyOptions{Precision: Precision(-2)})).Equal("NaN")
+		})
+
+		g.It("Should use the locale presets", func() {
+			g.Assert(NumberToCurrency(1234.567, CurrencyOptions{Locale: "fr"})).Equal("1 234,57 €")
+			g.Assert(NumberToCurrency(1234.567, CurrencyOptions{Locale: "de"})).Equal("1.234,57 €")
+			g.Assert(NumberToCurrency(1234.567, CurrencyOptions{Locale: "nl"})).Equal("€ 1.234,57")
+			g.Assert(NumberToCurrency(1234.567, CurrencyOptions{Locale: "ja"})).Equal("1,235円")
+			g.Assert(NumberToCurrency(1234.567, CurrencyOptions{Locale: "de", Unit: "CHF"})).Equal("1.234,57 CHF")
+			g.Assert(NumberToCurrency(1234.567, CurrencyOptions{Locale: "xx"})).Equal("$1,234.57")
+		})
+	})
+}
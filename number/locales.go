@@ -0,0 +1,112 @@
+package number
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/mattetti/goRailsYourself/i18n"
+)
+
+// Locale holds the number formats of a locale, as defined by the number
+// keys of the rails-i18n locale files. It is shared by all the helpers of
+// the package. The formats of the locale files loaded with the i18n package
+// override the registered ones.
+type Locale struct {
+	// Separator separates the integer and the decimals (number.format.separator).
+	Separator string
+	// Delimiter separates the groups of thousands (number.format.delimiter).
+	Delimiter string
+	// Currency holds the currency defaults (number.currency.format).
+	Currency CurrencyFormat
+	// PercentageFormat places the number in a percentage
+	// (number.percentage.format.format).
+	PercentageFormat string
+}
+
+// CurrencyFormat holds the currency defaults of a locale.
+type CurrencyFormat struct {
+	Unit      string
+	Separator string
+	Delimiter string
+	Format    string
+	Precision int
+}
+
+var (
+	localesMu sync.RWMutex
+	locales   = map[string]Locale{
+		"en":    {".", ",", CurrencyFormat{"$", ".", ",", "%u%n", 2}, "%n%"},
+		"fr":    {",", " ", CurrencyFormat{"€", ",", " ", "%n %u", 2}, "%n %"},
+		"de":    {",", ".", CurrencyFormat{"€", ",", ".", "%n %u", 2}, "%n %"},
+		"es":    {",", ".", CurrencyFormat{"€", ",", ".", "%n %u", 2}, "%n %"},
+		"it":    {",", ".", CurrencyFormat{"€", ",", ".", "%n %u", 2}, "%n%"},
+		"nl":    {",", ".", CurrencyFormat{"€", ",", ".", "%u %n", 2}, "%n%"},
+		"pt-BR": {",", ".", CurrencyFormat{"R$", ",", ".", "%u %n", 2}, "%n%"},
+		"ja":    {".", ",", CurrencyFormat{"円", ".", ",", "%n%u", 0}, "%n%"},
+	}
+)
+
+// RegisterLocale adds or replaces the number formats of a locale.
+//
+//	number.RegisterLocale("en-GB", number.Locale{
+//		Separator: ".", Delimiter: ",", PercentageFormat: "%n%",
+//		Currency:  number.CurrencyFormat{Unit: "£", Separator: ".", Delimiter: ",", Format: "%u%n", Precision: 2},
+//	})
+func RegisterLocale(name string, l Locale) {
+	localesMu.Lock()
+	defer localesMu.Unlock()
+	locales[name] = l
+}
+
+// lookupLocale returns the formats of the locale, falling back to its
+// language ("fr" for "fr-CA") and then to English. When translations were
+// loaded in the i18n package for the locale, the number keys they define
+// take precedence.
+func lookupLocale(name string) Locale {
+	l := builtinLocale(name)
+	if i18n.Available(name) {
+		localize(name, &l)
+	}
+	return l
+}
+
+func builtinLocale(name string) Locale {
+	localesMu.RLock()
+	defer localesMu.RUnlock()
+	if l, ok := locales[name]; ok {
+		return l
+	}
+	if i := strings.IndexAny(name, "-_"); i > 0 {
+		if l, ok := locales[name[:i]]; ok {
+			return l
+		}
+	}
+	return locales["en"]
+}
+
+// localize overrides the formats with the ones found in the translations
+// of the locale.
+func localize(name string, l *Locale) {
+	str := func(key string, dst *string) {
+		if v, ok := i18n.Lookup(name, key); ok {
+			if s, ok := v.(string); ok {
+				*dst = s
+			}
+		}
+	}
+	str("number.format.separator", &l.Separator)
+	str("number.format.delimiter", &l.Delimiter)
+	// like in Rails, the currency formats default to the number ones
+	str("number.format.separator", &l.Currency.Separator)
+	str("number.format.delimiter", &l.Currency.Delimiter)
+	str("number.currency.format.unit", &l.Currency.Unit)
+	str("number.currency.format.separator", &l.Currency.Separator)
+	str("number.currency.format.delimiter", &l.Currency.Delimiter)
+	str("number.currency.format.format", &l.Currency.Format)
+	str("number.percentage.format.format", &l.PercentageFormat)
+	if v, ok := i18n.Lookup(name, "number.currency.format.precision"); ok {
+		if p, ok := v.(int); ok {
+			l.Currency.Precision = p
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package number
+
+import (
+	"testing"
+
+	. "github.com/franela/goblin"
+	"github.com/mattetti/goRailsYourself/i18n"
+)
+
+func TestLocales(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Locales", func() {
+		g.It("Should fall back to the language and then English", func() {
+			g.Assert(lookupLocale("fr-CA").Separator).Equal(",")
+			g.Assert(lookupLocale("pt-BR").Currency.Unit).Equal("R$")
+			g.Assert(lookupLocale("pt_BR").Currency.Unit).Equal("$")
+			g.Assert(lookupLocale("xx").Separator).Equal(".")
+			g.Assert(lookupLocale("").Currency.Unit).Equal("$")
+		})
+
+		g.It("Should register new locales", func() {
+			RegisterLocale("en-GB", Locale{
+				Separator: ".", Delimiter: ",", PercentageFormat: "%n%",
+				Currency: CurrencyFormat{Unit: "£", Separator: ".", Delimiter: ",", Format: "%u%n", Precision: 2},
+			})
+			g.Assert(NumberToCurrency(1234.5, CurrencyOptions{Locale: "en-GB"})).Equal("£1,234.50")
+			g.Assert(NumberToCurrency(1234.5, CurrencyOptions{Locale: "en-US"})).Equal("$1,234.50")
+		})
+
+		g.It("Should use the i18n formats", func() {
+			err := i18n.Load([]byte(`
+sv:
+  number:
+    format:
+      separator: ","
+      delimiter: " "
+    currency:
+      format:
+        unit: "kr"
+        format: "%n %u"
+        precision: 0
+    percentage:
+      format:
+        format: "%n %"
+`))
+			g.Assert(err).Equal(nil)
+			g.Assert(NumberToCurrency(1234.5, CurrencyOptions{Locale: "sv"})).Equal("1 235 kr")
+			g.Assert(NumberToDelimited(1234.5, DelimitedOptions{Locale: "sv"})).Equal("1 234,5")
+			g.Assert(NumberToPercentage(12.5, PercentageOptions{Locale: "sv", Precision: Precision(1)})).Equal("12,5 %")
+		})
+	})
+}
@@ -0,0 +1,115 @@
+// The number package ports ActionView's number helpers, so amounts,
+// percentages and phone numbers rendered by Go services look exactly like
+// the ones rendered by a Rails view.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActionView/Helpers/NumberHelper.html
+package number
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Precision returns a pointer to n, to set the Precision of the helper
+// options. A nil precision means the helper default.
+//
+//	NumberToCurrency(1234.5, CurrencyOptions{Precision: Precision(0)}) // => "$1,235"
+func Precision(n int) *int {
+	return &n
+}
+
+// nonFinite returns "NaN", "Inf" or "-Inf" for the numbers which can't be
+// formatted, which the helpers return as is like Rails does.
+func nonFinite(value float64) (string, bool) {
+	switch {
+	case math.IsNaN(value):
+		return "NaN", true
+	case math.IsInf(value, 1):
+		return "Inf", true
+	case math.IsInf(value, -1):
+		return "-Inf", true
+	}
+	return "", false
+}
+
+// round rounds the absolute value of the number half up to the passed
+// number of decimals, working on its shortest decimal representation like
+// Rails does with BigDecimal, and returns the integer and fractional parts.
+// A negative number of decimals rounds the integer part, round(1234, -2)
+// being "1200".
+func round(value float64, decimals int) (intPart, fracPart string) {
+	s := strconv.FormatFloat(math.Abs(value), 'f', -1, 64)
+	intPart, fracPart = s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	if len(fracPart) <= decimals {
+		return intPart, fracPart + strings.Repeat("0", decimals-len(fracPart))
+	}
+	all := intPart + fracPart
+	keep := len(intPart) + decimals
+	if keep < 0 {
+		return "0", ""
+	}
+	digits := []byte(all[:keep])
+	if all[keep] >= '5' {
+		i := len(digits) - 1
+		for ; i >= 0; i-- {
+			if digits[i] < '9' {
+				digits[i]++
+				break
+			}
+			digits[i] = '0'
+		}
+		if i < 0 {
+			digits = append([]byte{'1'}, digits...)
+		}
+	}
+	if decimals < 0 {
+		intPart = strings.TrimLeft(string(digits)+strings.Repeat("0", -decimals), "0")
+		if intPart == "" {
+			intPart = "0"
+		}
+		return intPart, ""
+	}
+	n := len(digits) - decimals
+	return string(digits[:n]), string(digits[n:])
+}
+
+// digitCount returns the number of digits before the decimal point, or
+// minus the number of leading zeros after it for numbers below 1.
+func digitCount(value float64) int {
+	if value == 0 {
+		return 1
+	}
+	return int(math.Floor(math.Log10(math.Abs(value)))) + 1
+}
+
+// delimit inserts the delimiter between each group of thousands.
+func delimit(intPart, delimiter string) string {
+	if delimiter == "" || len(intPart) <= 3 {
+		return intPart
+	}
+	var b strings.Builder
+	head := len(intPart) % 3
+	if head > 0 {
+		b.WriteString(intPart[:head])
+	}
+	for i := head; i < len(intPart); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(delimiter)
+		}
+		b.WriteString(intPart[i : i+3])
+	}
+	return b.String()
+}
+
+// join assembles the parts of a formatted number.
+func join(intPart, fracPart, delimiter, separator string) string {
+	s := delimit(intPart, delimiter)
+	if fracPart != "" {
+		s += separator + fracPart
+	}
+	return s
+}
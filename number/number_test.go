@@ -0,0 +1,59 @@
+package number
+
+import (
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestNumber(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("round", func() {
+		g.It("Should round half up", func() {
+			parts := func(v float64, p int) [2]string {
+				i, f := round(v, p)
+				return [2]string{i, f}
+			}
+			g.Assert(parts(1234.565, 2)).Eql([2]string{"1234", "57"})
+			g.Assert(parts(1234.564, 2)).Eql([2]string{"1234", "56"})
+			g.Assert(parts(0.5, 0)).Eql([2]string{"1", ""})
+			g.Assert(parts(9.995, 2)).Eql([2]string{"10", "00"})
+			g.Assert(parts(999.9, 0)).Eql([2]string{"1000", ""})
+			g.Assert(parts(-12.5, 1)).Eql([2]string{"12", "5"})
+			g.Assert(parts(3, 3)).Eql([2]string{"3", "000"})
+		})
+
+		g.It("Should round the integer part", func() {
+			parts := func(v float64, p int) [2]string {
+				i, f := round(v, p)
+				return [2]string{i, f}
+			}
+			g.Assert(parts(1234, -2)).Eql([2]string{"1200", ""})
+			g.Assert(parts(1250, -2)).Eql([2]string{"1300", ""})
+			g.Assert(parts(9999, -2)).Eql([2]string{"10000", ""})
+			g.Assert(parts(40, -2)).Eql([2]string{"0", ""})
+			g.Assert(parts(60, -2)).Eql([2]string{"100", ""})
+			g.Assert(parts(60, -3)).Eql([2]string{"0", ""})
+		})
+
+		g.It("Should count digits", func() {
+			g.Assert(digitCount(0)).Equal(1)
+			g.Assert(digitCount(5)).Equal(1)
+			g.Assert(digitCount(123.4)).Equal(3)
+			g.Assert(digitCount(-1000)).Equal(4)
+			g.Assert(digitCount(0.0123)).Equal(-1)
+		})
+	})
+
+	g.Describe("delimit", func() {
+		g.It("Should group thousands", func() {
+			g.Assert(delimit("1", ",")).Equal("1")
+			g.Assert(delimit("123", ",")).Equal("123")
+			g.Assert(delimit("1234", ",")).Equal("1,234")
+			g.Assert(delimit("123456", ",")).Equal("123,456")
+			g.Assert(delimit("1234567", " ")).Equal("1 234 567")
+			g.Assert(delimit("1234567", "")).Equal("1234567")
+		})
+	})
+}
@@ -0,0 +1,53 @@
+package number
+
+import "strings"
+
+// PercentageOptions customizes NumberToPercentage. Empty options use the
+// defaults of the Locale (English if not set or unknown).
+type PercentageOptions struct {
+	// Precision is the number of decimals, 3 by default.
+	Precision *int
+	// Separator separates the integer and the decimals, "." in English.
+	Separator string
+	// Delimiter separates the groups of thousands, none by default.
+	Delimiter string
+	// Format places the number (%n), "%n%" in English.
+	Format string
+	// Locale selects the default options.
+	Locale string
+}
+
+// NumberToPercentage formats the number as a percentage.
+//
+//	NumberToPercentage(100, PercentageOptions{})                                    // => "100.000%"
+//	NumberToPercentage(100, PercentageOptions{Precision: Precision(0)})             // => "100%"
+//	NumberToPercentage(1000, PercentageOptions{Delimiter: ".", Separator: ","})     // => "1.000,000%"
+//	NumberToPercentage(302.24398923423, PercentageOptions{Precision: Precision(5)}) // => "302.24399%"
+//	NumberToPercentage(100, PercentageOptions{Format: "%n  %"})                     // => "100.000  %"
+//	NumberToPercentage(12.5, PercentageOptions{Locale: "fr"})                       // => "12,500 %"
+//
+// NaN and infinite numbers are returned as is, "NaN", "Inf" or "-Inf".
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActionView/Helpers/NumberHelper.html#method-i-number_to_percentage
+func NumberToPercentage(value float64, opts PercentageOptions) string {
+	if s, ok := nonFinite(value); ok {
+		return s
+	}
+	locale := lookupLocale(opts.Locale)
+	precision := 3
+	if opts.Precision != nil {
+		precision = *opts.Precision
+	}
+	if opts.Separator == "" {
+		opts.Separator = locale.Separator
+	}
+	if opts.Format == "" {
+		opts.Format = locale.PercentageFormat
+	}
+	intPart, fracPart := round(value, precision)
+	n := join(intPart, fracPart, opts.Delimiter, opts.Separator)
+	if value < 0 {
+		n = "-" + n
+	}
+	return strings.Replace(opts.Format, "%n", n, -1)
+}
@@ -0,0 +1,49 @@
+package number
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleNumberToPercentage() {
+	fmt.Println(NumberToPercentage(100, PercentageOptions{}))
+	fmt.Println(NumberToPercentage(1000, PercentageOptions{Delimiter: ".", Separator: ","}))
+	// Output: 100.000%
+	// 1.000,000%
+}
+
+func TestNumberToPercentage(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("NumberToPercentage", func() {
+		g.It("Should use three decimals by default", func() {
+			g.Assert(NumberToPercentage(100, PercentageOptions{})).Equal("100.000%")
+			g.Assert(NumberToPercentage(98.6, PercentageOptions{})).Equal("98.600%")
+			g.Assert(NumberToPercentage(-0.13, PercentageOptions{})).Equal("-0.130%")
+			g.Assert(NumberToPercentage(1000, PercentageOptions{})).Equal("1000.000%")
+		})
+
+		g.It("Should support custom options", func() {
+			g.Assert(NumberToPercentage(100, PercentageOptions{Precision: Precision(0)})).Equal("100%")
+			g.Assert(NumberToPercentage(302.24398923423, PercentageOptions{Precision: Precision(5)})).Equal("302.24399%")
+			g.Assert(NumberToPercentage(1000, PercentageOptions{Delimiter: ".", Separator: ","})).Equal("1.000,000%")
+			g.Assert(NumberToPercentage(123.400, PercentageOptions{Precision: Precision(2), Separator: ","})).Equal("123,40%")
+			g.Assert(NumberToPercentage(100, PercentageOptions{Format: "%n  %"})).Equal("100.000  %")
+		})
+
+		g.It("Should use the locale formats", func() {
+			g.Assert(NumberToPercentage(12.5, PercentageOptions{Locale: "fr"})).Equal("12,500 %")
+			g.Assert(NumberToPercentage(1234.5, PercentageOptions{Locale: "de", Precision: Precision(1)})).Equal("1234,5 %")
+			g.Assert(NumberToPercentage(12.5, PercentageOptions{Locale: "nl"})).Equal("12,500%")
+		})
+
+		g.It("Should return the non-finite numbers as is", func() {
+			g.Assert(NumberToPercentage(math.Inf(1), PercentageOptions{})).Equal("Inf")
+			g.Assert(NumberToPercentage(math.Inf(-1), PercentageOptions{})).Equal("-Inf")
+			g.Assert(NumberToPercentage(math.NaN(), PercentageOptions{Precision: Precision(-2)})).Equal("NaN")
+		})
+	})
+}
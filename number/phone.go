@@ -0,0 +1,70 @@
+package number
+
+import (
+	"regexp"
+	"strings"
+)
+
+// PhoneOptions customizes NumberToPhone.
+type PhoneOptions struct {
+	// AreaCode adds parentheses around the area code.
+	AreaCode bool
+	// Delimiter separates the groups of digits, "-" by default.
+	Delimiter string
+	// Extension is appended after the number, prefixed by " x ".
+	Extension string
+	// CountryCode is prepended to the number, prefixed by "+".
+	CountryCode string
+	// Pattern splits the number in three groups instead of the default
+	// North American one.
+	Pattern *regexp.Regexp
+}
+
+var (
+	areaCodePattern   = regexp.MustCompile(`(\d{1,3})(\d{3})(\d{4}$)`)
+	noAreaCodePattern = regexp.MustCompile(`(\d{0,3})(\d{3})(\d{4})$`)
+)
+
+// NumberToPhone formats the number as a phone number, US style by default.
+//
+//	NumberToPhone("5551234", PhoneOptions{})                                     // => "555-1234"
+//	NumberToPhone("1235551234", PhoneOptions{AreaCode: true})                    // => "(123) 555-1234"
+//	NumberToPhone("1235551234", PhoneOptions{Delimiter: " "})                    // => "123 555 1234"
+//	NumberToPhone("1235551234", PhoneOptions{AreaCode: true, Extension: "555"}) // => "(123) 555-1234 x 555"
+//	NumberToPhone("1235551234", PhoneOptions{CountryCode: "1"})                 // => "+1-123-555-1234"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActionView/Helpers/NumberHelper.html#method-i-number_to_phone
+func NumberToPhone(number string, opts PhoneOptions) string {
+	if opts.Delimiter == "" {
+		opts.Delimiter = "-"
+	}
+	// the delimiter is used in a replacement template
+	delimiter := strings.Replace(opts.Delimiter, "$", "$$", -1)
+	number = strings.TrimSpace(number)
+
+	var phone string
+	if opts.AreaCode {
+		re := areaCodePattern
+		if opts.Pattern != nil {
+			re = opts.Pattern
+		}
+		phone = re.ReplaceAllString(number, "(${1}) ${2}"+delimiter+"${3}")
+	} else {
+		re := noAreaCodePattern
+		if opts.Pattern != nil {
+			re = opts.Pattern
+		}
+		phone = re.ReplaceAllString(number, "${1}"+delimiter+"${2}"+delimiter+"${3}")
+		if strings.HasPrefix(phone, opts.Delimiter) {
+			phone = phone[1:]
+		}
+	}
+
+	if opts.CountryCode != "" {
+		phone = "+" + opts.CountryCode + opts.Delimiter + phone
+	}
+	if opts.Extension != "" {
+		phone += " x " + opts.Extension
+	}
+	return phone
+}
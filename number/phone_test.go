@@ -0,0 +1,49 @@
+package number
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleNumberToPhone() {
+	fmt.Println(NumberToPhone("1235551234", PhoneOptions{}))
+	fmt.Println(NumberToPhone("1235551234", PhoneOptions{AreaCode: true, Extension: "555"}))
+	// Output: 123-555-1234
+	// (123) 555-1234 x 555
+}
+
+func TestNumberToPhone(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("NumberToPhone", func() {
+		g.It("Should format US numbers", func() {
+			g.Assert(NumberToPhone("5551234", PhoneOptions{})).Equal("555-1234")
+			g.Assert(NumberToPhone("1235551234", PhoneOptions{})).Equal("123-555-1234")
+			g.Assert(NumberToPhone(" 1235551234 ", PhoneOptions{})).Equal("123-555-1234")
+			g.Assert(NumberToPhone("1235551234", PhoneOptions{AreaCode: true})).Equal("(123) 555-1234")
+			g.Assert(NumberToPhone("1235551234", PhoneOptions{Delimiter: " "})).Equal("123 555 1234")
+			g.Assert(NumberToPhone("1235551234", PhoneOptions{Delimiter: "."})).Equal("123.555.1234")
+		})
+
+		g.It("Should add country codes and extensions", func() {
+			g.Assert(NumberToPhone("1235551234", PhoneOptions{AreaCode: true, Extension: "555"})).Equal("(123) 555-1234 x 555")
+			g.Assert(NumberToPhone("1235551234", PhoneOptions{CountryCode: "1"})).Equal("+1-123-555-1234")
+			g.Assert(NumberToPhone("1235551234", PhoneOptions{CountryCode: "1", Extension: "1343", Delimiter: "."})).Equal("+1.123.555.1234 x 1343")
+		})
+
+		g.It("Should support custom patterns", func() {
+			re := regexp.MustCompile(`(\d{3})(\d{4})(\d{4})`)
+			g.Assert(NumberToPhone("75561234567", PhoneOptions{Pattern: re, AreaCode: true})).Equal("(755) 6123-4567")
+			g.Assert(NumberToPhone("13312345678", PhoneOptions{Pattern: re})).Equal("133-1234-5678")
+		})
+
+		g.It("Should leave other strings alone", func() {
+			g.Assert(NumberToPhone("", PhoneOptions{})).Equal("")
+			g.Assert(NumberToPhone("x", PhoneOptions{})).Equal("x")
+			g.Assert(NumberToPhone("123", PhoneOptions{})).Equal("123")
+		})
+	})
+}
@@ -0,0 +1,121 @@
+package number
+
+import (
+	"strconv"
+	"strings"
+)
+
+// RoundedOptions customizes NumberToRounded. Empty options use the defaults
+// of the Locale (English if not set or unknown).
+type RoundedOptions struct {
+	// Precision is the number of decimals, or of significant digits when
+	// Significant is set, 3 by default.
+	Precision *int
+	// Significant makes Precision the number of significant digits.
+	Significant bool
+	// Separator separates the integer and the decimals, "." in English.
+	Separator string
+	// Delimiter separates the groups of thousands, none by default.
+	Delimiter string
+	// StripInsignificantZeros removes the trailing zeros of the decimals.
+	StripInsignificantZeros bool
+	// Locale selects the default options.
+	Locale string
+}
+
+// NumberToRounded formats the number with the passed precision.
+//
+//	NumberToRounded(111.2345, RoundedOptions{})                                       // => "111.235"
+//	NumberToRounded(111.2345, RoundedOptions{Precision: Precision(2)})                // => "111.23"
+//	NumberToRounded(111.2345, RoundedOptions{Significant: true})                      // => "111"
+//	NumberToRounded(13, RoundedOptions{Precision: Precision(5), Significant: true})   // => "13.000"
+//	NumberToRounded(13, RoundedOptions{StripInsignificantZeros: true})                // => "13"
+//	NumberToRounded(1111.2345, RoundedOptions{Locale: "fr", Precision: Precision(2)}) // => "1111,23"
+//
+// NaN and infinite numbers are returned as is, "NaN", "Inf" or "-Inf".
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActionView/Helpers/NumberHelper.html#method-i-number_to_rounded
+func NumberToRounded(value float64, opts RoundedOptions) string {
+	if s, ok := nonFinite(value); ok {
+		return s
+	}
+	locale := lookupLocale(opts.Locale)
+	precision := 3
+	if opts.Precision != nil {
+		precision = *opts.Precision
+	}
+	if opts.Separator == "" {
+		opts.Separator = locale.Separator
+	}
+
+	var intPart, fracPart string
+	if opts.Significant && precision > 0 {
+		intPart, fracPart = round(value, precision-digitCount(value))
+		// rounding can add a digit, 9.995 becoming 10.0
+		digits := len(intPart)
+		if intPart == "0" {
+			digits = digitCount(value)
+		}
+		if decimals := precision - digits; decimals < len(fracPart) {
+			if decimals < 0 {
+				decimals = 0
+			}
+			fracPart = fracPart[:decimals]
+		}
+	} else {
+		intPart, fracPart = round(value, precision)
+	}
+	if opts.StripInsignificantZeros {
+		fracPart = strings.TrimRight(fracPart, "0")
+	}
+
+	n := join(intPart, fracPart, opts.Delimiter, opts.Separator)
+	if value < 0 {
+		n = "-" + n
+	}
+	return n
+}
+
+// DelimitedOptions customizes NumberToDelimited. Empty options use the
+// defaults of the Locale (English if not set or unknown).
+type DelimitedOptions struct {
+	// Delimiter separates the groups of thousands, "," in English.
+	Delimiter string
+	// Separator separates the integer and the decimals, "." in English.
+	Separator string
+	// Locale selects the default options.
+	Locale string
+}
+
+// NumberToDelimited formats the integer part of the number in groups of
+// thousands, keeping all its decimals.
+//
+//	NumberToDelimited(12345678.05, DelimitedOptions{})            // => "12,345,678.05"
+//	NumberToDelimited(1234.57, DelimitedOptions{Locale: "fr"})    // => "1 234,57"
+//	NumberToDelimited(12345678, DelimitedOptions{Delimiter: "."}) // => "12.345.678"
+//
+// NaN and infinite numbers are returned as is, "NaN", "Inf" or "-Inf".
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActionView/Helpers/NumberHelper.html#method-i-number_to_delimited
+func NumberToDelimited(value float64, opts DelimitedOptions) string {
+	if s, ok := nonFinite(value); ok {
+		return s
+	}
+	locale := lookupLocale(opts.Locale)
+	if opts.Delimiter == "" {
+		opts.Delimiter = locale.Delimiter
+	}
+	if opts.Separator == "" {
+		opts.Separator = locale.Separator
+	}
+	s := strconv.FormatFloat(value, 'f', -1, 64)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	return sign + join(intPart, fracPart, opts.Delimiter, opts.Separator)
+}
@@ -0,0 +1,89 @@
+package number
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleNumberToRounded() {
+	fmt.Println(NumberToRounded(111.2345, RoundedOptions{}))
+	fmt.Println(NumberToRounded(111.2345, RoundedOptions{Significant: true}))
+	fmt.Println(NumberToRounded(1234.567, RoundedOptions{Locale: "fr", Precision: Precision(2), Delimiter: " "}))
+	// Output: 111.235
+	// 111
+	// 1 234,57
+}
+
+func ExampleNumberToDelimited() {
+	fmt.Println(NumberToDelimited(12345678.05, DelimitedOptions{}))
+	fmt.Println(NumberToDelimited(1234.57, DelimitedOptions{Locale: "fr"}))
+	// Output: 12,345,678.05
+	// 1 234,57
+}
+
+func TestNumberToRounded(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("NumberToRounded", func() {
+		g.It("Should round to three decimals by default", func() {
+			g.Assert(NumberToRounded(111.2345, RoundedOptions{})).Equal("111.235")
+			g.Assert(NumberToRounded(31.825, RoundedOptions{Precision: Precision(2)})).Equal("31.83")
+			g.Assert(NumberToRounded(111.2345, RoundedOptions{Precision: Precision(0)})).Equal("111")
+			g.Assert(NumberToRounded(111, RoundedOptions{Precision: Precision(2)})).Equal("111.00")
+			g.Assert(NumberToRounded(-111.2345, RoundedOptions{})).Equal("-111.235")
+			g.Assert(NumberToRounded(1111.2345, RoundedOptions{Precision: Precision(2), Separator: ",", Delimiter: "."})).Equal("1.111,23")
+		})
+
+		g.It("Should support significant digits", func() {
+			g.Assert(NumberToRounded(111.2345, RoundedOptions{Significant: true})).Equal("111")
+			g.Assert(NumberToRounded(123.987, RoundedOptions{Precision: Precision(2), Significant: true})).Equal("120")
+			g.Assert(NumberToRounded(13, RoundedOptions{Precision: Precision(5), Significant: true})).Equal("13.000")
+			g.Assert(NumberToRounded(389.32314, RoundedOptions{Precision: Precision(0), Significant: true})).Equal("389")
+			g.Assert(NumberToRounded(9.995, RoundedOptions{Significant: true})).Equal("10.0")
+			g.Assert(NumberToRounded(0.0123456, RoundedOptions{Significant: true})).Equal("0.0123")
+			g.Assert(NumberToRounded(0, RoundedOptions{Precision: Precision(3), Significant: true})).Equal("0.00")
+		})
+
+		g.It("Should strip insignificant zeros", func() {
+			g.Assert(NumberToRounded(13, RoundedOptions{StripInsignificantZeros: true})).Equal("13")
+			g.Assert(NumberToRounded(13.10, RoundedOptions{StripInsignificantZeros: true})).Equal("13.1")
+			g.Assert(NumberToRounded(13, RoundedOptions{Precision: Precision(5), Significant: true, StripInsignificantZeros: true})).Equal("13")
+		})
+
+		g.It("Should use the locale separator", func() {
+			g.Assert(NumberToRounded(1234.567, RoundedOptions{Locale: "fr", Precision: Precision(2)})).Equal("1234,57")
+			g.Assert(NumberToRounded(1234.567, RoundedOptions{Locale: "fr-CA", Precision: Precision(2)})).Equal("1234,57")
+			g.Assert(NumberToRounded(1234.567, RoundedOptions{Locale: "de", Precision: Precision(2), Delimiter: "."})).Equal("1.234,57")
+		})
+	})
+
+	g.Describe("Non-finite numbers", func() {
+		g.It("Should be returned as is", func() {
+			g.Assert(NumberToRounded(math.Inf(1), RoundedOptions{})).Equal("Inf")
+			g.Assert(NumberToRounded(math.Inf(-1), RoundedOptions{Significant: true})).Equal("-Inf")
+			g.Assert(NumberToRounded(math.NaN(), RoundedOptions{Precision: Precision(-2)})).Equal("NaN")
+			g.Assert(NumberToRounded(math.Inf(1), RoundedOptions{Precision: Precision(-2)})).Equal("Inf")
+			g.Assert(NumberToDelimited(math.Inf(1), DelimitedOptions{})).Equal("Inf")
+			g.Assert(NumberToDelimited(math.NaN(), DelimitedOptions{})).Equal("NaN")
+		})
+	})
+
+	g.Describe("NumberToDelimited", func() {
+		g.It("Should group thousands", func() {
+			g.Assert(NumberToDelimited(12345678, DelimitedOptions{})).Equal("12,345,678")
+			g.Assert(NumberToDelimited(123, DelimitedOptions{})).Equal("123")
+			g.Assert(NumberToDelimited(12345678.05, DelimitedOptions{})).Equal("12,345,678.05")
+			g.Assert(NumberToDelimited(-123456.78, DelimitedOptions{})).Equal("-123,456.78")
+			g.Assert(NumberToDelimited(12345678, DelimitedOptions{Delimiter: "."})).Equal("12.345.678")
+			g.Assert(NumberToDelimited(98765432.98, DelimitedOptions{Delimiter: " ", Separator: ","})).Equal("98 765 432,98")
+		})
+
+		g.It("Should use the locale formats", func() {
+			g.Assert(NumberToDelimited(1234.57, DelimitedOptions{Locale: "fr"})).Equal("1 234,57")
+			g.Assert(NumberToDelimited(1234567.8, DelimitedOptions{Locale: "de"})).Equal("1.234.567,8")
+		})
+	})
+}
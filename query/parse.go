@@ -0,0 +1,191 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ParamDepthLimit is the maximum nesting depth of the parameters accepted by
+// ParseNestedQuery, like Rack's default.
+const ParamDepthLimit = 100
+
+// ErrParamsTooDeep is returned when the parameters are nested deeper than
+// ParamDepthLimit.
+var ErrParamsTooDeep = errors.New("query: params nested too deep")
+
+// ParseNestedQuery parses a query string using Rack's conventions for
+// nested parameters. Nested parameters are returned as
+// map[string]interface{} values, arrays as []interface{} values and the
+// other values as strings. Parameters without a value ("a" instead of
+// "a=") are set to nil.
+//
+//	ParseNestedQuery("user[name]=David&user[hobbies][]=Rails&user[hobbies][]=coding")
+//	// => map[user:map[hobbies:[Rails coding] name:David]]
+//	ParseNestedQuery("items[][id]=1&items[][qty]=2&items[][id]=3")
+//	// => map[items:[map[id:1 qty:2] map[id:3]]]
+//
+// An error is returned when the query can't be unescaped or when a
+// parameter is used both as a value and as a nested parameter (a=1&a[b]=2).
+//
+// Rack documentation: https://www.rubydoc.info/gems/rack/Rack/QueryParser#parse_nested_query-instance_method
+func ParseNestedQuery(qs string) (map[string]interface{}, error) {
+	params := map[string]interface{}{}
+	if qs == "" {
+		return params, nil
+	}
+	for _, part := range strings.Split(qs, "&") {
+		if part == "" {
+			continue
+		}
+		var v interface{}
+		name := part
+		if i := strings.IndexByte(part, '='); i >= 0 {
+			name = part[:i]
+			value, err := url.QueryUnescape(part[i+1:])
+			if err != nil {
+				return nil, err
+			}
+			v = value
+		}
+		name, err := url.QueryUnescape(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := normalizeParams(params, name, v, 0); err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
+}
+
+// normalizeParams ports Rack::QueryParser#normalize_params, setting the
+// value of the named parameter in params. It returns the updated params,
+// or a slice holding the value when the name is "[]".
+func normalizeParams(params map[string]interface{}, name string, v interface{}, depth int) (interface{}, error) {
+	if depth >= ParamDepthLimit {
+		return nil, ErrParamsTooDeep
+	}
+
+	var k, after string
+	switch {
+	case depth == 0:
+		// don't treat [ at the start of the name specially
+		k = name
+		if len(name) > 1 {
+			if start := strings.IndexByte(name[1:], '[') + 1; start > 0 {
+				k, after = name[:start], name[start:]
+			}
+		}
+	case strings.HasPrefix(name, "[]"):
+		k, after = "[]", name[2:]
+	case strings.HasPrefix(name, "[") && strings.IndexByte(name[1:], ']') >= 0:
+		end := strings.IndexByte(name[1:], ']') + 1
+		k, after = name[1:end], name[end+1:]
+	default:
+		// probably malformed, use the whole name as the key
+		k = name
+	}
+
+	if k == "" {
+		return params, nil
+	}
+
+	switch {
+	case after == "":
+		if k == "[]" && depth != 0 {
+			return []interface{}{v}, nil
+		}
+		params[k] = v
+	case after == "[":
+		params[name] = v
+	case after == "[]":
+		list, err := arrayParam(params, k)
+		if err != nil {
+			return nil, err
+		}
+		params[k] = append(list, v)
+	case strings.HasPrefix(after, "[]"):
+		// recognize x[][y] (hash inside array) parameters
+		childKey := after[2:]
+		if len(after) > 4 && after[2] == '[' && strings.HasSuffix(after, "]") {
+			if key := after[3 : len(after)-1]; !strings.ContainsAny(key, "[]") {
+				childKey = key
+			}
+		}
+		list, err := arrayParam(params, k)
+		if err != nil {
+			return nil, err
+		}
+		if len(list) > 0 {
+			if last, ok := list[len(list)-1].(map[string]interface{}); ok && !hasKey(last, childKey) {
+				if _, err := normalizeParams(last, childKey, v, depth+1); err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
+		child, err := normalizeParams(map[string]interface{}{}, childKey, v, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		params[k] = append(list, child)
+	default:
+		if params[k] == nil {
+			params[k] = map[string]interface{}{}
+		}
+		child, ok := params[k].(map[string]interface{})
+		if !ok {
+			return nil, typeError("Hash", params[k], k)
+		}
+		if _, err := normalizeParams(child, after, v, depth+1); err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
+}
+
+// arrayParam returns the array set for the key, or an error if the key is
+// set to something else.
+func arrayParam(params map[string]interface{}, k string) ([]interface{}, error) {
+	if params[k] == nil {
+		return nil, nil
+	}
+	list, ok := params[k].([]interface{})
+	if !ok {
+		return nil, typeError("Array", params[k], k)
+	}
+	return list, nil
+}
+
+// hasKey ports Rack::QueryParser#params_hash_has_key?.
+func hasKey(params map[string]interface{}, key string) bool {
+	if strings.Contains(key, "[]") {
+		return false
+	}
+	var current interface{} = params
+	for _, part := range strings.FieldsFunc(key, func(r rune) bool { return r == '[' || r == ']' }) {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if current, ok = m[part]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// typeError returns the error reported when a parameter is used both as
+// a value and as a nested parameter.
+func typeError(expected string, got interface{}, k string) error {
+	class := "String"
+	switch got.(type) {
+	case map[string]interface{}:
+		class = "Hash"
+	case []interface{}:
+		class = "Array"
+	}
+	return fmt.Errorf("query: expected %s (got %s) for param `%s'", expected, class, k)
+}
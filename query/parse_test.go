@@ -0,0 +1,95 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleParseNestedQuery() {
+	params, _ := ParseNestedQuery("user[name]=David&user[hobbies][]=Rails&user[hobbies][]=coding")
+	fmt.Println(params)
+	params, _ = ParseNestedQuery("items[][id]=1&items[][qty]=2&items[][id]=3")
+	fmt.Println(params)
+	// Output: map[user:map[hobbies:[Rails coding] name:David]]
+	// map[items:[map[id:1 qty:2] map[id:3]]]
+}
+
+func TestParseNestedQuery(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("ParseNestedQuery", func() {
+		parse := func(qs string) map[string]interface{} {
+			params, err := ParseNestedQuery(qs)
+			g.Assert(err == nil).IsTrue()
+			return params
+		}
+
+		g.It("Should parse simple parameters", func() {
+			g.Assert(parse("")).Equal(map[string]interface{}{})
+			g.Assert(parse("foo=bar&baz=a+b%26c")).Equal(map[string]interface{}{"foo": "bar", "baz": "a b&c"})
+			g.Assert(parse("foo=")).Equal(map[string]interface{}{"foo": ""})
+			g.Assert(parse("foo")).Equal(map[string]interface{}{"foo": nil})
+			g.Assert(parse("foo=1&foo=2")).Equal(map[string]interface{}{"foo": "2"})
+			g.Assert(parse("&foo=1&&")).Equal(map[string]interface{}{"foo": "1"})
+		})
+
+		g.It("Should parse arrays", func() {
+			g.Assert(parse("a[]=1&a[]=2")).Equal(map[string]interface{}{"a": []interface{}{"1", "2"}})
+			g.Assert(parse("a%5B%5D=1")).Equal(map[string]interface{}{"a": []interface{}{"1"}})
+			g.Assert(parse("a[][]=1")).Equal(map[string]interface{}{"a": []interface{}{[]interface{}{"1"}}})
+		})
+
+		g.It("Should parse nested hashes", func() {
+			g.Assert(parse("a[b][c]=1&a[b][d]=2")).Equal(map[string]interface{}{
+				"a": map[string]interface{}{"b": map[string]interface{}{"c": "1", "d": "2"}},
+			})
+			g.Assert(parse("a[b][]=1&a[b][]=2")).Equal(map[string]interface{}{
+				"a": map[string]interface{}{"b": []interface{}{"1", "2"}},
+			})
+		})
+
+		g.It("Should parse hashes in arrays", func() {
+			g.Assert(parse("a[][b]=1&a[][c]=2&a[][b]=3")).Equal(map[string]interface{}{
+				"a": []interface{}{
+					map[string]interface{}{"b": "1", "c": "2"},
+					map[string]interface{}{"b": "3"},
+				},
+			})
+			g.Assert(parse("a[][b][c]=1&a[][b][d]=2&a[][b][c]=3")).Equal(map[string]interface{}{
+				"a": []interface{}{
+					map[string]interface{}{"b": map[string]interface{}{"c": "1", "d": "2"}},
+					map[string]interface{}{"b": map[string]interface{}{"c": "3"}},
+				},
+			})
+		})
+
+		g.It("Should keep malformed names", func() {
+			g.Assert(parse("[a]=1")).Equal(map[string]interface{}{"[a]": "1"})
+			g.Assert(parse("a[=1")).Equal(map[string]interface{}{"a[": "1"})
+		})
+
+		g.It("Should round trip ToQuery", func() {
+			v := map[string]interface{}{
+				"user":  map[string]interface{}{"name": "David", "tags": []interface{}{"a", "b"}},
+				"items": []interface{}{map[string]interface{}{"id": "1", "qty": "2"}, map[string]interface{}{"id": "3"}},
+			}
+			g.Assert(parse(ToQuery(v, ""))).Equal(v)
+		})
+
+		g.It("Should report type conflicts", func() {
+			_, err := ParseNestedQuery("a=1&a[b]=2")
+			g.Assert(err.Error()).Equal("query: expected Hash (got String) for param `a'")
+			_, err = ParseNestedQuery("a[b]=1&a[]=2")
+			g.Assert(err.Error()).Equal("query: expected Array (got Hash) for param `a'")
+		})
+
+		g.It("Should report invalid queries", func() {
+			_, err := ParseNestedQuery("a=%zz")
+			g.Assert(err != nil).IsTrue()
+			_, err = ParseNestedQuery("a" + strings.Repeat("[a]", ParamDepthLimit) + "=1")
+			g.Assert(err).Equal(ErrParamsTooDeep)
+		})
+	})
+}
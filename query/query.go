@@ -0,0 +1,141 @@
+// The query package ports the nested query string conventions shared by
+// Rails and Rack (a[b][]=1&a[b][]=2), so Go services can build and parse
+// the same query strings as Rails forms and to_query.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Hash.html#method-i-to_query
+package query
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ToQuery converts the value into a query string, using the namespace as
+// the name of the top level parameter. Maps are encoded as nested
+// parameters and slices as arrays, recursively. Empty maps and slices
+// nested in maps are skipped.
+//
+//	ToQuery(map[string]interface{}{"name": "David", "nationality": "Danish"}, "user")
+//	// => "user%5Bname%5D=David&user%5Bnationality%5D=Danish"
+//	ToQuery([]string{"Rails", "coding"}, "hobbies")
+//	// => "hobbies%5B%5D=Rails&hobbies%5B%5D=coding"
+//
+// Like in Rails, the parameters are sorted, except for the maps nested in
+// slices. Since Go maps aren't ordered, the keys of those are sorted
+// before being encoded.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Hash.html#method-i-to_query
+func ToQuery(value interface{}, namespace string) string {
+	if value == nil {
+		return paramQuery(namespace, nil)
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		return mapQuery(rv, namespace)
+	case reflect.Slice, reflect.Array:
+		if _, ok := value.([]byte); !ok {
+			return sliceQuery(rv, namespace)
+		}
+	}
+	return paramQuery(namespace, value)
+}
+
+// mapQuery ports Hash#to_query.
+func mapQuery(rv reflect.Value, namespace string) string {
+	keys := rv.MapKeys()
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = toParam(k.Interface())
+	}
+	sort.Sort(byName{keys, names})
+
+	parts := make([]string, 0, len(keys))
+	for i, k := range keys {
+		v := rv.MapIndex(k)
+		if isEmptyCollection(v) {
+			continue
+		}
+		key := names[i]
+		if namespace != "" {
+			key = namespace + "[" + key + "]"
+		}
+		parts = append(parts, ToQuery(v.Interface(), key))
+	}
+	if !strings.Contains(namespace, "[]") {
+		sort.Strings(parts)
+	}
+	return strings.Join(parts, "&")
+}
+
+// sliceQuery ports Array#to_query.
+func sliceQuery(rv reflect.Value, key string) string {
+	prefix := key + "[]"
+	if rv.Len() == 0 {
+		return paramQuery(prefix, nil)
+	}
+	parts := make([]string, rv.Len())
+	for i := range parts {
+		parts[i] = ToQuery(rv.Index(i).Interface(), prefix)
+	}
+	return strings.Join(parts, "&")
+}
+
+// paramQuery ports Object#to_query.
+func paramQuery(key string, value interface{}) string {
+	return url.QueryEscape(key) + "=" + url.QueryEscape(toParam(value))
+}
+
+// isEmptyCollection reports whether the value is an empty map or slice.
+func isEmptyCollection(v reflect.Value) bool {
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Map, reflect.Array:
+		return v.Len() == 0
+	case reflect.Slice:
+		return v.Len() == 0 && v.Type().Elem().Kind() != reflect.Uint8
+	}
+	return false
+}
+
+// toParam returns the representation of the value used in URLs.
+func toParam(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case []byte:
+		return string(val)
+	case time.Time:
+		if val.Location() == time.UTC {
+			return val.Format("2006-01-02 15:04:05 UTC")
+		}
+		return val.Format("2006-01-02 15:04:05 -0700")
+	case fmt.Stringer:
+		return val.String()
+	}
+	return fmt.Sprint(v)
+}
+
+// byName sorts map keys using their names.
+type byName struct {
+	keys  []reflect.Value
+	names []string
+}
+
+func (b byName) Len() int           { return len(b.keys) }
+func (b byName) Less(i, j int) bool { return b.names[i] < b.names[j] }
+func (b byName) Swap(i, j int) {
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+	b.names[i], b.names[j] = b.names[j], b.names[i]
+}
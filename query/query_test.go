@@ -0,0 +1,58 @@
+package query
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleToQuery() {
+	fmt.Println(ToQuery(map[string]interface{}{"name": "David", "nationality": "Danish"}, "user"))
+	fmt.Println(ToQuery([]string{"Rails", "coding"}, "hobbies"))
+	// Output: user%5Bname%5D=David&user%5Bnationality%5D=Danish
+	// hobbies%5B%5D=Rails&hobbies%5B%5D=coding
+}
+
+func TestToQuery(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("ToQuery", func() {
+		g.It("Should encode simple values", func() {
+			g.Assert(ToQuery("a b&c", "q")).Equal("q=a+b%26c")
+			g.Assert(ToQuery(10, "page")).Equal("page=10")
+			g.Assert(ToQuery(true, "admin")).Equal("admin=true")
+			g.Assert(ToQuery(nil, "empty")).Equal("empty=")
+		})
+
+		g.It("Should sort the top level parameters", func() {
+			g.Assert(ToQuery(map[string]interface{}{"b": 2, "a": 1, "c": nil}, "")).Equal("a=1&b=2&c=")
+			g.Assert(ToQuery(map[string]string{"z": "last", "a": "first"}, "")).Equal("a=first&z=last")
+		})
+
+		g.It("Should encode nested maps and slices", func() {
+			v := map[string]interface{}{
+				"person": map[string]interface{}{
+					"login": "seckar",
+					"tags":  []interface{}{"a", "b"},
+				},
+			}
+			g.Assert(ToQuery(v, "")).Equal("person%5Blogin%5D=seckar&person%5Btags%5D%5B%5D=a&person%5Btags%5D%5B%5D=b")
+		})
+
+		g.It("Should encode maps nested in slices in order", func() {
+			v := map[string]interface{}{
+				"items": []interface{}{
+					map[string]interface{}{"id": 1, "qty": 2},
+					map[string]interface{}{"id": 3},
+				},
+			}
+			g.Assert(ToQuery(v, "")).Equal("items%5B%5D%5Bid%5D=1&items%5B%5D%5Bqty%5D=2&items%5B%5D%5Bid%5D=3")
+		})
+
+		g.It("Should handle empty collections", func() {
+			g.Assert(ToQuery(map[string]interface{}{}, "")).Equal("")
+			g.Assert(ToQuery([]string{}, "tags")).Equal("tags%5B%5D=")
+			g.Assert(ToQuery(map[string]interface{}{"a": []string{}, "b": map[string]int{}, "c": 1}, "")).Equal("c=1")
+		})
+	})
+}
@@ -0,0 +1,65 @@
+package securepassword
+
+import (
+	"golang.org/x/crypto/bcrypt"
+)
+
+// DeviseStretches is the default number of stretches of Devise.
+const DeviseStretches = 12
+
+// DevisePasswordVerifier creates and checks the encrypted_password values
+// of Devise's database_authenticatable, which appends the pepper to the
+// passwords before hashing them with bcrypt.
+//
+//	devise := securepassword.DevisePasswordVerifier{Pepper: os.Getenv("DEVISE_PEPPER"), Stretches: 11}
+//	ok := devise.ValidPassword(user.EncryptedPassword, password)
+//
+// Devise documentation: https://www.rubydoc.info/github/heartcombo/devise/Devise/Models/DatabaseAuthenticatable
+type DevisePasswordVerifier struct {
+	// Pepper is the config.pepper of Devise, none if empty.
+	Pepper string
+	// Stretches is the config.stretches of Devise, used as the bcrypt cost,
+	// DeviseStretches if not set. Like the bcrypt gem, lower costs than
+	// MinCost are raised to it, so the 1 stretch of the Devise test
+	// environments is a cost of 4.
+	Stretches int
+}
+
+// HashPassword returns the encrypted_password of the password, like
+// Devise::Encryptor.digest. Devise doesn't validate the length of the
+// passwords, so only the first MaxPasswordLength bytes of the password and
+// its pepper are hashed, like the bcrypt gem does.
+func (d DevisePasswordVerifier) HashPassword(password string) (string, error) {
+	cost := d.Stretches
+	if cost == 0 {
+		cost = DeviseStretches
+	}
+	if cost < MinCost {
+		cost = MinCost
+	}
+	digest, err := bcrypt.GenerateFromPassword(d.peppered(password), cost)
+	if err != nil {
+		return "", err
+	}
+	return string(digest), nil
+}
+
+// ValidPassword reports whether the password matches the encrypted
+// password, like the valid_password? method of Devise. The cost is read
+// from the encrypted password, so it doesn't depend on Stretches.
+func (d DevisePasswordVerifier) ValidPassword(encryptedPassword, password string) bool {
+	if encryptedPassword == "" {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(encryptedPassword), d.peppered(password)) == nil
+}
+
+// peppered returns the password followed by the pepper, truncated to the
+// bytes bcrypt uses.
+func (d DevisePasswordVerifier) peppered(password string) []byte {
+	b := []byte(password + d.Pepper)
+	if len(b) > MaxPasswordLength {
+		b = b[:MaxPasswordLength]
+	}
+	return b
+}
@@ -0,0 +1,63 @@
+package securepassword
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/franela/goblin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestDevisePasswordVerifier(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("DevisePasswordVerifier", func() {
+		g.It("Should hash the passwords followed by the pepper", func() {
+			devise := DevisePasswordVerifier{Pepper: "pepper", Stretches: 4}
+			encrypted, err := devise.HashPassword("s3cr3t")
+			g.Assert(err).Eql(nil)
+			g.Assert(strings.HasPrefix(encrypted, "$2a$04$")).IsTrue()
+			g.Assert(devise.ValidPassword(encrypted, "s3cr3t")).IsTrue()
+			g.Assert(devise.ValidPassword(encrypted, "s3cr3tpepper")).IsFalse()
+			g.Assert(bcrypt.CompareHashAndPassword([]byte(encrypted), []byte("s3cr3tpepper"))).Eql(nil)
+
+			unpeppered := DevisePasswordVerifier{Stretches: 4}
+			g.Assert(unpeppered.ValidPassword(encrypted, "s3cr3t")).IsFalse()
+		})
+
+		g.It("Should use the stretches as the cost", func() {
+			encrypted, _ := DevisePasswordVerifier{Stretches: 1}.HashPassword("s3cr3t")
+			cost, _ := Cost(encrypted)
+			g.Assert(cost).Equal(MinCost)
+			encrypted, _ = DevisePasswordVerifier{}.HashPassword("s3cr3t")
+			cost, _ = Cost(encrypted)
+			g.Assert(cost).Equal(DeviseStretches)
+		})
+
+		g.It("Should read the passwords without a pepper like has_secure_password", func() {
+			digest, _ := Digest("s3cr3t", MinCost)
+			g.Assert(DevisePasswordVerifier{}.ValidPassword(digest, "s3cr3t")).IsTrue()
+		})
+
+		g.It("Should truncate the long peppered passwords like bcrypt", func() {
+			devise := DevisePasswordVerifier{Pepper: "pepper", Stretches: 4}
+			password := strings.Repeat("a", 70)
+			encrypted, err := devise.HashPassword(password)
+			g.Assert(err).Eql(nil)
+			g.Assert(devise.ValidPassword(encrypted, password)).IsTrue()
+			g.Assert(DevisePasswordVerifier{Pepper: "peXXXX"}.ValidPassword(encrypted, password)).IsTrue()
+		})
+
+		g.It("Should refuse empty encrypted passwords", func() {
+			g.Assert(DevisePasswordVerifier{}.ValidPassword("", "")).IsFalse()
+		})
+	})
+}
+
+func ExampleDevisePasswordVerifier() {
+	devise := DevisePasswordVerifier{Pepper: "pepper", Stretches: 1}
+	encrypted, _ := devise.HashPassword("s3cr3t")
+	fmt.Println(devise.ValidPassword(encrypted, "s3cr3t"))
+	// Output: true
+}
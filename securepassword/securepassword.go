@@ -0,0 +1,85 @@
+// The securepassword package creates and checks the bcrypt password
+// digests of ActiveModel's has_secure_password, so Go login endpoints can
+// authenticate the users against the password_digest columns written by a
+// Rails app, and the other way around. DevisePasswordVerifier does the same
+// for the encrypted_password columns of Devise.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveModel/SecurePassword/ClassMethods.html
+package securepassword
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// MaxPasswordLength is the length in bytes of the longest password
+	// accepted by Digest. bcrypt ignores the bytes after it, so Rails
+	// refuses the longer passwords like
+	// ActiveModel::SecurePassword::MAX_PASSWORD_LENGTH_ALLOWED.
+	MaxPasswordLength = 72
+	// DefaultCost is the cost used by Digest when none is given, the
+	// default BCrypt::Engine.cost of the bcrypt gem.
+	DefaultCost = 12
+	// MinCost is the lowest cost, the one used by Rails in the tests when
+	// ActiveModel::SecurePassword.min_cost is set.
+	MinCost = bcrypt.MinCost
+	// MaxCost is the highest cost.
+	MaxCost = bcrypt.MaxCost
+)
+
+var (
+	// ErrBlankPassword is returned by Digest for empty passwords, which
+	// Rails refuses.
+	ErrBlankPassword = errors.New("securepassword: password can't be blank")
+	// ErrPasswordTooLong is returned by Digest for the passwords longer than
+	// MaxPasswordLength bytes.
+	ErrPasswordTooLong = fmt.Errorf("securepassword: password is too long (maximum is %d bytes)", MaxPasswordLength)
+)
+
+// Digest returns the bcrypt digest of the password, the value Rails stores
+// in the password_digest column. The cost is DefaultCost if 0, otherwise it
+// must be between MinCost and MaxCost.
+//
+//	digest, err := securepassword.Digest("s3cr3t", 0) // => "$2a$12$..."
+func Digest(password string, cost int) (string, error) {
+	if password == "" {
+		return "", ErrBlankPassword
+	}
+	if len(password) > MaxPasswordLength {
+		return "", ErrPasswordTooLong
+	}
+	if cost == 0 {
+		cost = DefaultCost
+	}
+	if cost < MinCost || cost > MaxCost {
+		return "", fmt.Errorf("securepassword: cost %d is outside the allowed range (%d, %d)", cost, MinCost, MaxCost)
+	}
+	digest, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		return "", err
+	}
+	return string(digest), nil
+}
+
+// Authenticate reports whether the password matches the digest, like
+// the authenticate method of has_secure_password. It is false if the
+// digest is empty or malformed. Like the bcrypt gem, only the first
+// MaxPasswordLength bytes of the password are compared.
+func Authenticate(digest, password string) bool {
+	if digest == "" {
+		return false
+	}
+	if len(password) > MaxPasswordLength {
+		password = password[:MaxPasswordLength]
+	}
+	return bcrypt.CompareHashAndPassword([]byte(digest), []byte(password)) == nil
+}
+
+// Cost returns the cost of a digest, for instance to find the digests to
+// recompute after raising the cost.
+func Cost(digest string) (int, error) {
+	return bcrypt.Cost([]byte(digest))
+}
@@ -0,0 +1,69 @@
+package securepassword
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestSecurePassword(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Digest", func() {
+		g.It("Should create digests Authenticate accepts", func() {
+			digest, err := Digest("s3cr3t", MinCost)
+			g.Assert(err).Eql(nil)
+			g.Assert(strings.HasPrefix(digest, "$2a$04$")).IsTrue()
+			g.Assert(Authenticate(digest, "s3cr3t")).IsTrue()
+			g.Assert(Authenticate(digest, "s3cr3T")).IsFalse()
+		})
+
+		g.It("Should use the cost of the bcrypt gem by default", func() {
+			digest, err := Digest("s3cr3t", 0)
+			g.Assert(err).Eql(nil)
+			cost, err := Cost(digest)
+			g.Assert(err).Eql(nil)
+			g.Assert(cost).Equal(DefaultCost)
+		})
+
+		g.It("Should refuse the passwords Rails refuses", func() {
+			_, err := Digest("", MinCost)
+			g.Assert(err).Equal(ErrBlankPassword)
+			_, err = Digest(strings.Repeat("a", 73), MinCost)
+			g.Assert(err).Equal(ErrPasswordTooLong)
+			_, err = Digest(strings.Repeat("a", 72), MinCost)
+			g.Assert(err).Eql(nil)
+		})
+
+		g.It("Should refuse costs out of range", func() {
+			_, err := Digest("s3cr3t", 3)
+			g.Assert(err.Error()).Equal("securepassword: cost 3 is outside the allowed range (4, 31)")
+		})
+	})
+
+	g.Describe("Authenticate", func() {
+		g.It("Should accept the digests of the bcrypt gem", func() {
+			g.Assert(Authenticate("$2a$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW", "U*U")).IsTrue()
+			g.Assert(Authenticate("$2a$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW", "U*V")).IsFalse()
+		})
+
+		g.It("Should only compare the first 72 bytes like bcrypt", func() {
+			password := strings.Repeat("a", 72)
+			digest, _ := Digest(password, MinCost)
+			g.Assert(Authenticate(digest, password+"b")).IsTrue()
+		})
+
+		g.It("Should refuse empty or malformed digests", func() {
+			g.Assert(Authenticate("", "")).IsFalse()
+			g.Assert(Authenticate("s3cr3t", "s3cr3t")).IsFalse()
+		})
+	})
+}
+
+func ExampleAuthenticate() {
+	digest, _ := Digest("s3cr3t", MinCost)
+	fmt.Println(Authenticate(digest, "s3cr3t"), Authenticate(digest, "guess"))
+	// Output: true false
+}
@@ -0,0 +1,148 @@
+// The securerandom package ports Ruby's SecureRandom and its ActiveSupport
+// extensions, so tokens generated in Go use the exact alphabets and lengths
+// Rails code expects, like the base58 tokens of has_secure_token.
+//
+// The lengths are the ones passed to the Ruby methods, which default to 16.
+// The randomness comes from crypto/rand and the characters of the alphabets
+// are picked without modulo bias.
+//
+// Ruby documentation: https://ruby-doc.org/stdlib/libdoc/securerandom/rdoc/SecureRandom.html
+package securerandom
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+)
+
+// The alphabets of the tokens.
+const (
+	// Base58Alphabet excludes the characters which can be confused: 0, O, I
+	// and l, like SecureRandom::BASE58_ALPHABET.
+	Base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	// Base36Alphabet is made of the digits and the lowercase letters, like
+	// SecureRandom::BASE36_ALPHABET.
+	Base36Alphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+	// AlphanumericAlphabet is the alphabet of SecureRandom.alphanumeric.
+	AlphanumericAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+// randReader is the source of randomness, replaced by the tests.
+var randReader io.Reader = rand.Reader
+
+// Bytes returns n random bytes, like SecureRandom.random_bytes.
+func Bytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(randReader, b); err != nil {
+		return nil, fmt.Errorf("securerandom: %w", err)
+	}
+	return b, nil
+}
+
+// Hex returns n random bytes encoded in lowercase hexadecimal, 2n
+// characters, like SecureRandom.hex.
+func Hex(n int) (string, error) {
+	b, err := Bytes(n)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Base64 returns n random bytes encoded in padded standard base64, like
+// SecureRandom.base64.
+func Base64(n int) (string, error) {
+	b, err := Bytes(n)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// URLSafeBase64 returns n random bytes encoded in URL safe base64, padded
+// only if padding is set, like SecureRandom.urlsafe_base64.
+func URLSafeBase64(n int, padding bool) (string, error) {
+	b, err := Bytes(n)
+	if err != nil {
+		return "", err
+	}
+	if padding {
+		return base64.URLEncoding.EncodeToString(b), nil
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Alphanumeric returns n random characters among A-Z, a-z and 0-9, like
+// SecureRandom.alphanumeric.
+func Alphanumeric(n int) (string, error) {
+	return Choose(AlphanumericAlphabet, n)
+}
+
+// Base58 returns n random characters of Base58Alphabet, like
+// SecureRandom.base58 which generates the tokens of has_secure_token.
+func Base58(n int) (string, error) {
+	return Choose(Base58Alphabet, n)
+}
+
+// Base36 returns n random characters of Base36Alphabet, like
+// SecureRandom.base36.
+func Base36(n int) (string, error) {
+	return Choose(Base36Alphabet, n)
+}
+
+// Choose returns n random characters of the alphabet, which must be made
+// of at most 256 ASCII characters.
+func Choose(alphabet string, n int) (string, error) {
+	if len(alphabet) == 0 || len(alphabet) > 256 {
+		return "", fmt.Errorf("securerandom: bad alphabet size %d", len(alphabet))
+	}
+	// the bytes above the largest multiple of the alphabet size are
+	// rejected, so all the characters are equally likely
+	limit := 256 - 256%len(alphabet)
+	token := make([]byte, 0, n)
+	buf := make([]byte, n+n/4+1)
+	for len(token) < n {
+		if _, err := io.ReadFull(randReader, buf); err != nil {
+			return "", fmt.Errorf("securerandom: %w", err)
+		}
+		for _, b := range buf {
+			if int(b) < limit && len(token) < n {
+				token = append(token, alphabet[int(b)%len(alphabet)])
+			}
+		}
+	}
+	return string(token), nil
+}
+
+// UUID returns a random version 4 UUID, like SecureRandom.uuid.
+func UUID() (string, error) {
+	b, err := Bytes(16)
+	if err != nil {
+		return "", err
+	}
+	return formatUUID(b, 4), nil
+}
+
+// UUIDv7 returns a version 7 UUID, made of the current Unix time in
+// milliseconds followed by random bits, like SecureRandom.uuid_v7. They
+// sort by creation time, which suits database primary keys.
+func UUIDv7() (string, error) {
+	b, err := Bytes(16)
+	if err != nil {
+		return "", err
+	}
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(time.Now().UnixMilli()))
+	copy(b[:6], ms[2:])
+	return formatUUID(b, 7), nil
+}
+
+func formatUUID(b []byte, version byte) string {
+	b[6] = (b[6] & 0x0f) | version<<4
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,120 @@
+package securerandom
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	. "github.com/franela/goblin"
+)
+
+func TestSecureRandom(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Encoded random bytes", func() {
+		g.It("Should be hex encoded", func() {
+			token, err := Hex(16)
+			g.Assert(err).Eql(nil)
+			g.Assert(regexp.MustCompile(`^[0-9a-f]{32}$`).MatchString(token)).IsTrue()
+		})
+
+		g.It("Should be base64 encoded", func() {
+			token, err := Base64(16)
+			g.Assert(err).Eql(nil)
+			g.Assert(len(token)).Eql(24)
+			_, err = base64.StdEncoding.DecodeString(token)
+			g.Assert(err).Eql(nil)
+		})
+
+		g.It("Should be URL safe base64 encoded", func() {
+			for i := 0; i < 50; i++ {
+				token, _ := URLSafeBase64(16, false)
+				g.Assert(len(token)).Eql(22)
+				g.Assert(strings.ContainsAny(token, "+/=")).IsFalse()
+			}
+			token, _ := URLSafeBase64(16, true)
+			g.Assert(strings.HasSuffix(token, "==")).IsTrue()
+		})
+	})
+
+	g.Describe("Random tokens", func() {
+		g.It("Should use the alphabets", func() {
+			for _, tc := range []struct {
+				generate func(int) (string, error)
+				alphabet string
+			}{
+				{Alphanumeric, AlphanumericAlphabet},
+				{Base58, Base58Alphabet},
+				{Base36, Base36Alphabet},
+			} {
+				for _, n := range []int{0, 1, 16, 24, 100} {
+					token, err := tc.generate(n)
+					g.Assert(err).Eql(nil)
+					g.Assert(len(token)).Eql(n)
+					g.Assert(strings.Trim(token, tc.alphabet)).Eql("")
+				}
+			}
+		})
+
+		g.It("Should use all the characters of the alphabets", func() {
+			token, _ := Base58(5000)
+			for _, c := range Base58Alphabet {
+				g.Assert(strings.ContainsRune(token, c)).IsTrue()
+			}
+		})
+
+		g.It("Should refuse bad alphabets", func() {
+			_, err := Choose("", 16)
+			g.Assert(err.Error()).Eql("securerandom: bad alphabet size 0")
+		})
+	})
+
+	g.Describe("Random UUIDs", func() {
+		format := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-([0-9a-f])[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+		g.It("Should be version 4 UUIDs", func() {
+			uuid, err := UUID()
+			g.Assert(err).Eql(nil)
+			g.Assert(format.FindStringSubmatch(uuid)[1]).Eql("4")
+		})
+
+		g.It("Should be version 7 UUIDs starting with the time", func() {
+			before := time.Now().UnixMilli()
+			uuid, err := UUIDv7()
+			g.Assert(err).Eql(nil)
+			g.Assert(format.FindStringSubmatch(uuid)[1]).Eql("7")
+			var ms int64
+			fmt.Sscanf(strings.Replace(uuid[:13], "-", "", 1), "%x", &ms)
+			g.Assert(ms >= before && ms <= time.Now().UnixMilli()).IsTrue()
+		})
+	})
+
+	g.Describe("A failing random source", func() {
+		g.It("Should be reported", func() {
+			failure := errors.New("no entropy")
+			defer func(r io.Reader) { randReader = r }(randReader)
+			randReader = iotest.ErrReader(failure)
+			for _, generate := range []func() (string, error){
+				func() (string, error) { return Hex(16) },
+				func() (string, error) { return Base58(16) },
+				UUID,
+				UUIDv7,
+			} {
+				_, err := generate()
+				g.Assert(errors.Is(err, failure)).IsTrue()
+			}
+		})
+	})
+}
+
+func ExampleBase58() {
+	token, _ := Base58(24)
+	fmt.Println(len(token))
+	// Output: 24
+}
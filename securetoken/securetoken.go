@@ -0,0 +1,55 @@
+// The securetoken package generates and checks the tokens of
+// ActiveRecord's has_secure_token, so Go services can mint and verify API
+// tokens interchangeably with a Rails app.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActiveRecord/SecureToken/ClassMethods.html
+package securetoken
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mattetti/goRailsYourself/crypto"
+	"github.com/mattetti/goRailsYourself/securerandom"
+)
+
+// MinimumLength is the length of the tokens by default and the shortest
+// one accepted, like ActiveRecord::SecureToken::MINIMUM_TOKEN_LENGTH.
+const MinimumLength = 24
+
+// ErrMinimumLength is returned when generating tokens shorter than
+// MinimumLength, like Rails raises a MinimumLengthError.
+var ErrMinimumLength = errors.New("securetoken: token requires a minimum length of 24 characters")
+
+// TokenFor returns a random base58 token of length characters, or
+// MinimumLength if length is 0, like the tokens generated by
+// has_secure_token:
+//
+//	token, err := securetoken.TokenFor(0) // => "pX27zsMN2ViQKta1bGfLmVJE"
+func TokenFor(length int) (string, error) {
+	if length == 0 {
+		length = MinimumLength
+	}
+	if length < MinimumLength {
+		return "", ErrMinimumLength
+	}
+	return securerandom.Base58(length)
+}
+
+// Valid reports whether token could have been generated by TokenFor with
+// length, or MinimumLength if length is 0: it has the length and only
+// contains base58 characters. It doesn't tell whether the token is known,
+// see Equal.
+func Valid(token string, length int) bool {
+	if length == 0 {
+		length = MinimumLength
+	}
+	return len(token) == length && strings.Trim(token, securerandom.Base58Alphabet) == ""
+}
+
+// Equal compares a token with the expected one in constant time, so the
+// comparison doesn't reveal how many leading characters match, see
+// crypto.SecureCompare.
+func Equal(token, expected string) bool {
+	return crypto.SecureCompare(token, expected)
+}
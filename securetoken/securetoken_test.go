@@ -0,0 +1,60 @@
+package securetoken
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestTokenFor(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("TokenFor", func() {
+		g.It("Should generate 24 character base58 tokens by default", func() {
+			token, err := TokenFor(0)
+			g.Assert(err).Eql(nil)
+			g.Assert(len(token)).Equal(24)
+			g.Assert(Valid(token, 0)).IsTrue()
+			other, _ := TokenFor(0)
+			g.Assert(other != token).IsTrue()
+		})
+
+		g.It("Should generate longer tokens", func() {
+			token, err := TokenFor(36)
+			g.Assert(err).Eql(nil)
+			g.Assert(len(token)).Equal(36)
+			g.Assert(Valid(token, 36)).IsTrue()
+			g.Assert(Valid(token, 0)).IsFalse()
+		})
+
+		g.It("Should refuse tokens shorter than the minimum length", func() {
+			_, err := TokenFor(16)
+			g.Assert(err).Equal(ErrMinimumLength)
+		})
+	})
+
+	g.Describe("Valid", func() {
+		g.It("Should refuse the characters base58 excludes", func() {
+			g.Assert(Valid("pX27zsMN2ViQKta1bGfLmVJE", 0)).IsTrue()
+			g.Assert(Valid("pX27zsMN2ViQKta1bGfLmVJ0", 0)).IsFalse()
+			g.Assert(Valid("pX27zsMN2ViQKta1bGfLmVJl", 0)).IsFalse()
+			g.Assert(Valid("pX27zsMN2ViQKta1bGfLmV-E", 0)).IsFalse()
+		})
+	})
+
+	g.Describe("Equal", func() {
+		g.It("Should compare the tokens", func() {
+			g.Assert(Equal("pX27zsMN2ViQKta1bGfLmVJE", "pX27zsMN2ViQKta1bGfLmVJE")).IsTrue()
+			g.Assert(Equal("pX27zsMN2ViQKta1bGfLmVJF", "pX27zsMN2ViQKta1bGfLmVJE")).IsFalse()
+			g.Assert(Equal("pX27zsMN2ViQKta1bGfLmVJ", "pX27zsMN2ViQKta1bGfLmVJE")).IsFalse()
+			g.Assert(Equal("", "")).IsTrue()
+		})
+	})
+}
+
+func ExampleTokenFor() {
+	token, _ := TokenFor(0)
+	fmt.Println(len(token), Valid(token, 0))
+	// Output: 24 true
+}
@@ -0,0 +1,117 @@
+// The session package ports the Rails cookie session store, so Go services
+// can read and write the sessions of a Rails app knowing only its
+// secret_key_base.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActionDispatch/Session/CookieStore.html
+package session
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/mattetti/goRailsYourself/crypto"
+)
+
+// Codec encrypts and decrypts the session cookie of a Rails app, deriving
+// the keys from secret_key_base like the preset's Rails version does:
+//
+//	codec := session.NewCodec(secretKeyBase, "_myapp_session", session.Rails7)
+//	var data map[string]interface{}
+//	err := codec.Decode(cookie.Value, &data)
+//
+// A Codec can be used concurrently once configured.
+type Codec struct {
+	// CookieName is the name of the session cookie, used as purpose of the
+	// Rails 6+ sessions.
+	CookieName string
+	// ExpireAfter embeds an expiration date in the encoded sessions when
+	// the preset supports metadata, like the expire_after option of the
+	// cookie store.
+	ExpireAfter time.Duration
+	// Path, Domain, Secure and SameSite are the attributes of the written
+	// cookies. Path defaults to "/" and SameSite to http.SameSiteLaxMode,
+	// like Rails.
+	Path     string
+	Domain   string
+	Secure   bool
+	SameSite http.SameSite
+	// OnWriteError is called by the middleware when the changed session
+	// can't be written into the response cookie, for instance because it
+	// is larger than MaxCookieSize. The client then keeps its previous
+	// cookie. The errors are logged if not set.
+	OnWriteError func(r *http.Request, err error)
+
+	preset    Preset
+	encryptor *crypto.MessageEncryptor
+}
+
+// NewCodec returns the codec of the cookieName session cookie of an app
+// using secretKeyBase and the preset configuration.
+func NewCodec(secretKeyBase, cookieName string, preset Preset) *Codec {
+	return &Codec{
+		CookieName: cookieName,
+		preset:     preset,
+		encryptor:  preset.encryptor(secretKeyBase),
+	}
+}
+
+// Rotate accepts the sessions encrypted with another secret_key_base or
+// preset, like config.action_dispatch.cookies_rotations when upgrading
+// Rails. New sessions are still encoded with the codec's configuration.
+//
+//	codec := session.NewCodec(secretKeyBase, "_myapp_session", session.Rails7)
+//	codec.Rotate(secretKeyBase, session.Rails6)
+//
+// Rotate isn't safe to call while the codec is in use.
+func (c *Codec) Rotate(secretKeyBase string, preset Preset) {
+	c.encryptor.RotateEncryptor(preset.encryptor(secretKeyBase))
+}
+
+// Decode decrypts a session cookie value into target. The sessions of
+// Rails 6+ must have been written for the codec's cookie, but the ones
+// written before the app enabled metadata are accepted too.
+// The operation is instrumented as "decode.session_codec".
+func (c *Codec) Decode(value string, target interface{}) error {
+	return instrument("decode.session_codec", c.payload, func() error {
+		return c.decode(value, target)
+	})
+}
+
+func (c *Codec) decode(value string, target interface{}) error {
+	if !c.preset.Metadata {
+		return c.encryptor.DecryptAndVerify(value, target)
+	}
+	err := c.encryptor.DecryptAndVerifyWithPurpose(value, target, c.purpose())
+	if errors.Is(err, crypto.ErrWrongPurpose) {
+		// sessions without metadata have no purpose
+		if c.encryptor.DecryptAndVerify(value, target) == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// Encode encrypts a session into a cookie value.
+// The operation is instrumented as "encode.session_codec".
+func (c *Codec) Encode(session interface{}) (value string, err error) {
+	err = instrument("encode.session_codec", c.payload, func() error {
+		value, err = c.encode(session)
+		return err
+	})
+	return value, err
+}
+
+func (c *Codec) encode(session interface{}) (string, error) {
+	if !c.preset.Metadata {
+		return c.encryptor.EncryptAndSign(session)
+	}
+	return c.encryptor.EncryptAndSignWithOptions(session, crypto.MessageOptions{
+		Purpose:   c.purpose(),
+		ExpiresIn: c.ExpireAfter,
+	})
+}
+
+func (c *Codec) purpose() string {
+	return crypto.CookiePurpose(c.CookieName)
+}
@@ -0,0 +1,91 @@
+package session
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mattetti/goRailsYourself/crypto"
+
+	. "github.com/franela/goblin"
+)
+
+const secretKeyBase = "f7b5763636f4c1f3ff4bd444eacccca295d87b990cc104124017ad70550edcfd22b8e89465338254e0b608592a9aac29025440bfd9ce53579835ba06a86f85f9"
+
+// sessions written by Rails apps using secretKeyBase
+const (
+	rails4Cookie  = "TDZIdC9GcEVRSnR0aFlqYTI1SmRWTmw3NWxpRkJZNDVMK0NIUXFlcThWWitLeVQzMFVBUTE2RU82RnRsUUxQWnhyWG95dFJSRDc0OVpkVzhGWXlIb1hERHhPdk5mYStkd3pVVUZNbE1vcDRqU01MYVZJMVpMWVI5SmIweFo1N2tqWTdZcVhyWmdnc2NhZUY2b1BBMlNKWkVsT0Y0aEVQcVVKaGRISk0zR3JLWXdjaFMxamN2aThVL2hBMHBmSGx5bGg4UjUzRFErejlQVEM0eUZjcStSM3VYUkNERjBMdUVqQzZaQk5ZNHpjRT0tLUhDQ2RraWpKRDBleUp1Rm1OeVA5Snc9PQ==--61cd94a037a0a006a01403952a652ddc5da1a597"
+	rails52Cookie = "Co+XxC9PK1ptoHftqua6C3PNrlvk4EA09IpKho+wk5qbMi4jrl6SS2g6xexK68b8kjKWqXzCcT/ZjkbAO/0Sxm01JIK0zY/qGa56ogFaVViZKgaCGlSQYDWrVDm3mCSTlTzHDl3nrIjMffwNEn2x5IPHaQQoR0skkv3A17zejE4d18pRqRYaCuZLg2H04HWYv0Y/s88Kurmevw8w/8xUwLIV8P3SpszfMHEU--Cs17rTBCsResqqC5--ym0c0ZE+ts7wExyw/t35QA=="
+)
+
+func TestCodec(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Codec", func() {
+		g.It("decodes Rails 4 sessions", func() {
+			codec := NewCodec(secretKeyBase, "_app_session", Rails4)
+			var session map[string]interface{}
+			g.Assert(codec.Decode(rails4Cookie, &session)).Eql(nil)
+			g.Assert(session["session_id"]).Eql("b2d63c07ea7a9d58e415e3672e3f31a2")
+		})
+
+		g.It("decodes Rails 5.2 sessions", func() {
+			codec := NewCodec(secretKeyBase, "_app_session", Rails52)
+			var session map[string]interface{}
+			g.Assert(codec.Decode(rails52Cookie, &session)).Eql(nil)
+			g.Assert(session["session_id"]).Eql("b2d63c07ea7a9d58e415e3672e3f31a2")
+		})
+
+		g.It("decodes the sessions written before metadata was enabled", func() {
+			codec := NewCodec(secretKeyBase, "_app_session", Rails6)
+			var session map[string]interface{}
+			g.Assert(codec.Decode(rails52Cookie, &session)).Eql(nil)
+			g.Assert(session["session_id"]).Eql("b2d63c07ea7a9d58e415e3672e3f31a2")
+		})
+
+		for name, preset := range map[string]Preset{"Rails4": Rails4, "Rails52": Rails52, "Rails6": Rails6, "Rails7": Rails7} {
+			preset := preset
+			g.It("round trips sessions using "+name, func() {
+				codec := NewCodec(secretKeyBase, "_app_session", preset)
+				value, err := codec.Encode(map[string]string{"session_id": "abc"})
+				g.Assert(err).Eql(nil)
+				var session map[string]string
+				g.Assert(codec.Decode(value, &session)).Eql(nil)
+				g.Assert(session).Eql(map[string]string{"session_id": "abc"})
+			})
+		}
+
+		g.It("refuses the sessions of another cookie", func() {
+			value, _ := NewCodec(secretKeyBase, "_other_session", Rails7).Encode(map[string]string{"session_id": "abc"})
+			var session map[string]string
+			g.Assert(NewCodec(secretKeyBase, "_app_session", Rails7).Decode(value, &session)).Eql(crypto.ErrWrongPurpose)
+		})
+
+		g.It("refuses expired sessions", func() {
+			codec := NewCodec(secretKeyBase, "_app_session", Rails7)
+			codec.ExpireAfter = -time.Minute
+			value, _ := codec.Encode(map[string]string{"session_id": "abc"})
+			var session map[string]string
+			g.Assert(codec.Decode(value, &session)).Eql(crypto.ErrExpired)
+		})
+
+		g.It("accepts the sessions of rotated configurations", func() {
+			value, _ := NewCodec(secretKeyBase, "_app_session", Rails6).Encode(map[string]string{"session_id": "abc"})
+			codec := NewCodec(secretKeyBase, "_app_session", Rails7)
+			var session map[string]string
+			g.Assert(codec.Decode(value, &session) != nil).IsTrue()
+			codec.Rotate(secretKeyBase, Rails6)
+			g.Assert(codec.Decode(value, &session)).Eql(nil)
+			g.Assert(session["session_id"]).Eql("abc")
+		})
+	})
+}
+
+func ExampleCodec() {
+	codec := NewCodec(secretKeyBase, "_app_session", Rails52)
+
+	var session map[string]interface{}
+	err := codec.Decode(rails52Cookie, &session)
+	fmt.Println(session["session_id"], err)
+	// Output: b2d63c07ea7a9d58e415e3672e3f31a2 <nil>
+}
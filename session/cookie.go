@@ -0,0 +1,87 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// MaxCookieSize is the largest size in bytes of the session cookie name
+// and value, like ActionDispatch::Cookies::MAX_COOKIE_SIZE. Browsers drop
+// the larger cookies.
+const MaxCookieSize = 4096
+
+// ErrCookieOverflow is returned by WriteSessionCookie when the encrypted
+// session is larger than MaxCookieSize, like Rails raises a
+// CookieOverflow.
+var ErrCookieOverflow = errors.New("session: cookie overflow")
+
+// ReadSessionCookie decrypts the session cookie of a request into target.
+// The cookie value is URL-unescaped first, the way Rack escapes it, so the
+// '+', '/' and '=' characters of the base64 payload survive. It returns
+// http.ErrNoCookie if the request has no session cookie.
+//
+//	var data map[string]interface{}
+//	err := session.ReadSessionCookie(r, codec, &data)
+func ReadSessionCookie(r *http.Request, c *Codec, target interface{}) error {
+	cookie, err := r.Cookie(c.CookieName)
+	if err != nil {
+		return err
+	}
+	value, err := url.QueryUnescape(cookie.Value)
+	if err != nil {
+		return err
+	}
+	return c.Decode(value, target)
+}
+
+// WriteSessionCookie encrypts a session into the session cookie of a
+// response. Like Rails, the cookie is HttpOnly and SameSite=Lax by default,
+// it expires after the codec's ExpireAfter duration if set, and it is
+// Secure if the codec says so. ErrCookieOverflow is returned, and no cookie
+// is set, if the session is too large.
+func WriteSessionCookie(w http.ResponseWriter, c *Codec, session interface{}) error {
+	value, err := c.Encode(session)
+	if err != nil {
+		return err
+	}
+	if err := checkCookieSize(c.CookieName, value); err != nil {
+		return err
+	}
+	http.SetCookie(w, c.cookie(url.QueryEscape(value)))
+	return nil
+}
+
+// checkCookieSize returns ErrCookieOverflow if the cookie is larger than
+// MaxCookieSize.
+func checkCookieSize(name, value string) error {
+	if size := len(name) + len(value); size > MaxCookieSize {
+		return fmt.Errorf("%w, %s cookie overflowed with size %d bytes", ErrCookieOverflow, name, size)
+	}
+	return nil
+}
+
+// cookie returns the session cookie with the codec's attributes.
+func (c *Codec) cookie(value string) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     c.CookieName,
+		Value:    value,
+		Path:     c.Path,
+		Domain:   c.Domain,
+		Secure:   c.Secure,
+		HttpOnly: true,
+		SameSite: c.SameSite,
+	}
+	if cookie.Path == "" {
+		cookie.Path = "/"
+	}
+	if cookie.SameSite == 0 {
+		cookie.SameSite = http.SameSiteLaxMode
+	}
+	if c.ExpireAfter > 0 {
+		cookie.Expires = time.Now().Add(c.ExpireAfter)
+	}
+	return cookie
+}
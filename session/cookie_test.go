@@ -0,0 +1,83 @@
+package session
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/franela/goblin"
+)
+
+func TestSessionCookie(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("ReadSessionCookie", func() {
+		codec := NewCodec(secretKeyBase, "_app_session", Rails52)
+
+		g.It("unescapes the cookie value", func() {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("Cookie", "_app_session="+url.QueryEscape(rails52Cookie))
+			var session map[string]interface{}
+			g.Assert(ReadSessionCookie(req, codec, &session)).Eql(nil)
+			g.Assert(session["session_id"]).Eql("b2d63c07ea7a9d58e415e3672e3f31a2")
+		})
+
+		g.It("returns http.ErrNoCookie without session cookie", func() {
+			var session map[string]interface{}
+			g.Assert(ReadSessionCookie(httptest.NewRequest("GET", "/", nil), codec, &session)).Eql(http.ErrNoCookie)
+		})
+	})
+
+	g.Describe("WriteSessionCookie", func() {
+		g.It("sets the Rails default attributes", func() {
+			codec := NewCodec(secretKeyBase, "_app_session", Rails7)
+			rec := httptest.NewRecorder()
+			g.Assert(WriteSessionCookie(rec, codec, map[string]string{"session_id": "abc"})).Eql(nil)
+			cookie := rec.Result().Cookies()[0]
+			g.Assert(cookie.Path).Eql("/")
+			g.Assert(cookie.HttpOnly).IsTrue()
+			g.Assert(cookie.Secure).IsFalse()
+			g.Assert(cookie.SameSite).Eql(http.SameSiteLaxMode)
+			g.Assert(cookie.Expires.IsZero()).IsTrue()
+			g.Assert(strings.ContainsAny(cookie.Value, "+/=")).IsFalse()
+		})
+
+		g.It("sets the codec's attributes", func() {
+			codec := NewCodec(secretKeyBase, "_app_session", Rails7)
+			codec.ExpireAfter = time.Hour
+			codec.Secure = true
+			codec.Domain = "example.com"
+			codec.SameSite = http.SameSiteStrictMode
+			rec := httptest.NewRecorder()
+			g.Assert(WriteSessionCookie(rec, codec, map[string]string{"session_id": "abc"})).Eql(nil)
+			cookie := rec.Result().Cookies()[0]
+			g.Assert(cookie.Secure).IsTrue()
+			g.Assert(cookie.Domain).Eql("example.com")
+			g.Assert(cookie.SameSite).Eql(http.SameSiteStrictMode)
+			g.Assert(cookie.Expires.After(time.Now().Add(59 * time.Minute))).IsTrue()
+		})
+
+		g.It("writes cookies ReadSessionCookie reads", func() {
+			codec := NewCodec(secretKeyBase, "_app_session", Rails4)
+			rec := httptest.NewRecorder()
+			WriteSessionCookie(rec, codec, map[string]string{"session_id": "abc"})
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("Cookie", rec.Header().Get("Set-Cookie"))
+			var session map[string]string
+			g.Assert(ReadSessionCookie(req, codec, &session)).Eql(nil)
+			g.Assert(session["session_id"]).Eql("abc")
+		})
+
+		g.It("refuses the sessions larger than MaxCookieSize", func() {
+			codec := NewCodec(secretKeyBase, "_app_session", Rails4)
+			rec := httptest.NewRecorder()
+			err := WriteSessionCookie(rec, codec, map[string]string{"notes": strings.Repeat("a", MaxCookieSize)})
+			g.Assert(errors.Is(err, ErrCookieOverflow)).IsTrue()
+			g.Assert(rec.Header().Get("Set-Cookie")).Eql("")
+		})
+	})
+}
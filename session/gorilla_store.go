@@ -0,0 +1,112 @@
+package session
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/sessions"
+)
+
+// GorillaStore implements the gorilla/sessions Store interface on top of
+// the Rails cookie crypto, so apps built on gorilla share their sessions
+// with a Rails app. The session name is the name of the Rails cookie:
+//
+//	store := session.NewGorillaStore(secretKeyBase, session.Rails7)
+//	s, err := store.Get(r, "_myapp_session")
+//	userID := s.Values["user_id"]
+//
+// The serializer is the one of the preset, which can be overridden to
+// match the app's cookies_serializer. Since Rails sessions are hashes of
+// strings, the keys of the saved values must be strings.
+type GorillaStore struct {
+	// Options are the default attributes of the session cookies.
+	Options *sessions.Options
+
+	codec *Codec
+}
+
+// NewGorillaStore returns a store for the sessions of an app using
+// secretKeyBase and the preset configuration.
+func NewGorillaStore(secretKeyBase string, preset Preset) *GorillaStore {
+	return &GorillaStore{
+		Options: &sessions.Options{
+			Path:     "/",
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		},
+		codec: NewCodec(secretKeyBase, "", preset),
+	}
+}
+
+// Rotate accepts the sessions encrypted with another secret_key_base or
+// preset, see Codec.Rotate.
+func (s *GorillaStore) Rotate(secretKeyBase string, preset Preset) {
+	s.codec.Rotate(secretKeyBase, preset)
+}
+
+// Get returns the named session, cached in the request's registry.
+func (s *GorillaStore) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return sessions.GetRegistry(r).Get(s, name)
+}
+
+// New returns the named session decrypted from the request cookie, or a
+// new session if the cookie is missing or invalid. The decoding error is
+// returned along with the new session.
+func (s *GorillaStore) New(r *http.Request, name string) (*sessions.Session, error) {
+	session := sessions.NewSession(s, name)
+	opts := *s.Options
+	session.Options = &opts
+	session.IsNew = true
+
+	var values map[string]interface{}
+	if err := ReadSessionCookie(r, s.codecFor(name, 0), &values); err != nil {
+		if err == http.ErrNoCookie {
+			return session, nil
+		}
+		return session, err
+	}
+	for k, v := range values {
+		session.Values[k] = v
+	}
+	session.IsNew = false
+	return session, nil
+}
+
+// Save encrypts the session into the response cookie, or deletes the
+// cookie if the session's MaxAge is negative. ErrCookieOverflow is
+// returned, and no cookie is set, if the session is too large.
+func (s *GorillaStore) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	if session.Options.MaxAge < 0 {
+		http.SetCookie(w, sessions.NewCookie(session.Name(), "", session.Options))
+		return nil
+	}
+	values := make(map[string]interface{}, len(session.Values))
+	for k, v := range session.Values {
+		key, ok := k.(string)
+		if !ok {
+			return fmt.Errorf("session: non string key %v", k)
+		}
+		values[key] = v
+	}
+	expireAfter := time.Duration(session.Options.MaxAge) * time.Second
+	value, err := s.codecFor(session.Name(), expireAfter).Encode(values)
+	if err != nil {
+		return err
+	}
+	if err := checkCookieSize(session.Name(), value); err != nil {
+		return err
+	}
+	http.SetCookie(w, sessions.NewCookie(session.Name(), url.QueryEscape(value), session.Options))
+	return nil
+}
+
+// codecFor returns a copy of the store's codec for a cookie name, sharing
+// its encryptor.
+func (s *GorillaStore) codecFor(name string, expireAfter time.Duration) *Codec {
+	codec := *s.codec
+	codec.CookieName = name
+	codec.ExpireAfter = expireAfter
+	return &codec
+}
@@ -0,0 +1,84 @@
+package session
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestGorillaStore(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("GorillaStore", func() {
+		store := NewGorillaStore(secretKeyBase, Rails52)
+
+		g.It("reads the sessions written by Rails", func() {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.AddCookie(&http.Cookie{Name: "_app_session", Value: url.QueryEscape(rails52Cookie)})
+			s, err := store.Get(req, "_app_session")
+			g.Assert(err).Eql(nil)
+			g.Assert(s.IsNew).IsFalse()
+			g.Assert(s.Values["session_id"]).Eql("b2d63c07ea7a9d58e415e3672e3f31a2")
+		})
+
+		g.It("returns a new session when the cookie is missing", func() {
+			s, err := store.New(httptest.NewRequest("GET", "/", nil), "_app_session")
+			g.Assert(err).Eql(nil)
+			g.Assert(s.IsNew).IsTrue()
+			g.Assert(len(s.Values)).Eql(0)
+		})
+
+		g.It("returns the decoding error of invalid cookies", func() {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.AddCookie(&http.Cookie{Name: "_app_session", Value: "tampered"})
+			s, err := store.New(req, "_app_session")
+			g.Assert(err != nil).IsTrue()
+			g.Assert(s.IsNew).IsTrue()
+		})
+
+		g.It("saves sessions Rails can read", func() {
+			s, _ := store.New(httptest.NewRequest("GET", "/", nil), "_app_session")
+			s.Values["user_id"] = "42"
+			rec := httptest.NewRecorder()
+			g.Assert(s.Save(httptest.NewRequest("GET", "/", nil), rec)).Eql(nil)
+
+			cookies := rec.Result().Cookies()
+			g.Assert(len(cookies)).Eql(1)
+			g.Assert(cookies[0].HttpOnly).IsTrue()
+			value, _ := url.QueryUnescape(cookies[0].Value)
+			var session map[string]string
+			g.Assert(NewCodec(secretKeyBase, "_app_session", Rails52).Decode(value, &session)).Eql(nil)
+			g.Assert(session["user_id"]).Eql("42")
+		})
+
+		g.It("refuses non string keys", func() {
+			s, _ := store.New(httptest.NewRequest("GET", "/", nil), "_app_session")
+			s.Values[42] = "user"
+			g.Assert(s.Save(httptest.NewRequest("GET", "/", nil), httptest.NewRecorder()) != nil).IsTrue()
+		})
+
+		g.It("refuses the sessions larger than MaxCookieSize", func() {
+			s, _ := store.New(httptest.NewRequest("GET", "/", nil), "_app_session")
+			s.Values["notes"] = strings.Repeat("a", MaxCookieSize)
+			rec := httptest.NewRecorder()
+			err := s.Save(httptest.NewRequest("GET", "/", nil), rec)
+			g.Assert(errors.Is(err, ErrCookieOverflow)).IsTrue()
+			g.Assert(rec.Header().Get("Set-Cookie")).Eql("")
+		})
+
+		g.It("deletes the cookie of sessions with a negative MaxAge", func() {
+			s, _ := store.New(httptest.NewRequest("GET", "/", nil), "_app_session")
+			s.Options.MaxAge = -1
+			rec := httptest.NewRecorder()
+			g.Assert(s.Save(httptest.NewRequest("GET", "/", nil), rec)).Eql(nil)
+			cookies := rec.Result().Cookies()
+			g.Assert(cookies[0].Value).Eql("")
+			g.Assert(cookies[0].MaxAge < 0).IsTrue()
+		})
+	})
+}
@@ -0,0 +1,19 @@
+package session
+
+import "github.com/mattetti/goRailsYourself/notifications"
+
+// instrument runs fn as an event of the default notifier. The payload is
+// only built if the event is listened to.
+func instrument(name string, payload func() map[string]interface{}, fn func() error) error {
+	if !notifications.Listening(name) {
+		return fn()
+	}
+	return notifications.Instrument(name, payload(), func(map[string]interface{}) error {
+		return fn()
+	})
+}
+
+// payload returns the information about the codec sent with its events.
+func (c *Codec) payload() map[string]interface{} {
+	return map[string]interface{}{"cookie": c.CookieName, "cipher": c.preset.Cipher}
+}
@@ -0,0 +1,38 @@
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	. "github.com/franela/goblin"
+	"github.com/mattetti/goRailsYourself/notifications"
+)
+
+func TestInstrumentation(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Instrumentation", func() {
+		g.It("Should publish the session encoding and decoding events", func() {
+			var events []notifications.Event
+			sub := notifications.SubscribeRegexp(regexp.MustCompile(`\.session_codec$`), func(e notifications.Event) { events = append(events, e) })
+			defer notifications.Unsubscribe(sub)
+
+			codec := NewCodec(secretKeyBase, "_app_session", Rails7)
+			handler := codec.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				FromContext(r.Context())["user_id"] = float64(42)
+			}))
+			req := httptest.NewRequest("GET", "/", nil)
+			req.AddCookie(&http.Cookie{Name: "_app_session", Value: "tampered"})
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			g.Assert(len(events)).Eql(2)
+			g.Assert(events[0].Name).Eql("decode.session_codec")
+			g.Assert(events[0].Err != nil).IsTrue()
+			g.Assert(events[1].Name).Eql("encode.session_codec")
+			g.Assert(events[1].Err).Eql(nil)
+			g.Assert(events[1].Payload).Eql(map[string]interface{}{"cookie": "_app_session", "cipher": "aes-256-gcm"})
+		})
+	})
+}
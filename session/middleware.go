@@ -0,0 +1,100 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+type contextKey struct{}
+
+// FromContext returns the session of a request handled by the codec's
+// middleware, or nil.
+func FromContext(ctx context.Context) Session {
+	s, _ := ctx.Value(contextKey{}).(Session)
+	return s
+}
+
+// Middleware decrypts the session cookie of the requests into a Session
+// available to next using FromContext. Changes to the session are
+// encrypted back into the cookie of the response, before its headers are
+// written:
+//
+//	codec := session.NewCodec(secretKeyBase, "_myapp_session", session.Rails7)
+//	http.Handle("/", codec.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//		s := session.FromContext(r.Context())
+//		s["last_seen_by"] = "go"
+//	})))
+//
+// Like Rails, invalid or tampered cookies are ignored and the request
+// gets an empty session. The errors writing the session are passed to the
+// codec's OnWriteError.
+func (c *Codec) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var s Session
+		if ReadSessionCookie(r, c, &s) != nil || s == nil {
+			s = Session{}
+		}
+		original, _ := json.Marshal(s)
+		sw := &sessionWriter{ResponseWriter: w, request: r, codec: c, session: s, original: original}
+		next.ServeHTTP(sw, r.WithContext(context.WithValue(r.Context(), contextKey{}, s)))
+		sw.commit()
+	})
+}
+
+// sessionWriter sets the session cookie before the response headers are
+// written, if the session changed.
+type sessionWriter struct {
+	http.ResponseWriter
+	request   *http.Request
+	codec     *Codec
+	session   Session
+	original  []byte
+	committed bool
+}
+
+func (w *sessionWriter) commit() {
+	if w.committed {
+		return
+	}
+	w.committed = true
+	current, err := json.Marshal(w.session)
+	if err == nil && string(current) == string(w.original) {
+		return
+	}
+	if err == nil {
+		err = WriteSessionCookie(w.ResponseWriter, w.codec, w.session)
+	}
+	if err == nil {
+		return
+	}
+	if w.codec.OnWriteError != nil {
+		w.codec.OnWriteError(w.request, err)
+		return
+	}
+	log.Printf("session: can't write the %s cookie: %v", w.codec.CookieName, err)
+}
+
+func (w *sessionWriter) WriteHeader(status int) {
+	w.commit()
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *sessionWriter) Write(b []byte) (int, error) {
+	w.commit()
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher if the underlying writer does.
+func (w *sessionWriter) Flush() {
+	w.commit()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController.
+func (w *sessionWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
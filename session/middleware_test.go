@@ -0,0 +1,98 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestMiddleware(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Middleware", func() {
+		codec := NewCodec(secretKeyBase, "_app_session", Rails52)
+
+		g.It("decrypts the session cookie into the request context", func() {
+			var sessionID interface{}
+			handler := codec.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				sessionID = FromContext(r.Context())["session_id"]
+			}))
+			req := httptest.NewRequest("GET", "/", nil)
+			req.AddCookie(&http.Cookie{Name: "_app_session", Value: url.QueryEscape(rails52Cookie)})
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			g.Assert(sessionID).Eql("b2d63c07ea7a9d58e415e3672e3f31a2")
+			g.Assert(rec.Header().Get("Set-Cookie")).Eql("")
+		})
+
+		g.It("encrypts the changed sessions into the response cookie", func() {
+			handler := codec.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				FromContext(r.Context())["user_id"] = float64(42)
+				w.WriteHeader(http.StatusCreated)
+			}))
+			req := httptest.NewRequest("GET", "/", nil)
+			req.AddCookie(&http.Cookie{Name: "_app_session", Value: url.QueryEscape(rails52Cookie)})
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			cookies := rec.Result().Cookies()
+			g.Assert(len(cookies)).Eql(1)
+			g.Assert(cookies[0].HttpOnly).IsTrue()
+			value, err := url.QueryUnescape(cookies[0].Value)
+			g.Assert(err).Eql(nil)
+			var session map[string]interface{}
+			g.Assert(codec.Decode(value, &session)).Eql(nil)
+			g.Assert(session["user_id"]).Eql(float64(42))
+			g.Assert(session["session_id"]).Eql("b2d63c07ea7a9d58e415e3672e3f31a2")
+		})
+
+		g.It("starts an empty session when the cookie is invalid", func() {
+			var session Session
+			handler := codec.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				session = FromContext(r.Context())
+			}))
+			req := httptest.NewRequest("GET", "/", nil)
+			req.AddCookie(&http.Cookie{Name: "_app_session", Value: "tampered"})
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			g.Assert(session).Eql(Session{})
+		})
+
+		g.It("reports the sessions too large for the cookie", func() {
+			codec := NewCodec(secretKeyBase, "_app_session", Rails52)
+			var writeErr error
+			codec.OnWriteError = func(r *http.Request, err error) { writeErr = err }
+			handler := codec.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				FromContext(r.Context())["notes"] = strings.Repeat("a", MaxCookieSize)
+			}))
+			req := httptest.NewRequest("GET", "/", nil)
+			req.AddCookie(&http.Cookie{Name: "_app_session", Value: url.QueryEscape(rails52Cookie)})
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			g.Assert(errors.Is(writeErr, ErrCookieOverflow)).IsTrue()
+			g.Assert(rec.Header().Get("Set-Cookie")).Eql("")
+		})
+
+		g.It("returns a nil session outside of the middleware", func() {
+			req := httptest.NewRequest("GET", "/", nil)
+			g.Assert(FromContext(req.Context()) == nil).IsTrue()
+		})
+	})
+}
+
+func ExampleCodec_Middleware() {
+	codec := NewCodec(secretKeyBase, "_app_session", Rails52)
+	handler := codec.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Println(FromContext(r.Context())["session_id"])
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(&http.Cookie{Name: "_app_session", Value: url.QueryEscape(rails52Cookie)})
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	// Output: b2d63c07ea7a9d58e415e3672e3f31a2
+}
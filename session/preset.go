@@ -0,0 +1,128 @@
+package session
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+
+	"github.com/mattetti/goRailsYourself/crypto"
+	"github.com/mattetti/goRailsYourself/version"
+)
+
+// Preset is the cookie store configuration of a Rails version: how the
+// keys are derived from secret_key_base and how the session is encrypted
+// and serialized. The fields can be changed to match an application
+// overriding the defaults, for instance to use crypto.MarshalMsgSerializer.
+type Preset struct {
+	// Cipher is "aes-cbc" or "aes-256-gcm".
+	Cipher string
+	// EncryptedCookieSalt and EncryptedSignedCookieSalt derive the keys of
+	// the aes-cbc cipher, config.action_dispatch.encrypted_cookie_salt and
+	// encrypted_signed_cookie_salt in Rails.
+	EncryptedCookieSalt       string
+	EncryptedSignedCookieSalt string
+	// AuthenticatedEncryptedCookieSalt derives the key of the aes-256-gcm
+	// cipher, config.action_dispatch.authenticated_encrypted_cookie_salt in
+	// Rails.
+	AuthenticatedEncryptedCookieSalt string
+	// Iterations is the number of PBKDF2 iterations.
+	Iterations int
+	// KeyDigest is the PBKDF2 digest,
+	// config.active_support.key_generator_hash_digest_class in Rails.
+	KeyDigest func() hash.Hash
+	// Serializer is config.action_dispatch.cookies_serializer.
+	Serializer crypto.MsgSerializer
+	// Metadata embeds the cookie purpose in the session like Rails 6+,
+	// config.action_dispatch.use_cookies_with_metadata in Rails.
+	Metadata bool
+}
+
+var (
+	// Rails4 is the configuration of Rails 4.1 to 5.1 apps using the JSON
+	// cookies serializer.
+	Rails4 = Preset{
+		Cipher:                    "aes-cbc",
+		EncryptedCookieSalt:       "encrypted cookie",
+		EncryptedSignedCookieSalt: "signed encrypted cookie",
+		Iterations:                1000,
+		KeyDigest:                 sha1.New,
+		Serializer:                crypto.JsonMsgSerializer{},
+	}
+	// Rails52 is the configuration of Rails 5.2 apps.
+	Rails52 = Preset{
+		Cipher:                           "aes-256-gcm",
+		AuthenticatedEncryptedCookieSalt: "authenticated encrypted cookie",
+		Iterations:                       1000,
+		KeyDigest:                        sha1.New,
+		Serializer:                       crypto.JsonMsgSerializer{},
+	}
+	// Rails6 is the configuration of Rails 6.0 and 6.1 apps.
+	Rails6 = Preset{
+		Cipher:                           "aes-256-gcm",
+		AuthenticatedEncryptedCookieSalt: "authenticated encrypted cookie",
+		Iterations:                       1000,
+		KeyDigest:                        sha1.New,
+		Serializer:                       crypto.JsonMsgSerializer{},
+		Metadata:                         true,
+	}
+	// Rails7 is the configuration of Rails 7+ apps, which derive their
+	// keys using sha256.
+	Rails7 = Preset{
+		Cipher:                           "aes-256-gcm",
+		AuthenticatedEncryptedCookieSalt: "authenticated encrypted cookie",
+		Iterations:                       1000,
+		KeyDigest:                        sha256.New,
+		Serializer:                       crypto.JsonMsgSerializer{},
+		Metadata:                         true,
+	}
+)
+
+// PresetFor returns the preset of a Rails version such as "6.1.7". The
+// versions before 4.1 aren't supported since they have no secret_key_base.
+//
+//	preset, err := PresetFor("7.1.3")
+func PresetFor(railsVersion string) (Preset, error) {
+	v, err := version.Parse(railsVersion)
+	if err != nil {
+		return Preset{}, err
+	}
+	// the prereleases of a version use its configuration
+	v = v.Release()
+	switch {
+	case v.Compare(version.MustParse("4.1")) < 0:
+		return Preset{}, fmt.Errorf("session: Rails %s isn't supported", railsVersion)
+	case v.Compare(version.MustParse("5.2")) < 0:
+		return Rails4, nil
+	case v.Compare(version.MustParse("6.0")) < 0:
+		return Rails52, nil
+	case v.Compare(version.MustParse("7.0")) < 0:
+		return Rails6, nil
+	}
+	return Rails7, nil
+}
+
+// encryptor returns the encryptor of the preset. The verifier of the
+// aes-cbc cipher is set upfront so the encryptor can be used concurrently.
+func (p Preset) encryptor(secretKeyBase string) *crypto.MessageEncryptor {
+	kg := crypto.KeyGenerator{Secret: secretKeyBase, Iterations: p.Iterations, HashDigest: p.KeyDigest}
+	serializer := p.Serializer
+	if serializer == nil {
+		serializer = crypto.JsonMsgSerializer{}
+	}
+	if p.Cipher == "aes-256-gcm" {
+		return &crypto.MessageEncryptor{
+			Key:        kg.Generate([]byte(p.AuthenticatedEncryptedCookieSalt), 32),
+			Cipher:     p.Cipher,
+			Serializer: serializer,
+		}
+	}
+	signKey := kg.Generate([]byte(p.EncryptedSignedCookieSalt), 64)
+	return &crypto.MessageEncryptor{
+		Key:        kg.Generate([]byte(p.EncryptedCookieSalt), 32),
+		SignKey:    signKey,
+		Cipher:     "aes-cbc",
+		Verifier:   &crypto.MessageVerifier{Secret: signKey, Hasher: sha1.New, Serializer: crypto.NullMsgSerializer{}},
+		Serializer: serializer,
+	}
+}
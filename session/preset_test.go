@@ -0,0 +1,46 @@
+package session
+
+import (
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestPresetFor(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("PresetFor", func() {
+		g.It("returns the preset of the Rails version", func() {
+			examples := map[string]Preset{
+				"4.1.0":     Rails4,
+				"5.1.7":     Rails4,
+				"5.2.8.1":   Rails52,
+				"6.0.0.rc1": Rails6,
+				"6.1.7":     Rails6,
+				"7.0.8":     Rails7,
+				"7.1.3":     Rails7,
+			}
+			for v, expected := range examples {
+				preset, err := PresetFor(v)
+				g.Assert(err).Eql(nil)
+				g.Assert(preset.Cipher).Eql(expected.Cipher)
+				g.Assert(preset.Metadata).Eql(expected.Metadata)
+				g.Assert(preset.AuthenticatedEncryptedCookieSalt).Eql(expected.AuthenticatedEncryptedCookieSalt)
+			}
+		})
+
+		g.It("tells Rails 6 and 7 apart by their key digest", func() {
+			rails6, _ := PresetFor("6.1.7")
+			rails7, _ := PresetFor("7.0.0")
+			g.Assert(rails6.KeyDigest().Size()).Eql(20)
+			g.Assert(rails7.KeyDigest().Size()).Eql(32)
+		})
+
+		g.It("refuses unsupported versions", func() {
+			_, err := PresetFor("4.0.13")
+			g.Assert(err != nil).IsTrue()
+			_, err = PresetFor("not a version")
+			g.Assert(err != nil).IsTrue()
+		})
+	})
+}
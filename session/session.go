@@ -0,0 +1,110 @@
+package session
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// Session is the content of a Rails session. It decodes from and encodes
+// to the hash Rails stores in the cookie, and its accessors read the keys
+// Rails and its common gems use:
+//
+//	var s session.Session
+//	err := session.ReadSessionCookie(r, codec, &s)
+//	id, ok := s.UserID()
+type Session map[string]interface{}
+
+// SessionID returns the id of the session, or an empty string.
+func (s Session) SessionID() string {
+	id, _ := s["session_id"].(string)
+	return id
+}
+
+// CSRFToken returns the CSRF token of the session, or an empty string.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActionController/RequestForgeryProtection.html
+func (s Session) CSRFToken() string {
+	token, _ := s["_csrf_token"].(string)
+	return token
+}
+
+// Flash returns the flash messages available to the current request, the
+// ones Rails didn't mark for discarding at the end of the previous one.
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActionDispatch/Flash/FlashHash.html
+func (s Session) Flash() map[string]interface{} {
+	flash, ok := s["flash"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	flashes, _ := flash["flashes"].(map[string]interface{})
+	discard, _ := flash["discard"].([]interface{})
+	messages := make(map[string]interface{}, len(flashes))
+	for k, v := range flashes {
+		messages[k] = v
+	}
+	for _, k := range discard {
+		if key, ok := k.(string); ok {
+			delete(messages, key)
+		}
+	}
+	return messages
+}
+
+// UserID returns the user id stored at path, "user_id" by default. The path
+// walks nested hashes by key and arrays by index, so the id Devise stores
+// is found with:
+//
+//	id, ok := s.UserID("warden.user.user.key", "0", "0")
+//
+// Numeric ids are formatted in base 10.
+func (s Session) UserID(path ...string) (string, bool) {
+	if len(path) == 0 {
+		path = []string{"user_id"}
+	}
+	var value interface{} = map[string]interface{}(s)
+	for _, key := range path {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[key]
+		case Session:
+			value = v[key]
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return "", false
+			}
+			value = v[i]
+		default:
+			return "", false
+		}
+	}
+	return formatID(value)
+}
+
+// formatID returns the string form of a record id.
+func formatID(value interface{}) (string, bool) {
+	switch id := value.(type) {
+	case string:
+		return id, true
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(id), true
+	case int64:
+		return strconv.FormatInt(id, 10), true
+	case json.Number:
+		return id.String(), true
+	}
+	return "", false
+}
+
+// Set stores a value in the session.
+func (s Session) Set(key string, value interface{}) {
+	s[key] = value
+}
+
+// Delete removes a key from the session.
+func (s Session) Delete(key string) {
+	delete(s, key)
+}
@@ -0,0 +1,84 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+// a Rails session after a sign in through Devise and a redirect with a notice
+const railsSession = `{
+	"session_id": "b2d63c07ea7a9d58e415e3672e3f31a2",
+	"_csrf_token": "t8Bn2ZCBpUFEZhEUvvsjYVo9YnRoIJX2cPQR3sFCLwE=",
+	"warden.user.user.key": [[42], "$2a$12$uWb0pIpeSHNdDI5Lrpa39e"],
+	"flash": {"discard": ["alert"], "flashes": {"notice": "Signed in successfully.", "alert": "old"}}
+}`
+
+func TestSession(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("Session", func() {
+		var s Session
+		g.BeforeEach(func() {
+			s = nil
+			if err := json.Unmarshal([]byte(railsSession), &s); err != nil {
+				panic(err)
+			}
+		})
+
+		g.It("returns the session id and CSRF token", func() {
+			g.Assert(s.SessionID()).Eql("b2d63c07ea7a9d58e415e3672e3f31a2")
+			g.Assert(s.CSRFToken()).Eql("t8Bn2ZCBpUFEZhEUvvsjYVo9YnRoIJX2cPQR3sFCLwE=")
+			g.Assert(Session{}.SessionID()).Eql("")
+		})
+
+		g.It("returns the flash messages which weren't discarded", func() {
+			g.Assert(s.Flash()).Eql(map[string]interface{}{"notice": "Signed in successfully."})
+			g.Assert(Session{}.Flash() == nil).IsTrue()
+		})
+
+		g.It("returns the user id at a path", func() {
+			id, ok := s.UserID("warden.user.user.key", "0", "0")
+			g.Assert(ok).IsTrue()
+			g.Assert(id).Eql("42")
+			s.Set("user_id", "7")
+			id, ok = s.UserID()
+			g.Assert(ok).IsTrue()
+			g.Assert(id).Eql("7")
+		})
+
+		g.It("reports missing user ids", func() {
+			for _, path := range [][]string{{}, {"warden.user.user.key", "2"}, {"warden.user.user.key", "x"}, {"session_id", "0"}} {
+				_, ok := s.UserID(path...)
+				g.Assert(ok).IsFalse()
+			}
+		})
+
+		g.It("marshals back to the Rails shape", func() {
+			s.Set("user_id", 42)
+			s.Delete("flash")
+			data, _ := json.Marshal(s)
+			var decoded map[string]interface{}
+			json.Unmarshal(data, &decoded)
+			g.Assert(decoded["user_id"]).Eql(float64(42))
+			g.Assert(decoded["warden.user.user.key"]).Eql([]interface{}{[]interface{}{float64(42)}, "$2a$12$uWb0pIpeSHNdDI5Lrpa39e"})
+			_, ok := decoded["flash"]
+			g.Assert(ok).IsFalse()
+		})
+	})
+}
+
+func ExampleSession_UserID() {
+	codec := NewCodec(secretKeyBase, "_app_session", Rails52)
+	var s Session
+	codec.Decode(rails52Cookie, &s)
+
+	fmt.Println(s.SessionID())
+	_, ok := s.UserID()
+	fmt.Println(ok)
+	// Output:
+	// b2d63c07ea7a9d58e415e3672e3f31a2
+	// false
+}
@@ -0,0 +1,74 @@
+package session
+
+import (
+	"crypto/subtle"
+	"sort"
+	"strings"
+)
+
+// WardenUser is a user Warden stored in the session after authenticating
+// it, as [[id], salt] under the "warden.user.<scope>.key" key.
+//
+// Devise documentation: https://www.rubydoc.info/github/heartcombo/devise/Devise/Models/Authenticatable/ClassMethods#serialize_into_session-instance_method
+type WardenUser struct {
+	// Scope is the Devise scope of the user, such as "user" or "admin".
+	Scope string
+	// ID is the primary key of the user.
+	ID string
+	// Salt is the authenticatable_salt of the user when it signed in: the
+	// first 29 characters of its bcrypt password digest.
+	Salt string
+}
+
+// WardenUser returns the user authenticated in scope, or in any scope if
+// scope is empty.
+//
+//	user, ok := s.WardenUser("user")
+//	if !ok || !user.ValidSalt(encryptedPassword) {
+//		// not signed in
+//	}
+func (s Session) WardenUser(scope string) (WardenUser, bool) {
+	if scope != "" {
+		return s.wardenUser(scope)
+	}
+	var scopes []string
+	for key := range s {
+		if strings.HasPrefix(key, "warden.user.") && strings.HasSuffix(key, ".key") && len(key) > len("warden.user..key") {
+			scopes = append(scopes, key[len("warden.user."):len(key)-len(".key")])
+		}
+	}
+	sort.Strings(scopes)
+	for _, scope := range scopes {
+		if user, ok := s.wardenUser(scope); ok {
+			return user, true
+		}
+	}
+	return WardenUser{}, false
+}
+
+func (s Session) wardenUser(scope string) (WardenUser, bool) {
+	entry, ok := s["warden.user."+scope+".key"].([]interface{})
+	if !ok || len(entry) != 2 {
+		return WardenUser{}, false
+	}
+	key, ok := entry[0].([]interface{})
+	if !ok || len(key) != 1 {
+		return WardenUser{}, false
+	}
+	id, ok := formatID(key[0])
+	if !ok {
+		return WardenUser{}, false
+	}
+	salt, _ := entry[1].(string)
+	return WardenUser{Scope: scope, ID: id, Salt: salt}, true
+}
+
+// ValidSalt reports whether the user's salt matches the bcrypt password
+// digest of the user record, the encrypted_password column of Devise. Like
+// Devise, it invalidates the sessions opened before a password change.
+func (u WardenUser) ValidSalt(passwordDigest string) bool {
+	if u.Salt == "" || len(passwordDigest) < 29 {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(u.Salt), []byte(passwordDigest[:29])) == 1
+}
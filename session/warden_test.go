@@ -0,0 +1,47 @@
+package session
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestWardenUser(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("WardenUser", func() {
+		var s Session
+		g.BeforeEach(func() {
+			s = nil
+			if err := json.Unmarshal([]byte(railsSession), &s); err != nil {
+				panic(err)
+			}
+		})
+
+		g.It("extracts the user of a scope", func() {
+			user, ok := s.WardenUser("user")
+			g.Assert(ok).IsTrue()
+			g.Assert(user).Eql(WardenUser{Scope: "user", ID: "42", Salt: "$2a$12$uWb0pIpeSHNdDI5Lrpa39e"})
+			_, ok = s.WardenUser("admin")
+			g.Assert(ok).IsFalse()
+		})
+
+		g.It("finds the scope of the user", func() {
+			user, ok := s.WardenUser("")
+			g.Assert(ok).IsTrue()
+			g.Assert(user.Scope).Eql("user")
+			_, ok = Session{"session_id": "abc"}.WardenUser("")
+			g.Assert(ok).IsFalse()
+		})
+
+		g.It("validates the salt against the password digest", func() {
+			user, _ := s.WardenUser("user")
+			g.Assert(user.ValidSalt("$2a$12$uWb0pIpeSHNdDI5Lrpa39eLiFQkiYfvPkJjXc2aSRWiWvSpcIPgWC")).IsTrue()
+			// the password changed since the user signed in
+			g.Assert(user.ValidSalt("$2a$12$Jy9sn6bVZDu4KOq7aY4Ly.8xtWGSvRhnOKeGFlM0ltCj0gZiH1ZUm")).IsFalse()
+			g.Assert(user.ValidSalt("")).IsFalse()
+			g.Assert(WardenUser{}.ValidSalt("$2a$12$uWb0pIpeSHNdDI5Lrpa39eLiFQkiYfvPkJjXc2aSRWiWvSpcIPgWC")).IsFalse()
+		})
+	})
+}
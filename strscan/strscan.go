@@ -0,0 +1,278 @@
+// The strscan package ports Ruby's StringScanner, providing lexical
+// scanning operations on a string. It makes it easier to port Ruby
+// parsers which are written against strscan.
+//
+// Ruby documentation http://ruby-doc.org/stdlib/libdoc/strscan/rdoc/StringScanner.html
+package strscan
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	"github.com/mattetti/goRailsYourself/internal/recache"
+)
+
+// Scanner scans a string, keeping track of its position and of the last
+// match.
+//
+//	s := New("This is an example string")
+//	s.EOS()               // => false
+//	s.Scan(`\w+`)         // => "This", true
+//	s.Scan(`\w+`)         // => "", false
+//	s.Scan(`\s+`)         // => " ", true
+//	s.ScanUntil(`ex`)     // => "is an ex", true
+type Scanner struct {
+	str string
+	pos int
+	// prev is the position before the last match, used by Unscan.
+	prev int
+	// match holds the submatch indexes of the last match, nil if the last
+	// scan failed.
+	match []int
+}
+
+// New returns a scanner positioned at the start of str.
+func New(str string) *Scanner {
+	return &Scanner{str: str}
+}
+
+// do runs the pattern from the current position and records the match.
+// It returns the length from the current position to the end of the
+// match.
+func (s *Scanner) do(pattern string, anchored, advance bool) (int, bool) {
+	// anchor the pattern so it only matches at the current position.
+	if anchored {
+		pattern = `^(?:` + pattern + `)`
+	}
+	re := recache.MustCompile(pattern)
+	loc := re.FindStringSubmatchIndex(s.str[s.pos:])
+	if loc == nil {
+		s.match = nil
+		return 0, false
+	}
+	for i := range loc {
+		if loc[i] >= 0 {
+			loc[i] += s.pos
+		}
+	}
+	s.match = loc
+	s.prev = s.pos
+	length := loc[1] - s.pos
+	if advance {
+		s.pos = loc[1]
+	}
+	return length, true
+}
+
+// Scan tries to match the pattern at the current position. If there's a
+// match, the scanner advances and the matched string is returned.
+func (s *Scanner) Scan(pattern string) (string, bool) {
+	if _, ok := s.do(pattern, true, true); !ok {
+		return "", false
+	}
+	return s.Matched(), true
+}
+
+// ScanUntil scans the string until the pattern is matched and returns the
+// substring up to and including the end of the match, advancing the
+// scanner.
+func (s *Scanner) ScanUntil(pattern string) (string, bool) {
+	start := s.pos
+	if _, ok := s.do(pattern, false, true); !ok {
+		return "", false
+	}
+	return s.str[start:s.pos], true
+}
+
+// Check returns the value Scan would return, without advancing the scanner.
+func (s *Scanner) Check(pattern string) (string, bool) {
+	if _, ok := s.do(pattern, true, false); !ok {
+		return "", false
+	}
+	return s.Matched(), true
+}
+
+// CheckUntil returns the value ScanUntil would return, without advancing the
+// scanner.
+func (s *Scanner) CheckUntil(pattern string) (string, bool) {
+	n, ok := s.do(pattern, false, false)
+	if !ok {
+		return "", false
+	}
+	return s.str[s.pos : s.pos+n], true
+}
+
+// Skip attempts to skip over the pattern at the current position and
+// returns the length of the match.
+func (s *Scanner) Skip(pattern string) (int, bool) {
+	return s.do(pattern, true, true)
+}
+
+// SkipUntil advances the scanner until the pattern is matched and returns
+// the number of bytes advanced.
+func (s *Scanner) SkipUntil(pattern string) (int, bool) {
+	return s.do(pattern, false, true)
+}
+
+// Match tests whether the pattern matches at the current position and
+// returns the length of the match, without advancing the scanner.
+func (s *Scanner) Match(pattern string) (int, bool) {
+	return s.do(pattern, true, false)
+}
+
+// Exist tests whether the pattern matches anywhere after the current
+// position and returns the length up to the end of the match, without
+// advancing the scanner.
+func (s *Scanner) Exist(pattern string) (int, bool) {
+	return s.do(pattern, false, false)
+}
+
+// Getch scans one character (which can be several bytes long) and returns
+// it.
+func (s *Scanner) Getch() (string, bool) {
+	if s.EOS() {
+		s.match = nil
+		return "", false
+	}
+	_, size := utf8.DecodeRuneInString(s.str[s.pos:])
+	s.prev = s.pos
+	s.match = []int{s.pos, s.pos + size}
+	s.pos += size
+	return s.Matched(), true
+}
+
+// Peek returns the next n bytes without advancing the scanner.
+func (s *Scanner) Peek(n int) string {
+	end := s.pos + n
+	if end > len(s.str) {
+		end = len(s.str)
+	}
+	return s.str[s.pos:end]
+}
+
+// Unscan sets the scanner back to the position it had before the last
+// successful scan.
+func (s *Scanner) Unscan() error {
+	if s.match == nil {
+		return errors.New("unscan error: no previous match")
+	}
+	s.pos = s.prev
+	s.match = nil
+	return nil
+}
+
+// Pos returns the byte position of the scanner.
+func (s *Scanner) Pos() int {
+	return s.pos
+}
+
+// SetPos sets the byte position of the scanner.
+func (s *Scanner) SetPos(pos int) error {
+	if pos < 0 {
+		pos += len(s.str)
+	}
+	if pos < 0 || pos > len(s.str) {
+		return errors.New("index out of range")
+	}
+	s.pos = pos
+	return nil
+}
+
+// IsMatched reports whether the last match was successful.
+func (s *Scanner) IsMatched() bool {
+	return s.match != nil
+}
+
+// Matched returns the last matched string, or an empty string if the last
+// match failed.
+func (s *Scanner) Matched() string {
+	if s.match == nil {
+		return ""
+	}
+	return s.str[s.match[0]:s.match[1]]
+}
+
+// MatchedSize returns the size of the last matched string, or -1 if the
+// last match failed.
+func (s *Scanner) MatchedSize() int {
+	if s.match == nil {
+		return -1
+	}
+	return s.match[1] - s.match[0]
+}
+
+// Submatch returns the i-th subgroup of the last match, 0 being the whole
+// match. An empty string is returned if the group didn't participate.
+func (s *Scanner) Submatch(i int) string {
+	if s.match == nil || 2*i+1 >= len(s.match) || s.match[2*i] < 0 {
+		return ""
+	}
+	return s.str[s.match[2*i]:s.match[2*i+1]]
+}
+
+// PreMatch returns the part of the string before the last match.
+func (s *Scanner) PreMatch() string {
+	if s.match == nil {
+		return ""
+	}
+	return s.str[:s.match[0]]
+}
+
+// PostMatch returns the part of the string after the last match.
+func (s *Scanner) PostMatch() string {
+	if s.match == nil {
+		return ""
+	}
+	return s.str[s.match[1]:]
+}
+
+// EOS reports whether the scanner reached the end of the string.
+func (s *Scanner) EOS() bool {
+	return s.pos >= len(s.str)
+}
+
+// BOL reports whether the scanner is at the beginning of a line.
+func (s *Scanner) BOL() bool {
+	return s.pos == 0 || s.str[s.pos-1] == '\n'
+}
+
+// Rest returns the rest of the string, after the current position.
+func (s *Scanner) Rest() string {
+	return s.str[s.pos:]
+}
+
+// RestSize returns the size of the rest of the string.
+func (s *Scanner) RestSize() int {
+	return len(s.str) - s.pos
+}
+
+// Reset moves the scanner back to the start of the string and clears the
+// match data.
+func (s *Scanner) Reset() {
+	s.pos = 0
+	s.match = nil
+}
+
+// Terminate moves the scanner to the end of the string and clears the
+// match data.
+func (s *Scanner) Terminate() {
+	s.pos = len(s.str)
+	s.match = nil
+}
+
+// String returns the string being scanned.
+func (s *Scanner) String() string {
+	return s.str
+}
+
+// SetString changes the string being scanned and resets the scanner.
+func (s *Scanner) SetString(str string) {
+	s.str = str
+	s.Reset()
+}
+
+// Concat appends str to the string being scanned, leaving the position
+// untouched.
+func (s *Scanner) Concat(str string) {
+	s.str += str
+}
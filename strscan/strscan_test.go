@@ -0,0 +1,130 @@
+package strscan
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleScanner() {
+	s := New("3.14 + 42")
+	for !s.EOS() {
+		s.Skip(`\s+`)
+		if num, ok := s.Scan(`\d+(\.\d+)?`); ok {
+			fmt.Println("number", num)
+		} else if op, ok := s.Scan(`[-+*/]`); ok {
+			fmt.Println("operator", op)
+		}
+	}
+	// Output: number 3.14
+	// operator +
+	// number 42
+}
+
+func TestScanner(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("Scanner", func() {
+		g.It("Should scan at the current position", func() {
+			s := New("This is an example string")
+			g.Assert(s.EOS()).IsFalse()
+			str, ok := s.Scan(`\w+`)
+			g.Assert(ok).IsTrue()
+			g.Assert(str).Equal("This")
+			_, ok = s.Scan(`\w+`)
+			g.Assert(ok).IsFalse()
+			str, _ = s.Scan(`\s+`)
+			g.Assert(str).Equal(" ")
+			g.Assert(s.Pos()).Equal(5)
+		})
+
+		g.It("Should scan until a pattern", func() {
+			s := New("Fri Dec 12 1975 14:39")
+			str, ok := s.ScanUntil(`1`)
+			g.Assert(ok).IsTrue()
+			g.Assert(str).Equal("Fri Dec 1")
+			g.Assert(s.PreMatch()).Equal("Fri Dec ")
+			g.Assert(s.Matched()).Equal("1")
+			g.Assert(s.PostMatch()).Equal("2 1975 14:39")
+			_, ok = s.ScanUntil(`XYZ`)
+			g.Assert(ok).IsFalse()
+		})
+
+		g.It("Should check without advancing", func() {
+			s := New("Fri Dec 12 1975 14:39")
+			str, ok := s.Check(`Fri`)
+			g.Assert(ok).IsTrue()
+			g.Assert(str).Equal("Fri")
+			g.Assert(s.Pos()).Equal(0)
+			str, ok = s.CheckUntil(`12`)
+			g.Assert(str).Equal("Fri Dec 12")
+			g.Assert(s.Pos()).Equal(0)
+			n, ok := s.Match(`\w+`)
+			g.Assert(n).Equal(3)
+			n, ok = s.Exist(`s`)
+			g.Assert(ok).IsFalse()
+		})
+
+		g.It("Should skip", func() {
+			s := New("test string")
+			n, ok := s.Skip(`\w+`)
+			g.Assert(ok).IsTrue()
+			g.Assert(n).Equal(4)
+			n, _ = s.SkipUntil(`g`)
+			g.Assert(n).Equal(7)
+			g.Assert(s.EOS()).IsTrue()
+		})
+
+		g.It("Should expose the last match", func() {
+			s := New("Fri Dec 12 1975 14:39")
+			s.Scan(`(\w+) (\w+) (\d+) `)
+			g.Assert(s.IsMatched()).IsTrue()
+			g.Assert(s.MatchedSize()).Equal(11)
+			g.Assert(s.Submatch(0)).Equal("Fri Dec 12 ")
+			g.Assert(s.Submatch(2)).Equal("Dec")
+			g.Assert(s.Submatch(9)).Equal("")
+			s.Scan(`Fri`)
+			g.Assert(s.IsMatched()).IsFalse()
+			g.Assert(s.MatchedSize()).Equal(-1)
+			g.Assert(s.Matched()).Equal("")
+		})
+
+		g.It("Should backtrack", func() {
+			s := New("test string")
+			s.Scan(`\w+`)
+			g.Assert(s.Unscan()).Eql(nil)
+			g.Assert(s.Pos()).Equal(0)
+			g.Assert(s.Unscan() != nil).IsTrue()
+			g.Assert(s.SetPos(5)).Eql(nil)
+			g.Assert(s.Rest()).Equal("string")
+			g.Assert(s.SetPos(-3)).Eql(nil)
+			g.Assert(s.Rest()).Equal("ing")
+			g.Assert(s.SetPos(42) != nil).IsTrue()
+		})
+
+		g.It("Should peek and get characters", func() {
+			s := New("été")
+			g.Assert(s.Peek(2)).Equal("é")
+			g.Assert(s.Peek(42)).Equal("été")
+			c, _ := s.Getch()
+			g.Assert(c).Equal("é")
+			g.Assert(s.RestSize()).Equal(3)
+			s.Terminate()
+			_, ok := s.Getch()
+			g.Assert(ok).IsFalse()
+			s.Reset()
+			g.Assert(s.Pos()).Equal(0)
+		})
+
+		g.It("Should handle multiple lines", func() {
+			s := New("test\nstring")
+			g.Assert(s.BOL()).IsTrue()
+			s.Scan(`te`)
+			g.Assert(s.BOL()).IsFalse()
+			s.Scan(`st\n`)
+			g.Assert(s.BOL()).IsTrue()
+			s.Concat(" more")
+			g.Assert(s.Rest()).Equal("string more")
+		})
+	})
+}
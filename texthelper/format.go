@@ -0,0 +1,91 @@
+package texthelper
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var formatDirectiveRegexp = regexp.MustCompile(`^%(?:\{([^}]*)\}|<([^>]*)>([-+ 0#]*\d*(?:\.\d+)?[bBcdiouxXeEfgGsp]))`)
+
+// Format renders a template using Ruby's named format references, so
+// templated messages shared with a Ruby app render identically:
+//
+//	%{name}     is replaced by the value converted to a string.
+//	%<name>05d  is replaced by the value formatted using the printf spec.
+//	%%          is replaced by a single %.
+//
+// An error is returned if a reference has no matching value.
+//
+//	Format("%<count>03d %{item}", map[string]interface{}{"count": 7, "item": "apples"})
+//	// => "007 apples"
+//
+// Ruby documentation: http://ruby-doc.org/core/Kernel.html#method-i-format
+func Format(template string, values map[string]interface{}) (string, error) {
+	var buf bytes.Buffer
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+		if c != '%' {
+			buf.WriteByte(c)
+			continue
+		}
+		if strings.HasPrefix(template[i:], "%%") {
+			buf.WriteByte('%')
+			i++
+			continue
+		}
+		m := formatDirectiveRegexp.FindStringSubmatch(template[i:])
+		if m == nil {
+			return "", fmt.Errorf("malformed format string - %s", template[i:])
+		}
+		name := m[1] + m[2]
+		v, ok := values[name]
+		if !ok {
+			return "", fmt.Errorf("key<%s> not found", name)
+		}
+		if m[2] == "" {
+			buf.WriteString(fmt.Sprint(v))
+		} else {
+			buf.WriteString(formatValue(m[3], v))
+		}
+		i += len(m[0]) - 1
+	}
+	return buf.String(), nil
+}
+
+// formatValue formats a value using a Ruby printf spec, converting the
+// value the way Ruby would for the directive.
+func formatValue(spec string, v interface{}) string {
+	verb := spec[len(spec)-1]
+	flags := spec[:len(spec)-1]
+	switch verb {
+	case 's':
+		return fmt.Sprintf("%"+flags+"s", fmt.Sprint(v))
+	case 'p':
+		return fmt.Sprintf("%"+flags+"s", fmt.Sprintf("%#v", v))
+	case 'd', 'i', 'u', 'x', 'X', 'o', 'b', 'B':
+		switch n := v.(type) {
+		case float32:
+			v = int64(n)
+		case float64:
+			v = int64(n)
+		}
+		switch verb {
+		case 'i', 'u':
+			verb = 'd'
+		case 'B':
+			verb = 'b'
+		}
+	case 'e', 'E', 'f', 'g', 'G':
+		switch n := v.(type) {
+		case int:
+			v = float64(n)
+		case int32:
+			v = float64(n)
+		case int64:
+			v = float64(n)
+		}
+	}
+	return fmt.Sprintf("%"+flags+string(verb), v)
+}
@@ -0,0 +1,61 @@
+package texthelper
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleFormat() {
+	msg, _ := Format("%<count>03d %{item} (%<ratio>.1f%%)", map[string]interface{}{"count": 7, "item": "apples", "ratio": 12.345})
+	fmt.Println(msg)
+	// Output: 007 apples (12.3%)
+}
+
+func TestFormat(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("Format", func() {
+		values := map[string]interface{}{"name": "Matt", "count": 42, "price": 3.5}
+
+		g.It("Should replace plain references", func() {
+			out, err := Format("Hello %{name}, you have %{count} messages", values)
+			g.Assert(err).Eql(nil)
+			g.Assert(out).Equal("Hello Matt, you have 42 messages")
+		})
+
+		g.It("Should apply the format specs", func() {
+			expectations := map[string]string{
+				"%<count>05d":  "00042",
+				"%<count>-5d|": "42   |",
+				"%<count>x":    "2a",
+				"%<price>.2f":  "3.50",
+				"%<price>d":    "3",
+				"%<count>.1f":  "42.0",
+				"%<name>10s":   "      Matt",
+				"%<count>s":    "42",
+				"%<count>i":    "42",
+			}
+			for input, output := range expectations {
+				out, err := Format(input, values)
+				g.Assert(err).Eql(nil)
+				g.Assert(out).Equal(output)
+			}
+		})
+
+		g.It("Should escape percent signs", func() {
+			out, _ := Format("100%% %{name}", values)
+			g.Assert(out).Equal("100% Matt")
+		})
+
+		g.It("Should fail on missing keys", func() {
+			_, err := Format("%{missing}", values)
+			g.Assert(err.Error()).Equal("key<missing> not found")
+		})
+
+		g.It("Should fail on malformed directives", func() {
+			_, err := Format("%d", values)
+			g.Assert(err != nil).IsTrue()
+		})
+	})
+}
@@ -0,0 +1,77 @@
+package texthelper
+
+import "github.com/mattetti/goRailsYourself/i18n"
+
+// SentenceOptions customizes the connectors used by ToSentence. Empty
+// connectors use the ones of the Locale (English if not set or unknown),
+// the support.array keys of the locale files loaded with the i18n package
+// taking precedence over the built-in ones.
+type SentenceOptions struct {
+	// WordsConnector joins all but the last two elements, ", " in English.
+	WordsConnector string
+	// TwoWordsConnector joins two elements, " and " in English.
+	TwoWordsConnector string
+	// LastWordConnector joins the last element when there are more than
+	// two, ", and " in English.
+	LastWordConnector string
+	// Locale selects the default connectors.
+	Locale string
+}
+
+// sentenceConnectors holds the words, two words and last word connectors
+// per locale, as defined by the rails-i18n locale files.
+var sentenceConnectors = map[string][3]string{
+	"en": {", ", " and ", ", and "},
+	"fr": {", ", " et ", " et "},
+	"de": {", ", " und ", " und "},
+	"es": {", ", " y ", " y "},
+	"it": {", ", " e ", " e "},
+	"pt": {", ", " e ", " e "},
+	"nl": {", ", " en ", " en "},
+}
+
+// ToSentence converts the list into a comma separated sentence.
+//
+//	ToSentence([]string{"one", "two"}, SentenceOptions{})          // => "one and two"
+//	ToSentence([]string{"one", "two", "three"}, SentenceOptions{}) // => "one, two, and three"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/Array.html#method-i-to_sentence
+func ToSentence(words []string, opts SentenceOptions) string {
+	connectors, ok := sentenceConnectors[opts.Locale]
+	if !ok {
+		connectors = sentenceConnectors["en"]
+	}
+	if i18n.Available(opts.Locale) {
+		for i, key := range [3]string{"words_connector", "two_words_connector", "last_word_connector"} {
+			if c, err := i18n.Translate(opts.Locale, "support.array."+key, nil); err == nil {
+				connectors[i] = c
+			}
+		}
+	}
+	if opts.WordsConnector == "" {
+		opts.WordsConnector = connectors[0]
+	}
+	if opts.TwoWordsConnector == "" {
+		opts.TwoWordsConnector = connectors[1]
+	}
+	if opts.LastWordConnector == "" {
+		opts.LastWordConnector = connectors[2]
+	}
+
+	switch len(words) {
+	case 0:
+		return ""
+	case 1:
+		return words[0]
+	case 2:
+		return words[0] + opts.TwoWordsConnector + words[1]
+	}
+	var sentence string
+	for i, word := range words[:len(words)-1] {
+		if i > 0 {
+			sentence += opts.WordsConnector
+		}
+		sentence += word
+	}
+	return sentence + opts.LastWordConnector + words[len(words)-1]
+}
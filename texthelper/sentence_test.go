@@ -0,0 +1,47 @@
+package texthelper
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+	"github.com/mattetti/goRailsYourself/i18n"
+)
+
+func ExampleToSentence() {
+	fmt.Println(ToSentence([]string{"one", "two", "three"}, SentenceOptions{}))
+	fmt.Println(ToSentence([]string{"un", "deux", "trois"}, SentenceOptions{Locale: "fr"}))
+	// Output: one, two, and three
+	// un, deux et trois
+}
+
+func TestToSentence(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("ToSentence", func() {
+		g.It("Should join the words", func() {
+			g.Assert(ToSentence(nil, SentenceOptions{})).Equal("")
+			g.Assert(ToSentence([]string{"one"}, SentenceOptions{})).Equal("one")
+			g.Assert(ToSentence([]string{"one", "two"}, SentenceOptions{})).Equal("one and two")
+			g.Assert(ToSentence([]string{"one", "two", "three"}, SentenceOptions{})).Equal("one, two, and three")
+		})
+
+		g.It("Should use the custom connectors", func() {
+			opts := SentenceOptions{WordsConnector: " ", TwoWordsConnector: " & ", LastWordConnector: " or "}
+			g.Assert(ToSentence([]string{"one", "two"}, opts)).Equal("one & two")
+			g.Assert(ToSentence([]string{"one", "two", "three"}, opts)).Equal("one two or three")
+		})
+
+		g.It("Should use the locale connectors", func() {
+			g.Assert(ToSentence([]string{"eins", "zwei", "drei"}, SentenceOptions{Locale: "de"})).Equal("eins, zwei und drei")
+			g.Assert(ToSentence([]string{"one", "two"}, SentenceOptions{Locale: "unknown"})).Equal("one and two")
+		})
+
+		g.It("Should use the i18n connectors", func() {
+			i18n.Store("sv", map[string]interface{}{"support": map[string]interface{}{"array": map[string]interface{}{
+				"words_connector": ", ", "two_words_connector": " och ", "last_word_connector": " och ",
+			}}})
+			g.Assert(ToSentence([]string{"ett", "två", "tre"}, SentenceOptions{Locale: "sv"})).Equal("ett, två och tre")
+			g.Assert(ToSentence([]string{"ett", "två"}, SentenceOptions{Locale: "sv", TwoWordsConnector: " & "})).Equal("ett & två")
+		})
+	})
+}
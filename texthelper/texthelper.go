@@ -0,0 +1,107 @@
+// The texthelper package ports some of ActionView's text helpers, so text
+// snippets rendered by Go services look the same as the ones rendered by
+// the Rails views.
+//
+// Rails documentation http://api.rubyonrails.org/classes/ActionView/Helpers/TextHelper.html
+package texthelper
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mattetti/goRailsYourself/internal/recache"
+)
+
+// ExcerptOptions customizes the output of Excerpt. Zero values use the
+// Rails defaults.
+type ExcerptOptions struct {
+	// Radius is the number of units (characters, or separated parts when a
+	// Separator is set) kept around the phrase. Defaults to 100.
+	Radius int
+	// Omission is prepended/appended when the text is cut. Defaults to "...".
+	Omission string
+	// Separator is used to split the text into units, defaults to
+	// characters. Use " " to work with words.
+	Separator string
+}
+
+// Excerpt extracts the text surrounding the first occurrence of phrase
+// (case insensitive) in text. An empty string is returned if the phrase
+// isn't found.
+//
+//	Excerpt("This is an example", "an", ExcerptOptions{Radius: 5})
+//	// => "...s is an exam..."
+//	Excerpt("This is also an example", "an", ExcerptOptions{Radius: 1, Separator: " "})
+//	// => "...also an example"
+//
+// Rails documentation: http://api.rubyonrails.org/classes/ActionView/Helpers/TextHelper.html#method-i-excerpt
+func Excerpt(text, phrase string, opts ExcerptOptions) string {
+	if text == "" || phrase == "" {
+		return ""
+	}
+	return ExcerptRegexp(text, recache.MustCompile("(?i)"+regexp.QuoteMeta(phrase)), opts)
+}
+
+// ExcerptRegexp is like Excerpt but the phrase is the first match of the
+// passed regular expression.
+func ExcerptRegexp(text string, re *regexp.Regexp, opts ExcerptOptions) string {
+	if opts.Radius == 0 {
+		opts.Radius = 100
+	}
+	if opts.Omission == "" {
+		opts.Omission = "..."
+	}
+	loc := re.FindStringIndex(text)
+	if loc == nil {
+		return ""
+	}
+	phrase := text[loc[0]:loc[1]]
+	if opts.Separator != "" {
+		for _, value := range splitExcerpt(text, opts.Separator) {
+			if re.MatchString(value) {
+				phrase = value
+				break
+			}
+		}
+	}
+
+	parts := strings.SplitN(text, phrase, 2)
+	prefix, first := cutExcerptPart(true, parts[0], opts)
+	var postfix, second string
+	if len(parts) > 1 {
+		postfix, second = cutExcerptPart(false, parts[1], opts)
+	}
+	affix := strings.TrimSpace(first + opts.Separator + phrase + opts.Separator + second)
+	return prefix + affix + postfix
+}
+
+// cutExcerptPart keeps the radius closest units of the part and returns the
+// omission to use if units were removed.
+func cutExcerptPart(first bool, part string, opts ExcerptOptions) (string, string) {
+	units := splitExcerpt(part, opts.Separator)
+	var affix string
+	if len(units) > opts.Radius {
+		affix = opts.Omission
+		if first {
+			units = units[len(units)-opts.Radius:]
+		} else {
+			units = units[:opts.Radius]
+		}
+	}
+	return affix, strings.Join(units, opts.Separator)
+}
+
+// splitExcerpt splits the text the way Ruby's String#split does, ignoring
+// empty units. A single space splits on any whitespace.
+func splitExcerpt(text, sep string) []string {
+	if sep == " " {
+		return strings.Fields(text)
+	}
+	var units []string
+	for _, unit := range strings.Split(text, sep) {
+		if unit != "" {
+			units = append(units, unit)
+		}
+	}
+	return units
+}
@@ -0,0 +1,74 @@
+package texthelper
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleExcerpt() {
+	fmt.Println(Excerpt("This is an example", "an", ExcerptOptions{Radius: 5}))
+	fmt.Println(Excerpt("This is also an example", "an", ExcerptOptions{Radius: 1, Separator: " "}))
+	// Output: ...s is an exam...
+	// ...also an example
+}
+
+func TestExcerpt(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("Excerpt", func() {
+		g.It("Should cut the text around the phrase", func() {
+			g.Assert(Excerpt("This is a beautiful morning", "beautiful", ExcerptOptions{Radius: 5})).Equal("...is a beautiful morn...")
+			g.Assert(Excerpt("This is a beautiful morning", "this", ExcerptOptions{Radius: 5})).Equal("This is a...")
+			g.Assert(Excerpt("This is a beautiful morning", "morning", ExcerptOptions{Radius: 5})).Equal("...iful morning")
+		})
+
+		g.It("Should return an empty string when the phrase isn't found", func() {
+			g.Assert(Excerpt("This is a beautiful morning", "day", ExcerptOptions{})).Equal("")
+			g.Assert(Excerpt("", "day", ExcerptOptions{})).Equal("")
+		})
+
+		g.It("Should use the default radius", func() {
+			text := "This is a beautiful morning"
+			g.Assert(Excerpt(text, "beautiful", ExcerptOptions{})).Equal(text)
+		})
+
+		g.It("Should use the custom omission", func() {
+			g.Assert(Excerpt("This is a beautiful morning", "beautiful", ExcerptOptions{Radius: 5, Omission: "[...]"})).Equal("[...]is a beautiful morn[...]")
+		})
+
+		g.It("Should be case insensitive and keep the original case", func() {
+			g.Assert(Excerpt("This is a beautiful morning", "BEAUTIFUL", ExcerptOptions{Radius: 5})).Equal("...is a beautiful morn...")
+		})
+
+		g.It("Should work with multibyte characters", func() {
+			g.Assert(Excerpt("Ærøskøbing est une ville", "est", ExcerptOptions{Radius: 2})).Equal("...g est u...")
+		})
+
+		g.It("Should split on the separator", func() {
+			options := ExcerptOptions{Separator: " ", Radius: 1}
+			g.Assert(Excerpt("This is a beautiful morning", "beautiful", options)).Equal("...a beautiful morning")
+			options.Radius = 2
+			g.Assert(Excerpt("This is a beautiful morning", "beautiful", options)).Equal("...is a beautiful morning")
+			g.Assert(Excerpt("my very very very long string", "long", ExcerptOptions{Separator: " ", Radius: 1})).Equal("...very long string")
+			g.Assert(Excerpt("This is a beautiful morning", "beaut", ExcerptOptions{Separator: " ", Radius: 1})).Equal("...a beautiful morning")
+		})
+
+		g.It("Should work with a multi-line separator", func() {
+			text := "This is a beautiful evening\nThis is a beautiful morning\nThis is a beautiful day\nThis is a beautiful night\nThis is a beautiful evening"
+			g.Assert(Excerpt(text, "day", ExcerptOptions{Separator: "\n", Radius: 1})).Equal("...This is a beautiful morning\nThis is a beautiful day\nThis is a beautiful night...")
+		})
+
+		g.It("Should accept a regular expression", func() {
+			re := regexp.MustCompile(`b\w+l`)
+			g.Assert(ExcerptRegexp("This is a beautiful morning", re, ExcerptOptions{Radius: 5})).Equal("...is a beautiful morn...")
+		})
+	})
+}
+
+func BenchmarkExcerpt(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Excerpt("This is a beautiful morning", "beautiful", ExcerptOptions{Radius: 5})
+	}
+}
@@ -0,0 +1,94 @@
+package version
+
+import (
+	"sort"
+	"strings"
+)
+
+// NaturalLess reports whether a sorts before b in natural order: runs of
+// digits are compared by their numeric value and the other characters
+// are compared ignoring case, so "file2" sorts before "file10" and
+// "alpha" before "Beta". Strings that are equal in natural order are
+// compared byte by byte.
+//
+//	NaturalLess("file2", "file10")  // => true
+//	NaturalLess("v1.9.1", "v1.10") // => true
+func NaturalLess(a, b string) bool {
+	if c := naturalCompare(a, b); c != 0 {
+		return c < 0
+	}
+	return a < b
+}
+
+// SortNatural sorts the strings in natural order.
+func SortNatural(s []string) {
+	sort.SliceStable(s, func(i, j int) bool { return NaturalLess(s[i], s[j]) })
+}
+
+// naturalCompare compares the strings chunk by chunk.
+func naturalCompare(a, b string) int {
+	for a != "" && b != "" {
+		var ca, cb string
+		ca, a = nextChunk(a)
+		cb, b = nextChunk(b)
+		aDigits, bDigits := isDigit(ca[0]), isDigit(cb[0])
+		switch {
+		case aDigits && bDigits:
+			if c := compareNumbers(ca, cb); c != 0 {
+				return c
+			}
+		case aDigits != bDigits:
+			// numbers sort before letters
+			if aDigits {
+				return -1
+			}
+			return 1
+		default:
+			if c := strings.Compare(strings.ToLower(ca), strings.ToLower(cb)); c != 0 {
+				return c
+			}
+		}
+	}
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	}
+	return 1
+}
+
+// nextChunk splits the leading run of digits or non digits from s.
+func nextChunk(s string) (chunk, rest string) {
+	digits := isDigit(s[0])
+	i := 1
+	for i < len(s) && isDigit(s[i]) == digits {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+// compareNumbers compares two runs of digits by value, then by length so
+// "01" sorts after "1".
+func compareNumbers(a, b string) int {
+	ta, tb := strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	switch {
+	case len(ta) != len(tb):
+		if len(ta) < len(tb) {
+			return -1
+		}
+		return 1
+	case ta != tb:
+		return strings.Compare(ta, tb)
+	case len(a) != len(b):
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
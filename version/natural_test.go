@@ -0,0 +1,47 @@
+package version
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleSortNatural() {
+	files := []string{"file10.txt", "file2.txt", "File1.txt"}
+	SortNatural(files)
+	fmt.Println(files)
+	// Output: [File1.txt file2.txt file10.txt]
+}
+
+func TestNaturalLess(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("NaturalLess", func() {
+		g.It("Should compare the numbers by value", func() {
+			g.Assert(NaturalLess("file2", "file10")).IsTrue()
+			g.Assert(NaturalLess("file10", "file2")).IsFalse()
+			g.Assert(NaturalLess("v1.9.1", "v1.10")).IsTrue()
+			g.Assert(NaturalLess("1", "01")).IsTrue()
+			g.Assert(NaturalLess("item 99999999999999999999", "item 100000000000000000000")).IsTrue()
+		})
+
+		g.It("Should ignore the case", func() {
+			g.Assert(NaturalLess("Beta", "alpha2")).IsFalse()
+			g.Assert(NaturalLess("alpha2", "Beta")).IsTrue()
+			g.Assert(NaturalLess("ABC", "abc")).IsTrue()
+		})
+
+		g.It("Should sort the prefixes first", func() {
+			g.Assert(NaturalLess("file", "file1")).IsTrue()
+			g.Assert(NaturalLess("", "a")).IsTrue()
+			g.Assert(NaturalLess("a", "a")).IsFalse()
+			g.Assert(NaturalLess("2", "a")).IsTrue()
+		})
+
+		g.It("Should sort a list", func() {
+			ids := []string{"img12.png", "img10.png", "IMG2.png", "img1.png"}
+			SortNatural(ids)
+			g.Assert(ids).Equal([]string{"img1.png", "IMG2.png", "img10.png", "img12.png"})
+		})
+	})
+}
@@ -0,0 +1,192 @@
+// The version package ports RubyGems' Gem::Version comparison, so Go
+// tooling can order gem and application versions exactly like Bundler
+// does, and offers a natural order for strings with embedded numbers.
+//
+// RubyGems documentation: https://docs.ruby-lang.org/en/master/Gem/Version.html
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// validVersion is the pattern Gem::Version validates versions with.
+var validVersion = regexp.MustCompile(`^\s*([0-9]+(\.[0-9a-zA-Z]+)*(-[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?)?\s*$`)
+
+// segmentRegexp splits a version into its numeric and string segments.
+var segmentRegexp = regexp.MustCompile(`[0-9]+|[a-zA-Z]+`)
+
+// Version is a parsed gem version.
+type Version struct {
+	version  string
+	segments []interface{}
+}
+
+// Parse parses a version. Like in RubyGems, dashes are read as prerelease
+// markers ("1.0-rc1" is "1.0.pre.rc1") and an empty version is "0".
+//
+//	v, _ := Parse("1.10.2")
+//	v.Segments() // => [1 10 2]
+func Parse(str string) (Version, error) {
+	if !validVersion.MatchString(str) {
+		return Version{}, fmt.Errorf("version: malformed version number string %s", str)
+	}
+	str = strings.TrimSpace(str)
+	if str == "" {
+		str = "0"
+	}
+	str = strings.Replace(str, "-", ".pre.", -1)
+	return Version{version: str, segments: splitSegments(str)}, nil
+}
+
+// MustParse is like Parse but panics if the version is malformed.
+func MustParse(str string) Version {
+	v, err := Parse(str)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// String returns the version, with the dashes replaced by ".pre.".
+func (v Version) String() string {
+	return v.version
+}
+
+// Segments returns the segments of the version: ints for the numeric
+// parts and strings for the other ones.
+func (v Version) Segments() []interface{} {
+	return append([]interface{}(nil), v.segments...)
+}
+
+// Prerelease reports whether the version contains a letter, like
+// "1.0.0.rc1" or "2.0.b".
+func (v Version) Prerelease() bool {
+	return strings.IndexFunc(v.version, isLetter) >= 0
+}
+
+// Release returns the release of a prerelease version ("1.2.0.a" => "1.2.0")
+// or the version itself.
+func (v Version) Release() Version {
+	if !v.Prerelease() {
+		return v
+	}
+	var parts []string
+	for _, s := range v.segments {
+		if _, ok := s.(string); ok {
+			break
+		}
+		parts = append(parts, strconv.Itoa(s.(int)))
+	}
+	return MustParse(strings.Join(parts, "."))
+}
+
+// Compare returns -1, 0 or 1 depending on whether v is lower, equal or
+// greater than other. Trailing zeros are ignored ("1.0" equals "1") and
+// prereleases are lower than releases ("1.0.a" < "1.0").
+//
+// RubyGems documentation: https://docs.ruby-lang.org/en/master/Gem/Version.html#method-i-3C-3D-3E
+func (v Version) Compare(other Version) int {
+	if v.version == other.version {
+		return 0
+	}
+	lhs, rhs := canonicalSegments(v.segments), canonicalSegments(other.segments)
+	size := len(lhs)
+	if len(rhs) > size {
+		size = len(rhs)
+	}
+	for i := 0; i < size; i++ {
+		var l, r interface{} = 0, 0
+		if i < len(lhs) {
+			l = lhs[i]
+		}
+		if i < len(rhs) {
+			r = rhs[i]
+		}
+		if l == r {
+			continue
+		}
+		ls, lString := l.(string)
+		rs, rString := r.(string)
+		switch {
+		case lString && !rString:
+			return -1
+		case !lString && rString:
+			return 1
+		case lString:
+			return strings.Compare(ls, rs)
+		case l.(int) < r.(int):
+			return -1
+		default:
+			return 1
+		}
+	}
+	return 0
+}
+
+// CompareVersions compares two version strings the way RubyGems does,
+// returning -1, 0 or 1. Malformed versions are compared using their
+// numeric and letter segments.
+//
+//	CompareVersions("1.10.2", "1.9")    // => 1
+//	CompareVersions("1.0.0.rc1", "1.0") // => -1
+//	CompareVersions("1.0", "1")         // => 0
+func CompareVersions(a, b string) int {
+	return lenientParse(a).Compare(lenientParse(b))
+}
+
+// Less reports whether the version a is lower than the version b. It can be
+// used with sort.Slice.
+func Less(a, b string) bool {
+	return CompareVersions(a, b) < 0
+}
+
+// lenientParse parses a version without validating it.
+func lenientParse(str string) Version {
+	if v, err := Parse(str); err == nil {
+		return v
+	}
+	return Version{version: str, segments: splitSegments(str)}
+}
+
+// splitSegments splits the version into ints and strings.
+func splitSegments(str string) []interface{} {
+	matches := segmentRegexp.FindAllString(str, -1)
+	segments := make([]interface{}, len(matches))
+	for i, m := range matches {
+		if n, err := strconv.Atoi(m); err == nil {
+			segments[i] = n
+		} else {
+			segments[i] = m
+		}
+	}
+	return segments
+}
+
+// canonicalSegments drops the trailing zeros of the numeric part and of the
+// prerelease part of the segments.
+func canonicalSegments(segments []interface{}) []interface{} {
+	stringStart := len(segments)
+	for i, s := range segments {
+		if _, ok := s.(string); ok {
+			stringStart = i
+			break
+		}
+	}
+	canonical := dropTrailingZeros(segments[:stringStart])
+	return append(canonical, dropTrailingZeros(segments[stringStart:])...)
+}
+
+func dropTrailingZeros(segments []interface{}) []interface{} {
+	end := len(segments)
+	for end > 0 && segments[end-1] == 0 {
+		end--
+	}
+	return append([]interface{}(nil), segments[:end]...)
+}
+
+func isLetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
@@ -0,0 +1,69 @@
+package version
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func ExampleCompareVersions() {
+	fmt.Println(CompareVersions("1.10.2", "1.9"))
+	fmt.Println(CompareVersions("1.0.0.rc1", "1.0"))
+	fmt.Println(CompareVersions("1.0", "1"))
+	// Output: 1
+	// -1
+	// 0
+}
+
+func TestVersion(t *testing.T) {
+	g := Goblin(t)
+	g.Describe("Parse", func() {
+		g.It("Should parse valid versions", func() {
+			g.Assert(MustParse("1.10.2").Segments()).Equal([]interface{}{1, 10, 2})
+			g.Assert(MustParse("1.0-rc1").String()).Equal("1.0.pre.rc1")
+			g.Assert(MustParse("1.0-rc1").Segments()).Equal([]interface{}{1, 0, "pre", "rc", 1})
+			g.Assert(MustParse(" ").String()).Equal("0")
+		})
+
+		g.It("Should refuse malformed versions", func() {
+			_, err := Parse("junk")
+			g.Assert(err.Error()).Equal("version: malformed version number string junk")
+			_, err = Parse("1..2")
+			g.Assert(err != nil).IsTrue()
+		})
+
+		g.It("Should detect prereleases", func() {
+			g.Assert(MustParse("1.0.0.rc1").Prerelease()).IsTrue()
+			g.Assert(MustParse("1.0.0").Prerelease()).IsFalse()
+			g.Assert(MustParse("1.2.0.a").Release().String()).Equal("1.2.0")
+			g.Assert(MustParse("1.0.rc1").Release().String()).Equal("1.0")
+			g.Assert(MustParse("1.2").Release().String()).Equal("1.2")
+		})
+	})
+
+	g.Describe("CompareVersions", func() {
+		g.It("Should compare the numeric segments", func() {
+			g.Assert(CompareVersions("1.10.2", "1.9")).Equal(1)
+			g.Assert(CompareVersions("1.9", "1.10.2")).Equal(-1)
+			g.Assert(CompareVersions("1.0", "1")).Equal(0)
+			g.Assert(CompareVersions("1.0.0.1", "1")).Equal(1)
+		})
+
+		g.It("Should sort the prereleases first", func() {
+			g.Assert(CompareVersions("1.0.a", "1.0")).Equal(-1)
+			g.Assert(CompareVersions("1.0.a", "1.0.b")).Equal(-1)
+			g.Assert(CompareVersions("1.0.a.2", "1.0.a.10")).Equal(-1)
+			g.Assert(CompareVersions("1.0-rc1", "1.0.pre.rc1")).Equal(0)
+			g.Assert(CompareVersions("1.0.b1", "1.0.a.2")).Equal(1)
+			g.Assert(CompareVersions("2.0.0.rc1", "1.9.9")).Equal(1)
+		})
+
+		g.It("Should sort with Less", func() {
+			versions := []string{"1.10", "1.9", "1.0.rc1", "1.0", "0.9.9", "1.10.beta"}
+			sort.Slice(versions, func(i, j int) bool { return Less(versions[i], versions[j]) })
+			g.Assert(versions).Equal([]string{"0.9.9", "1.0.rc1", "1.0", "1.9", "1.10.beta", "1.10"})
+		})
+	})
+}